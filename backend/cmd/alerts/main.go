@@ -0,0 +1,106 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"log"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/gigaptera/hikkoshi-lens/backend/internal/config"
+	"github.com/gigaptera/hikkoshi-lens/backend/internal/domain"
+	"github.com/gigaptera/hikkoshi-lens/backend/internal/domain/service"
+	"github.com/gigaptera/hikkoshi-lens/backend/internal/infrastructure"
+	"github.com/gigaptera/hikkoshi-lens/backend/internal/infrastructure/alert"
+	"github.com/gigaptera/hikkoshi-lens/backend/internal/infrastructure/mail"
+	"github.com/gigaptera/hikkoshi-lens/backend/internal/infrastructure/repository"
+	"github.com/gigaptera/hikkoshi-lens/backend/internal/usecase"
+)
+
+// alerts は保存した検索を再実行し、新しく条件に合った駅・家賃相場の変化を通知する
+//
+//	go run ./cmd/alerts          # 1回だけ実行 (インポート後に cron などから呼ぶ)
+//	go run ./cmd/alerts -watch   # 起動時に1回実行し、以後は家賃相場・スコアの更新通知ごとに実行
+func main() {
+	watch := flag.Bool("watch", false, "データ更新通知 (market_prices, station_scores) を待ち受けて実行し続ける")
+	timeout := flag.Duration("webhook-timeout", 10*time.Second, "Webhook 送信のタイムアウト")
+	flag.Parse()
+
+	cfg, err := config.Load()
+	if err != nil {
+		log.Fatal(err)
+	}
+	if cfg.DatabaseURL == "" {
+		log.Fatal("DATABASE_URL is required")
+	}
+
+	db := infrastructure.NewDB(cfg.DatabaseURL)
+	defer db.Close()
+
+	mailer := mail.NewSender(cfg.MailSender, cfg.SMTPAddr, cfg.SMTPUsername, cfg.SMTPPassword, cfg.MailFrom)
+	notifiers := map[string]domain.Notifier{
+		domain.NotifyChannelLog:     &alert.LogNotifier{},
+		domain.NotifyChannelEmail:   &alert.EmailNotifier{Mailer: mailer},
+		domain.NotifyChannelWebhook: alert.NewWebhookNotifier(*timeout),
+	}
+	if _, ok := notifiers[cfg.AlertNotifier]; !ok {
+		log.Fatalf("Unknown ALERT_NOTIFIER %q (log, email, webhook)", cfg.AlertNotifier)
+	}
+
+	repoStation := repository.NewStationRepository(db)
+	repoStationScore := repository.NewStationScoreRepository(db)
+	// 検索だけを使うため、駅詳細のリポジトリは渡さない
+	ucStation := usecase.NewStationUsecase(usecase.StationUsecaseDeps{
+		Repo:      repoStation,
+		ScoreRepo: repoStationScore,
+		TagRepo:   repository.NewTagRepository(db),
+		Scoring:   service.NewScoringService(),
+	})
+	job := usecase.NewSearchAlertUsecase(repository.NewSavedSearchRepository(db), ucStation, notifiers, cfg.AlertNotifier)
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	run := func() {
+		start := time.Now()
+		stats, err := job.Run(ctx)
+		if err != nil {
+			log.Printf("Alert run failed: %v", err)
+			return
+		}
+		log.Printf("Alert run completed in %s: %d searches, %d baseline, %d notified, %d failed",
+			time.Since(start).Round(time.Millisecond), stats.Searches, stats.Baseline, stats.Notified, stats.Failed)
+	}
+
+	if !*watch {
+		run()
+		return
+	}
+
+	// 更新通知はインポート中に連続して届くため、実行中に届いた分は1回にまとめる
+	trigger := make(chan struct{}, 1)
+	err = infrastructure.ListenDataUpdated(ctx, db, func(table string) {
+		if table != "market_prices" && table != "station_scores" {
+			return
+		}
+		select {
+		case trigger <- struct{}{}:
+		default:
+		}
+	})
+	if err != nil {
+		log.Fatalf("Failed to listen for data updates: %v", err)
+	}
+
+	run()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-trigger:
+			run()
+		}
+	}
+}
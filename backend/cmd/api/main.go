@@ -1,15 +1,13 @@
 package main
 
 import (
+	"context"
 	"log"
 	"net/http"
 
 	"github.com/gigaptera/hikkoshi-lens/backend/internal/config"
-	"github.com/gigaptera/hikkoshi-lens/backend/internal/domain/service"
 	"github.com/gigaptera/hikkoshi-lens/backend/internal/infrastructure"
-	"github.com/gigaptera/hikkoshi-lens/backend/internal/infrastructure/repository"
-	"github.com/gigaptera/hikkoshi-lens/backend/internal/interface/handler"
-	"github.com/gigaptera/hikkoshi-lens/backend/internal/usecase"
+	"github.com/gigaptera/hikkoshi-lens/backend/internal/interface/openapi"
 	"github.com/labstack/echo/v4"
 	"github.com/labstack/echo/v4/middleware"
 )
@@ -21,43 +19,41 @@ func main() {
 		log.Fatal(err)
 	}
 
+	// Load OpenAPI spec
+	spec, err := openapi.Load()
+	if err != nil {
+		log.Fatal(err)
+	}
+
 	// Initialize DB
 	db := infrastructure.NewDB(cfg.DatabaseURL)
 	defer db.Close()
 
 	// Echo instance
 	e := echo.New()
+	// 口コミの投稿数の制限に使う投稿元のIPアドレス (プロキシ配下でのみ X-Forwarded-For を使う)
+	if cfg.TrustProxy {
+		e.IPExtractor = echo.ExtractIPFromXFFHeader()
+	} else {
+		e.IPExtractor = echo.ExtractIPDirect()
+	}
 
 	// Middleware
 	e.Use(middleware.Logger())
 	e.Use(middleware.Recover())
 	e.Use(middleware.CORSWithConfig(middleware.CORSConfig{
 		AllowOrigins: []string{"http://localhost:3000"},
-		AllowMethods: []string{http.MethodGet, http.MethodPut, http.MethodPost, http.MethodDelete},
+		AllowMethods: []string{http.MethodGet, http.MethodPut, http.MethodPost, http.MethodPatch, http.MethodDelete},
 	}))
 
 	// Routes
-	e.GET("/", func(c echo.Context) error {
-		return c.String(http.StatusOK, "Hello, World!")
-	})
-
-	api := e.Group("/api")
-	{
-		// Health
-		hHealth := handler.NewHealthHandler()
-		api.GET("/health", hHealth.Check)
-
-		// Station
-		repoStation := repository.NewStationRepository(db)
-		svcScoring := service.NewScoringService()
-		ucStation := usecase.NewStationUsecase(repoStation, svcScoring)
-		hStation := handler.NewStationHandler(ucStation)
-		api.GET("/stations/search", hStation.Search)    // New search endpoint
-		api.GET("/stations/nearby", hStation.GetNearby) // Backward compatibility
-		api.GET("/stations/line", hStation.GetStationsByLine)
-		api.GET("/stations/:id/three-stops", hStation.GetStationsWithinThreeStops)
-		api.GET("/stations/:id/details", hStation.GetStationDetail)
+	deps := registerRoutes(e, db, cfg, spec)
 
+	// インポーター・バッチからのデータ更新通知でキャッシュを破棄
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	if err := infrastructure.ListenDataUpdated(ctx, db, deps.Invalidate); err != nil {
+		log.Printf("Warning: failed to listen for data updates, cache will expire by TTL only: %v", err)
 	}
 
 	// Start server
@@ -0,0 +1,252 @@
+package main
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"runtime"
+	"time"
+
+	"github.com/gigaptera/hikkoshi-lens/backend/internal/config"
+	"github.com/gigaptera/hikkoshi-lens/backend/internal/domain"
+	"github.com/gigaptera/hikkoshi-lens/backend/internal/domain/service"
+	"github.com/gigaptera/hikkoshi-lens/backend/internal/infrastructure/mail"
+	"github.com/gigaptera/hikkoshi-lens/backend/internal/infrastructure/osm"
+	"github.com/gigaptera/hikkoshi-lens/backend/internal/infrastructure/repository"
+	"github.com/gigaptera/hikkoshi-lens/backend/internal/interface/handler"
+	"github.com/gigaptera/hikkoshi-lens/backend/internal/interface/openapi"
+	"github.com/gigaptera/hikkoshi-lens/backend/internal/usecase"
+	"github.com/labstack/echo/v4"
+	"github.com/uptrace/bun"
+)
+
+// dependencies はルート登録時に組み立てたコンポーネントのうち、main側でも使うもの
+type dependencies struct {
+	stationCache *usecase.CachedStationUsecase
+	tileCache    *usecase.CachedTileUsecase
+	isoCache     *usecase.CachedIsochroneUsecase
+	commute      usecase.CommuteUsecase
+}
+
+// Invalidate はデータ更新時にすべての応答キャッシュ (と定期代の計算結果) を破棄する
+func (d *dependencies) Invalidate(table string) {
+	d.stationCache.Invalidate(table)
+	d.tileCache.Invalidate(table)
+	d.isoCache.Invalidate(table)
+	d.commute.Invalidate(table)
+}
+
+// registerRoutes はAPIのルートを登録する
+// /api 配下のルートは openapi.json にも定義すること（routes_test.go で検証）
+func registerRoutes(e *echo.Echo, db *bun.DB, cfg *config.Config, spec *openapi.Spec) *dependencies {
+	deps := &dependencies{}
+
+	e.GET("/", func(c echo.Context) error {
+		return c.String(http.StatusOK, "Hello, World!")
+	})
+
+	api := e.Group("/api", openapi.RequestValidator(spec))
+	{
+		// Health
+		hHealth := handler.NewHealthHandler()
+		api.GET("/health", hHealth.Check)
+
+		// OpenAPI
+		hOpenAPI := handler.NewOpenAPIHandler(spec)
+		api.GET("/openapi.json", hOpenAPI.Spec)
+
+		// Station
+		repoStation := repository.NewStationRepository(db)
+		repoStationScore := repository.NewStationScoreRepository(db)
+		repoPOI := repository.NewPOIRepository(db)
+		svcScoring := service.NewScoringService()
+		// 勤務地 -> 駅、駅 -> 周辺施設 の道路距離 (徒歩ネットワークがなければ直線距離から推定)
+		walk := usecase.NewWalkDistances(loadWalkRouter(cfg.WalkNetworkFile), cfg.WalkCacheEntries)
+		ucStation := usecase.NewStationUsecase(usecase.StationUsecaseDeps{
+			Repo:          repoStation,
+			ScoreRepo:     repoStationScore,
+			TagRepo:       repository.NewTagRepository(db),
+			InsightRepo:   repository.NewInsightRepository(db),
+			ReviewRepo:    repository.NewReviewRepository(db),
+			PassengerRepo: repository.NewPassengerRepository(db),
+			TerrainRepo:   repository.NewTerrainRepository(db),
+			POIRepo:       repoPOI,
+			NightRepo:     repository.NewNightSafetyRepository(db),
+			Walk:          walk,
+			Scoring:       svcScoring,
+		})
+		deps.stationCache = usecase.NewCachedStationUsecase(ucStation, cfg.CacheTTL, cfg.CacheMaxEntries)
+		// 路線グラフ (全国分) は定期代と通勤時間圏で1つを共有する
+		transitGraphs := usecase.NewTransitGraphs(repoStation)
+		// 月額総額 (家賃 + 定期代 - 家賃補助)。運賃表は cmd/import/fares で取り込む
+		deps.commute = usecase.NewCommuteUsecase(transitGraphs, repoStation, repository.NewFareRepository(db))
+		// 初期費用 (敷金・礼金などの都道府県ごとの慣習と引越し料金)
+		moveInConfig := service.DefaultMoveInConfig()
+		if cfg.MoveInCostsFile != "" {
+			data, err := os.ReadFile(cfg.MoveInCostsFile)
+			if err != nil {
+				log.Fatalf("Failed to read move-in costs: %v", err)
+			}
+			if moveInConfig, err = service.ParseMoveInConfig(data); err != nil {
+				log.Fatalf("Invalid move-in costs: %v", err)
+			}
+		}
+		moveInEstimator := service.NewMoveInEstimator(moveInConfig)
+		hStation := handler.NewStationHandler(deps.stationCache, deps.commute, usecase.NewMoveInUsecase(repoStation, moveInEstimator))
+		api.GET("/stations/search", hStation.Search)    // New search endpoint
+		api.GET("/stations/nearby", hStation.GetNearby) // Backward compatibility
+		api.GET("/stations/line", hStation.GetStationsByLine)
+		api.GET("/stations/:id/three-stops", hStation.GetStationsWithinThreeStops)
+		api.GET("/stations/:id/details", hStation.GetStationDetail)
+
+		// Compare (候補駅の並べて比較)
+		hCompare := handler.NewCompareHandler(usecase.NewCompareUsecase(repoStation, repoStationScore, svcScoring, deps.commute, moveInEstimator))
+		api.GET("/stations/compare", hCompare.Compare)
+
+		// Budget (手取りから家賃の目安を求める。税・社会保険料の表は年ごとに組み込む)
+		hBudget := handler.NewBudgetHandler(usecase.NewBudgetUsecase(service.NewBudgetAdvisor(service.DefaultTaxTables())))
+		api.GET("/budget", hBudget.Advise)
+
+		// Reviews (口コミ。承認されたものだけを公開・集計する)
+		bannedWords := service.DefaultBannedWords()
+		if cfg.ReviewBannedWordsFile != "" {
+			data, err := os.ReadFile(cfg.ReviewBannedWordsFile)
+			if err != nil {
+				log.Fatalf("Failed to read banned words: %v", err)
+			}
+			bannedWords = append(bannedWords, service.ParseBannedWords(string(data))...)
+		}
+		clientSecret := cfg.ReviewClientSecret
+		if clientSecret == "" {
+			// 再起動すると口コミの投稿数・ログインリンクの送信数の制限がリセットされる
+			log.Println("Warning: REVIEW_CLIENT_SECRET is not set, using a random secret")
+			clientSecret = randomSecret()
+		}
+		ucReview := usecase.NewReviewUsecase(repository.NewReviewRepository(db), repoStation, repoStationScore, service.NewReviewFilter(bannedWords), clientSecret)
+		hReview := handler.NewReviewHandler(ucReview)
+		api.POST("/stations/:id/reviews", hReview.Submit)
+		api.GET("/stations/:id/reviews", hReview.List)
+
+		// Permalinks (検索条件の共有リンク)
+		ucPermalink := usecase.NewPermalinkUsecase(repository.NewPermalinkRepository(db), deps.stationCache, cfg.AppBaseURL)
+		hPermalink := handler.NewPermalinkHandler(ucPermalink)
+		api.POST("/permalinks", hPermalink.Create)
+		api.GET("/permalinks/:code", hPermalink.Resolve)
+
+		// POI (駅周辺の施設・地図レイヤー)
+		ucPOI := usecase.NewPOIUsecase(repoStation, repoPOI, walk)
+		hPOI := handler.NewPOIHandler(ucPOI)
+		api.GET("/stations/:id/pois", hPOI.GetStationPOIs)
+
+		// Affiliate (外部サイトへの送客)
+		linkBuilder, err := service.NewAffiliateLinkBuilder(cfg.AffiliateParams)
+		if err != nil {
+			log.Fatalf("Invalid affiliate config: %v", err)
+		}
+		repoAffiliate := repository.NewAffiliateRepository(db)
+		ucAffiliate := usecase.NewAffiliateUsecase(repoStation, repoAffiliate, linkBuilder)
+		hAffiliate := handler.NewAffiliateHandler(ucAffiliate)
+		api.GET("/stations/:id/affiliate/:source", hAffiliate.Redirect)
+
+		// Users (マジックリンクでログインし、お気に入り・候補リストを保存する)
+		repoUser := repository.NewUserRepository(db)
+		mailer := mail.NewSender(cfg.MailSender, cfg.SMTPAddr, cfg.SMTPUsername, cfg.SMTPPassword, cfg.MailFrom)
+		ucAuth := usecase.NewAuthUsecase(repoUser, mailer, usecase.AuthOptions{
+			BaseURL:      cfg.AppBaseURL,
+			MagicLinkTTL: cfg.MagicLinkTTL,
+			SessionTTL:   cfg.SessionTTL,
+			ClientSecret: clientSecret,
+		})
+		hAuth := handler.NewAuthHandler(ucAuth)
+		api.POST("/auth/magic-link", hAuth.RequestMagicLink)
+		api.POST("/auth/verify", hAuth.VerifyMagicLink)
+		api.POST("/auth/logout", hAuth.Logout)
+
+		repoShortlist := repository.NewShortlistRepository(db)
+		ucShortlist := usecase.NewShortlistUsecase(repoShortlist, repoStation, repoStationScore, svcScoring)
+		hShortlist := handler.NewShortlistHandler(ucShortlist)
+		me := api.Group("/me", handler.RequireUser(ucAuth))
+		me.GET("", hAuth.Me)
+		me.GET("/favorites", hShortlist.ListFavorites)
+		me.PUT("/favorites/:station_id", hShortlist.SaveFavorite)
+		me.DELETE("/favorites/:station_id", hShortlist.DeleteFavorite)
+		me.GET("/shortlists", hShortlist.ListShortlists)
+		me.POST("/shortlists", hShortlist.CreateShortlist)
+		me.GET("/shortlists/:id", hShortlist.GetShortlist)
+		me.PATCH("/shortlists/:id", hShortlist.RenameShortlist)
+		me.DELETE("/shortlists/:id", hShortlist.DeleteShortlist)
+		me.PUT("/shortlists/:id/stations/:station_id", hShortlist.SaveShortlistItem)
+		me.DELETE("/shortlists/:id/stations/:station_id", hShortlist.DeleteShortlistItem)
+
+		// Saved searches (結果の変化は cmd/alerts が通知する)
+		ucSavedSearch := usecase.NewSavedSearchUsecase(repository.NewSavedSearchRepository(db))
+		hSavedSearch := handler.NewSavedSearchHandler(ucSavedSearch)
+		me.GET("/saved-searches", hSavedSearch.List)
+		me.POST("/saved-searches", hSavedSearch.Create)
+		me.GET("/saved-searches/:id", hSavedSearch.Get)
+		me.DELETE("/saved-searches/:id", hSavedSearch.Delete)
+
+		// Admin (口コミの審査)
+		admin := api.Group("/admin", handler.RequireUser(ucAuth), handler.RequireAdmin(cfg.AdminEmails))
+		admin.GET("/reviews", hReview.Queue)
+		admin.PATCH("/reviews/:id", hReview.Moderate)
+
+		// Vector tiles
+		repoStationTile := repository.NewStationTileRepository(db)
+		ucTile := usecase.NewTileUsecase(repoStationTile, repoStationScore, svcScoring)
+		deps.tileCache = usecase.NewCachedTileUsecase(ucTile, cfg.CacheTTL, cfg.CacheMaxEntries)
+		hTile := handler.NewTileHandler(deps.tileCache)
+		api.GET("/tiles/stations/:z/:x/:y", hTile.GetStationTile) // :y は "123.mvt"
+
+		// Grid heatmap
+		repoGrid := repository.NewGridRepository(db)
+		ucGrid := usecase.NewGridUsecase(repoGrid)
+		hGrid := handler.NewGridHandler(ucGrid)
+		api.GET("/grid", hGrid.GetHeatmap)
+
+		// Isochrones (通勤時間圏)
+		repoIsochrone := repository.NewIsochroneRepository(db)
+		ucIsochrone := usecase.NewIsochroneUsecase(transitGraphs, repoIsochrone)
+		deps.isoCache = usecase.NewCachedIsochroneUsecase(ucIsochrone, cfg.CacheTTL, cfg.CacheMaxEntries)
+		hIsochrone := handler.NewIsochroneHandler(deps.isoCache)
+		api.GET("/isochrones", hIsochrone.GetIsochrones)
+
+		// Metrics
+		hMetrics := handler.NewMetricsHandler(map[string]handler.CacheStatsProvider{
+			"stations":   deps.stationCache,
+			"tiles":      deps.tileCache,
+			"isochrones": deps.isoCache,
+		})
+		api.GET("/metrics/cache", hMetrics.CacheStats)
+	}
+
+	return deps
+}
+
+func randomSecret() string {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		log.Fatal(err)
+	}
+	return hex.EncodeToString(b)
+}
+
+// loadWalkRouter はOSMのPBF抽出ファイルから徒歩ネットワークを読み込む (ファイルの指定がなければ nil)
+func loadWalkRouter(file string) domain.WalkRouter {
+	if file == "" {
+		return nil
+	}
+	started := time.Now()
+	open := func() (io.ReadCloser, error) { return os.Open(file) }
+	net, stats, err := osm.ScanWalkNetwork(context.Background(), open, runtime.NumCPU())
+	if err != nil {
+		log.Fatalf("Failed to load walk network: %v", err)
+	}
+	log.Printf("Loaded walk network from %s in %s: %d ways, %d nodes, %d edges (%d nodes missing)",
+		file, time.Since(started).Round(time.Millisecond), stats.Ways, stats.Nodes, stats.Edges, stats.MissingNodes)
+	return service.NewWalkGraph(net)
+}
@@ -0,0 +1,41 @@
+package main
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/gigaptera/hikkoshi-lens/backend/internal/config"
+	"github.com/gigaptera/hikkoshi-lens/backend/internal/interface/openapi"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/uptrace/bun"
+)
+
+// TestRoutesDocumentedInSpec はregisterRoutesで登録した /api 配下のルートが
+// すべてopenapi.jsonに定義されていることを検証する
+func TestRoutesDocumentedInSpec(t *testing.T) {
+	spec, err := openapi.Load()
+	require.NoError(t, err)
+
+	e := echo.New()
+	registerRoutes(e, &bun.DB{}, &config.Config{}, spec)
+
+	checked := 0
+	for _, r := range e.Routes() {
+		if !strings.HasPrefix(r.Path, "/api/") || strings.HasSuffix(r.Path, "*") {
+			continue
+		}
+		switch r.Method {
+		case http.MethodGet, http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete:
+		default:
+			continue // グループミドルウェア用のRouteNotFoundなど
+		}
+
+		assert.NotNil(t, spec.Operation(r.Method, r.Path),
+			"%s %s is registered but missing from openapi.json (%s)", r.Method, r.Path, spec.PathFromRoute(r.Path))
+		checked++
+	}
+	assert.Greater(t, checked, 0)
+}
@@ -7,11 +7,14 @@ import (
 	"log"
 	"net/http"
 	"os"
+	"regexp"
 	"strings"
 	"time"
 
 	"github.com/PuerkitoBio/goquery"
 	"github.com/gigaptera/hikkoshi-lens/backend/internal/domain"
+	"github.com/gigaptera/hikkoshi-lens/backend/internal/infrastructure"
+	"github.com/gigaptera/hikkoshi-lens/backend/internal/infrastructure/repository"
 	"github.com/uptrace/bun"
 	"github.com/uptrace/bun/dialect/pgdialect"
 	"github.com/uptrace/bun/driver/pgdriver"
@@ -58,19 +61,29 @@ func main() {
 	// Note: Names might strictly match "新宿" vs "新宿駅".
 	// SUUMO usually uses "新宿" in lists but title might be "新宿駅".
 	stationMap := make(map[string]domain.Station)
+	// SUUMOの駅ページは路線をまたいだ駅単位なので、外部IDは同名の全駅 (路線ごとの行) に保存する
+	idsByName := make(map[string][]int64)
 	for _, s := range stations {
 		// Normalize: Remove "駅" suffix if present (though internal naming is inconsistent, let's assume we handle both)
 		name := strings.TrimSuffix(s.Name, "駅")
 		stationMap[name] = s
+		idsByName[name] = append(idsByName[name], s.ID)
 	}
+	ext := &externalIDs{repo: repository.NewAffiliateRepository(db), idsByName: idsByName, saved: make(map[string]bool)}
 
 	// 2. Start Crawling
 	// Entry point: Tokyo Lines
 	startURL := "https://suumo.jp/chintai/soba/tokyo/ensen/"
-	crawlLines(ctx, db, startURL, stationMap)
+	crawlLines(ctx, db, startURL, stationMap, ext)
+	log.Printf("Saved SUUMO station codes for %d stations", len(ext.saved))
+
+	// APIサーバーのキャッシュを破棄させる
+	if err := infrastructure.NotifyDataUpdated(ctx, db, "market_prices"); err != nil {
+		log.Printf("Warning: failed to notify data update: %v", err)
+	}
 }
 
-func crawlLines(ctx context.Context, db *bun.DB, url string, stationMap map[string]domain.Station) {
+func crawlLines(ctx context.Context, db *bun.DB, url string, stationMap map[string]domain.Station, ext *externalIDs) {
 	doc := fetch(url)
 	if doc == nil {
 		return
@@ -89,12 +102,12 @@ func crawlLines(ctx context.Context, db *bun.DB, url string, stationMap map[stri
 		if strings.Contains(href, "/chintai/soba/tokyo/en_") {
 			fullURL := BaseURL + href
 			log.Printf("Found Line: %s", fullURL)
-			crawlStations(ctx, db, fullURL, stationMap)
+			crawlStations(ctx, db, fullURL, stationMap, ext)
 		}
 	})
 }
 
-func crawlStations(ctx context.Context, db *bun.DB, url string, stationMap map[string]domain.Station) {
+func crawlStations(ctx context.Context, db *bun.DB, url string, stationMap map[string]domain.Station, ext *externalIDs) {
 	doc := fetch(url)
 	if doc == nil {
 		return
@@ -115,6 +128,7 @@ func crawlStations(ctx context.Context, db *bun.DB, url string, stationMap map[s
 
 			// Check if we have this station in DB
 			if st, ok := stationMap[stationName]; ok {
+				ext.save(ctx, stationName, href)
 				// Crawl this station
 				crawlMarketPrice(ctx, db, BaseURL+href, st)
 			}
@@ -186,6 +200,42 @@ func crawlMarketPrice(ctx context.Context, db *bun.DB, baseURL string, station d
 	}
 }
 
+// suumoStationPath は駅ページのURLから地域と駅コードを取り出す
+// 例: /chintai/soba/tokyo/ek_20110/?ts=1 -> tokyo, 20110
+var suumoStationPath = regexp.MustCompile(`/chintai/(?:soba/)?([a-z]+)/ek_([0-9]+)/`)
+
+// externalIDs はクロール中に見つけたSUUMOの駅コードを station_external_ids に保存する
+type externalIDs struct {
+	repo      domain.AffiliateRepository
+	idsByName map[string][]int64
+	saved     map[string]bool // 駅名ごとに1回だけ保存する
+}
+
+func (e *externalIDs) save(ctx context.Context, stationName, href string) {
+	if e.saved[stationName] {
+		return
+	}
+	m := suumoStationPath.FindStringSubmatch(href)
+	if m == nil {
+		return
+	}
+
+	var ids []*domain.StationExternalID
+	for _, id := range e.idsByName[stationName] {
+		ids = append(ids, &domain.StationExternalID{
+			StationID:  id,
+			Source:     domain.AffiliateSourceSuumo,
+			Area:       m[1],
+			ExternalID: m[2],
+		})
+	}
+	if err := e.repo.UpsertExternalIDs(ctx, ids); err != nil {
+		log.Printf("Error saving SUUMO code for %s: %v", stationName, err)
+		return
+	}
+	e.saved[stationName] = true
+}
+
 func parseAndSave(ctx context.Context, db *bun.DB, stationID int64, buildingType, layoutRaw, priceRaw string) {
 	// Normalize Layout
 	layout := normalizeLayout(layoutRaw)
@@ -10,6 +10,7 @@ import (
 	"log"
 	"net/http"
 	"os"
+	"strconv"
 	"time"
 
 	"github.com/joho/godotenv"
@@ -18,6 +19,9 @@ import (
 	"github.com/uptrace/bun/driver/pgdriver"
 )
 
+// 少数の駅をOverpass APIで確認するためのツール
+// 全駅の facilities は cmd/import/osm_pois (PBFからの一括取り込み) で更新する
+
 // DB Models
 type Station struct {
 	bun.BaseModel `bun:"table:stations,alias:s"`
@@ -94,6 +98,7 @@ func main() {
 	fmt.Printf("Fetching facility data for %d stations (sample)\\n", len(stationsData))
 
 	client := &http.Client{Timeout: 30 * time.Second}
+	var failed []int64
 
 	for i, station := range stationsData {
 		fmt.Printf("[%d/%d] Processing %s...\\n", i+1, len(stationsData), station.Name)
@@ -103,26 +108,34 @@ func main() {
 		}
 
 		// Query Overpass API for each amenity type
-		facilities.SupermarketsCount = queryOverpass(client, station.Lat, station.Lon, `shop=supermarket`)
-		time.Sleep(1 * time.Second) // Rate limiting
-
-		facilities.ConvenienceStoresCount = queryOverpass(client, station.Lat, station.Lon, `shop=convenience`)
-		time.Sleep(1 * time.Second)
-
-		facilities.HospitalsCount = queryOverpass(client, station.Lat, station.Lon, `amenity=hospital`)
-		time.Sleep(1 * time.Second)
-
-		facilities.DrugstoresCount = queryOverpass(client, station.Lat, station.Lon, `shop=chemist`)
-		time.Sleep(1 * time.Second)
-
-		facilities.RestaurantsCount = queryOverpass(client, station.Lat, station.Lon, `amenity=restaurant`)
-		time.Sleep(1 * time.Second)
-
-		facilities.GymsCount = queryOverpass(client, station.Lat, station.Lon, `leisure=fitness_centre`)
-		time.Sleep(1 * time.Second)
-
-		facilities.ParksCount = queryOverpass(client, station.Lat, station.Lon, `leisure=park`)
-		time.Sleep(1 * time.Second)
+		// 1件でも取得に失敗した駅は、0件と区別するため書き込まない
+		queries := []struct {
+			filter string
+			dest   *int
+		}{
+			{`shop=supermarket`, &facilities.SupermarketsCount},
+			{`shop=convenience`, &facilities.ConvenienceStoresCount},
+			{`amenity=hospital`, &facilities.HospitalsCount},
+			{`shop=chemist`, &facilities.DrugstoresCount},
+			{`amenity=restaurant`, &facilities.RestaurantsCount},
+			{`leisure=fitness_centre`, &facilities.GymsCount},
+			{`leisure=park`, &facilities.ParksCount},
+		}
+		var queryErr error
+		for _, q := range queries {
+			count, err := queryOverpass(client, station.Lat, station.Lon, q.filter)
+			time.Sleep(1 * time.Second) // Rate limiting
+			if err != nil {
+				queryErr = fmt.Errorf("%s: %w", q.filter, err)
+				break
+			}
+			*q.dest = count
+		}
+		if queryErr != nil {
+			log.Printf("Failed to fetch facilities for station %d (%s): %v", station.ID, station.Name, queryErr)
+			failed = append(failed, station.ID)
+			continue
+		}
 
 		// Insert into DB
 		_, err := db.NewInsert().Model(&facilities).
@@ -151,10 +164,16 @@ func main() {
 		)
 	}
 
+	if len(failed) > 0 {
+		log.Printf("Facility data fetch completed with %d failed stations: %v", len(failed), failed)
+		return
+	}
 	fmt.Println("Facility data fetch completed!")
 }
 
-func queryOverpass(client *http.Client, lat, lon float64, filter string) int {
+// queryOverpass は半径800m以内の該当施設数を返す
+// "out count" の応答は件数を tags.total に持つ要素1件だけなので、要素数ではなくその値を読む
+func queryOverpass(client *http.Client, lat, lon float64, filter string) (int, error) {
 	// Overpass QL query: find POIs within 800m radius
 	query := fmt.Sprintf(`
 [out:json][timeout:25];
@@ -167,29 +186,35 @@ out count;
 
 	req, err := http.NewRequest("POST", "https://overpass-api.de/api/interpreter", bytes.NewBufferString(query))
 	if err != nil {
-		log.Printf("Failed to create request: %v", err)
-		return 0
+		return 0, fmt.Errorf("create request: %w", err)
 	}
 	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
 
 	resp, err := client.Do(req)
 	if err != nil {
-		log.Printf("Failed to query Overpass API: %v", err)
-		return 0
+		return 0, fmt.Errorf("query Overpass API: %w", err)
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != 200 {
 		body, _ := io.ReadAll(resp.Body)
-		log.Printf("Overpass API error %d: %s", resp.StatusCode, string(body))
-		return 0
+		return 0, fmt.Errorf("Overpass API error %d: %s", resp.StatusCode, string(body))
 	}
 
 	var result OverpassResponse
 	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
-		log.Printf("Failed to decode response: %v", err)
-		return 0
+		return 0, fmt.Errorf("decode response: %w", err)
 	}
 
-	return len(result.Elements)
+	for _, el := range result.Elements {
+		if el.Type != "count" {
+			continue
+		}
+		total, ok := el.Tags["total"].(string)
+		if !ok {
+			return 0, fmt.Errorf("count element has no total: %v", el.Tags)
+		}
+		return strconv.Atoi(total)
+	}
+	return 0, fmt.Errorf("response has no count element")
 }
@@ -0,0 +1,174 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"time"
+
+	"github.com/gigaptera/hikkoshi-lens/backend/internal/config"
+	"github.com/gigaptera/hikkoshi-lens/backend/internal/domain"
+	"github.com/gigaptera/hikkoshi-lens/backend/internal/domain/service"
+	"github.com/gigaptera/hikkoshi-lens/backend/internal/infrastructure"
+	"github.com/gigaptera/hikkoshi-lens/backend/internal/infrastructure/repository"
+	"github.com/uptrace/bun"
+)
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "Usage: grid <command> [flags]")
+	fmt.Fprintln(os.Stderr, "")
+	fmt.Fprintln(os.Stderr, "Commands:")
+	fmt.Fprintln(os.Stderr, "  cells     駅の周辺を六角形・正方形のセルに分割して grid_cells を作成する")
+	fmt.Fprintln(os.Stderr, "  metrics   セルごとの指標 (施設密度・災害リスク・治安・家賃) を再計算する")
+}
+
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+		os.Exit(2)
+	}
+
+	switch os.Args[1] {
+	case "cells":
+		cells(os.Args[2:])
+	case "metrics":
+		metrics(os.Args[2:])
+	default:
+		usage()
+		os.Exit(2)
+	}
+}
+
+func openDB() *bun.DB {
+	cfg, err := config.Load()
+	if err != nil {
+		log.Fatal(err)
+	}
+	if cfg.DatabaseURL == "" {
+		log.Fatal("DATABASE_URL is required")
+	}
+	return infrastructure.NewDB(cfg.DatabaseURL)
+}
+
+func cells(args []string) {
+	fs := flag.NewFlagSet("cells", flag.ExitOnError)
+	shape := fs.String("shape", domain.GridShapeHex, "セルの形 (hex, square)")
+	size := fs.Int("size", 1000, "セルの大きさ (m)")
+	radius := fs.Int("radius", 3000, "駅からこの距離 (m) 以内のセルだけを作成する")
+	fs.Parse(args)
+
+	spec, err := domain.ParseGridSpec(fmt.Sprintf("%s_%d", *shape, *size))
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	db := openDB()
+	defer db.Close()
+
+	created, err := repository.NewGridRepository(db).CreateCells(context.Background(), spec, *radius)
+	if err != nil {
+		log.Fatalf("Failed to create cells: %v", err)
+	}
+	log.Printf("Created %d cells for grid %s", created, spec.Name())
+}
+
+func metrics(args []string) {
+	fs := flag.NewFlagSet("metrics", flag.ExitOnError)
+	grid := fs.String("grid", domain.DefaultGrid, "対象のグリッド")
+	version := fs.String("version", time.Now().Format("20060102150405"), "データバージョン")
+	maxDist := fs.Float64("max-distance", 5000, "補間に使う駅の最大距離 (m)")
+	neighbors := fs.Int("neighbors", 8, "補間に使う駅の最大数")
+	power := fs.Float64("power", 2, "逆距離加重の指数")
+	batchSize := fs.Int("batch", 5000, "書き込みバッチサイズ")
+	fs.Parse(args)
+
+	if _, err := domain.ParseGridSpec(*grid); err != nil {
+		log.Fatal(err)
+	}
+
+	db := openDB()
+	defer db.Close()
+
+	ctx := context.Background()
+	repoGrid := repository.NewGridRepository(db)
+
+	// 1. 施設・街灯の密度と最寄りの交番までの距離はPOIからSQLで計算する
+	density, distance := domain.GridPOIDensityMetrics(), domain.GridPOIDistanceMetrics()
+	for metric, categories := range density {
+		n, err := repoGrid.RefreshPOIDensity(ctx, *grid, metric, categories, *version)
+		if err != nil {
+			log.Fatalf("Failed to refresh %s: %v", metric, err)
+		}
+		log.Printf("Refreshed %s for %d cells", metric, n)
+	}
+	for metric, categories := range distance {
+		n, err := repoGrid.RefreshPOIDistance(ctx, *grid, metric, categories, *version)
+		if err != nil {
+			log.Fatalf("Failed to refresh %s: %v", metric, err)
+		}
+		log.Printf("Refreshed %s for %d cells", metric, n)
+	}
+
+	// 2. 駅ごとの値をセルの重心に補間する
+	cells, err := repoGrid.ListCells(ctx, *grid)
+	if err != nil {
+		log.Fatalf("Failed to load cells: %v", err)
+	}
+	if len(cells) == 0 {
+		log.Fatalf("Grid %s has no cells, run `grid cells` first", *grid)
+	}
+	samples, err := repoGrid.StationSamples(ctx)
+	if err != nil {
+		log.Fatalf("Failed to load station samples: %v", err)
+	}
+	log.Printf("Interpolating %d cells from %d stations", len(cells), len(samples))
+
+	var written int
+	for _, metric := range domain.GridMetrics() {
+		if density[metric] != nil || distance[metric] != nil {
+			continue
+		}
+
+		var points []service.IDWPoint
+		for _, s := range samples {
+			if v, ok := s.Values[metric]; ok {
+				points = append(points, service.IDWPoint{Lat: s.Lat, Lon: s.Lon, Value: v})
+			}
+		}
+		if len(points) == 0 {
+			log.Printf("Skipping %s: no station has data", metric)
+			continue
+		}
+
+		it := service.NewIDWInterpolator(points, *power, *maxDist, *neighbors)
+		var batch []*domain.GridCellMetric
+		var skipped int
+		for _, c := range cells {
+			v, ok := it.Interpolate(c.Lat, c.Lon)
+			if !ok {
+				skipped++ // 近くにデータのある駅がないセルは値を持たない
+				continue
+			}
+			batch = append(batch, &domain.GridCellMetric{CellID: c.ID, Metric: metric, Value: v, DataVersion: *version})
+			if len(batch) >= *batchSize {
+				if err := repoGrid.UpsertMetrics(ctx, batch); err != nil {
+					log.Fatalf("Failed to upsert %s: %v", metric, err)
+				}
+				written += len(batch)
+				batch = batch[:0]
+			}
+		}
+		if err := repoGrid.UpsertMetrics(ctx, batch); err != nil {
+			log.Fatalf("Failed to upsert %s: %v", metric, err)
+		}
+		written += len(batch)
+		log.Printf("Interpolated %s from %d stations (%d cells without nearby data)", metric, len(points), skipped)
+	}
+
+	if err := infrastructure.NotifyDataUpdated(ctx, db, "grid_cell_metrics"); err != nil {
+		log.Printf("Warning: failed to notify data update: %v", err)
+	}
+	log.Printf("Metrics completed: %d interpolated values (version=%s)", written, *version)
+}
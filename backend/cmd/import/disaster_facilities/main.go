@@ -0,0 +1,130 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"io"
+	"log"
+	"os"
+	"time"
+
+	"github.com/gigaptera/hikkoshi-lens/backend/internal/config"
+	"github.com/gigaptera/hikkoshi-lens/backend/internal/domain"
+	"github.com/gigaptera/hikkoshi-lens/backend/internal/infrastructure"
+	"github.com/gigaptera/hikkoshi-lens/backend/internal/infrastructure/gsi"
+	"github.com/gigaptera/hikkoshi-lens/backend/internal/infrastructure/mlit"
+	"github.com/gigaptera/hikkoshi-lens/backend/internal/infrastructure/repository"
+)
+
+// 避難場所と救急病院を pois に取り込み、全駅の最寄りまでの距離 (station_disaster_access) を集計し直す
+//
+//	go run ./cmd/import/disaster_facilities -shelters mergeFromCity_1.csv -hospitals P04-20.geojson
+//
+// -shelters は国土地理院「指定緊急避難場所データ」の CSV (UTF-8)、
+// -hospitals は国土数値情報「医療機関」(P04) の GeoJSON で、救急告示病院・災害拠点病院だけを取り込む
+// 指定したデータだけを入れ替え (ファイルに含まれなくなった施設は削除)、省略したデータはそのまま残す
+// disaster 軸は距離を使うため、取り込み後に cmd/scores recompute を実行する
+func main() {
+	shelters := flag.String("shelters", "", "指定緊急避難場所データの CSV ファイルのパス")
+	hospitals := flag.String("hospitals", "", "医療機関 (P04) の GeoJSON ファイルのパス")
+	emergencyCol := flag.String("emergency-col", mlit.DefaultP04Layout().EmergencyColumn, "P04 の救急告示病院の属性名 (配布年版で異なる)")
+	disasterBaseCol := flag.String("disaster-base-col", mlit.DefaultP04Layout().DisasterBaseColumn, "P04 の災害拠点病院の属性名")
+	batchSize := flag.Int("batch", 2000, "書き込みバッチサイズ")
+	flag.Parse()
+
+	if *shelters == "" && *hospitals == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		log.Fatal(err)
+	}
+	if cfg.DatabaseURL == "" {
+		log.Fatal("DATABASE_URL is required")
+	}
+
+	// ファイルを先に読み、形式の誤りでDBを中途半端に更新しないようにする
+	type dataset struct {
+		source string
+		pois   []*domain.POI
+	}
+	var datasets []dataset
+	if *shelters != "" {
+		pois, skipped, err := readFile(*shelters, gsi.ReadShelterCSV)
+		if err != nil {
+			log.Fatalf("Failed to read %s: %v", *shelters, err)
+		}
+		log.Printf("Read %d shelters (%d without ID or coordinates)", len(pois), skipped)
+		datasets = append(datasets, dataset{source: gsi.ShelterSource, pois: pois})
+	}
+	if *hospitals != "" {
+		layout := mlit.P04Layout{EmergencyColumn: *emergencyCol, DisasterBaseColumn: *disasterBaseCol}
+		pois, skipped, err := readFile(*hospitals, func(r io.Reader) ([]*domain.POI, int, error) {
+			return mlit.ReadP04GeoJSON(r, layout)
+		})
+		if err != nil {
+			log.Fatalf("Failed to read %s: %v", *hospitals, err)
+		}
+		log.Printf("Read %d emergency hospitals (%d other medical institutions or without coordinates)", len(pois), skipped)
+		datasets = append(datasets, dataset{source: mlit.HospitalSource, pois: pois})
+	}
+	for _, ds := range datasets {
+		if len(ds.pois) == 0 {
+			// 空のファイルで既存の施設を全て消さないようにする
+			log.Fatalf("No facilities to import for %s", ds.source)
+		}
+	}
+
+	ctx := context.Background()
+	db := infrastructure.NewDB(cfg.DatabaseURL)
+	defer db.Close()
+
+	repoPOI := repository.NewPOIRepository(db)
+	repoAccess := repository.NewDisasterAccessRepository(db)
+
+	for _, ds := range datasets {
+		// 取り込み開始時刻 (DB時刻)。これより古い施設は今回のファイルに存在しないものとして削除する
+		var startedAt time.Time
+		if err := db.NewRaw("SELECT CURRENT_TIMESTAMP").Scan(ctx, &startedAt); err != nil {
+			log.Fatalf("Failed to get database time: %v", err)
+		}
+		for i := 0; i < len(ds.pois); i += *batchSize {
+			end := i + *batchSize
+			if end > len(ds.pois) {
+				end = len(ds.pois)
+			}
+			if err := repoPOI.Upsert(ctx, ds.pois[i:end]); err != nil {
+				log.Fatalf("Failed to upsert %s batch %d-%d: %v", ds.source, i, end, err)
+			}
+		}
+		deleted, err := repoPOI.DeleteStale(ctx, ds.source, startedAt)
+		if err != nil {
+			log.Fatalf("Failed to delete stale %s POIs: %v", ds.source, err)
+		}
+		log.Printf("Imported %d POIs from %s (%d stale deleted)", len(ds.pois), ds.source, deleted)
+	}
+
+	updated, err := repoAccess.Refresh(ctx)
+	if err != nil {
+		log.Fatalf("Failed to refresh disaster access: %v", err)
+	}
+	log.Printf("Refreshed nearest shelter / emergency hospital distances for %d stations", updated)
+
+	// APIサーバーのキャッシュを破棄させる
+	for _, table := range []string{"pois", "station_disaster_access"} {
+		if err := infrastructure.NotifyDataUpdated(ctx, db, table); err != nil {
+			log.Printf("Warning: failed to notify data update: %v", err)
+		}
+	}
+}
+
+func readFile(path string, read func(r io.Reader) ([]*domain.POI, int, error)) ([]*domain.POI, int, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer f.Close()
+	return read(f)
+}
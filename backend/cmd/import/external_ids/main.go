@@ -0,0 +1,100 @@
+package main
+
+import (
+	"context"
+	"encoding/csv"
+	"flag"
+	"io"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/gigaptera/hikkoshi-lens/backend/internal/config"
+	"github.com/gigaptera/hikkoshi-lens/backend/internal/domain"
+	"github.com/gigaptera/hikkoshi-lens/backend/internal/infrastructure"
+	"github.com/gigaptera/hikkoshi-lens/backend/internal/infrastructure/repository"
+)
+
+// 外部サイトの駅ID (HOME'S の駅コードなど) をCSVから station_external_ids に取り込む
+// SUUMOの駅コードはクローラー (cmd/crawler) が保存するため、クロールしないサイトの分を補う
+//
+//	go run ./cmd/import/external_ids -file homes_stations.csv
+//
+// CSVはヘッダー付きで station_code,source,area,external_id の4列
+// (例: 1130208,homes,tokyo,shinjuku_00580-st)
+func main() {
+	file := flag.String("file", "", "CSVファイルのパス (必須)")
+	flag.Parse()
+
+	if *file == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		log.Fatal(err)
+	}
+	if cfg.DatabaseURL == "" {
+		log.Fatal("DATABASE_URL is required")
+	}
+
+	db := infrastructure.NewDB(cfg.DatabaseURL)
+	defer db.Close()
+
+	ctx := context.Background()
+
+	// 駅コード -> 駅ID
+	var stations []domain.Station
+	if err := db.NewSelect().Model(&stations).Column("id", "station_code").Scan(ctx); err != nil {
+		log.Fatalf("Failed to load stations: %v", err)
+	}
+	idByCode := make(map[string]int64, len(stations))
+	for _, s := range stations {
+		idByCode[s.StationCode] = s.ID
+	}
+
+	f, err := os.Open(*file)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer f.Close()
+
+	r := csv.NewReader(f)
+	if _, err := r.Read(); err != nil { // ヘッダー
+		log.Fatalf("Failed to read header: %v", err)
+	}
+
+	var ids []*domain.StationExternalID
+	var unknownStation, invalid int
+	for line := 2; ; line++ {
+		rec, err := r.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			log.Fatalf("Line %d: %v", line, err)
+		}
+		if len(rec) < 4 {
+			invalid++
+			continue
+		}
+		code, source, area, externalID := strings.TrimSpace(rec[0]), strings.TrimSpace(rec[1]), strings.TrimSpace(rec[2]), strings.TrimSpace(rec[3])
+		if !domain.IsAffiliateSource(source) || area == "" || externalID == "" {
+			log.Printf("Line %d: skipping invalid row %v", line, rec)
+			invalid++
+			continue
+		}
+		id, ok := idByCode[code]
+		if !ok {
+			unknownStation++
+			continue
+		}
+		ids = append(ids, &domain.StationExternalID{StationID: id, Source: source, Area: area, ExternalID: externalID})
+	}
+
+	if err := repository.NewAffiliateRepository(db).UpsertExternalIDs(ctx, ids); err != nil {
+		log.Fatalf("Failed to save external IDs: %v", err)
+	}
+	log.Printf("Imported %d external IDs (%d unknown stations, %d invalid rows)", len(ids), unknownStation, invalid)
+}
@@ -0,0 +1,128 @@
+package main
+
+import (
+	"context"
+	"encoding/csv"
+	"flag"
+	"io"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gigaptera/hikkoshi-lens/backend/internal/config"
+	"github.com/gigaptera/hikkoshi-lens/backend/internal/domain"
+	"github.com/gigaptera/hikkoshi-lens/backend/internal/domain/service"
+	"github.com/gigaptera/hikkoshi-lens/backend/internal/infrastructure"
+	"github.com/gigaptera/hikkoshi-lens/backend/internal/infrastructure/repository"
+)
+
+// 事業者ごとの距離帯別の運賃・定期代をCSVから fare_bands に取り込む
+// CSVに含まれる事業者の運賃表は丸ごと置き換え、含まれない事業者はそのまま残す
+//
+//	go run ./cmd/import/fares -file fares.csv
+//
+// CSVはヘッダー付きで organization_code,min_km,max_km,fare,pass_1m,pass_3m,pass_6m の7列 (金額は円)
+// 距離は営業キロ (1km未満切り上げ) の範囲で、max_km を含む。発売していない定期券は 0 か空欄
+// (例: 11302,1,3,150,4620,13170,24950)
+func main() {
+	file := flag.String("file", "", "CSVファイルのパス (必須)")
+	version := flag.String("version", time.Now().Format("20060102150405"), "データバージョン")
+	flag.Parse()
+
+	if *file == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		log.Fatal(err)
+	}
+	if cfg.DatabaseURL == "" {
+		log.Fatal("DATABASE_URL is required")
+	}
+
+	f, err := os.Open(*file)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer f.Close()
+
+	r := csv.NewReader(f)
+	if _, err := r.Read(); err != nil { // ヘッダー
+		log.Fatalf("Failed to read header: %v", err)
+	}
+
+	var bands []*domain.FareBand
+	var invalid int
+	for line := 2; ; line++ {
+		rec, err := r.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			log.Fatalf("Line %d: %v", line, err)
+		}
+		band, ok := parseBand(rec)
+		if !ok {
+			log.Printf("Line %d: skipping invalid row %v", line, rec)
+			invalid++
+			continue
+		}
+		band.DataVersion = *version
+		bands = append(bands, band)
+	}
+	if len(bands) == 0 {
+		log.Fatal("No fare bands to import")
+	}
+	// 距離帯の重なりなどは一部だけ取り込むと運賃がずれるため、全体を中止する
+	if err := service.ValidateFareBands(bands); err != nil {
+		log.Fatalf("Invalid fare table: %v", err)
+	}
+
+	ctx := context.Background()
+	db := infrastructure.NewDB(cfg.DatabaseURL)
+	defer db.Close()
+
+	if err := repository.NewFareRepository(db).ReplaceOperators(ctx, bands); err != nil {
+		log.Fatalf("Failed to save fare bands: %v", err)
+	}
+	if err := infrastructure.NotifyDataUpdated(ctx, db, "fare_bands"); err != nil {
+		log.Printf("Warning: failed to notify data update: %v", err)
+	}
+	log.Printf("Imported %d fare bands (%d invalid rows, version=%s)", len(bands), invalid, *version)
+}
+
+// parseBand は1行を運賃帯に変換する。定期代の空欄は 0 (発売なし)
+func parseBand(rec []string) (*domain.FareBand, bool) {
+	if len(rec) < 7 {
+		return nil, false
+	}
+	org := strings.TrimSpace(rec[0])
+	if org == "" {
+		return nil, false
+	}
+	var nums [6]int
+	for i := range nums {
+		s := strings.TrimSpace(rec[i+1])
+		if s == "" && i >= 3 {
+			continue
+		}
+		v, err := strconv.Atoi(s)
+		if err != nil {
+			return nil, false
+		}
+		nums[i] = v
+	}
+	return &domain.FareBand{
+		OrganizationCode: org,
+		MinKm:            nums[0],
+		MaxKm:            nums[1],
+		Fare:             nums[2],
+		Pass1M:           nums[3],
+		Pass3M:           nums[4],
+		Pass6M:           nums[5],
+	}, true
+}
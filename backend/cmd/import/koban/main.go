@@ -0,0 +1,113 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"log"
+	"os"
+	"time"
+
+	"github.com/gigaptera/hikkoshi-lens/backend/internal/config"
+	"github.com/gigaptera/hikkoshi-lens/backend/internal/domain"
+	"github.com/gigaptera/hikkoshi-lens/backend/internal/domain/service"
+	"github.com/gigaptera/hikkoshi-lens/backend/internal/infrastructure"
+	"github.com/gigaptera/hikkoshi-lens/backend/internal/infrastructure/opendata"
+	"github.com/gigaptera/hikkoshi-lens/backend/internal/infrastructure/repository"
+	"github.com/gigaptera/hikkoshi-lens/backend/internal/usecase"
+)
+
+// 都道府県警察・自治体が公開する交番・駐在所の一覧 (CSV) を pois に取り込み、
+// 全駅の夜間の安全性 (最寄りの交番までの距離・街灯の密度) と night_safety・safety 軸のスコアを集計し直す
+//
+//	go run ./cmd/import/koban -file koban_tokyo.csv
+//
+// 公開元ごとに列名が異なるため -name-col などで指定する。ファイルに含まれなくなった交番は削除する
+// (複数の都道府県を取り込む場合は、結合した1つのファイルにする)
+// safety 軸は交番までの距離を使うため、night_safety 軸と合わせてこのコマンドで計算し直す
+func main() {
+	file := flag.String("file", "", "交番・駐在所一覧の CSV ファイルのパス (必須、UTF-8)")
+	layout := opendata.DefaultKobanLayout()
+	flag.StringVar(&layout.IDColumn, "id-col", layout.IDColumn, "IDの列名 (空の場合は名称と住所から生成)")
+	flag.StringVar(&layout.NameColumn, "name-col", layout.NameColumn, "名称の列名")
+	flag.StringVar(&layout.AddressColumn, "address-col", layout.AddressColumn, "住所の列名")
+	flag.StringVar(&layout.LatColumn, "lat-col", layout.LatColumn, "緯度の列名")
+	flag.StringVar(&layout.LonColumn, "lon-col", layout.LonColumn, "経度の列名")
+	lampRadius := flag.Int("lamp-radius", domain.DefaultStreetLampRadiusMeter, "街灯の密度の集計半径 (m)")
+	batchSize := flag.Int("batch", 2000, "書き込みバッチサイズ")
+	version := flag.String("version", time.Now().Format("20060102150405"), "night_safety・safety 軸のデータバージョン")
+	flag.Parse()
+
+	if *file == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		log.Fatal(err)
+	}
+	if cfg.DatabaseURL == "" {
+		log.Fatal("DATABASE_URL is required")
+	}
+
+	// ファイルを先に読み、形式の誤りでDBを中途半端に更新しないようにする
+	f, err := os.Open(*file)
+	if err != nil {
+		log.Fatalf("Failed to open %s: %v", *file, err)
+	}
+	pois, skipped, err := opendata.ReadKobanCSV(f, layout)
+	f.Close()
+	if err != nil {
+		log.Fatalf("Failed to read %s: %v", *file, err)
+	}
+	log.Printf("Read %d police boxes (%d without name or coordinates)", len(pois), skipped)
+	if len(pois) == 0 {
+		// 空のファイルで既存の交番を全て消さないようにする
+		log.Fatalf("No police boxes to import")
+	}
+
+	ctx := context.Background()
+	db := infrastructure.NewDB(cfg.DatabaseURL)
+	defer db.Close()
+
+	repoPOI := repository.NewPOIRepository(db)
+	repoNight := repository.NewNightSafetyRepository(db)
+	repoScore := repository.NewStationScoreRepository(db)
+
+	// 取り込み開始時刻 (DB時刻)。これより古い交番は今回のファイルに存在しないものとして削除する
+	var startedAt time.Time
+	if err := db.NewRaw("SELECT CURRENT_TIMESTAMP").Scan(ctx, &startedAt); err != nil {
+		log.Fatalf("Failed to get database time: %v", err)
+	}
+	for i := 0; i < len(pois); i += *batchSize {
+		end := i + *batchSize
+		if end > len(pois) {
+			end = len(pois)
+		}
+		if err := repoPOI.Upsert(ctx, pois[i:end]); err != nil {
+			log.Fatalf("Failed to upsert batch %d-%d: %v", i, end, err)
+		}
+	}
+	deleted, err := repoPOI.DeleteStale(ctx, opendata.KobanSource, startedAt)
+	if err != nil {
+		log.Fatalf("Failed to delete stale POIs: %v", err)
+	}
+	log.Printf("Imported %d POIs from %s (%d stale deleted)", len(pois), opendata.KobanSource, deleted)
+
+	// night_safety 軸と、夜間の成分を含む safety 軸を同じ集計結果から更新する
+	ucNight := usecase.NewNightSafetyUsecase(repository.NewStationRepository(db), repoNight, repoScore, service.NewScoringService())
+	stats, err := ucNight.Refresh(ctx, usecase.NightSafetyOptions{RadiusMeter: *lampRadius, Version: *version, BatchSize: *batchSize})
+	if err != nil {
+		log.Fatalf("Failed to refresh night safety: %v", err)
+	}
+	log.Printf("Refreshed night safety for %d stations (lamp radius=%dm, %d night_safety scores, %d without data, %d safety scores, version=%s)",
+		stats.Stations, *lampRadius, stats.NightScores, stats.NoData, stats.SafetyScores, *version)
+
+	// APIサーバーのキャッシュを破棄させる
+	// (station_scores は NightSafetyUsecase が通知する)
+	for _, table := range []string{"pois", "station_night_safety"} {
+		if err := infrastructure.NotifyDataUpdated(ctx, db, table); err != nil {
+			log.Printf("Warning: failed to notify data update: %v", err)
+		}
+	}
+}
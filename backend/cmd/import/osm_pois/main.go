@@ -0,0 +1,128 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"io"
+	"log"
+	"os"
+	"runtime"
+	"time"
+
+	"github.com/gigaptera/hikkoshi-lens/backend/internal/config"
+	"github.com/gigaptera/hikkoshi-lens/backend/internal/domain"
+	"github.com/gigaptera/hikkoshi-lens/backend/internal/domain/service"
+	"github.com/gigaptera/hikkoshi-lens/backend/internal/infrastructure"
+	"github.com/gigaptera/hikkoshi-lens/backend/internal/infrastructure/osm"
+	"github.com/gigaptera/hikkoshi-lens/backend/internal/infrastructure/repository"
+	"github.com/gigaptera/hikkoshi-lens/backend/internal/usecase"
+)
+
+// OSMのPBF抽出ファイル (例: Geofabrik の japan-latest.osm.pbf) から施設を pois に取り込み、
+// 全駅の facilities と夜間の安全性 (街灯の密度・最寄りの交番)、night_safety・safety 軸のスコアを空間結合で再集計する
+//
+//	go run ./cmd/import/osm_pois -file japan-latest.osm.pbf
+func main() {
+	file := flag.String("file", "", "OSM PBFファイルのパス (必須)")
+	workers := flag.Int("workers", runtime.NumCPU(), "PBFデコードの並列数")
+	batchSize := flag.Int("batch", 2000, "書き込みバッチサイズ")
+	radius := flag.Int("radius", 800, "facilities の集計半径 (m)")
+	skipFacilities := flag.Bool("skip-facilities", false, "facilities と夜間の安全性の再集計を行わない")
+	lampRadius := flag.Int("lamp-radius", domain.DefaultStreetLampRadiusMeter, "街灯の密度の集計半径 (m)")
+	version := flag.String("version", time.Now().Format("20060102150405"), "night_safety・safety 軸のデータバージョン")
+	flag.Parse()
+
+	if *file == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		log.Fatal(err)
+	}
+	if cfg.DatabaseURL == "" {
+		log.Fatal("DATABASE_URL is required")
+	}
+
+	db := infrastructure.NewDB(cfg.DatabaseURL)
+	defer db.Close()
+
+	ctx := context.Background()
+	repoPOI := repository.NewPOIRepository(db)
+
+	// 取り込み開始時刻 (DB時刻)。これより古いOSMのPOIは今回のファイルに存在しないものとして削除する
+	var startedAt time.Time
+	if err := db.NewRaw("SELECT CURRENT_TIMESTAMP").Scan(ctx, &startedAt); err != nil {
+		log.Fatalf("Failed to get database time: %v", err)
+	}
+
+	open := func() (io.ReadCloser, error) { return os.Open(*file) }
+
+	var batch []*domain.POI
+	var written int
+	flush := func() error {
+		if err := repoPOI.Upsert(ctx, batch); err != nil {
+			return err
+		}
+		written += len(batch)
+		batch = batch[:0]
+		return nil
+	}
+
+	log.Printf("Scanning %s (workers=%d)", *file, *workers)
+	stats, err := osm.ScanPOIs(ctx, open, *workers, func(poi *domain.POI) error {
+		batch = append(batch, poi)
+		if len(batch) >= *batchSize {
+			if err := flush(); err != nil {
+				return err
+			}
+			log.Printf("Upserted %d POIs", written)
+		}
+		return nil
+	})
+	if err == nil {
+		err = flush()
+	}
+	if err != nil {
+		// 途中で失敗した場合は古いPOIを消さず、facilities も更新しない
+		log.Fatalf("Import failed after %d POIs: %v", written, err)
+	}
+
+	log.Printf("Imported %d POIs (nodes=%d, ways=%d, ways_missing_nodes=%d, skipped=%d)",
+		written, stats.Nodes, stats.Ways, stats.WaysMissingNodes, stats.Skipped)
+
+	deleted, err := repoPOI.DeleteStale(ctx, osm.Source, startedAt)
+	if err != nil {
+		log.Fatalf("Failed to delete stale POIs: %v", err)
+	}
+	log.Printf("Deleted %d stale POIs", deleted)
+
+	tables := []string{"pois"}
+	if !*skipFacilities {
+		updated, err := repoPOI.RefreshFacilities(ctx, *radius)
+		if err != nil {
+			log.Fatalf("Failed to refresh facilities: %v", err)
+		}
+		log.Printf("Refreshed facilities for %d stations (radius=%dm)", updated, *radius)
+		tables = append(tables, "facilities")
+
+		// 街灯はOSMから取り込むため、夜間の安全性と night_safety 軸、夜間の成分を含む safety 軸も更新する
+		ucNight := usecase.NewNightSafetyUsecase(repository.NewStationRepository(db), repository.NewNightSafetyRepository(db),
+			repository.NewStationScoreRepository(db), service.NewScoringService())
+		stats, err := ucNight.Refresh(ctx, usecase.NightSafetyOptions{RadiusMeter: *lampRadius, Version: *version, BatchSize: *batchSize})
+		if err != nil {
+			log.Fatalf("Failed to refresh night safety: %v", err)
+		}
+		log.Printf("Refreshed night safety for %d stations (lamp radius=%dm, %d night_safety scores, %d without data, %d safety scores, version=%s)",
+			stats.Stations, *lampRadius, stats.NightScores, stats.NoData, stats.SafetyScores, *version)
+		tables = append(tables, "station_night_safety")
+	}
+
+	// APIサーバーのキャッシュを破棄させる
+	for _, table := range tables {
+		if err := infrastructure.NotifyDataUpdated(ctx, db, table); err != nil {
+			log.Printf("Warning: failed to notify data update: %v", err)
+		}
+	}
+}
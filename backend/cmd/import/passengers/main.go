@@ -0,0 +1,175 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/gigaptera/hikkoshi-lens/backend/internal/config"
+	"github.com/gigaptera/hikkoshi-lens/backend/internal/domain"
+	"github.com/gigaptera/hikkoshi-lens/backend/internal/domain/service"
+	"github.com/gigaptera/hikkoshi-lens/backend/internal/infrastructure"
+	"github.com/gigaptera/hikkoshi-lens/backend/internal/infrastructure/mlit"
+	"github.com/gigaptera/hikkoshi-lens/backend/internal/infrastructure/repository"
+)
+
+// 国土数値情報「駅別乗降客数」(S12) を station_details.daily_passengers に取り込み、
+// 乗降客数から bustle 軸のスコアを全駅分計算し直して station_scores に保存する
+//
+//	go run ./cmd/import/passengers -file S12-23_NumberOfPassengers.geojson
+//
+// GeoJSON か、シェープファイルの属性を書き出したCSV (1行目が S12_001 などの属性名) を受け付ける
+// 駅は S12_001c (駅コード) と stations.station_code で対応付け、データのある最新の年度を使う
+// ファイルに含まれない駅の乗降客数はそのまま残す
+func main() {
+	file := flag.String("file", "", "S12 の GeoJSON / CSV ファイルのパス (必須)")
+	format := flag.String("format", "", "geojson または csv (省略時は拡張子で判定)")
+	firstYear := flag.Int("first-year", mlit.DefaultS12Layout().FirstYear, "S12_006〜S12_009 の年度 (配布年版で異なる)")
+	year := flag.Int("year", 0, "取り込む年度 (0 はデータのある最新の年度)")
+	version := flag.String("version", time.Now().Format("20060102150405"), "データバージョン")
+	batchSize := flag.Int("batch", 1000, "書き込みバッチサイズ")
+	flag.Parse()
+
+	if *file == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+	if *format == "" {
+		*format = "geojson"
+		if strings.EqualFold(filepath.Ext(*file), ".csv") {
+			*format = "csv"
+		}
+	}
+	if *format != "geojson" && *format != "csv" {
+		log.Fatalf("Unknown format %q (geojson or csv)", *format)
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		log.Fatal(err)
+	}
+	if cfg.DatabaseURL == "" {
+		log.Fatal("DATABASE_URL is required")
+	}
+
+	records, skipped, err := readRecords(*file, *format, mlit.S12Layout{FirstYear: *firstYear, Year: *year})
+	if err != nil {
+		log.Fatalf("Failed to read %s: %v", *file, err)
+	}
+	log.Printf("Read %d records (%d without station code or data)", len(records), skipped)
+
+	ctx := context.Background()
+	db := infrastructure.NewDB(cfg.DatabaseURL)
+	defer db.Close()
+
+	repoStation := repository.NewStationRepository(db)
+	repoPassenger := repository.NewPassengerRepository(db)
+	repoScore := repository.NewStationScoreRepository(db)
+
+	stations, err := repoStation.ListAll(ctx)
+	if err != nil {
+		log.Fatalf("Failed to load stations: %v", err)
+	}
+	rows, unmatched := matchStations(stations, records, *version)
+	if len(rows) == 0 {
+		log.Fatal("No records matched stations.station_code")
+	}
+	for i := 0; i < len(rows); i += *batchSize {
+		end := i + *batchSize
+		if end > len(rows) {
+			end = len(rows)
+		}
+		if err := repoPassenger.Upsert(ctx, rows[i:end]); err != nil {
+			log.Fatalf("Failed to upsert passengers batch %d-%d: %v", i, end, err)
+		}
+	}
+	log.Printf("Imported daily passengers for %d stations (%d station codes not found)", len(rows), unmatched)
+
+	// bustle 軸は全駅の相対値のため、取り込んでいない駅を含めて計算し直す
+	passengers, err := repoPassenger.ListAll(ctx)
+	if err != nil {
+		log.Fatalf("Failed to load passengers: %v", err)
+	}
+	raw := service.BustleRawScores(stations, passengers)
+	normalized := service.NormalizeAxisScores(raw)
+	scores := make([]*domain.StationScore, 0, len(raw))
+	for stationID, v := range raw {
+		scores = append(scores, &domain.StationScore{
+			StationID:       stationID,
+			Axis:            domain.BustleScoreAxis,
+			RawScore:        v,
+			NormalizedScore: normalized[stationID],
+			DataVersion:     *version,
+		})
+	}
+	for i := 0; i < len(scores); i += *batchSize {
+		end := i + *batchSize
+		if end > len(scores) {
+			end = len(scores)
+		}
+		if err := repoScore.Upsert(ctx, scores[i:end]); err != nil {
+			log.Fatalf("Failed to upsert bustle scores batch %d-%d: %v", i, end, err)
+		}
+	}
+
+	// APIサーバーのキャッシュを破棄させる
+	if err := infrastructure.NotifyDataUpdated(ctx, db, "station_details"); err != nil {
+		log.Printf("Warning: failed to notify data update: %v", err)
+	}
+	log.Printf("Updated bustle scores for %d stations (version=%s)", len(scores), *version)
+}
+
+func readRecords(path, format string, layout mlit.S12Layout) ([]mlit.PassengerRecord, int, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer f.Close()
+
+	if format == "csv" {
+		return mlit.ReadS12CSV(f, layout)
+	}
+	return mlit.ReadS12GeoJSON(f, layout)
+}
+
+// matchStations は駅コードで駅に対応付ける
+// 同じ駅コードの地物が複数ある場合は乗降客数の多いものを使う
+func matchStations(stations []*domain.Station, records []mlit.PassengerRecord, version string) ([]*domain.StationPassengers, int) {
+	byCode := make(map[string]int64, len(stations))
+	for _, s := range stations {
+		if s.StationCode != "" {
+			byCode[s.StationCode] = s.ID
+		}
+	}
+
+	rows := make(map[int64]*domain.StationPassengers)
+	var order []int64
+	unmatched := 0
+	for _, rec := range records {
+		id, ok := byCode[rec.StationCode]
+		if !ok {
+			unmatched++
+			continue
+		}
+		if row, ok := rows[id]; ok {
+			if rec.Passengers > *row.DailyPassengers {
+				n := rec.Passengers
+				row.DailyPassengers, row.PassengersYear = &n, rec.Year
+			}
+			continue
+		}
+		n := rec.Passengers
+		rows[id] = &domain.StationPassengers{StationID: id, DailyPassengers: &n, PassengersYear: rec.Year, DataVersion: version}
+		order = append(order, id)
+	}
+
+	result := make([]*domain.StationPassengers, len(order))
+	for i, id := range order {
+		result[i] = rows[id]
+	}
+	return result, unmatched
+}
@@ -0,0 +1,158 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"io"
+	"log"
+	"os"
+	"runtime"
+	"sync"
+	"time"
+
+	"github.com/gigaptera/hikkoshi-lens/backend/internal/config"
+	"github.com/gigaptera/hikkoshi-lens/backend/internal/domain"
+	"github.com/gigaptera/hikkoshi-lens/backend/internal/domain/service"
+	"github.com/gigaptera/hikkoshi-lens/backend/internal/infrastructure"
+	"github.com/gigaptera/hikkoshi-lens/backend/internal/infrastructure/gsi"
+	"github.com/gigaptera/hikkoshi-lens/backend/internal/infrastructure/osm"
+	"github.com/gigaptera/hikkoshi-lens/backend/internal/infrastructure/repository"
+)
+
+// 国土地理院の標高タイルから全駅の周辺の地形 (坂・上りの累計・低地からの比高) を計算し、
+// station_terrain と hilliness 軸のスコア (station_scores) に保存する
+//
+//	go run ./cmd/import/terrain -dem-dir ./data/dem5a -network japan-latest.osm.pbf
+//
+// -dem-dir には地理院タイルの標高タイル (テキスト形式) を {zoom}/{x}/{y}.txt の構成で置く
+// GeoTIFF には対応していないため、基盤地図情報の DEM は gdal2tiles などで標高タイルに変換する
+// -network を指定すると徒歩ネットワークの経路に沿って、省略すると駅から放射状の直線に沿って計算する
+// disaster 軸は比高を使うため、取り込み後に cmd/scores recompute を実行する
+func main() {
+	demDir := flag.String("dem-dir", "", "標高タイルのディレクトリ (必須)")
+	zoom := flag.Int("zoom", 15, "標高タイルのズームレベル (dem5a は15、dem10b は14)")
+	tileCache := flag.Int("tile-cache", 1024, "メモリに保持する標高タイルの枚数 (1枚あたり約256KB)")
+	network := flag.String("network", "", "徒歩ネットワークに使う OSM PBF ファイル (省略時は放射状に計算)")
+	radius := flag.Float64("radius", service.DefaultTerrainOptions().RadiusMeter, "徒歩圏の半径 (m)")
+	workers := flag.Int("workers", runtime.NumCPU(), "並列数")
+	version := flag.String("version", time.Now().Format("20060102150405"), "データバージョン")
+	batchSize := flag.Int("batch", 1000, "書き込みバッチサイズ")
+	flag.Parse()
+
+	if *demDir == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+	if *workers < 1 {
+		*workers = 1
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		log.Fatal(err)
+	}
+	if cfg.DatabaseURL == "" {
+		log.Fatal("DATABASE_URL is required")
+	}
+
+	var walk *service.WalkGraph
+	if *network != "" {
+		started := time.Now()
+		open := func() (io.ReadCloser, error) { return os.Open(*network) }
+		net, stats, err := osm.ScanWalkNetwork(context.Background(), open, *workers)
+		if err != nil {
+			log.Fatalf("Failed to load walk network: %v", err)
+		}
+		log.Printf("Loaded walk network from %s in %s: %d ways, %d nodes, %d edges",
+			*network, time.Since(started).Round(time.Millisecond), stats.Ways, stats.Nodes, stats.Edges)
+		walk = service.NewWalkGraph(net)
+	}
+
+	ctx := context.Background()
+	db := infrastructure.NewDB(cfg.DatabaseURL)
+	defer db.Close()
+
+	repoStation := repository.NewStationRepository(db)
+	repoTerrain := repository.NewTerrainRepository(db)
+	repoScore := repository.NewStationScoreRepository(db)
+
+	stations, err := repoStation.ListAll(ctx)
+	if err != nil {
+		log.Fatalf("Failed to load stations: %v", err)
+	}
+
+	opts := service.DefaultTerrainOptions()
+	opts.RadiusMeter = *radius
+	dem := gsi.NewDEMTiles(*demDir, *zoom, *tileCache)
+	log.Printf("Computing terrain for %d stations (zoom=%d, radius=%.0fm, workers=%d)", len(stations), *zoom, opts.RadiusMeter, *workers)
+	rows := computeTerrain(stations, dem, walk, opts, *workers)
+	if len(rows) == 0 {
+		log.Fatalf("No station has elevation data in %s/%d", *demDir, *zoom)
+	}
+
+	scores := make([]*domain.StationScore, len(rows))
+	byMethod := make(map[string]int)
+	for i, t := range rows {
+		t.DataVersion = *version
+		byMethod[t.Method]++
+		// 平坦さは全国の駅の相対値ではなく勾配の絶対的な基準で評価するため、正規化せずに保存する
+		v := service.HillinessScore(t)
+		scores[i] = &domain.StationScore{
+			StationID:       t.StationID,
+			Axis:            domain.HillinessScoreAxis,
+			RawScore:        v,
+			NormalizedScore: v,
+			DataVersion:     *version,
+		}
+	}
+
+	for i := 0; i < len(rows); i += *batchSize {
+		end := i + *batchSize
+		if end > len(rows) {
+			end = len(rows)
+		}
+		if err := repoTerrain.Upsert(ctx, rows[i:end]); err != nil {
+			log.Fatalf("Failed to upsert terrain batch %d-%d: %v", i, end, err)
+		}
+		if err := repoScore.Upsert(ctx, scores[i:end]); err != nil {
+			log.Fatalf("Failed to upsert hilliness scores batch %d-%d: %v", i, end, err)
+		}
+	}
+
+	// APIサーバーのキャッシュを破棄させる
+	if err := infrastructure.NotifyDataUpdated(ctx, db, "station_terrain"); err != nil {
+		log.Printf("Warning: failed to notify data update: %v", err)
+	}
+	log.Printf("Imported terrain for %d stations (%d network, %d radial, %d without elevation data, version=%s)",
+		len(rows), byMethod[domain.TerrainMethodNetwork], byMethod[domain.TerrainMethodRadial], len(stations)-len(rows), *version)
+}
+
+// computeTerrain は駅ごとの地形を並列に計算する。駅の標高が読めない駅 (タイルの範囲外など) は含めない
+func computeTerrain(stations []*domain.Station, dem domain.ElevationModel, walk *service.WalkGraph, opts service.TerrainOptions, workers int) []*domain.StationTerrain {
+	jobs := make(chan *domain.Station)
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	var rows []*domain.StationTerrain
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for station := range jobs {
+				t, ok := service.ComputeTerrain(station, dem, walk, opts)
+				if !ok {
+					continue
+				}
+				mu.Lock()
+				rows = append(rows, t)
+				mu.Unlock()
+			}
+		}()
+	}
+
+	for _, station := range stations {
+		jobs <- station
+	}
+	close(jobs)
+	wg.Wait()
+	return rows
+}
@@ -0,0 +1,145 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/gigaptera/hikkoshi-lens/backend/internal/config"
+	"github.com/gigaptera/hikkoshi-lens/backend/internal/domain"
+	"github.com/gigaptera/hikkoshi-lens/backend/internal/domain/service"
+	"github.com/gigaptera/hikkoshi-lens/backend/internal/infrastructure"
+	"github.com/gigaptera/hikkoshi-lens/backend/internal/infrastructure/llm"
+	"github.com/gigaptera/hikkoshi-lens/backend/internal/infrastructure/repository"
+	"github.com/gigaptera/hikkoshi-lens/backend/internal/usecase"
+)
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "Usage: insights <command> [flags]")
+	fmt.Fprintln(os.Stderr, "")
+	fmt.Fprintln(os.Stderr, "Commands:")
+	fmt.Fprintln(os.Stderr, "  generate   入力データが変わった駅の解説文 (station_insights) を生成する")
+	fmt.Fprintln(os.Stderr, "  preview    1駅分の入力と生成結果を表示する (保存しない)")
+}
+
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+		os.Exit(2)
+	}
+
+	switch os.Args[1] {
+	case "generate":
+		generate(os.Args[2:])
+	case "preview":
+		preview(os.Args[2:])
+	default:
+		usage()
+		os.Exit(2)
+	}
+}
+
+// newGenerator は -generator (省略時は INSIGHT_GENERATOR) に応じた生成手段を返す
+func newGenerator(cfg *config.Config, name string) domain.InsightGenerator {
+	if name == "" {
+		name = cfg.InsightGenerator
+	}
+	switch name {
+	case "template":
+		return service.NewTemplateInsightGenerator()
+	case "llm":
+		if cfg.LLMAPIKey == "" {
+			log.Println("Warning: LLM_API_KEY is not set")
+		}
+		return llm.NewInsightGenerator(cfg.LLMEndpoint, cfg.LLMAPIKey, cfg.LLMModel, cfg.LLMTimeout)
+	default:
+		log.Fatalf("Unknown generator %q (template or llm)", name)
+		return nil
+	}
+}
+
+func loadConfig() *config.Config {
+	cfg, err := config.Load()
+	if err != nil {
+		log.Fatal(err)
+	}
+	if cfg.DatabaseURL == "" {
+		log.Fatal("DATABASE_URL is required")
+	}
+	return cfg
+}
+
+func generate(args []string) {
+	fs := flag.NewFlagSet("generate", flag.ExitOnError)
+	generatorName := fs.String("generator", "", "生成手段 template|llm (省略時は INSIGHT_GENERATOR)")
+	force := fs.Bool("force", false, "入力が変わっていない駅も生成し直す")
+	limit := fs.Int("limit", 0, "生成する駅数の上限 (0: 無制限)")
+	fs.Parse(args)
+
+	cfg := loadConfig()
+	generator := newGenerator(cfg, *generatorName)
+
+	db := infrastructure.NewDB(cfg.DatabaseURL)
+	defer db.Close()
+
+	ctx := context.Background()
+	uc := usecase.NewInsightUsecase(repository.NewInsightRepository(db), generator)
+	stats, err := uc.Regenerate(ctx, *force, *limit)
+	if err != nil {
+		log.Fatalf("Failed to generate insights: %v", err)
+	}
+	if stats.Generated > 0 {
+		if err := infrastructure.NotifyDataUpdated(ctx, db, "station_insights"); err != nil {
+			log.Printf("Warning: failed to notify data update: %v", err)
+		}
+	}
+	log.Printf("Generate completed (%s): %d stations, %d generated, %d unchanged, %d without data, %d failed",
+		generator.Name(), stats.Stations, stats.Generated, stats.Unchanged, stats.NoData, stats.Failed)
+	if stats.LimitReached {
+		log.Printf("Limit of %d reached; run again to continue", *limit)
+	}
+	if stats.Failed > 0 {
+		os.Exit(1)
+	}
+}
+
+func preview(args []string) {
+	fs := flag.NewFlagSet("preview", flag.ExitOnError)
+	generatorName := fs.String("generator", "", "生成手段 template|llm (省略時は INSIGHT_GENERATOR)")
+	stationID := fs.Int64("station", 0, "駅ID")
+	fs.Parse(args)
+	if *stationID == 0 {
+		log.Fatal("-station is required")
+	}
+
+	cfg := loadConfig()
+	generator := newGenerator(cfg, *generatorName)
+
+	db := infrastructure.NewDB(cfg.DatabaseURL)
+	defer db.Close()
+
+	ctx := context.Background()
+	inputs, err := repository.NewInsightRepository(db).ListInputs(ctx)
+	if err != nil {
+		log.Fatalf("Failed to load inputs: %v", err)
+	}
+	service.SetInsightLineMedians(inputs)
+
+	for _, in := range inputs {
+		if in.StationID != *stationID {
+			continue
+		}
+		fmt.Println(service.BuildInsightPrompt(in))
+		insight, err := generator.Generate(ctx, in)
+		if err != nil {
+			log.Fatalf("Failed to generate: %v", err)
+		}
+		out, _ := json.MarshalIndent(insight.Summary, "", "  ")
+		fmt.Printf("\n%s\ntrend: %s\n", out, insight.Trend)
+		return
+	}
+	log.Fatalf("Station %d not found", *stationID)
+}
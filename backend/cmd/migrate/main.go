@@ -0,0 +1,152 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/gigaptera/hikkoshi-lens/backend/internal/config"
+	"github.com/gigaptera/hikkoshi-lens/backend/internal/infrastructure"
+	"github.com/gigaptera/hikkoshi-lens/backend/internal/infrastructure/migrate"
+	"github.com/gigaptera/hikkoshi-lens/backend/migrations"
+	"github.com/uptrace/bun"
+)
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "Usage: migrate <command> [args]")
+	fmt.Fprintln(os.Stderr, "")
+	fmt.Fprintln(os.Stderr, "Commands:")
+	fmt.Fprintln(os.Stderr, "  up                 未適用のマイグレーションをすべて適用する")
+	fmt.Fprintln(os.Stderr, "  down               最後に適用したマイグレーションを1件取り消す")
+	fmt.Fprintln(os.Stderr, "  status             マイグレーションの適用状況を表示する")
+	fmt.Fprintln(os.Stderr, "  create <name>      空のマイグレーションファイルを作成する (-dir で出力先を指定)")
+	fmt.Fprintln(os.Stderr, "  baseline <version> version以下を実行せずに適用済みとして記録する (既存DB用)")
+	fmt.Fprintln(os.Stderr, "  check              BunモデルとDBスキーマの一致を確認する")
+}
+
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+		os.Exit(2)
+	}
+	cmd, args := os.Args[1], os.Args[2:]
+
+	// create はDB接続不要
+	if cmd == "create" {
+		fs := flag.NewFlagSet("create", flag.ExitOnError)
+		dir := fs.String("dir", "migrations", "マイグレーションディレクトリ")
+		fs.Parse(args)
+		if fs.NArg() != 1 {
+			usage()
+			os.Exit(2)
+		}
+		path, err := migrate.Create(*dir, fs.Arg(0), time.Now())
+		if err != nil {
+			log.Fatalf("Failed to create migration: %v", err)
+		}
+		log.Printf("Created %s", path)
+		return
+	}
+
+	loaded, err := migrate.Load(migrations.FS)
+	if err != nil {
+		log.Fatalf("Failed to load migrations: %v", err)
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		log.Fatal(err)
+	}
+	if cfg.DatabaseURL == "" {
+		log.Fatal("DATABASE_URL is required")
+	}
+
+	db := infrastructure.NewDB(cfg.DatabaseURL)
+	defer db.Close()
+
+	ctx := context.Background()
+	m := migrate.New(db, loaded)
+
+	switch cmd {
+	case "up":
+		done, err := m.Up(ctx)
+		for _, mig := range done {
+			log.Printf("Applied %s", mig.Name)
+		}
+		if err != nil {
+			log.Fatal(err)
+		}
+		if len(done) == 0 {
+			log.Println("No pending migrations")
+		}
+		checkModels(ctx, db)
+
+	case "down":
+		mig, err := m.Down(ctx)
+		if errors.Is(err, sql.ErrNoRows) {
+			log.Println("No applied migrations")
+			return
+		}
+		if err != nil {
+			log.Fatal(err)
+		}
+		log.Printf("Rolled back %s", mig.Name)
+
+	case "status":
+		statuses, err := m.Status(ctx)
+		if err != nil {
+			log.Fatal(err)
+		}
+		for _, st := range statuses {
+			applied := "pending"
+			if st.AppliedAt != nil {
+				applied = st.AppliedAt.Local().Format(time.RFC3339)
+			}
+			fmt.Printf("%-25s  %s\n", applied, st.Migration.Name)
+		}
+
+	case "baseline":
+		if len(args) != 1 {
+			usage()
+			os.Exit(2)
+		}
+		version, err := strconv.ParseInt(args[0], 10, 64)
+		if err != nil {
+			log.Fatalf("Invalid version %q: %v", args[0], err)
+		}
+		marked, err := m.Baseline(ctx, version)
+		for _, mig := range marked {
+			log.Printf("Marked %s as applied", mig.Name)
+		}
+		if err != nil {
+			log.Fatal(err)
+		}
+
+	case "check":
+		checkModels(ctx, db)
+
+	default:
+		usage()
+		os.Exit(2)
+	}
+}
+
+func checkModels(ctx context.Context, db *bun.DB) {
+	problems, err := migrate.CheckModels(ctx, db, migrate.Models...)
+	if err != nil {
+		log.Fatalf("Failed to check schema: %v", err)
+	}
+	for _, p := range problems {
+		log.Printf("Schema mismatch: %s", p)
+	}
+	if len(problems) > 0 {
+		log.Fatalf("%d schema mismatches between Bun models and migrations", len(problems))
+	}
+	log.Println("Bun models match the database schema")
+}
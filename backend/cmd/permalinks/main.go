@@ -0,0 +1,60 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"time"
+
+	"github.com/gigaptera/hikkoshi-lens/backend/internal/config"
+	"github.com/gigaptera/hikkoshi-lens/backend/internal/infrastructure"
+	"github.com/gigaptera/hikkoshi-lens/backend/internal/infrastructure/repository"
+)
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "Usage: permalinks <command> [flags]")
+	fmt.Fprintln(os.Stderr, "")
+	fmt.Fprintln(os.Stderr, "Commands:")
+	fmt.Fprintln(os.Stderr, "  prune   期限切れから一定期間たった共有リンクを削除する")
+}
+
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+		os.Exit(2)
+	}
+
+	switch os.Args[1] {
+	case "prune":
+		prune(os.Args[2:])
+	default:
+		usage()
+		os.Exit(2)
+	}
+}
+
+func prune(args []string) {
+	fs := flag.NewFlagSet("prune", flag.ExitOnError)
+	// 期限切れ直後のリンクは「期限切れ」と表示できるよう、しばらく残してから消す
+	grace := fs.Duration("grace", 30*24*time.Hour, "期限切れからこの期間を過ぎたリンクを削除する")
+	fs.Parse(args)
+
+	cfg, err := config.Load()
+	if err != nil {
+		log.Fatal(err)
+	}
+	if cfg.DatabaseURL == "" {
+		log.Fatal("DATABASE_URL is required")
+	}
+
+	db := infrastructure.NewDB(cfg.DatabaseURL)
+	defer db.Close()
+
+	n, err := repository.NewPermalinkRepository(db).DeleteExpired(context.Background(), time.Now().Add(-*grace))
+	if err != nil {
+		log.Fatalf("Failed to prune permalinks: %v", err)
+	}
+	log.Printf("Pruned %d expired permalinks", n)
+}
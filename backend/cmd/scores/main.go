@@ -0,0 +1,167 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"runtime"
+	"sync"
+	"time"
+
+	"github.com/gigaptera/hikkoshi-lens/backend/internal/config"
+	"github.com/gigaptera/hikkoshi-lens/backend/internal/domain"
+	"github.com/gigaptera/hikkoshi-lens/backend/internal/domain/service"
+	"github.com/gigaptera/hikkoshi-lens/backend/internal/infrastructure"
+	"github.com/gigaptera/hikkoshi-lens/backend/internal/infrastructure/repository"
+)
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "Usage: scores <command> [flags]")
+	fmt.Fprintln(os.Stderr, "")
+	fmt.Fprintln(os.Stderr, "Commands:")
+	fmt.Fprintln(os.Stderr, "  recompute   全駅の事前計算スコア (station_scores) を再計算する")
+}
+
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+		os.Exit(2)
+	}
+
+	switch os.Args[1] {
+	case "recompute":
+		recompute(os.Args[2:])
+	default:
+		usage()
+		os.Exit(2)
+	}
+}
+
+func recompute(args []string) {
+	fs := flag.NewFlagSet("recompute", flag.ExitOnError)
+	workers := fs.Int("workers", runtime.NumCPU(), "並列数")
+	version := fs.String("version", time.Now().Format("20060102150405"), "データバージョン")
+	batchSize := fs.Int("batch", 1000, "書き込みバッチサイズ")
+	fs.Parse(args)
+
+	cfg, err := config.Load()
+	if err != nil {
+		log.Fatal(err)
+	}
+	if cfg.DatabaseURL == "" {
+		log.Fatal("DATABASE_URL is required")
+	}
+
+	db := infrastructure.NewDB(cfg.DatabaseURL)
+	defer db.Close()
+
+	ctx := context.Background()
+	repoStation := repository.NewStationRepository(db)
+	repoScore := repository.NewStationScoreRepository(db)
+	repoTerrain := repository.NewTerrainRepository(db)
+	repoDisasterAccess := repository.NewDisasterAccessRepository(db)
+	repoNightSafety := repository.NewNightSafetyRepository(db)
+	svcScoring := service.NewScoringService()
+
+	stations, err := repoStation.ListAll(ctx)
+	if err != nil {
+		log.Fatalf("Failed to load stations: %v", err)
+	}
+	// disaster 軸は周辺の低地からの比高 (cmd/import/terrain が計算) と
+	// 最寄りの避難場所・救急病院までの距離 (cmd/import/disaster_facilities が集計) も使う
+	terrains, err := repoTerrain.ListAll(ctx)
+	if err != nil {
+		log.Fatalf("Failed to load station terrain: %v", err)
+	}
+	access, err := repoDisasterAccess.ListAll(ctx)
+	if err != nil {
+		log.Fatalf("Failed to load disaster access: %v", err)
+	}
+	// safety 軸は街灯の密度と最寄りの交番までの距離 (cmd/import/osm_pois・cmd/import/koban が集計) も使う
+	night, err := repoNightSafety.ListAll(ctx)
+	if err != nil {
+		log.Fatalf("Failed to load night safety: %v", err)
+	}
+	for _, s := range stations {
+		s.Terrain = terrains[s.ID]
+		s.DisasterAccess = access[s.ID]
+		s.NightSafety = night[s.ID]
+	}
+	log.Printf("Recomputing scores for %d stations (version=%s, workers=%d)", len(stations), *version, *workers)
+
+	// 1. 生スコアを並列計算 (axis -> station_id -> raw)
+	raw := computeRawScores(stations, svcScoring, *workers)
+
+	// 2. 軸ごとに全駅で正規化
+	var scores []*domain.StationScore
+	for axis, byStation := range raw {
+		normalized := service.NormalizeAxisScores(byStation)
+		for stationID, v := range byStation {
+			scores = append(scores, &domain.StationScore{
+				StationID:       stationID,
+				Axis:            axis,
+				RawScore:        v,
+				NormalizedScore: normalized[stationID],
+				DataVersion:     *version,
+			})
+		}
+	}
+
+	// 3. バッチで書き込み
+	for i := 0; i < len(scores); i += *batchSize {
+		end := i + *batchSize
+		if end > len(scores) {
+			end = len(scores)
+		}
+		if err := repoScore.Upsert(ctx, scores[i:end]); err != nil {
+			log.Fatalf("Failed to upsert scores batch %d-%d: %v", i, end, err)
+		}
+		log.Printf("Upserted scores batch %d-%d", i, end)
+	}
+
+	// APIサーバーのキャッシュを破棄させる
+	if err := infrastructure.NotifyDataUpdated(ctx, db, "station_scores"); err != nil {
+		log.Printf("Warning: failed to notify data update: %v", err)
+	}
+
+	log.Printf("Recompute completed: %d scores (%d axes)", len(scores), len(raw))
+}
+
+func computeRawScores(stations []*domain.Station, svcScoring *service.ScoringService, workers int) map[string]map[int64]float64 {
+	if workers < 1 {
+		workers = 1
+	}
+
+	raw := make(map[string]map[int64]float64)
+	for _, axis := range svcScoring.PrecomputedAxes() {
+		raw[axis] = make(map[int64]float64, len(stations))
+	}
+
+	jobs := make(chan *domain.Station)
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for station := range jobs {
+				axisScores := svcScoring.CalculateRawAxisScores(station)
+				mu.Lock()
+				for axis, v := range axisScores {
+					raw[axis][station.ID] = v
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+
+	for _, station := range stations {
+		jobs <- station
+	}
+	close(jobs)
+	wg.Wait()
+
+	return raw
+}
@@ -8,6 +8,7 @@ import (
 	"log"
 	"os"
 
+	"github.com/gigaptera/hikkoshi-lens/backend/internal/infrastructure"
 	"github.com/joho/godotenv"
 	"github.com/uptrace/bun"
 	"github.com/uptrace/bun/dialect/pgdialect"
@@ -172,4 +173,9 @@ func main() {
 	}
 
 	fmt.Printf("Successfully inserted/updated %d market prices\n", len(marketPrices))
+
+	// APIサーバーのキャッシュを破棄させる
+	if err := infrastructure.NotifyDataUpdated(ctx, db, "market_prices"); err != nil {
+		log.Printf("Warning: failed to notify data update: %v", err)
+	}
 }
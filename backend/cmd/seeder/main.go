@@ -11,6 +11,7 @@ import (
 	"github.com/gigaptera/hikkoshi-lens/backend/internal/config"
 	"github.com/gigaptera/hikkoshi-lens/backend/internal/domain"
 	"github.com/gigaptera/hikkoshi-lens/backend/internal/infrastructure"
+	"github.com/gigaptera/hikkoshi-lens/backend/internal/infrastructure/migrate"
 )
 
 type StationJSON struct {
@@ -41,37 +42,16 @@ func main() {
 
 	ctx := context.Background()
 
-	// テーブル作成
-	if _, err := db.Exec("CREATE EXTENSION IF NOT EXISTS postgis"); err != nil {
-		log.Printf("Warning: failed to create postgis extension: %v", err)
-	}
-
-	// テーブルをリセットしてから作成
-	if _, err := db.NewDropTable().Model((*domain.Station)(nil)).IfExists().Exec(ctx); err != nil {
-		log.Printf("Warning: failed to drop stations table: %v", err)
-	}
-	if _, err := db.NewCreateTable().Model((*domain.Station)(nil)).Exec(ctx); err != nil {
-		log.Fatal(err)
-	}
-
-	if _, err := db.NewDropTable().Model((*domain.Line)(nil)).IfExists().Exec(ctx); err != nil {
-		log.Printf("Warning: failed to drop lines table: %v", err)
-	}
-	if _, err := db.NewCreateTable().Model((*domain.Line)(nil)).Exec(ctx); err != nil {
-		log.Fatal(err)
-	}
-
-	// MarketPriceテーブル作成
-	if _, err := db.NewDropTable().Model((*domain.MarketPrice)(nil)).IfExists().Exec(ctx); err != nil {
-		log.Printf("Warning: failed to drop market_prices table: %v", err)
-	}
-	if _, err := db.NewCreateTable().Model((*domain.MarketPrice)(nil)).Exec(ctx); err != nil {
-		log.Fatal(err)
+	// テーブルは cmd/migrate up で作成済みであること（seederはデータ投入のみ）
+	problems, err := migrate.CheckModels(ctx, db, migrate.Models...)
+	if err != nil {
+		log.Fatalf("Failed to check schema: %v", err)
 	}
-
-	// Create Unique Index for Upsert
-	if _, err := db.ExecContext(ctx, "CREATE UNIQUE INDEX IF NOT EXISTS uq_station_building_layout ON market_prices (station_id, building_type, layout)"); err != nil {
-		log.Printf("Warning: failed to create unique index: %v", err)
+	if len(problems) > 0 {
+		for _, p := range problems {
+			log.Printf("Schema mismatch: %s", p)
+		}
+		log.Fatal("Database schema is not up to date. Run `go run ./cmd/migrate up` first")
 	}
 
 	// 1. Stationデータの読み込み (stationcode.json)
@@ -191,5 +171,10 @@ func main() {
 		}
 	*/
 
+	// APIサーバーのキャッシュを破棄させる
+	if err := infrastructure.NotifyDataUpdated(ctx, db, "stations"); err != nil {
+		log.Printf("Warning: failed to notify data update: %v", err)
+	}
+
 	log.Println("Seeding completed!")
 }
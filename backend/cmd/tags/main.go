@@ -0,0 +1,119 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"time"
+
+	"github.com/gigaptera/hikkoshi-lens/backend/internal/config"
+	"github.com/gigaptera/hikkoshi-lens/backend/internal/domain"
+	"github.com/gigaptera/hikkoshi-lens/backend/internal/domain/service"
+	"github.com/gigaptera/hikkoshi-lens/backend/internal/infrastructure"
+	"github.com/gigaptera/hikkoshi-lens/backend/internal/infrastructure/repository"
+)
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "Usage: tags <command> [flags]")
+	fmt.Fprintln(os.Stderr, "")
+	fmt.Fprintln(os.Stderr, "Commands:")
+	fmt.Fprintln(os.Stderr, "  recompute   ルールを評価して全駅のタグ (station_tags) を作り直す")
+	fmt.Fprintln(os.Stderr, "  rules       使用するルールを表示する")
+}
+
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+		os.Exit(2)
+	}
+
+	switch os.Args[1] {
+	case "recompute":
+		recompute(os.Args[2:])
+	case "rules":
+		printRules(os.Args[2:])
+	default:
+		usage()
+		os.Exit(2)
+	}
+}
+
+// loadRules は -rules で指定したJSONファイル、なければ組み込みのルールを返す
+func loadRules(path string) []domain.TagRule {
+	if path == "" {
+		return service.DefaultTagRules()
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		log.Fatalf("Failed to read rules: %v", err)
+	}
+	rules, err := service.ParseTagRules(data)
+	if err != nil {
+		log.Fatalf("Invalid rules %s: %v", path, err)
+	}
+	return rules
+}
+
+func printRules(args []string) {
+	fs := flag.NewFlagSet("rules", flag.ExitOnError)
+	rulesPath := fs.String("rules", "", "ルール定義のJSONファイル (省略時は組み込みのルール)")
+	fs.Parse(args)
+
+	for _, r := range loadRules(*rulesPath) {
+		fmt.Printf("%s\t%s\n", r.Tag, r.Description)
+		for _, c := range r.All {
+			fmt.Printf("\t%s %s %g\n", c.Fact, c.Op, c.Value)
+		}
+	}
+}
+
+func recompute(args []string) {
+	fs := flag.NewFlagSet("recompute", flag.ExitOnError)
+	rulesPath := fs.String("rules", "", "ルール定義のJSONファイル (省略時は組み込みのルール)")
+	version := fs.String("version", time.Now().Format("20060102150405"), "データバージョン")
+	dryRun := fs.Bool("dry-run", false, "書き込まずにタグごとの駅数だけを表示する")
+	fs.Parse(args)
+
+	rules := loadRules(*rulesPath)
+
+	cfg, err := config.Load()
+	if err != nil {
+		log.Fatal(err)
+	}
+	if cfg.DatabaseURL == "" {
+		log.Fatal("DATABASE_URL is required")
+	}
+
+	db := infrastructure.NewDB(cfg.DatabaseURL)
+	defer db.Close()
+
+	ctx := context.Background()
+	repoTag := repository.NewTagRepository(db)
+
+	facts, err := repoTag.ListFacts(ctx)
+	if err != nil {
+		log.Fatalf("Failed to load station facts: %v", err)
+	}
+	tags := service.NewTagEngine(rules).StationTags(facts, *version)
+
+	counts := make(map[string]int)
+	for _, t := range tags {
+		counts[t.Tag]++
+	}
+	for _, r := range rules {
+		log.Printf("%s: %d stations", r.Tag, counts[r.Tag])
+	}
+	if *dryRun {
+		return
+	}
+
+	if err := repoTag.ReplaceAll(ctx, tags); err != nil {
+		log.Fatalf("Failed to save tags: %v", err)
+	}
+	if err := infrastructure.NotifyDataUpdated(ctx, db, "station_tags"); err != nil {
+		log.Printf("Warning: failed to notify data update: %v", err)
+	}
+	log.Printf("Recompute completed: %d tags for %d stations (version=%s)", len(tags), len(facts), *version)
+}
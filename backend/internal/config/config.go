@@ -3,6 +3,9 @@ package config
 import (
 	"log"
 	"os"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/joho/godotenv"
 )
@@ -10,6 +13,53 @@ import (
 type Config struct {
 	DatabaseURL string
 	Port        string
+
+	// 検索・詳細APIの応答キャッシュ
+	CacheTTL        time.Duration
+	CacheMaxEntries int
+
+	// 送客先ごとのアフィリエイトパラメータ (クエリ文字列、例: "vos=xxxx")
+	AffiliateParams map[string]string
+
+	// ログイン (マジックリンク)
+	AppBaseURL   string        // メールに載せるリンクの宛先 (フロントエンド)
+	MagicLinkTTL time.Duration // マジックリンクの有効期間
+	SessionTTL   time.Duration // ログイン状態の有効期間
+
+	// メール送信 (MAIL_SENDER=smtp 以外はログ出力のみ)
+	MailSender   string
+	SMTPAddr     string
+	SMTPUsername string
+	SMTPPassword string
+	MailFrom     string
+
+	// 保存した検索の通知 (channel 未指定の検索条件に使う送り先: log, email, webhook)
+	AlertNotifier string
+
+	// 駅の解説文の生成 (INSIGHT_GENERATOR=llm の場合に OpenAI 互換の API を呼ぶ)
+	InsightGenerator string // template, llm
+	LLMEndpoint      string
+	LLMAPIKey        string
+	LLMModel         string
+	LLMTimeout       time.Duration
+
+	// 管理者 (口コミの審査) のメールアドレス。ログイン済みの利用者のうちこのアドレスだけが /api/admin を使える
+	AdminEmails []string
+	// 口コミの投稿元・ログインリンクの要求元 (IPアドレス) をHMACにする鍵
+	ReviewClientSecret string
+	// 組み込みの禁止語に追加する禁止語のファイル (1行1語)
+	ReviewBannedWordsFile string
+	// リバースプロキシの X-Forwarded-For を信頼して投稿元のIPアドレスを取る
+	TrustProxy bool
+
+	// 初期費用の見積もり設定 (JSON)。空なら組み込みの設定を使う
+	MoveInCostsFile string
+
+	// 徒歩ネットワークを作るOSMのPBF抽出ファイル (起動時に読み込む。地域の抽出を推奨)
+	// 空なら徒歩分数・access は直線距離から推定する
+	WalkNetworkFile string
+	// 道路距離のキャッシュに保持する区間 (出発地と目的地の組) の数
+	WalkCacheEntries int
 }
 
 func Load() (*Config, error) {
@@ -28,7 +78,83 @@ func Load() (*Config, error) {
 	}
 
 	return &Config{
-		DatabaseURL: dbURL,
-		Port:        port,
+		DatabaseURL:     dbURL,
+		Port:            port,
+		CacheTTL:        getDuration("CACHE_TTL", 5*time.Minute),
+		CacheMaxEntries: getInt("CACHE_MAX_ENTRIES", 1000),
+		AffiliateParams: map[string]string{
+			"suumo": os.Getenv("AFFILIATE_SUUMO_PARAMS"),
+			"homes": os.Getenv("AFFILIATE_HOMES_PARAMS"),
+		},
+		AppBaseURL:   getString("APP_BASE_URL", "http://localhost:3000"),
+		MagicLinkTTL: getDuration("MAGIC_LINK_TTL", 15*time.Minute),
+		SessionTTL:   getDuration("SESSION_TTL", 30*24*time.Hour),
+		MailSender:   os.Getenv("MAIL_SENDER"),
+		SMTPAddr:     os.Getenv("SMTP_ADDR"),
+		SMTPUsername: os.Getenv("SMTP_USERNAME"),
+		SMTPPassword: os.Getenv("SMTP_PASSWORD"),
+		MailFrom:     getString("MAIL_FROM", "noreply@hikkoshi-lens.local"),
+
+		AlertNotifier: getString("ALERT_NOTIFIER", "log"),
+
+		InsightGenerator: getString("INSIGHT_GENERATOR", "template"),
+		LLMEndpoint:      getString("LLM_ENDPOINT", "https://api.openai.com/v1"),
+		LLMAPIKey:        os.Getenv("LLM_API_KEY"),
+		LLMModel:         getString("LLM_MODEL", "gpt-4o-mini"),
+		LLMTimeout:       getDuration("LLM_TIMEOUT", 60*time.Second),
+
+		AdminEmails:           getList("ADMIN_EMAILS"),
+		ReviewClientSecret:    os.Getenv("REVIEW_CLIENT_SECRET"),
+		ReviewBannedWordsFile: os.Getenv("REVIEW_BANNED_WORDS"),
+		TrustProxy:            os.Getenv("TRUST_PROXY") == "true",
+
+		MoveInCostsFile: os.Getenv("MOVE_IN_COSTS_FILE"),
+
+		WalkNetworkFile:  os.Getenv("WALK_NETWORK_FILE"),
+		WalkCacheEntries: getInt("WALK_CACHE_ENTRIES", 200000),
 	}, nil
 }
+
+func getString(key, def string) string {
+	if v := os.Getenv(key); v != "" {
+		return v
+	}
+	return def
+}
+
+func getDuration(key string, def time.Duration) time.Duration {
+	v := os.Getenv(key)
+	if v == "" {
+		return def
+	}
+	d, err := time.ParseDuration(v)
+	if err != nil {
+		log.Printf("Warning: invalid %s=%q, using default %s", key, v, def)
+		return def
+	}
+	return d
+}
+
+func getInt(key string, def int) int {
+	v := os.Getenv(key)
+	if v == "" {
+		return def
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil {
+		log.Printf("Warning: invalid %s=%q, using default %d", key, v, def)
+		return def
+	}
+	return n
+}
+
+// getList はカンマ区切りの値を読み取る (空要素は除く)
+func getList(key string) []string {
+	var list []string
+	for _, v := range strings.Split(os.Getenv(key), ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			list = append(list, v)
+		}
+	}
+	return list
+}
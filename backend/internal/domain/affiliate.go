@@ -0,0 +1,75 @@
+package domain
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/uptrace/bun"
+)
+
+// 送客先の外部サイト
+const (
+	AffiliateSourceSuumo = "suumo"
+	AffiliateSourceHomes = "homes"
+)
+
+// AffiliateSources は送客先の一覧 (駅詳細の affiliate_links の順)
+var AffiliateSources = []string{AffiliateSourceSuumo, AffiliateSourceHomes}
+
+// IsAffiliateSource は送客先として扱えるサイトかを返す
+func IsAffiliateSource(source string) bool {
+	for _, s := range AffiliateSources {
+		if s == source {
+			return true
+		}
+	}
+	return false
+}
+
+// StationExternalID は外部サイトでの駅の識別子
+type StationExternalID struct {
+	bun.BaseModel `bun:"table:station_external_ids,alias:sei"`
+	StationID     int64     `bun:"station_id,pk" json:"station_id"`
+	Source        string    `bun:"source,pk" json:"source"`
+	Area          string    `bun:"area,notnull" json:"area"`               // URLの地域部分 ('tokyo')
+	ExternalID    string    `bun:"external_id,notnull" json:"external_id"` // SUUMOなら ek_ に続く番号
+	UpdatedAt     time.Time `bun:"updated_at,nullzero,notnull,default:current_timestamp" json:"updated_at"`
+}
+
+// AffiliateClick は外部サイトへのリダイレクト1回分のログ
+type AffiliateClick struct {
+	bun.BaseModel `bun:"table:affiliate_clicks,alias:ac"`
+	ID            int64     `bun:"id,pk,autoincrement"`
+	StationID     int64     `bun:"station_id,notnull"`
+	Source        string    `bun:"source,notnull"`
+	Layout        string    `bun:"layout,nullzero"`
+	MinRent       float64   `bun:"min_rent,nullzero"`
+	MaxRent       float64   `bun:"max_rent,nullzero"`
+	TargetURL     string    `bun:"target_url,notnull"`
+	Referer       string    `bun:"referer,nullzero"`
+	CreatedAt     time.Time `bun:"created_at,nullzero,notnull,default:current_timestamp"`
+}
+
+// AffiliateFilter は送客先の検索結果に引き継ぐ条件 (家賃は万円)
+type AffiliateFilter struct {
+	Layout  string
+	MinRent float64
+	MaxRent float64
+}
+
+func (f AffiliateFilter) IsZero() bool {
+	return f.Layout == "" && f.MinRent == 0 && f.MaxRent == 0
+}
+
+// AffiliateRedirectPath は送客 (クリック計測付きリダイレクト) のAPIパス
+func AffiliateRedirectPath(stationID int64, source string) string {
+	return fmt.Sprintf("/api/stations/%d/affiliate/%s", stationID, source)
+}
+
+type AffiliateRepository interface {
+	// GetExternalIDs は駅の外部IDを送客先ごとに返す
+	GetExternalIDs(ctx context.Context, stationID int64) (map[string]*StationExternalID, error)
+	UpsertExternalIDs(ctx context.Context, ids []*StationExternalID) error
+	LogClick(ctx context.Context, click *AffiliateClick) error
+}
@@ -0,0 +1,123 @@
+package domain
+
+import "errors"
+
+var (
+	ErrInvalidBudget  = errors.New("invalid budget input")
+	ErrUnknownTaxYear = errors.New("unknown tax year")
+)
+
+// MaxMonthlySalary は受け付ける月給の上限 (円)
+const MaxMonthlySalary = 10000000
+
+// RecommendedBudgetRule は家賃の目安のうち、検索条件の初期値に使うもの
+const RecommendedBudgetRule = "standard"
+
+// BudgetRule は手取り月収に対する家賃の割合の目安
+type BudgetRule struct {
+	Key      string
+	Label    string
+	MinRatio float64
+	MaxRatio float64
+}
+
+// BudgetRules は家賃の目安 (手取りの25% / 30% / 33%)
+var BudgetRules = []BudgetRule{
+	{Key: "saving", Label: "貯金重視 (手取りの25%まで)", MinRatio: 0, MaxRatio: 0.25},
+	{Key: "standard", Label: "標準 (手取りの25〜30%)", MinRatio: 0.25, MaxRatio: 0.30},
+	{Key: "stretch", Label: "上限 (手取りの30〜33%)", MinRatio: 0.30, MaxRatio: 0.33},
+}
+
+// TaxBracket は「対象額が UpTo 円以下なら Rate を掛けて Deduction を引く」段階
+// UpTo が 0 の段階は上限なし (最後の段階)
+type TaxBracket struct {
+	UpTo      int     `json:"up_to"`
+	Rate      float64 `json:"rate"`
+	Deduction int     `json:"deduction"`
+}
+
+// IncomeStep は「所得が UpTo 円以下なら Amount 円」の段階 (基礎控除など)
+type IncomeStep struct {
+	UpTo   int `json:"up_to"`
+	Amount int `json:"amount"`
+}
+
+// TaxTable は1年分の社会保険料・所得税・住民税の計算条件 (service/tax_tables/<year>.json)
+// 料率は労使合計ではなく被保険者 (本人) 負担分
+type TaxTable struct {
+	Year string `json:"year"`
+
+	// 健康保険 (協会けんぽの都道府県支部ごと。"0" は一覧にない都道府県)
+	HealthRates        map[string]float64 `json:"health_rates"`
+	NursingRate        float64            `json:"nursing_rate"`          // 介護保険 (40〜64歳)
+	HealthMonthlyCap   int                `json:"health_monthly_cap"`    // 標準報酬月額の上限
+	HealthBonusYearCap int                `json:"health_bonus_year_cap"` // 標準賞与額の年間上限
+	PensionRate        float64            `json:"pension_rate"`
+	PensionMonthlyCap  int                `json:"pension_monthly_cap"`
+	PensionBonusCap    int                `json:"pension_bonus_cap"` // 1回の賞与あたりの上限
+	EmploymentRate     float64            `json:"employment_rate"`
+
+	// 所得税
+	SalaryDeduction    []TaxBracket `json:"salary_deduction"` // 給与所得控除 (定額・加算の段階は負の Deduction)
+	IncomeTaxBasic     []IncomeStep `json:"income_tax_basic"` // 基礎控除 (合計所得で段階)
+	IncomeTaxBrackets  []TaxBracket `json:"income_tax_brackets"`
+	ReconstructionRate float64      `json:"reconstruction_rate"` // 復興特別所得税 (所得税額に対する割合)
+
+	// 住民税
+	ResidentBasic        int     `json:"resident_basic"`         // 基礎控除
+	ResidentRate         float64 `json:"resident_rate"`          // 所得割
+	ResidentPerCapita    int     `json:"resident_per_capita"`    // 均等割 (森林環境税を含む)
+	ResidentAdjustment   int     `json:"resident_adjustment"`    // 調整控除 (課税所得200万円以下)
+	ResidentExemptIncome int     `json:"resident_exempt_income"` // 合計所得がこれ以下なら非課税
+}
+
+// BudgetInput は予算の相談の入力 (金額は円)
+type BudgetInput struct {
+	MonthlySalary  int  // 額面の月給 (諸手当込み、賞与を除く)
+	AnnualBonus    int  // 賞与の年間合計 (年2回支給として計算する)
+	Age            int  // 0 は不明 (介護保険料を含めない)
+	PrefectureCode int  // 健康保険料率の都道府県。0 は全国平均
+	FirstYear      bool // 就職1年目 (前年の所得がないため住民税がかからない)
+	TaxYear        string
+}
+
+// SocialInsurance は社会保険料 (年額、円)
+type SocialInsurance struct {
+	Health     int `json:"health"`
+	Nursing    int `json:"nursing"`
+	Pension    int `json:"pension"`
+	Employment int `json:"employment"`
+	Total      int `json:"total"`
+}
+
+// TakeHomePay は額面から見積もった手取り (円)
+type TakeHomePay struct {
+	AnnualGross     int             `json:"annual_gross"`
+	SocialInsurance SocialInsurance `json:"social_insurance"`
+	IncomeTax       int             `json:"income_tax"`   // 復興特別所得税を含む
+	ResidentTax     int             `json:"resident_tax"` // 1年目は 0
+	AnnualTakeHome  int             `json:"annual_take_home"`
+	// 賞与を除いた毎月の手取り (家賃の目安はこれに対する割合で求める)
+	MonthlyTakeHome int `json:"monthly_take_home"`
+	// 賞与を含めた年間の手取りの12分の1
+	MonthlyAverage int `json:"monthly_average"`
+}
+
+// RentRange は家賃の目安。MinRent / MaxRent は万円で、StationFilter にそのまま使える
+type RentRange struct {
+	Key      string  `json:"key"`
+	Label    string  `json:"label"`
+	MinRatio float64 `json:"min_ratio"`
+	MaxRatio float64 `json:"max_ratio"`
+	MinRent  float64 `json:"min_rent"`
+	MaxRent  float64 `json:"max_rent"`
+}
+
+// BudgetAdvice は手取りの見積もりと家賃の目安
+type BudgetAdvice struct {
+	TaxYear     string      `json:"tax_year"`
+	TakeHome    TakeHomePay `json:"take_home"`
+	RentRanges  []RentRange `json:"rent_ranges"`
+	Recommended string      `json:"recommended"` // 検索条件の初期値に使う RentRange の key
+	Notes       []string    `json:"notes"`
+}
@@ -0,0 +1,29 @@
+package domain
+
+import (
+	"context"
+	"time"
+
+	"github.com/uptrace/bun"
+)
+
+// MaxNearestShelters は駅の詳細に表示する最寄りの避難場所の件数
+const MaxNearestShelters = 3
+
+// StationDisasterAccess は駅から最寄りの避難場所・救急病院までの直線距離
+// pois (避難場所・救急病院) から cmd/import/disaster_facilities が集計する。該当する施設がない場合は null
+type StationDisasterAccess struct {
+	bun.BaseModel `bun:"table:station_disaster_access,alias:sda"`
+
+	StationID                     int64     `bun:"station_id,pk" json:"station_id"`
+	NearestShelterMeter           *float64  `bun:"nearest_shelter_meter" json:"nearest_shelter_meter"`
+	NearestEmergencyHospitalMeter *float64  `bun:"nearest_emergency_hospital_meter" json:"nearest_emergency_hospital_meter"`
+	UpdatedAt                     time.Time `bun:"updated_at,nullzero,notnull,default:current_timestamp" json:"-"`
+}
+
+type DisasterAccessRepository interface {
+	// Refresh は全駅について最寄りの避難場所・救急病院までの距離を pois から集計し直し、更新した駅数を返す
+	Refresh(ctx context.Context) (int64, error)
+	// ListAll は全駅の集計結果を返す (station_id -> 距離)
+	ListAll(ctx context.Context) (map[int64]*StationDisasterAccess, error)
+}
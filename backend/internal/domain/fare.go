@@ -0,0 +1,110 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/uptrace/bun"
+)
+
+// MaxCommuteKm はこれより遠い駅の定期代は求めない (通勤圏外とみなす)
+const MaxCommuteKm = 100
+
+// PassMonths は定期券の有効期間の種類 (月)
+var PassMonths = []int{1, 3, 6}
+
+var ErrInvalidCommute = errors.New("invalid commute query")
+
+// FareBand は事業者ごとの距離帯の運賃と定期代 (円)
+// 距離は営業キロ (1km未満切り上げ) で MinKm 以上 MaxKm 以下の帯に当てはめる
+// 定期代が 0 の期間はその事業者で発売していないものとして扱う
+type FareBand struct {
+	bun.BaseModel    `bun:"table:fare_bands,alias:fb"`
+	OrganizationCode string    `bun:"organization_code,pk" json:"organization_code"`
+	MinKm            int       `bun:"min_km,pk" json:"min_km"`
+	MaxKm            int       `bun:"max_km,notnull" json:"max_km"`
+	Fare             int       `bun:"fare,notnull" json:"fare"` // 片道の普通運賃
+	Pass1M           int       `bun:"pass_1m,notnull" json:"pass_1m"`
+	Pass3M           int       `bun:"pass_3m,notnull" json:"pass_3m"`
+	Pass6M           int       `bun:"pass_6m,notnull" json:"pass_6m"`
+	DataVersion      string    `bun:"data_version,notnull" json:"data_version"`
+	UpdatedAt        time.Time `bun:"updated_at,nullzero,notnull,default:current_timestamp" json:"updated_at"`
+}
+
+// PassPrice は有効期間 months の定期代を返す (発売していない場合は 0)
+func (b *FareBand) PassPrice(months int) int {
+	switch months {
+	case 1:
+		return b.Pass1M
+	case 3:
+		return b.Pass3M
+	case 6:
+		return b.Pass6M
+	}
+	return 0
+}
+
+type FareRepository interface {
+	ListBands(ctx context.Context) ([]*FareBand, error)
+	// ReplaceOperators は bands に含まれる事業者の運賃表を置き換える (他の事業者はそのまま)
+	ReplaceOperators(ctx context.Context, bands []*FareBand) error
+}
+
+// CommutePass は勤務地の駅までの最も安い定期券
+// 駅間の距離は直線距離から求めた概算のため、実際の営業キロとは異なる場合がある
+type CommutePass struct {
+	WorkplaceStationID int64    `json:"workplace_station_id"`
+	DistanceKm         float64  `json:"distance_km"`
+	Operators          []string `json:"operators"` // 乗車する事業者コード (乗車順)
+	Fare               int      `json:"fare"`      // 片道の普通運賃
+	Months             int      `json:"months"`    // 1か月あたりが最も安い有効期間
+	Price              int      `json:"price"`     // その有効期間の定期代
+	Monthly            int      `json:"monthly"`   // 1か月あたりの定期代 (円未満切り上げ)
+}
+
+// CommuteQuery は月額総額の計算条件
+type CommuteQuery struct {
+	WorkplaceStationID int64  // 0 の場合は検索地点の最寄り駅
+	MonthlySubsidy     int    // 家賃補助 (円/月)
+	PassCovered        bool   // 定期代を会社が全額支給する
+	BuildingType       string // 家賃相場の建物種別 (詳細画面用)
+	Layout             string // 家賃相場の間取り (詳細画面用)
+}
+
+// MonthlyCost は家賃 + 定期代 - 家賃補助 の月額総額 (円)
+// 家賃相場や経路がなく求められない項目は null
+type MonthlyCost struct {
+	Rent                  *int         `json:"rent"`
+	Pass                  *int         `json:"pass"`
+	Subsidy               int          `json:"subsidy"` // 差し引いた家賃補助 (家賃を上限とする)
+	Total                 *int         `json:"total"`
+	PassCoveredByEmployer bool         `json:"pass_covered_by_employer"` // true の場合 pass は 0 (会社負担)
+	Commute               *CommutePass `json:"commute,omitempty"`
+}
+
+// NewMonthlyCost は家賃 (万円、0 は不明) と定期券から月額総額を組み立てる
+func NewMonthlyCost(rentManYen float64, pass *CommutePass, q CommuteQuery) *MonthlyCost {
+	mc := &MonthlyCost{Commute: pass, PassCoveredByEmployer: q.PassCovered}
+	if rentManYen > 0 {
+		rent := int(rentManYen*10000 + 0.5)
+		mc.Rent = &rent
+		mc.Subsidy = q.MonthlySubsidy
+		if mc.Subsidy > rent {
+			mc.Subsidy = rent
+		}
+	}
+	switch {
+	case q.PassCovered:
+		zero := 0
+		mc.Pass = &zero
+	case pass != nil:
+		monthly := pass.Monthly
+		mc.Pass = &monthly
+	}
+	if mc.Rent != nil && mc.Pass != nil {
+		total := *mc.Rent + *mc.Pass - mc.Subsidy
+		mc.Total = &total
+	}
+	return mc
+}
@@ -0,0 +1,79 @@
+package domain
+
+// GeoJSON (RFC 7946) の出力用の型
+// 地図ライブラリ (MapLibre など) にそのまま渡せる形で返す
+
+type FeatureCollection struct {
+	Type     string     `json:"type"` // "FeatureCollection"
+	Features []*Feature `json:"features"`
+}
+
+type Feature struct {
+	Type       string                 `json:"type"` // "Feature"
+	ID         interface{}            `json:"id,omitempty"`
+	Geometry   *Geometry              `json:"geometry"`
+	Properties map[string]interface{} `json:"properties"`
+}
+
+type Geometry struct {
+	Type        string      `json:"type"`        // "Point", "Polygon" ...
+	Coordinates interface{} `json:"coordinates"` // Pointなら [lon, lat]
+}
+
+func NewFeatureCollection() *FeatureCollection {
+	return &FeatureCollection{Type: "FeatureCollection", Features: []*Feature{}}
+}
+
+// NewPointFeature は Point のFeatureを作成する
+func NewPointFeature(id interface{}, lon, lat float64, properties map[string]interface{}) *Feature {
+	return &Feature{
+		Type:       "Feature",
+		ID:         id,
+		Geometry:   &Geometry{Type: "Point", Coordinates: []float64{lon, lat}},
+		Properties: properties,
+	}
+}
+
+// NewStationFeatureCollection は検索結果の駅をGeoJSONのPointとして返す
+// properties は通常のJSON応答と同じ項目 (位置と関連データを除く)
+func NewStationFeatureCollection(stations []*Station) *FeatureCollection {
+	fc := NewFeatureCollection()
+	for _, s := range stations {
+		props := map[string]interface{}{
+			"name":              s.Name,
+			"station_code":      s.StationCode,
+			"organization_code": s.OrganizationCode,
+			"line_name":         s.LineName,
+			"prefecture_code":   s.PrefectureCode,
+			"total_score":       s.TotalScore,
+			"is_nearby":         s.IsNearby,
+		}
+		if s.Distance > 0 {
+			props["distance"] = s.Distance
+		}
+		if s.WalkDistance > 0 {
+			props["walk_distance"] = s.WalkDistance
+		}
+		if s.WalkMinutes > 0 {
+			props["walk_minutes"] = s.WalkMinutes
+		}
+		if s.RentAvg > 0 {
+			props["rent_avg"] = s.RentAvg
+		}
+		if len(s.ScoreDetails) > 0 {
+			props["score_details"] = s.ScoreDetails
+		}
+		if len(s.Tags) > 0 {
+			props["tags"] = s.Tags
+		}
+		if s.SourceStation != "" {
+			props["source_station"] = s.SourceStation
+			props["stops_from_source"] = s.StopsFromSource
+		}
+		if s.MonthlyCost != nil {
+			props["monthly_cost"] = s.MonthlyCost
+		}
+		fc.Features = append(fc.Features, NewPointFeature(s.ID, s.Lon, s.Lat, props))
+	}
+	return fc
+}
@@ -0,0 +1,165 @@
+package domain
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/uptrace/bun"
+)
+
+// グリッドセルの形
+const (
+	GridShapeHex    = "hex"
+	GridShapeSquare = "square"
+)
+
+// DefaultGrid はAPIで grid 未指定の場合に使うグリッド
+const DefaultGrid = "hex_1000"
+
+// グリッドの指標
+const (
+	GridMetricFacilityDensity   = "facility_density"    // 生活施設 (生活レイヤーのPOI) の密度 (件/km²)
+	GridMetricStreetLampDensity = "street_lamp_density" // 街灯の密度 (本/km²)
+	GridMetricPoliceDistance    = "police_distance"     // セルの中心から最寄りの交番・警察署までの距離 (m)
+	GridMetricHazardLevel       = "hazard_level"        // 周辺駅の災害リスクレベル (0-3) の補間値
+	GridMetricSafetyScore       = "safety_score"        // 周辺駅の治安スコア (0-100) の補間値
+)
+
+// GridPOIDensityMetrics はセル内のPOIを数えて計算する指標と対象のカテゴリ
+func GridPOIDensityMetrics() map[string][]string {
+	var life []string
+	for _, c := range POICategories {
+		if POICategoryLayer(c) == POILayerLife {
+			life = append(life, c)
+		}
+	}
+	return map[string][]string{
+		GridMetricFacilityDensity:   life,
+		GridMetricStreetLampDensity: {POICategoryStreetLamp},
+	}
+}
+
+// GridPOIDistanceMetrics はセルの中心から最寄りのPOIまでの距離で計算する指標と対象のカテゴリ
+func GridPOIDistanceMetrics() map[string][]string {
+	return map[string][]string{
+		GridMetricPoliceDistance: {POICategoryPolice},
+	}
+}
+
+// GridRentMetric は間取りごとの補間家賃 (万円) の指標名を返す
+func GridRentMetric(layout string) string {
+	return "rent_" + layout
+}
+
+// GridMetrics は全指標の名前を返す
+func GridMetrics() []string {
+	metrics := []string{GridMetricFacilityDensity, GridMetricStreetLampDensity, GridMetricPoliceDistance, GridMetricHazardLevel, GridMetricSafetyScore}
+	for _, layout := range Layouts {
+		metrics = append(metrics, GridRentMetric(layout))
+	}
+	return metrics
+}
+
+// GridSpec はグリッドの形と大きさ
+type GridSpec struct {
+	Shape     string
+	SizeMeter int // 六角形は中心から頂点まで、正方形は一辺の長さ
+}
+
+// Name は grid_cells.grid に保存する名前 ("hex_1000" など) を返す
+func (s GridSpec) Name() string {
+	return fmt.Sprintf("%s_%d", s.Shape, s.SizeMeter)
+}
+
+// ParseGridSpec は "hex_1000" の形式のグリッド名を解釈する
+func ParseGridSpec(name string) (GridSpec, error) {
+	shape, size, ok := strings.Cut(name, "_")
+	if !ok || (shape != GridShapeHex && shape != GridShapeSquare) {
+		return GridSpec{}, fmt.Errorf("invalid grid %q: must be hex_<meters> or square_<meters>", name)
+	}
+	n, err := strconv.Atoi(size)
+	if err != nil || n <= 0 {
+		return GridSpec{}, fmt.Errorf("invalid grid %q: size must be a positive integer", name)
+	}
+	return GridSpec{Shape: shape, SizeMeter: n}, nil
+}
+
+// BBox は経緯度の矩形範囲
+type BBox struct {
+	MinLon, MinLat, MaxLon, MaxLat float64
+}
+
+// ParseBBox は "minLon,minLat,maxLon,maxLat" の形式の範囲を解釈する
+func ParseBBox(s string) (BBox, error) {
+	parts := strings.Split(s, ",")
+	if len(parts) != 4 {
+		return BBox{}, fmt.Errorf("bbox must be minLon,minLat,maxLon,maxLat")
+	}
+	var v [4]float64
+	for i, p := range parts {
+		f, err := strconv.ParseFloat(strings.TrimSpace(p), 64)
+		if err != nil {
+			return BBox{}, fmt.Errorf("bbox must be minLon,minLat,maxLon,maxLat")
+		}
+		v[i] = f
+	}
+	b := BBox{MinLon: v[0], MinLat: v[1], MaxLon: v[2], MaxLat: v[3]}
+	if b.MinLon >= b.MaxLon || b.MinLat >= b.MaxLat ||
+		b.MinLon < -180 || b.MaxLon > 180 || b.MinLat < -90 || b.MaxLat > 90 {
+		return BBox{}, fmt.Errorf("bbox is out of range or empty")
+	}
+	return b, nil
+}
+
+type GridCell struct {
+	bun.BaseModel `bun:"table:grid_cells,alias:gc"`
+
+	ID        int64     `bun:"id,pk,autoincrement" json:"id"`
+	Grid      string    `bun:"grid,notnull" json:"grid"`
+	Shape     string    `bun:"shape,notnull" json:"shape"`
+	SizeMeter int       `bun:"size_m,notnull" json:"size_m"`
+	I         int       `bun:"i,notnull" json:"i"`
+	J         int       `bun:"j,notnull" json:"j"`
+	Geom      string    `bun:"geom,type:geometry(POLYGON,4326)" json:"-"`
+	Centroid  string    `bun:"centroid,type:geography(POINT,4326)" json:"-"`
+	CreatedAt time.Time `bun:"created_at,nullzero,notnull,default:current_timestamp" json:"-"`
+
+	Lat float64 `bun:"lat,scanonly" json:"lat"` // 重心
+	Lon float64 `bun:"lon,scanonly" json:"lon"`
+}
+
+type GridCellMetric struct {
+	bun.BaseModel `bun:"table:grid_cell_metrics,alias:gcm"`
+
+	CellID      int64     `bun:"cell_id,pk" json:"cell_id"`
+	Metric      string    `bun:"metric,pk" json:"metric"`
+	Value       float64   `bun:"value,notnull" json:"value"`
+	DataVersion string    `bun:"data_version,notnull" json:"data_version"`
+	UpdatedAt   time.Time `bun:"updated_at,nullzero,notnull,default:current_timestamp" json:"updated_at"`
+}
+
+// GridSample はセルの値を補間するための、駅ごとの観測値
+type GridSample struct {
+	StationID int64
+	Lat, Lon  float64
+	Values    map[string]float64 // 指標名 -> 値 (データのない指標は含まない)
+}
+
+type GridRepository interface {
+	// CreateCells は駅の周辺 stationRadiusMeter 以内にかかるセルを作成し、新しく作成した数を返す
+	CreateCells(ctx context.Context, spec GridSpec, stationRadiusMeter int) (int64, error)
+	ListCells(ctx context.Context, grid string) ([]*GridCell, error)
+	// StationSamples は補間元となる駅ごとの家賃・災害リスク・治安スコアを返す
+	StationSamples(ctx context.Context) ([]*GridSample, error)
+	// RefreshPOIDensity はセル内の categories のPOIを数えて密度 (件/km²) を metric に保存する
+	// 対象のPOIが1件もない場合は、データなしとして何も保存しない
+	RefreshPOIDensity(ctx context.Context, grid, metric string, categories []string, version string) (int64, error)
+	// RefreshPOIDistance はセルの中心から最寄りの categories のPOIまでの距離 (m) を metric に保存する
+	RefreshPOIDistance(ctx context.Context, grid, metric string, categories []string, version string) (int64, error)
+	UpsertMetrics(ctx context.Context, metrics []*GridCellMetric) error
+	// GetFeatures は範囲内のセルを指標の値を持つGeoJSONのPolygonとして返す (最大 limit 件)
+	GetFeatures(ctx context.Context, grid string, bbox BBox, metric string, limit int) ([]*Feature, error)
+}
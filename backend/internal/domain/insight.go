@@ -0,0 +1,105 @@
+package domain
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+	"unicode/utf8"
+
+	"github.com/uptrace/bun"
+)
+
+// 生成した解説文の上限 (LLMの出力検証にも使う)
+const (
+	MaxInsightItems      = 5   // pros / cons の最大件数
+	MaxInsightItemLength = 80  // pros / cons 1件の最大文字数
+	MaxInsightTrendChars = 200 // trend の最大文字数
+)
+
+var (
+	// ErrInvalidInsight は生成した解説文が形式・長さの制約を満たさない場合のエラー
+	ErrInvalidInsight = errors.New("invalid insight")
+	// ErrInsufficientInsightData は材料となるデータがなく解説文を作れない場合のエラー
+	ErrInsufficientInsightData = errors.New("insufficient data for insight")
+)
+
+// InsightInput は駅の解説文を生成する材料 (実データのみ)
+// Hash が前回生成時と変わった駅だけを再生成する
+type InsightInput struct {
+	StationID  int64              `json:"station_id"`
+	LineKey    LineKey            `json:"-"`
+	Name       string             `json:"name"`
+	Lines      []string           `json:"lines"`
+	Tags       []string           `json:"tags,omitempty"`
+	Scores     map[string]float64 `json:"scores,omitempty"`      // 軸 -> 正規化スコア (0-100)
+	Rents      map[string]float64 `json:"rents,omitempty"`       // 間取り -> 家賃相場 (万円、建物種別平均)
+	LineMedian float64            `json:"line_median,omitempty"` // 同じ路線の 1R〜1DK 家賃相場の中央値
+	Facilities map[string]int     `json:"facilities,omitempty"`  // POIカテゴリ -> 徒歩圏の件数
+	Hazards    map[string]int     `json:"hazards,omitempty"`     // flood / landslide / earthquake -> 0:なし〜3:危険
+	Terrain    *StationTerrain    `json:"terrain,omitempty"`     // 駅周辺の坂・標高
+}
+
+// Hash は入力の内容から決まるハッシュ (map はキー順にエンコードされるため安定)
+func (in *InsightInput) Hash() string {
+	b, _ := json.Marshal(in)
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:])
+}
+
+// HasData は駅名・路線以外の材料 (家賃・スコア・施設・災害リスク・地形) があるかどうかを返す
+// 材料のない駅は ErrInsufficientInsightData として生成しない (推測で文章を作らない)
+func (in *InsightInput) HasData() bool {
+	return len(in.Rents) > 0 || len(in.Scores) > 0 || len(in.Facilities) > 0 || len(in.Hazards) > 0 || in.Terrain != nil
+}
+
+// InsightGenerator は駅の解説文の生成手段 (テンプレート、LLM など)
+type InsightGenerator interface {
+	// Name は生成手段の識別子。変わった場合も再生成の対象になる
+	Name() string
+	Generate(ctx context.Context, in *InsightInput) (*AIInsight, error)
+}
+
+// ValidateInsight は生成結果の件数・文字数を検証する
+func ValidateInsight(in *AIInsight) error {
+	if len(in.Summary.Pros) == 0 && len(in.Summary.Cons) == 0 {
+		return fmt.Errorf("%w: pros and cons are empty", ErrInvalidInsight)
+	}
+	for name, items := range map[string][]string{"pros": in.Summary.Pros, "cons": in.Summary.Cons} {
+		if len(items) > MaxInsightItems {
+			return fmt.Errorf("%w: too many %s (%d)", ErrInvalidInsight, name, len(items))
+		}
+		for _, item := range items {
+			if n := utf8.RuneCountInString(item); n == 0 || n > MaxInsightItemLength {
+				return fmt.Errorf("%w: %s item must be 1-%d characters", ErrInvalidInsight, name, MaxInsightItemLength)
+			}
+		}
+	}
+	if utf8.RuneCountInString(in.Trend) > MaxInsightTrendChars {
+		return fmt.Errorf("%w: trend must be at most %d characters", ErrInvalidInsight, MaxInsightTrendChars)
+	}
+	return nil
+}
+
+// StationInsight は生成済みの駅の解説文
+type StationInsight struct {
+	bun.BaseModel `bun:"table:station_insights,alias:si"`
+	StationID     int64     `bun:"station_id,pk" json:"station_id"`
+	Insight       AIInsight `bun:"insight,type:jsonb,notnull" json:"insight"`
+	InputHash     string    `bun:"input_hash,notnull" json:"-"`
+	Generator     string    `bun:"generator,notnull" json:"generator"`
+	GeneratedAt   time.Time `bun:"generated_at,notnull" json:"generated_at"`
+}
+
+type InsightRepository interface {
+	// ListInputs は全駅の生成材料を返す
+	ListInputs(ctx context.Context) ([]*InsightInput, error)
+	// ListStates は station_id -> 前回生成時の (入力ハッシュ, 生成手段) を返す
+	ListStates(ctx context.Context) (map[int64]StationInsight, error)
+	Upsert(ctx context.Context, insight *StationInsight) error
+	// Get は生成済みの解説文を返す。未生成なら sql.ErrNoRows
+	Get(ctx context.Context, stationID int64) (*StationInsight, error)
+}
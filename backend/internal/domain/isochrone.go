@@ -0,0 +1,36 @@
+package domain
+
+import "context"
+
+const (
+	// MaxIsochroneMinutes は等時間圏の時間帯の上限 (分)
+	MaxIsochroneMinutes = 120
+	// MaxIsochroneBands は1回の要求で計算する時間帯の数の上限
+	MaxIsochroneBands = 4
+	// MaxWalkBufferMeter は駅から徒歩で広げる範囲の上限
+	// 残り時間が長くても駅から遠い場所は (バスなど別の手段を使うため) 含めない
+	MaxWalkBufferMeter = 1200
+)
+
+// DefaultIsochroneBands は時間帯を指定しない場合の既定値 (分)
+var DefaultIsochroneBands = []int{30, 45, 60}
+
+// WalkCircle は地点から徒歩で到達できる範囲を表す円
+type WalkCircle struct {
+	Lat, Lon    float64
+	RadiusMeter float64
+}
+
+// WalkRadiusMeter は残り時間 (分) で歩ける直線距離 (m) を返す (EstimateWalkMinutes の逆算)
+func WalkRadiusMeter(minutes float64) float64 {
+	r := minutes * WalkMetersPerMinute / WalkDetourFactor
+	if r > MaxWalkBufferMeter {
+		return MaxWalkBufferMeter
+	}
+	return r
+}
+
+type IsochroneRepository interface {
+	// UnionCircles は円を結合した (Multi)Polygon を返す。円がなければ nil
+	UnionCircles(ctx context.Context, circles []WalkCircle) (*Geometry, error)
+}
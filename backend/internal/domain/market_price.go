@@ -6,6 +6,9 @@ import (
 	"github.com/uptrace/bun"
 )
 
+// Layouts は家賃相場の間取り区分
+var Layouts = []string{"1r_1k_1dk", "1ldk_2k_2dk", "2ldk_3k_3dk", "3ldk_4k", "4ldk"}
+
 type MarketPrice struct {
 	bun.BaseModel `bun:"table:market_prices,alias:mp"`
 	ID            int64     `bun:"id,pk,autoincrement" json:"id"`
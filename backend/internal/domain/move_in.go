@@ -0,0 +1,98 @@
+package domain
+
+import "errors"
+
+// 比較できる駅の数
+const (
+	MinCompareStations = 2
+	MaxCompareStations = 5
+)
+
+var (
+	ErrInvalidMoveIn  = errors.New("building_type and layout are required for move-in estimate")
+	ErrInvalidCompare = errors.New("invalid station comparison")
+)
+
+// 初期費用の内訳の項目
+const (
+	MoveInDeposit       = "deposit"        // 敷金
+	MoveInKeyMoney      = "key_money"      // 礼金
+	MoveInBrokerage     = "brokerage"      // 仲介手数料
+	MoveInGuarantee     = "guarantee"      // 保証会社の初回保証料
+	MoveInAdvanceRent   = "advance_rent"   // 前家賃
+	MoveInFireInsurance = "fire_insurance" // 火災保険
+	MoveInKeyExchange   = "key_exchange"   // 鍵交換
+	MoveInMoving        = "moving"         // 引越し業者
+)
+
+// MoveInCustom は都道府県ごとの賃貸契約の慣習 (家賃の何か月分か)
+// PrefectureCode が 0 のものは一覧にない都道府県に使う既定値
+type MoveInCustom struct {
+	PrefectureCode    int     `json:"prefecture_code"`
+	DepositMonths     float64 `json:"deposit_months"`
+	KeyMoneyMonths    float64 `json:"key_money_months"`
+	BrokerageMonths   float64 `json:"brokerage_months"`    // 税抜 (法定上限は1か月分)
+	GuaranteeRatio    float64 `json:"guarantee_ratio"`     // 月額家賃に対する初回保証料の割合
+	AdvanceRentMonths float64 `json:"advance_rent_months"` // 前家賃
+	FireInsurance     int     `json:"fire_insurance"`      // 円
+	KeyExchange       int     `json:"key_exchange"`        // 円
+}
+
+// MovingRate は間取りごとの引越し料金 (円)
+// IncludedKm までは基本料金のみ、超えた分は1kmごとに PerKm を加える
+type MovingRate struct {
+	Base  int `json:"base"`
+	PerKm int `json:"per_km"`
+}
+
+// MovingCostModel は距離制の引越し料金の見積もり条件
+type MovingCostModel struct {
+	Rates      map[string]MovingRate `json:"rates"`       // 間取り -> 料金
+	IncludedKm float64               `json:"included_km"` // 基本料金に含まれる距離
+	RoadFactor float64               `json:"road_factor"` // 直線距離から道のりへの係数
+}
+
+// MoveInConfig は初期費用の見積もり設定 (service/move_in_costs.json または MOVE_IN_COSTS_FILE)
+type MoveInConfig struct {
+	TaxRate float64         `json:"tax_rate"` // 仲介手数料にかかる消費税率
+	Customs []MoveInCustom  `json:"customs"`
+	Moving  MovingCostModel `json:"moving"`
+}
+
+// MoveInQuery は初期費用の見積もり条件
+// 引越し元 (現住所) の座標がない場合は引越し業者の費用を含めない
+type MoveInQuery struct {
+	BuildingType string
+	Layout       string
+	HasOrigin    bool
+	FromLat      float64
+	FromLon      float64
+}
+
+// MoveInItem は初期費用の内訳 (円)
+type MoveInItem struct {
+	Key        string `json:"key"`
+	Label      string `json:"label"`
+	Amount     int    `json:"amount"`
+	Basis      string `json:"basis"`                // 計算の根拠 (例: 家賃の1か月分)
+	Refundable bool   `json:"refundable,omitempty"` // 退去時に (一部) 返還される
+}
+
+// MoveInEstimate は駅の家賃相場から見積もった初期費用 (円)
+type MoveInEstimate struct {
+	Rent             int          `json:"rent"`
+	PrefectureCode   int          `json:"prefecture_code"`
+	Items            []MoveInItem `json:"items"`
+	Total            int          `json:"total"`
+	MovingDistanceKm float64      `json:"moving_distance_km,omitempty"` // 引越しの道のり (概算)
+}
+
+// CompareQuery は駅の比較条件
+// Commute は勤務地の駅の指定がある場合、MoveIn は move_in=true の場合のみ
+type CompareQuery struct {
+	Weights      map[string]int
+	BuildingType string
+	Layout       string
+	Commute      *CommuteQuery
+	MoveIn       *MoveInQuery
+}
@@ -0,0 +1,87 @@
+package domain
+
+import (
+	"context"
+	"math"
+	"time"
+
+	"github.com/uptrace/bun"
+)
+
+// NightSafetyScoreAxis は夜間の安全性 (街灯の多さ・交番の近さ) の軸
+// safety 軸に含まれる夜間の成分を単独で示す。cmd/import/osm_pois・cmd/import/koban が station_scores に保存する
+const NightSafetyScoreAxis = "night_safety"
+
+// 夜間の安全性の基準: 街灯の密度がこの値以上、交番・警察署がこの距離以内でそれぞれの成分が満点になる
+const (
+	StreetLampEnoughDensity = 300.0  // 本/km² (0本で0点)
+	PoliceNearMeter         = 300.0  // m (徒歩で数分)
+	PoliceFarMeter          = 1500.0 // m (これ以上で0点)
+)
+
+// DefaultStreetLampRadiusMeter は街灯の密度を数える駅からの半径 (駅から住宅街へ帰る道の範囲)
+const DefaultStreetLampRadiusMeter = 500
+
+// StationNightSafety は夜間の安全性の指標 (街灯の多さ・交番の近さ)
+// pois (街灯・交番・警察署) から集計する。データを取り込んでいない指標は null
+type StationNightSafety struct {
+	bun.BaseModel `bun:"table:station_night_safety,alias:sns"`
+
+	StationID int64 `bun:"station_id,pk" json:"-"`
+	// 駅から RadiusMeter 以内の街灯の密度 (本/km²)
+	StreetLampDensity  *float64  `bun:"street_lamp_density" json:"street_lamp_density"`
+	NearestPoliceMeter *float64  `bun:"nearest_police_meter" json:"nearest_police_meter"` // 最寄りの交番・警察署 (m)
+	RadiusMeter        int       `bun:"radius_meter,notnull" json:"radius"`
+	UpdatedAt          time.Time `bun:"updated_at,nullzero,notnull,default:current_timestamp" json:"-"`
+}
+
+// LampLevel は街灯の多さ (0-1)。街灯を取り込んでいない場合は ok=false
+func (n *StationNightSafety) LampLevel() (float64, bool) {
+	if n == nil || n.StreetLampDensity == nil {
+		return 0, false
+	}
+	return math.Min(1, math.Max(0, *n.StreetLampDensity/StreetLampEnoughDensity)), true
+}
+
+// PoliceLevel は交番・警察署の近さ (0-1)。交番を取り込んでいない場合は ok=false
+func (n *StationNightSafety) PoliceLevel() (float64, bool) {
+	if n == nil || n.NearestPoliceMeter == nil {
+		return 0, false
+	}
+	d := *n.NearestPoliceMeter
+	switch {
+	case d <= PoliceNearMeter:
+		return 1, true
+	case d >= PoliceFarMeter:
+		return 0, true
+	}
+	return (PoliceFarMeter - d) / (PoliceFarMeter - PoliceNearMeter), true
+}
+
+// Score は夜間の安全性のスコア (0-100)
+// 街灯の多さと交番の近さの平均で、取り込んでいない指標は除く。どちらもなければ ok=false
+// safety 軸の夜間の減点と同じ成分を使う
+func (n *StationNightSafety) Score() (float64, bool) {
+	sum, count := 0.0, 0
+	if lamp, ok := n.LampLevel(); ok {
+		sum += lamp
+		count++
+	}
+	if police, ok := n.PoliceLevel(); ok {
+		sum += police
+		count++
+	}
+	if count == 0 {
+		return 0, false
+	}
+	return math.Round(1000*sum/float64(count)) / 10, true
+}
+
+type NightSafetyRepository interface {
+	// Refresh は全駅について街灯の密度 (半径 radiusMeter) と最寄りの交番までの距離を pois から集計し直し、更新した駅数を返す
+	Refresh(ctx context.Context, radiusMeter int) (int64, error)
+	// Get は駅の集計結果を返す。未集計の場合は sql.ErrNoRows
+	Get(ctx context.Context, stationID int64) (*StationNightSafety, error)
+	// ListAll は全駅の集計結果を返す (station_id -> 指標)
+	ListAll(ctx context.Context) (map[int64]*StationNightSafety, error)
+}
@@ -0,0 +1,34 @@
+package domain
+
+import (
+	"context"
+	"time"
+
+	"github.com/uptrace/bun"
+)
+
+// BustleScoreAxis は乗降客数から作る「にぎわい」の軸 (cmd/import/passengers が station_scores に保存する)
+// 重みを負にすると静かな駅ほど高いスコアになる
+const BustleScoreAxis = "bustle"
+
+// StationPassengers は駅の1日あたりの乗降客数 (国土数値情報 駅別乗降客数 S12 から取り込む)
+// station_details の description は別の用途のため、このモデルでは扱わない
+type StationPassengers struct {
+	bun.BaseModel `bun:"table:station_details,alias:sd"`
+
+	StationID       int64     `bun:"station_id,pk" json:"station_id"`
+	DailyPassengers *int      `bun:"daily_passengers" json:"daily_passengers"`
+	PassengersYear  int       `bun:"passengers_year,nullzero" json:"passengers_year,omitempty"` // 集計年度
+	DataVersion     string    `bun:"passengers_version,nullzero" json:"-"`
+	CreatedAt       time.Time `bun:"created_at,nullzero,default:current_timestamp" json:"-"`
+	UpdatedAt       time.Time `bun:"updated_at,nullzero,default:current_timestamp" json:"-"`
+}
+
+type PassengerRepository interface {
+	// Upsert は乗降客数を保存する (description など他のカラムは変更しない)
+	Upsert(ctx context.Context, rows []*StationPassengers) error
+	// ListAll は乗降客数のある全駅を返す (station_id -> 乗降客数)
+	ListAll(ctx context.Context) (map[int64]int, error)
+	// Get は駅の乗降客数を返す。未登録の場合は sql.ErrNoRows
+	Get(ctx context.Context, stationID int64) (*StationPassengers, error)
+}
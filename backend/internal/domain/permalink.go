@@ -0,0 +1,46 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/uptrace/bun"
+)
+
+const (
+	// PermalinkCodeLength は共有リンクのコードの長さ (base62、約47ビット)
+	PermalinkCodeLength = 8
+	// DefaultPermalinkTTL は有効期限を指定しない共有リンクの有効期間
+	DefaultPermalinkTTL = 30 * 24 * time.Hour
+	// MaxPermalinkTTL は共有リンクの有効期間の上限
+	MaxPermalinkTTL = 365 * 24 * time.Hour
+)
+
+// ErrPermalinkExpired は有効期限を過ぎた共有リンクを開いた場合のエラー
+var ErrPermalinkExpired = errors.New("permalink expired")
+
+// SearchPermalink は共有リンクに保存した検索条件
+type SearchPermalink struct {
+	bun.BaseModel `bun:"table:search_permalinks,alias:pl"`
+	Code          string        `bun:"code,pk" json:"code"`
+	Lat           float64       `bun:"lat,notnull" json:"lat"`
+	Lon           float64       `bun:"lon,notnull" json:"lon"`
+	Filter        StationFilter `bun:"filter,type:jsonb,notnull" json:"filter"`
+	ViewCount     int           `bun:"view_count,notnull" json:"view_count"`
+	ExpiresAt     time.Time     `bun:"expires_at,notnull" json:"expires_at"`
+	LastViewedAt  *time.Time    `bun:"last_viewed_at" json:"last_viewed_at,omitempty"`
+	CreatedAt     time.Time     `bun:"created_at,nullzero,notnull,default:current_timestamp" json:"created_at"`
+
+	URL string `bun:"-" json:"url,omitempty"` // 共有用のフロントエンドのURL
+}
+
+// PermalinkRepository は共有リンクの保存先
+type PermalinkRepository interface {
+	// Create はコードが既に使われている場合 sql.ErrNoRows を返す (呼び出し側で別のコードを生成し直す)
+	Create(ctx context.Context, link *SearchPermalink) error
+	// View は有効期限内のリンクの閲覧数を1増やして返す。存在しなければ sql.ErrNoRows、期限切れなら ErrPermalinkExpired
+	View(ctx context.Context, code string, now time.Time) (*SearchPermalink, error)
+	// DeleteExpired は before より前に期限が切れたリンクを削除し、削除した件数を返す
+	DeleteExpired(ctx context.Context, before time.Time) (int, error)
+}
@@ -0,0 +1,233 @@
+package domain
+
+import (
+	"context"
+	"math"
+	"time"
+
+	"github.com/uptrace/bun"
+)
+
+// POIのカテゴリ
+const (
+	POICategorySupermarket = "supermarket"
+	POICategoryConvenience = "convenience"
+	POICategoryHospital    = "hospital"
+	POICategoryDrugstore   = "drugstore"
+	POICategoryRestaurant  = "restaurant"
+	POICategoryGym         = "gym"
+	POICategoryPark        = "park"
+	POICategoryUniversity  = "university"  // 大学・短大・専門学校
+	POICategoryShelter     = "shelter"     // 避難場所
+	POICategoryPolice      = "police"      // 交番・警察署
+	POICategoryStreetLamp  = "street_lamp" // 街灯
+	// POICategoryEmergencyHospital は救急告示病院・災害拠点病院 (国土数値情報 P04 から取り込む)
+	POICategoryEmergencyHospital = "emergency_hospital"
+)
+
+// 地図レイヤー
+const (
+	POILayerLife     = "life"     // 生活
+	POILayerDisaster = "disaster" // 防災
+	POILayerSafety   = "safety"   // 治安
+)
+
+// POICategories は全カテゴリを表示順に並べたもの
+var POICategories = []string{
+	POICategorySupermarket,
+	POICategoryConvenience,
+	POICategoryHospital,
+	POICategoryDrugstore,
+	POICategoryRestaurant,
+	POICategoryGym,
+	POICategoryPark,
+	POICategoryUniversity,
+	POICategoryShelter,
+	POICategoryEmergencyHospital,
+	POICategoryPolice,
+	POICategoryStreetLamp,
+}
+
+// POICategoryLayer はカテゴリが属する地図レイヤーを返す
+func POICategoryLayer(category string) string {
+	switch category {
+	case POICategoryShelter, POICategoryEmergencyHospital:
+		return POILayerDisaster
+	case POICategoryPolice, POICategoryStreetLamp:
+		return POILayerSafety
+	default:
+		return POILayerLife
+	}
+}
+
+// 徒歩時間の推定
+// 道路距離は直線距離の約1.3倍とし、不動産の表示規約と同じく80mを1分 (端数切り上げ) とする
+const (
+	WalkDetourFactor    = 1.3
+	WalkMetersPerMinute = 80.0
+)
+
+// EstimateWalkMinutes は直線距離 (m) から徒歩分数を推定する
+func EstimateWalkMinutes(distanceMeter float64) int {
+	return WalkMinutes(EstimateWalkMeters(distanceMeter))
+}
+
+// EstimateWalkMeters は直線距離 (m) から道路距離を推定する (徒歩ネットワークで経路が求まらない場合に使う)
+func EstimateWalkMeters(distanceMeter float64) float64 {
+	return distanceMeter * WalkDetourFactor
+}
+
+// WalkMinutes は道路距離 (m) を徒歩分数にする
+func WalkMinutes(walkMeter float64) int {
+	if walkMeter <= 0 {
+		return 0
+	}
+	return int(math.Ceil(walkMeter / WalkMetersPerMinute))
+}
+
+// POI は駅周辺の個別施設
+type POI struct {
+	bun.BaseModel `bun:"table:pois,alias:p"`
+
+	ID        int64             `bun:"id,pk,autoincrement" json:"id"`
+	Source    string            `bun:"source,notnull" json:"source"`
+	SourceID  string            `bun:"source_id,notnull" json:"source_id"`
+	Category  string            `bun:"category,notnull" json:"category"`
+	Name      string            `bun:"name" json:"name"`
+	Location  string            `bun:"location,type:geography(POINT,4326)" json:"-"` // PostGIS Point (WKT)
+	Tags      map[string]string `bun:"tags,type:jsonb" json:"tags,omitempty"`
+	UpdatedAt time.Time         `bun:"updated_at,nullzero,notnull,default:current_timestamp" json:"-"`
+
+	Lat         float64 `bun:"lat,scanonly" json:"lat"`
+	Lon         float64 `bun:"lon,scanonly" json:"lon"`
+	Distance    float64 `bun:"distance,scanonly" json:"distance"` // 検索時の駅からの直線距離(m)
+	WalkMinutes int     `bun:"-" json:"walk_minutes"`             // 徒歩分数 (徒歩ネットワークがない場合は直線距離からの推定)
+}
+
+// POIFilter は駅周辺のPOI検索条件
+type POIFilter struct {
+	RadiusMeter int
+	Categories  []string // 空なら全カテゴリ
+	Limit       int      // カテゴリごとの最大件数 (近い順)
+}
+
+// POIGroup は1カテゴリ分のPOI (近い順)
+type POIGroup struct {
+	Category string `json:"category"`
+	Layer    string `json:"layer"`
+	Count    int    `json:"count"`
+	POIs     []*POI `json:"pois"`
+}
+
+// StationPOIs は駅周辺のPOI一覧 (/api/stations/:id/pois)
+type StationPOIs struct {
+	StationID   int64       `json:"station_id"`
+	RadiusMeter int         `json:"radius"`
+	Groups      []*POIGroup `json:"groups"`
+}
+
+// FeatureCollection はPOIをGeoJSONのPointとして返す (地図レイヤー用)
+func (sp *StationPOIs) FeatureCollection() *FeatureCollection {
+	fc := NewFeatureCollection()
+	for _, g := range sp.Groups {
+		for _, p := range g.POIs {
+			fc.Features = append(fc.Features, NewPointFeature(p.ID, p.Lon, p.Lat, map[string]interface{}{
+				"name":         p.Name,
+				"category":     p.Category,
+				"layer":        g.Layer,
+				"distance":     p.Distance,
+				"walk_minutes": p.WalkMinutes,
+			}))
+		}
+	}
+	return fc
+}
+
+// Facility は駅から徒歩圏内の施設数 (pois からの集計結果)
+type Facility struct {
+	bun.BaseModel `bun:"table:facilities,alias:f"`
+
+	ID                     int64     `bun:"id,pk,autoincrement" json:"-"`
+	StationID              int64     `bun:"station_id,unique" json:"station_id"`
+	SupermarketsCount      int       `bun:"supermarkets_count" json:"supermarkets_count"`
+	ConvenienceStoresCount int       `bun:"convenience_stores_count" json:"convenience_stores_count"`
+	HospitalsCount         int       `bun:"hospitals_count" json:"hospitals_count"`
+	DrugstoresCount        int       `bun:"drugstores_count" json:"drugstores_count"`
+	RestaurantsCount       int       `bun:"restaurants_count" json:"restaurants_count"`
+	GymsCount              int       `bun:"gyms_count" json:"gyms_count"`
+	ParksCount             int       `bun:"parks_count" json:"parks_count"`
+	UpdatedAt              time.Time `bun:"updated_at,nullzero,notnull,default:current_timestamp" json:"updated_at"`
+}
+
+// poiRule はOSMタグ (key=value) とカテゴリの対応
+type poiRule struct {
+	key, value, category string
+}
+
+// poiRules は上から順に評価し、最初に一致したカテゴリを採用する
+var poiRules = []poiRule{
+	{"shop", "supermarket", POICategorySupermarket},
+	{"shop", "convenience", POICategoryConvenience},
+	{"amenity", "hospital", POICategoryHospital},
+	{"shop", "chemist", POICategoryDrugstore},
+	{"amenity", "pharmacy", POICategoryDrugstore},
+	{"amenity", "restaurant", POICategoryRestaurant},
+	{"leisure", "fitness_centre", POICategoryGym},
+	{"leisure", "park", POICategoryPark},
+	{"amenity", "university", POICategoryUniversity},
+	{"amenity", "college", POICategoryUniversity},
+	{"emergency", "assembly_point", POICategoryShelter},
+	{"amenity", "police", POICategoryPolice},
+}
+
+// poiNodeOnlyRules はノードだけを対象にするカテゴリ (poiRules に一致しない場合に評価する)
+// highway のように対象外の値を持つ要素が非常に多いキーのため、事前フィルタでも値まで確認する
+var poiNodeOnlyRules = []poiRule{
+	{"highway", "street_lamp", POICategoryStreetLamp},
+}
+
+// ClassifyPOI はOSMタグからPOIカテゴリを判定する。対象外なら ok=false
+func ClassifyPOI(tags map[string]string) (category string, ok bool) {
+	for _, rules := range [][]poiRule{poiRules, poiNodeOnlyRules} {
+		for _, r := range rules {
+			if tags[r.key] == r.value {
+				return r.category, true
+			}
+		}
+	}
+	return "", false
+}
+
+// IsPOITagKey はカテゴリ判定に使うタグのキーかどうかを返す (取り込み時の高速な事前フィルタ用)
+// ノードだけのカテゴリのキーは含めない (IsNodeOnlyPOITag で判定する)
+func IsPOITagKey(key string) bool {
+	for _, r := range poiRules {
+		if r.key == key {
+			return true
+		}
+	}
+	return false
+}
+
+// IsNodeOnlyPOITag はノードだけのカテゴリ (街灯) のタグかどうかを返す (ノードの事前フィルタ用)
+func IsNodeOnlyPOITag(key, value string) bool {
+	for _, r := range poiNodeOnlyRules {
+		if r.key == key && r.value == value {
+			return true
+		}
+	}
+	return false
+}
+
+type POIRepository interface {
+	// GetNearStation は駅から半径内のPOIをカテゴリごとに近い順で最大 filter.Limit 件ずつ返す
+	GetNearStation(ctx context.Context, stationID int64, filter POIFilter) ([]*POI, error)
+	// GetNearest は駅に最も近いカテゴリのPOIを距離によらず近い順に limit 件返す
+	GetNearest(ctx context.Context, stationID int64, category string, limit int) ([]*POI, error)
+	// Upsert は (source, source_id) をキーにPOIを登録・更新する
+	Upsert(ctx context.Context, pois []*POI) error
+	// DeleteStale は source のPOIのうち before より前に更新されたもの (今回の取り込みに含まれなかったもの) を削除する
+	DeleteStale(ctx context.Context, source string, before time.Time) (int64, error)
+	// RefreshFacilities は全駅について半径 radiusMeter 以内のPOIを数え facilities を更新し、更新した駅数を返す
+	RefreshFacilities(ctx context.Context, radiusMeter int) (int64, error)
+}
@@ -0,0 +1,86 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/uptrace/bun"
+)
+
+// 口コミの審査状態 (投稿はすべて pending から始まり、承認されたものだけを公開する)
+const (
+	ReviewStatusPending  = "pending"
+	ReviewStatusApproved = "approved"
+	ReviewStatusRejected = "rejected"
+)
+
+const (
+	// MinReviewTextLength / MaxReviewTextLength は良い点・気になる点それぞれの文字数の範囲
+	MinReviewTextLength = 5
+	MaxReviewTextLength = 400
+	// MaxReviewsPerClientPerDay は同じ投稿元から24時間に投稿できる件数
+	MaxReviewsPerClientPerDay = 3
+	// ReviewStationCooldown は同じ投稿元が同じ駅に再投稿できるまでの期間
+	ReviewStationCooldown = 30 * 24 * time.Hour
+	// MinReviewsForScore は review 軸のスコアを付けるのに必要な承認済み口コミの件数
+	MinReviewsForScore = 3
+	// MaxResidentVoices は駅詳細に載せる住民の声 (良い点・気になる点) のそれぞれの件数
+	MaxResidentVoices = 5
+	// ReviewScoreAxis は承認済み口コミの平均評価から作る軸 (station_scores に保存する)
+	ReviewScoreAxis = "review"
+)
+
+// ReviewAxes は口コミで評価する軸 (スコアの5軸と同じ)。評価は 1〜5
+var ReviewAxes = []string{"rent", "safety", "facility", "access", "disaster"}
+
+var (
+	// ErrInvalidReview は評価・本文が形式・長さの制約を満たさない場合のエラー
+	ErrInvalidReview = errors.New("invalid review")
+	// ErrReviewRejected は禁止語を含むなど、審査に回さず受け付けない投稿のエラー
+	ErrReviewRejected = errors.New("review rejected")
+)
+
+// ReviewInput は投稿された口コミ
+type ReviewInput struct {
+	Ratings  map[string]int `json:"ratings"`  // 軸 (ReviewAxes) -> 1〜5。評価しない軸は省略できる
+	Positive string         `json:"positive"` // 良い点
+	Negative string         `json:"negative"` // 気になる点
+}
+
+// Review は駅の口コミ
+type Review struct {
+	bun.BaseModel  `bun:"table:station_reviews,alias:rv"`
+	ID             int64          `bun:"id,pk,autoincrement" json:"id"`
+	StationID      int64          `bun:"station_id,notnull" json:"station_id"`
+	Ratings        map[string]int `bun:"ratings,type:jsonb,notnull" json:"ratings"`
+	Positive       string         `bun:"positive,notnull" json:"positive"`
+	Negative       string         `bun:"negative,notnull" json:"negative"`
+	Status         string         `bun:"status,notnull" json:"status"`
+	Flags          []string       `bun:"flags,array" json:"flags,omitempty"` // 自動チェックで見つかった審査時の注意点 (url, html など)
+	ClientHash     string         `bun:"client_hash,notnull" json:"-"`       // 投稿元 (IPアドレス) のHMAC。投稿数の制限に使う
+	ModerationNote string         `bun:"moderation_note,notnull" json:"moderation_note,omitempty"`
+	CreatedAt      time.Time      `bun:"created_at,nullzero,notnull,default:current_timestamp" json:"created_at"`
+	ModeratedAt    *time.Time     `bun:"moderated_at" json:"moderated_at,omitempty"`
+}
+
+// ReviewSummary は駅の承認済み口コミの集計
+type ReviewSummary struct {
+	Count   int                `json:"count"`
+	Ratings map[string]float64 `json:"ratings"` // 軸 -> 平均評価 (評価のある軸のみ)
+	Overall float64            `json:"overall"` // 全軸の評価の平均 (評価がなければ 0)
+}
+
+// ReviewRepository は口コミの保存先
+type ReviewRepository interface {
+	Create(ctx context.Context, review *Review) error
+	// CountByClientSince は投稿元が since 以降に投稿した件数を返す。stationID が 0 以外ならその駅の件数
+	CountByClientSince(ctx context.Context, clientHash string, stationID int64, since time.Time) (int, error)
+	// ListApproved は駅の承認済み口コミを新しい順に返す
+	ListApproved(ctx context.Context, stationID int64, limit int) ([]*Review, error)
+	Summary(ctx context.Context, stationID int64) (*ReviewSummary, error)
+	// ListByStatus は審査待ちなどの口コミを古い順に返す
+	ListByStatus(ctx context.Context, status string, limit, offset int) ([]*Review, error)
+	// Moderate は審査結果を保存して口コミを返す。存在しなければ sql.ErrNoRows
+	Moderate(ctx context.Context, id int64, status, note string, at time.Time) (*Review, error)
+}
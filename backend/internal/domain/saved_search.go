@@ -0,0 +1,147 @@
+package domain
+
+import (
+	"context"
+	"math"
+	"net/url"
+	"sort"
+	"time"
+
+	"github.com/uptrace/bun"
+)
+
+const (
+	// MaxSavedSearchNameLength は保存した検索の名前の最大文字数
+	MaxSavedSearchNameLength = 100
+	// MaxSavedSearchesPerUser は1人が保存できる検索条件の上限 (通知ジョブの実行時間を抑えるため)
+	MaxSavedSearchesPerUser = 20
+	// DefaultRentChangeThreshold は家賃相場の変化を通知する既定の幅 (万円)
+	DefaultRentChangeThreshold = 0.3
+)
+
+// 通知の送り先
+const (
+	NotifyChannelLog     = "log"
+	NotifyChannelEmail   = "email"
+	NotifyChannelWebhook = "webhook"
+)
+
+// 検索結果の変化の種類
+const (
+	SearchChangeNew      = "new"       // 新たに条件に合った駅 (家賃が予算内に下がった場合を含む)
+	SearchChangeRentDown = "rent_down" // 家賃相場が閾値以上下がった
+	SearchChangeRentUp   = "rent_up"   // 家賃相場が閾値以上上がった
+)
+
+// SavedSearch は保存した検索条件
+// 家賃相場 (rent_avg) は Filter の building_type と layout を両方指定した場合だけ比較できる
+type SavedSearch struct {
+	bun.BaseModel `bun:"table:saved_searches,alias:sv"`
+	ID            int64         `bun:"id,pk,autoincrement" json:"id"`
+	UserID        int64         `bun:"user_id,notnull" json:"-"`
+	Name          string        `bun:"name,notnull" json:"name"`
+	Lat           float64       `bun:"lat,notnull" json:"lat"`
+	Lon           float64       `bun:"lon,notnull" json:"lon"`
+	Filter        StationFilter `bun:"filter,type:jsonb,notnull" json:"filter"`
+	RentThreshold float64       `bun:"rent_threshold,notnull" json:"rent_threshold"`
+	Channel       string        `bun:"channel,notnull" json:"channel"`
+	WebhookURL    string        `bun:"webhook_url,nullzero" json:"webhook_url,omitempty"`
+	LastRunAt     *time.Time    `bun:"last_run_at" json:"last_run_at,omitempty"`
+	CreatedAt     time.Time     `bun:"created_at,nullzero,notnull,default:current_timestamp" json:"created_at"`
+	UpdatedAt     time.Time     `bun:"updated_at,nullzero,notnull,default:current_timestamp" json:"updated_at"`
+	UserEmail     string        `bun:"user_email,scanonly" json:"-"` // 通知ジョブ用
+
+	Results []*SavedSearchResult `bun:"-" json:"results,omitempty"` // 前回実行時の結果 (詳細取得時のみ)
+}
+
+// SavedSearchResult は前回実行時の検索結果の1駅
+type SavedSearchResult struct {
+	bun.BaseModel `bun:"table:saved_search_results,alias:svr"`
+	SavedSearchID int64   `bun:"saved_search_id,pk" json:"-"`
+	StationID     int64   `bun:"station_id,pk" json:"station_id"`
+	StationName   string  `bun:"station_name,notnull" json:"station_name"`
+	RentAvg       float64 `bun:"rent_avg,notnull" json:"rent_avg"`
+	TotalScore    float64 `bun:"total_score,notnull" json:"total_score"`
+}
+
+// SearchChange は前回の結果からの変化
+type SearchChange struct {
+	Kind        string  `json:"kind"`
+	StationID   int64   `json:"station_id"`
+	StationName string  `json:"station_name"`
+	OldRent     float64 `json:"old_rent,omitempty"`
+	NewRent     float64 `json:"new_rent,omitempty"`
+}
+
+// SearchAlert は保存した検索1件分の通知
+type SearchAlert struct {
+	Search  *SavedSearch   `json:"search"`
+	Changes []SearchChange `json:"changes"`
+}
+
+// Notifier は検索結果の変化の通知手段 (ログ、メール、Webhook)
+type Notifier interface {
+	Notify(ctx context.Context, alert *SearchAlert) error
+}
+
+// IsValidWebhookURL は Webhook の送信先として使える URL (https、ホスト名あり) か判定する
+func IsValidWebhookURL(raw string) bool {
+	u, err := url.Parse(raw)
+	return err == nil && u.Scheme == "https" && u.Hostname() != ""
+}
+
+// DiffSearchResults は前回と今回の検索結果を比べ、新しく条件に合った駅と家賃相場が threshold (万円) 以上変わった駅を返す
+// 条件から外れた駅は通知しない。結果は種類 (new, rent_down, rent_up)、駅ID の順
+func DiffSearchResults(prev, curr []*SavedSearchResult, threshold float64) []SearchChange {
+	before := make(map[int64]*SavedSearchResult, len(prev))
+	for _, r := range prev {
+		before[r.StationID] = r
+	}
+
+	var changes []SearchChange
+	for _, r := range curr {
+		old, ok := before[r.StationID]
+		if !ok {
+			changes = append(changes, SearchChange{Kind: SearchChangeNew, StationID: r.StationID, StationName: r.StationName, NewRent: r.RentAvg})
+			continue
+		}
+		// どちらかの家賃相場が不明 (0) なら比較しない
+		if old.RentAvg <= 0 || r.RentAvg <= 0 {
+			continue
+		}
+		diff := r.RentAvg - old.RentAvg
+		if math.Abs(diff) < threshold || diff == 0 {
+			continue
+		}
+		kind := SearchChangeRentUp
+		if diff < 0 {
+			kind = SearchChangeRentDown
+		}
+		changes = append(changes, SearchChange{Kind: kind, StationID: r.StationID, StationName: r.StationName, OldRent: old.RentAvg, NewRent: r.RentAvg})
+	}
+
+	order := map[string]int{SearchChangeNew: 0, SearchChangeRentDown: 1, SearchChangeRentUp: 2}
+	sort.Slice(changes, func(i, j int) bool {
+		if changes[i].Kind != changes[j].Kind {
+			return order[changes[i].Kind] < order[changes[j].Kind]
+		}
+		return changes[i].StationID < changes[j].StationID
+	})
+	return changes
+}
+
+// SavedSearchRepository は保存した検索条件と前回の結果の保存先
+// 他人の検索条件・存在しない行を指定した場合は sql.ErrNoRows を返す
+type SavedSearchRepository interface {
+	List(ctx context.Context, userID int64) ([]*SavedSearch, error)
+	Get(ctx context.Context, userID, id int64) (*SavedSearch, error)
+	Count(ctx context.Context, userID int64) (int, error)
+	Create(ctx context.Context, search *SavedSearch) error
+	Delete(ctx context.Context, userID, id int64) error
+
+	// ListAll は通知ジョブ用に全利用者の検索条件を利用者のメールアドレス付きで返す
+	ListAll(ctx context.Context) ([]*SavedSearch, error)
+	GetResults(ctx context.Context, searchID int64) ([]*SavedSearchResult, error)
+	// ReplaceResults は前回の結果を今回の結果で置き換え、last_run_at を更新する
+	ReplaceResults(ctx context.Context, searchID int64, results []*SavedSearchResult) error
+}
@@ -29,9 +29,14 @@ func (s *AccessScore) Name() string {
 // So within the radius, we score them.
 // 0m -> 100
 // 3000m -> 50 (example)
+//
+// 徒歩ネットワークを読み込んでいる場合は直線距離の代わりに道路距離 (WalkDistance) を使う
 func (s *AccessScore) Calculate(station *domain.Station) float64 {
 	dist := station.Distance // meters
 	// dist is float64 (not pointer anymore)
+	if station.WalkDistance > 0 {
+		dist = station.WalkDistance
+	}
 
 	// If distance is 0 (e.g. data missing), return 0? or 100?
 	// Assuming 0 means right there.
@@ -0,0 +1,24 @@
+package score
+
+import (
+	"github.com/gigaptera/hikkoshi-lens/backend/internal/domain"
+)
+
+// bustleNeutralScore は乗降客数のない駅の値 (重みの正負どちらでも順位に影響しない中間値)
+const bustleNeutralScore = 50.0
+
+type BustleScoreStrategy struct{}
+
+func NewBustleScore() Strategy {
+	return &BustleScoreStrategy{}
+}
+
+// Calculate は乗降客数のない駅に使う値を返す
+// 乗降客数のある駅は cmd/import/passengers が station_scores に保存した値を使う
+func (s *BustleScoreStrategy) Calculate(station *domain.Station) float64 {
+	return bustleNeutralScore
+}
+
+func (s *BustleScoreStrategy) Name() string {
+	return domain.BustleScoreAxis
+}
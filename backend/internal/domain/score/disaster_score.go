@@ -4,6 +4,22 @@ import (
 	"github.com/gigaptera/hikkoshi-lens/backend/internal/domain"
 )
 
+// 周辺の低地からの比高がこの値 (m) 未満の駅は、浸水しやすい低地として減点する
+const (
+	lowlandRelativeHeight = 5.0
+	lowlandMaxPenalty     = 1.0
+)
+
+// 最寄りの避難場所・救急病院が遠い駅の減点 (near 以内は減点なし、far 以上で最大)
+const (
+	shelterNearMeter            = 500.0 // 徒歩で数分
+	shelterFarMeter             = 2000.0
+	shelterMaxPenalty           = 1.0
+	emergencyHospitalNearMeter  = 2000.0
+	emergencyHospitalFarMeter   = 10000.0
+	emergencyHospitalMaxPenalty = 0.5
+)
+
 type DisasterScoreStrategy struct{}
 
 func NewDisasterScore() Strategy {
@@ -16,7 +32,33 @@ func (s *DisasterScoreStrategy) Calculate(station *domain.Station) float64 {
 	// Mock: Vary risk slightly by station ID
 	// In real implementation, this would query disaster_risks table
 	mockScore := 4.0 - float64((station.ID%4))*0.3 // Returns 4.0 to 3.1
-	return mockScore
+	score := mockScore - lowlandPenalty(station.Terrain)
+	if a := station.DisasterAccess; a != nil {
+		score -= distancePenalty(a.NearestShelterMeter, shelterNearMeter, shelterFarMeter, shelterMaxPenalty)
+		score -= distancePenalty(a.NearestEmergencyHospitalMeter, emergencyHospitalNearMeter, emergencyHospitalFarMeter, emergencyHospitalMaxPenalty)
+	}
+	return score
+}
+
+// lowlandPenalty は川沿いの低地ほど大きい減点 (比高0mで lowlandMaxPenalty)
+// 地形を計算していない駅は減点しない
+func lowlandPenalty(t *domain.StationTerrain) float64 {
+	if t == nil || t.RelativeHeight >= lowlandRelativeHeight {
+		return 0
+	}
+	return lowlandMaxPenalty * (lowlandRelativeHeight - t.RelativeHeight) / lowlandRelativeHeight
+}
+
+// distancePenalty は near から far まで距離に比例して maxPenalty まで増える減点
+// 施設のデータを取り込んでいない (nil) 場合は減点しない
+func distancePenalty(meter *float64, near, far, maxPenalty float64) float64 {
+	if meter == nil || *meter <= near {
+		return 0
+	}
+	if *meter >= far {
+		return maxPenalty
+	}
+	return maxPenalty * (*meter - near) / (far - near)
 }
 
 func (s *DisasterScoreStrategy) Name() string {
@@ -0,0 +1,24 @@
+package score
+
+import (
+	"github.com/gigaptera/hikkoshi-lens/backend/internal/domain"
+)
+
+// hillinessNeutralScore は地形を計算していない駅の値 (重みの大小で順位に影響しない中間値)
+const hillinessNeutralScore = 50.0
+
+type HillinessScoreStrategy struct{}
+
+func NewHillinessScore() Strategy {
+	return &HillinessScoreStrategy{}
+}
+
+// Calculate は地形を計算していない駅に使う値を返す
+// 計算済みの駅は cmd/import/terrain が station_scores に保存した値を使う
+func (s *HillinessScoreStrategy) Calculate(station *domain.Station) float64 {
+	return hillinessNeutralScore
+}
+
+func (s *HillinessScoreStrategy) Name() string {
+	return domain.HillinessScoreAxis
+}
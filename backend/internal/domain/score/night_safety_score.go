@@ -0,0 +1,25 @@
+package score
+
+import (
+	"github.com/gigaptera/hikkoshi-lens/backend/internal/domain"
+)
+
+// NightSafetyScoreStrategy は safety 軸に含まれる夜間の成分を単独で返す軸
+// 合計スコアには safety 軸を通じて反映するため既定の重みはなく、w_night_safety を指定した場合だけ単独で重み付けする
+type NightSafetyScoreStrategy struct{}
+
+func NewNightSafetyScore() Strategy {
+	return &NightSafetyScoreStrategy{}
+}
+
+// Calculate は Station.NightSafety から夜間の安全性のスコアを計算する (集計結果を読み込んだバッチ用)
+// 検索では cmd/import/osm_pois・cmd/import/koban が station_scores に保存した値を使い、
+// 集計していない駅は中間値で埋めずにスコアなしとして扱う (ScoringService.CalculateScores を参照)
+func (s *NightSafetyScoreStrategy) Calculate(station *domain.Station) float64 {
+	v, _ := station.NightSafety.Score()
+	return v
+}
+
+func (s *NightSafetyScoreStrategy) Name() string {
+	return domain.NightSafetyScoreAxis
+}
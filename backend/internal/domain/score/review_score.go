@@ -0,0 +1,36 @@
+package score
+
+import (
+	"github.com/gigaptera/hikkoshi-lens/backend/internal/domain"
+)
+
+// reviewNeutralScore は承認済み口コミが少なくスコアのない駅の値 (重みを付けても順位に影響しない中間値)
+const reviewNeutralScore = 50.0
+
+type ReviewScoreStrategy struct{}
+
+func NewReviewScore() Strategy {
+	return &ReviewScoreStrategy{}
+}
+
+// Calculate は口コミのスコアがない駅に使う値を返す
+// 口コミのある駅は審査時に station_scores に保存した値 (ReviewRatingScore) を使う
+func (s *ReviewScoreStrategy) Calculate(station *domain.Station) float64 {
+	return reviewNeutralScore
+}
+
+func (s *ReviewScoreStrategy) Name() string {
+	return domain.ReviewScoreAxis
+}
+
+// ReviewRatingScore は口コミの平均評価 (1〜5) を 0-100 のスコアにする
+// 全駅での相対値ではなく評価そのものを使う (口コミのある駅だけで正規化すると件数の少ない駅に振れやすいため)
+func ReviewRatingScore(overall float64) float64 {
+	if overall <= 1 {
+		return 0
+	}
+	if overall >= 5 {
+		return 100
+	}
+	return (overall - 1) / 4 * 100
+}
@@ -4,6 +4,13 @@ import (
 	"github.com/gigaptera/hikkoshi-lens/backend/internal/domain"
 )
 
+// 夜間の安全性 (night safety) の減点の最大値: 街灯が少ないほど、交番・警察署が遠いほど大きい
+// 基準の密度・距離は domain.StreetLampEnoughDensity などを参照
+const (
+	streetLampMaxPenalty = 0.5
+	policeMaxPenalty     = 0.5
+)
+
 type SafetyScoreStrategy struct{}
 
 func NewSafetyScore() Strategy {
@@ -16,7 +23,20 @@ func (s *SafetyScoreStrategy) Calculate(station *domain.Station) float64 {
 	// Mock: Most areas in Japan are quite safe, so default to high score
 	// In real implementation, this would query crime_stats table
 	mockScore := 4.5 - float64((station.ID%5))*0.2 // Returns 4.5 to 3.7
-	return mockScore
+	return mockScore - nightSafetyPenalty(station.NightSafety)
+}
+
+// nightSafetyPenalty は夜間の安全性の減点 (街灯の多さと交番の近さの成分から計算)
+// 同じ成分を night_safety 軸として単独でも返す。データを取り込んでいない指標は減点しない
+func nightSafetyPenalty(n *domain.StationNightSafety) float64 {
+	penalty := 0.0
+	if lamp, ok := n.LampLevel(); ok {
+		penalty += streetLampMaxPenalty * (1 - lamp)
+	}
+	if police, ok := n.PoliceLevel(); ok {
+		penalty += policeMaxPenalty * (1 - police)
+	}
+	return penalty
 }
 
 func (s *SafetyScoreStrategy) Name() string {
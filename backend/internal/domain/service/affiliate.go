@@ -0,0 +1,102 @@
+package service
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+
+	"github.com/gigaptera/hikkoshi-lens/backend/internal/domain"
+)
+
+// 外部IDが分からない駅の送客先 (サイトのトップの賃貸検索)
+const (
+	suumoFallbackURL = "https://suumo.jp/chintai/"
+	homesFallbackURL = "https://www.homes.co.jp/chintai/"
+)
+
+// suumoLayoutCodes は間取り区分からSUUMOの検索条件 md の値への対応
+// 01:ワンルーム 02:1K 03:1DK 04:1LDK 05:2K 06:2DK 07:2LDK 08:3K 09:3DK 10:3LDK 11:4K 12:4DK 13:4LDK 14:5K以上
+var suumoLayoutCodes = map[string][]string{
+	"1r_1k_1dk":   {"01", "02", "03"},
+	"1ldk_2k_2dk": {"04", "05", "06"},
+	"2ldk_3k_3dk": {"07", "08", "09"},
+	"3ldk_4k":     {"10", "11"},
+	"4ldk":        {"12", "13", "14"},
+}
+
+// AffiliateLinkBuilder は外部サイトの駅ごとの物件一覧へのディープリンクを作る
+type AffiliateLinkBuilder struct {
+	params map[string]url.Values // 送客先ごとのアフィリエイトパラメータ
+}
+
+// NewAffiliateLinkBuilder は送客先ごとのアフィリエイトパラメータ (クエリ文字列) からビルダーを作る
+func NewAffiliateLinkBuilder(params map[string]string) (*AffiliateLinkBuilder, error) {
+	b := &AffiliateLinkBuilder{params: make(map[string]url.Values)}
+	for source, raw := range params {
+		if raw == "" {
+			continue
+		}
+		v, err := url.ParseQuery(raw)
+		if err != nil {
+			return nil, fmt.Errorf("invalid affiliate params for %s: %w", source, err)
+		}
+		b.params[source] = v
+	}
+	return b, nil
+}
+
+// Build は送客先のURLを返す。ext が nil ならサイトの賃貸トップに送る
+func (b *AffiliateLinkBuilder) Build(source string, ext *domain.StationExternalID, filter domain.AffiliateFilter) (string, error) {
+	var u *url.URL
+	var err error
+	q := url.Values{}
+
+	switch source {
+	case domain.AffiliateSourceSuumo:
+		if ext == nil {
+			u, err = url.Parse(suumoFallbackURL)
+			break
+		}
+		u, err = url.Parse(fmt.Sprintf("https://suumo.jp/chintai/%s/ek_%s/", url.PathEscape(ext.Area), url.PathEscape(ext.ExternalID)))
+		// 家賃は万円単位 (cb: 下限, ct: 上限)、間取りは md を複数指定
+		if filter.MinRent > 0 {
+			q.Set("cb", formatRent(filter.MinRent))
+		}
+		if filter.MaxRent > 0 {
+			q.Set("ct", formatRent(filter.MaxRent))
+		}
+		for _, code := range suumoLayoutCodes[filter.Layout] {
+			q.Add("md", code)
+		}
+	case domain.AffiliateSourceHomes:
+		if ext == nil {
+			u, err = url.Parse(homesFallbackURL)
+			break
+		}
+		u, err = url.Parse(fmt.Sprintf("https://www.homes.co.jp/chintai/%s/%s/list/", url.PathEscape(ext.Area), url.PathEscape(ext.ExternalID)))
+		// HOME'S は家賃の範囲だけを引き継ぐ (間取りの条件は区分の対応が取れないため渡さない)
+		if filter.MinRent > 0 {
+			q.Set("cond[monthmoneyroom]", formatRent(filter.MinRent))
+		}
+		if filter.MaxRent > 0 {
+			q.Set("cond[monthmoneyroomh]", formatRent(filter.MaxRent))
+		}
+	default:
+		return "", fmt.Errorf("unknown affiliate source: %s", source)
+	}
+	if err != nil {
+		return "", err
+	}
+
+	for k, vs := range b.params[source] {
+		for _, v := range vs {
+			q.Add(k, v)
+		}
+	}
+	u.RawQuery = q.Encode()
+	return u.String(), nil
+}
+
+func formatRent(v float64) string {
+	return strconv.FormatFloat(v, 'f', 1, 64)
+}
@@ -0,0 +1,62 @@
+package service
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/gigaptera/hikkoshi-lens/backend/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAffiliateLinkBuilder_Suumo(t *testing.T) {
+	b, err := NewAffiliateLinkBuilder(map[string]string{domain.AffiliateSourceSuumo: "vos=aff123&utm_source=hikkoshi"})
+	require.NoError(t, err)
+
+	ext := &domain.StationExternalID{Source: domain.AffiliateSourceSuumo, Area: "tokyo", ExternalID: "20110"}
+	link, err := b.Build(domain.AffiliateSourceSuumo, ext, domain.AffiliateFilter{Layout: "1ldk_2k_2dk", MinRent: 8, MaxRent: 12.5})
+	require.NoError(t, err)
+
+	u, err := url.Parse(link)
+	require.NoError(t, err)
+	assert.Equal(t, "suumo.jp", u.Host)
+	assert.Equal(t, "/chintai/tokyo/ek_20110/", u.Path)
+	q := u.Query()
+	assert.Equal(t, "8.0", q.Get("cb"))
+	assert.Equal(t, "12.5", q.Get("ct"))
+	assert.Equal(t, []string{"04", "05", "06"}, q["md"])
+	assert.Equal(t, "aff123", q.Get("vos"))
+	assert.Equal(t, "hikkoshi", q.Get("utm_source"))
+}
+
+func TestAffiliateLinkBuilder_Homes(t *testing.T) {
+	b, err := NewAffiliateLinkBuilder(nil)
+	require.NoError(t, err)
+
+	ext := &domain.StationExternalID{Source: domain.AffiliateSourceHomes, Area: "tokyo", ExternalID: "shinjuku_00580-st"}
+	link, err := b.Build(domain.AffiliateSourceHomes, ext, domain.AffiliateFilter{Layout: "4ldk", MaxRent: 20})
+	require.NoError(t, err)
+	assert.Equal(t, "https://www.homes.co.jp/chintai/tokyo/shinjuku_00580-st/list/?cond%5Bmonthmoneyroomh%5D=20.0", link)
+}
+
+func TestAffiliateLinkBuilder_Fallback(t *testing.T) {
+	b, err := NewAffiliateLinkBuilder(map[string]string{domain.AffiliateSourceHomes: "aff=1"})
+	require.NoError(t, err)
+
+	// 外部IDが分からない駅はトップに送る (アフィリエイトパラメータは付ける)
+	link, err := b.Build(domain.AffiliateSourceSuumo, nil, domain.AffiliateFilter{MinRent: 5})
+	require.NoError(t, err)
+	assert.Equal(t, "https://suumo.jp/chintai/", link)
+
+	link, err = b.Build(domain.AffiliateSourceHomes, nil, domain.AffiliateFilter{})
+	require.NoError(t, err)
+	assert.Equal(t, "https://www.homes.co.jp/chintai/?aff=1", link)
+
+	_, err = b.Build("athome", nil, domain.AffiliateFilter{})
+	assert.Error(t, err)
+}
+
+func TestNewAffiliateLinkBuilder_InvalidParams(t *testing.T) {
+	_, err := NewAffiliateLinkBuilder(map[string]string{domain.AffiliateSourceSuumo: "a=%zz"})
+	assert.Error(t, err)
+}
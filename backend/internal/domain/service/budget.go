@@ -0,0 +1,263 @@
+package service
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"math"
+	"sort"
+	"strconv"
+
+	"github.com/gigaptera/hikkoshi-lens/backend/internal/domain"
+)
+
+//go:embed tax_tables/*.json
+var taxTableFS embed.FS
+
+// DefaultTaxTables は組み込みの税・社会保険料の表を年ごとに返す
+// 新しい年の表は tax_tables/<year>.json を追加する
+func DefaultTaxTables() map[string]*domain.TaxTable {
+	files, err := fs.Glob(taxTableFS, "tax_tables/*.json")
+	if err != nil {
+		panic(err)
+	}
+	tables := make(map[string]*domain.TaxTable)
+	for _, name := range files {
+		data, err := taxTableFS.ReadFile(name)
+		if err != nil {
+			panic(err)
+		}
+		t, err := ParseTaxTable(data)
+		if err != nil {
+			panic(fmt.Sprintf("invalid embedded tax table %s: %v", name, err))
+		}
+		tables[t.Year] = t
+	}
+	return tables
+}
+
+// ParseTaxTable はJSONの表を読み、段階の並びや全国平均の健康保険料率の有無を検証する
+func ParseTaxTable(data []byte) (*domain.TaxTable, error) {
+	var t domain.TaxTable
+	if err := json.Unmarshal(data, &t); err != nil {
+		return nil, err
+	}
+	if t.Year == "" {
+		return nil, fmt.Errorf("year is required")
+	}
+	if _, ok := t.HealthRates["0"]; !ok {
+		return nil, fmt.Errorf("default health rate (\"0\") is required")
+	}
+	for name, brackets := range map[string][]domain.TaxBracket{
+		"salary_deduction":    t.SalaryDeduction,
+		"income_tax_brackets": t.IncomeTaxBrackets,
+	} {
+		ups := make([]int, len(brackets))
+		for i, b := range brackets {
+			ups[i] = b.UpTo
+		}
+		if err := validateSteps(ups); err != nil {
+			return nil, fmt.Errorf("%s: %w", name, err)
+		}
+	}
+	ups := make([]int, len(t.IncomeTaxBasic))
+	for i, s := range t.IncomeTaxBasic {
+		ups[i] = s.UpTo
+	}
+	if err := validateSteps(ups); err != nil {
+		return nil, fmt.Errorf("income_tax_basic: %w", err)
+	}
+	return &t, nil
+}
+
+// validateSteps は段階の上限が昇順で、最後だけが上限なし (0) であることを確かめる
+func validateSteps(ups []int) error {
+	if len(ups) == 0 || ups[len(ups)-1] != 0 {
+		return fmt.Errorf("the last step must have no upper limit (up_to 0)")
+	}
+	for i := 1; i < len(ups)-1; i++ {
+		if ups[i] <= ups[i-1] {
+			return fmt.Errorf("up_to must be ascending")
+		}
+	}
+	if len(ups) > 1 && ups[0] <= 0 {
+		return fmt.Errorf("up_to must be positive")
+	}
+	return nil
+}
+
+// BudgetAdvisor は額面の給与から手取りと家賃の目安を求める
+type BudgetAdvisor struct {
+	tables map[string]*domain.TaxTable
+	latest string
+}
+
+func NewBudgetAdvisor(tables map[string]*domain.TaxTable) *BudgetAdvisor {
+	a := &BudgetAdvisor{tables: tables}
+	years := make([]string, 0, len(tables))
+	for y := range tables {
+		years = append(years, y)
+	}
+	sort.Strings(years)
+	if len(years) > 0 {
+		a.latest = years[len(years)-1]
+	}
+	return a
+}
+
+// Advise は手取りを見積もり、家賃の目安 (domain.BudgetRules) を万円で返す
+// 税年の指定がない場合は最新の表を使う
+func (a *BudgetAdvisor) Advise(in domain.BudgetInput) (*domain.BudgetAdvice, error) {
+	if in.MonthlySalary <= 0 || in.MonthlySalary > domain.MaxMonthlySalary ||
+		in.AnnualBonus < 0 || in.AnnualBonus > domain.MaxMonthlySalary*12 ||
+		in.Age < 0 || in.Age > 120 || in.PrefectureCode < 0 || in.PrefectureCode > 47 {
+		return nil, domain.ErrInvalidBudget
+	}
+	year := in.TaxYear
+	if year == "" {
+		year = a.latest
+	}
+	table, ok := a.tables[year]
+	if !ok {
+		return nil, domain.ErrUnknownTaxYear
+	}
+
+	pay := TakeHome(table, in)
+	advice := &domain.BudgetAdvice{
+		TaxYear:     table.Year,
+		TakeHome:    pay,
+		Recommended: domain.RecommendedBudgetRule,
+		Notes: []string{
+			"標準報酬月額の等級・扶養控除などは考慮しない概算です",
+			"家賃の目安は賞与を除いた毎月の手取りに対する割合です",
+		},
+	}
+	if in.FirstYear {
+		advice.Notes = append(advice.Notes, "就職1年目は住民税がかからないため、2年目から手取りが減ります")
+	}
+	if in.Age == 0 {
+		advice.Notes = append(advice.Notes, "年齢の指定がないため介護保険料 (40〜64歳) を含めていません")
+	}
+	for _, r := range domain.BudgetRules {
+		advice.RentRanges = append(advice.RentRanges, domain.RentRange{
+			Key:      r.Key,
+			Label:    r.Label,
+			MinRatio: r.MinRatio,
+			MaxRatio: r.MaxRatio,
+			MinRent:  manYen(float64(pay.MonthlyTakeHome) * r.MinRatio),
+			MaxRent:  manYen(float64(pay.MonthlyTakeHome) * r.MaxRatio),
+		})
+	}
+	return advice, nil
+}
+
+// TakeHome は額面の月給・賞与から社会保険料と税を引いた手取りを見積もる
+// 社会保険料は標準報酬月額の等級を使わず月給に料率を掛け、賞与は年2回の同額支給とする
+// 住民税は前年も同じ収入だったものとして計算する (1年目は 0)
+func TakeHome(t *domain.TaxTable, in domain.BudgetInput) domain.TakeHomePay {
+	salaryYear := in.MonthlySalary * 12
+	gross := salaryYear + in.AnnualBonus
+	healthRate, ok := t.HealthRates[strconv.Itoa(in.PrefectureCode)]
+	if !ok {
+		healthRate = t.HealthRates["0"]
+	}
+	nursing := in.Age >= 40 && in.Age < 65
+
+	// 社会保険料 (毎月の給与分と賞与分)
+	var salarySI, si domain.SocialInsurance
+	healthBase := minInt(in.MonthlySalary, t.HealthMonthlyCap)
+	salarySI.Health = yen(float64(healthBase)*healthRate) * 12
+	if nursing {
+		salarySI.Nursing = yen(float64(healthBase)*t.NursingRate) * 12
+	}
+	salarySI.Pension = yen(float64(minInt(in.MonthlySalary, t.PensionMonthlyCap))*t.PensionRate) * 12
+	salarySI.Employment = yen(float64(in.MonthlySalary)*t.EmploymentRate) * 12
+
+	si = salarySI
+	if in.AnnualBonus > 0 {
+		perBonus := in.AnnualBonus / 2 / 1000 * 1000 // 標準賞与額は1000円未満切り捨て
+		healthBonus := minInt(perBonus*2, t.HealthBonusYearCap)
+		si.Health += yen(float64(healthBonus) * healthRate)
+		if nursing {
+			si.Nursing += yen(float64(healthBonus) * t.NursingRate)
+		}
+		si.Pension += yen(float64(minInt(perBonus, t.PensionBonusCap)*2) * t.PensionRate)
+		si.Employment += yen(float64(in.AnnualBonus) * t.EmploymentRate)
+	}
+	salarySI.Total = salarySI.Health + salarySI.Nursing + salarySI.Pension + salarySI.Employment
+	si.Total = si.Health + si.Nursing + si.Pension + si.Employment
+
+	// 所得税 (復興特別所得税を含む)
+	salaryIncome := maxInt(0, gross-applyBracket(t.SalaryDeduction, gross))
+	taxable := floorTo(maxInt(0, salaryIncome-si.Total-stepAmount(t.IncomeTaxBasic, salaryIncome)), 1000)
+	incomeTax := floorTo(yen(float64(applyBracket(t.IncomeTaxBrackets, taxable))*(1+t.ReconstructionRate)), 100)
+
+	// 住民税 (所得割 + 均等割)
+	residentTax := 0
+	if !in.FirstYear && salaryIncome > t.ResidentExemptIncome {
+		residentTaxable := floorTo(maxInt(0, salaryIncome-si.Total-t.ResidentBasic), 1000)
+		incomePart := yen(float64(residentTaxable) * t.ResidentRate)
+		if residentTaxable <= 2000000 {
+			incomePart = maxInt(0, incomePart-t.ResidentAdjustment)
+		}
+		residentTax = floorTo(incomePart, 100) + t.ResidentPerCapita
+	}
+
+	annual := gross - si.Total - incomeTax - residentTax
+	// 毎月の手取り: 所得税は給与と賞与で按分し、住民税は毎月の給与から引かれる
+	salaryIncomeTax := incomeTax * salaryYear / gross
+	monthly := (salaryYear - salarySI.Total - salaryIncomeTax - residentTax) / 12
+
+	return domain.TakeHomePay{
+		AnnualGross:     gross,
+		SocialInsurance: si,
+		IncomeTax:       incomeTax,
+		ResidentTax:     residentTax,
+		AnnualTakeHome:  annual,
+		MonthlyTakeHome: monthly,
+		MonthlyAverage:  annual / 12,
+	}
+}
+
+// applyBracket は amount が当てはまる段階で amount × Rate - Deduction を返す
+func applyBracket(brackets []domain.TaxBracket, amount int) int {
+	for _, b := range brackets {
+		if b.UpTo == 0 || amount <= b.UpTo {
+			return maxInt(0, yen(float64(amount)*b.Rate)-b.Deduction)
+		}
+	}
+	return 0
+}
+
+func stepAmount(steps []domain.IncomeStep, income int) int {
+	for _, s := range steps {
+		if s.UpTo == 0 || income <= s.UpTo {
+			return s.Amount
+		}
+	}
+	return 0
+}
+
+// manYen は円を万円にする (0.1万円未満切り捨て)
+func manYen(v float64) float64 {
+	return math.Floor(v/1000) / 10
+}
+
+func floorTo(v, unit int) int {
+	return v / unit * unit
+}
+
+func minInt(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+func maxInt(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,99 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/gigaptera/hikkoshi-lens/backend/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDefaultTaxTables(t *testing.T) {
+	tables := DefaultTaxTables()
+	require.Contains(t, tables, "2025")
+	assert.Equal(t, "2025", tables["2025"].Year)
+}
+
+func TestParseTaxTable_Invalid(t *testing.T) {
+	base := `"health_rates": {"0": 0.05}, "salary_deduction": [{"up_to": 0}], "income_tax_basic": [{"up_to": 0}]`
+	for _, data := range []string{
+		`{` + base + `, "income_tax_brackets": [{"up_to": 0}]}`,                                                                           // 年なし
+		`{"year": "2025", "salary_deduction": [{"up_to": 0}], "income_tax_basic": [{"up_to": 0}], "income_tax_brackets": [{"up_to": 0}]}`, // 全国平均の料率なし
+		`{"year": "2025", ` + base + `, "income_tax_brackets": [{"up_to": 100}]}`,                                                         // 最後の段階に上限がある
+		`{"year": "2025", ` + base + `, "income_tax_brackets": [{"up_to": 200}, {"up_to": 100}, {"up_to": 0}]}`,                           // 昇順でない
+		`not json`,
+	} {
+		_, err := ParseTaxTable([]byte(data))
+		assert.Error(t, err, data)
+	}
+}
+
+func TestTakeHome(t *testing.T) {
+	table := DefaultTaxTables()["2025"]
+
+	// 月給22万円 (東京都、賞与なし)
+	pay := TakeHome(table, domain.BudgetInput{MonthlySalary: 220000, PrefectureCode: 13})
+	assert.Equal(t, 2640000, pay.AnnualGross)
+	assert.Equal(t, 220000*12-pay.SocialInsurance.Total-pay.IncomeTax-pay.ResidentTax, pay.AnnualTakeHome)
+	// 給与所得 176.8万 - 社会保険料 - 基礎控除88万 = 課税所得50.1万 → 5% × 1.021
+	assert.Equal(t, 25500, pay.IncomeTax)
+	// (176.8万 - 社会保険料 - 43万) × 10% - 調整控除 + 均等割
+	assert.Equal(t, 97600, pay.ResidentTax)
+	assert.Equal(t, 0, pay.SocialInsurance.Nursing)
+	assert.Equal(t, 177500, pay.MonthlyTakeHome)
+
+	// 1年目は住民税なし
+	first := TakeHome(table, domain.BudgetInput{MonthlySalary: 220000, PrefectureCode: 13, FirstYear: true})
+	assert.Equal(t, 0, first.ResidentTax)
+	assert.Greater(t, first.MonthlyTakeHome, pay.MonthlyTakeHome)
+}
+
+func TestTakeHome_BonusAndNursing(t *testing.T) {
+	table := DefaultTaxTables()["2025"]
+
+	pay := TakeHome(table, domain.BudgetInput{MonthlySalary: 300000, AnnualBonus: 1200000, Age: 45, PrefectureCode: 27})
+	assert.Equal(t, 4800000, pay.AnnualGross)
+	assert.Greater(t, pay.SocialInsurance.Nursing, 0)
+	// 賞与を含めた平均は毎月の手取りより多い
+	assert.Greater(t, pay.MonthlyAverage, pay.MonthlyTakeHome)
+	assert.Equal(t, pay.AnnualTakeHome/12, pay.MonthlyAverage)
+
+	// 一覧にない都道府県は全国平均の料率
+	other := TakeHome(table, domain.BudgetInput{MonthlySalary: 300000, PrefectureCode: 47})
+	assert.Equal(t, yen(300000*table.HealthRates["0"])*12, other.SocialInsurance.Health)
+}
+
+func TestBudgetAdvisor_Advise(t *testing.T) {
+	a := NewBudgetAdvisor(DefaultTaxTables())
+
+	advice, err := a.Advise(domain.BudgetInput{MonthlySalary: 220000, PrefectureCode: 13})
+	require.NoError(t, err)
+	assert.Equal(t, "2025", advice.TaxYear)
+	assert.Equal(t, domain.RecommendedBudgetRule, advice.Recommended)
+
+	// 手取り177,500円の 25% / 30% / 33% (0.1万円未満切り捨て)
+	require.Len(t, advice.RentRanges, 3)
+	assert.Equal(t, 0.0, advice.RentRanges[0].MinRent)
+	assert.Equal(t, 4.4, advice.RentRanges[0].MaxRent)
+	assert.Equal(t, 4.4, advice.RentRanges[1].MinRent)
+	assert.Equal(t, 5.3, advice.RentRanges[1].MaxRent)
+	assert.Equal(t, 5.8, advice.RentRanges[2].MaxRent)
+}
+
+func TestBudgetAdvisor_Invalid(t *testing.T) {
+	a := NewBudgetAdvisor(DefaultTaxTables())
+
+	for _, in := range []domain.BudgetInput{
+		{},
+		{MonthlySalary: -1},
+		{MonthlySalary: domain.MaxMonthlySalary + 1},
+		{MonthlySalary: 200000, AnnualBonus: -1},
+		{MonthlySalary: 200000, Age: 200},
+		{MonthlySalary: 200000, PrefectureCode: 48},
+	} {
+		_, err := a.Advise(in)
+		assert.ErrorIs(t, err, domain.ErrInvalidBudget)
+	}
+	_, err := a.Advise(domain.BudgetInput{MonthlySalary: 200000, TaxYear: "1999"})
+	assert.ErrorIs(t, err, domain.ErrUnknownTaxYear)
+}
@@ -0,0 +1,46 @@
+package service
+
+import (
+	"math"
+
+	"github.com/gigaptera/hikkoshi-lens/backend/internal/domain"
+)
+
+// bustleSameStationMeter は同名でこの距離以内の駅を乗換駅として乗降客数を合算する (タグの乗換駅判定と同じ)
+const bustleSameStationMeter = 500
+
+// BustleRawScores は bustle 軸の生スコア (乗換駅全体の1日あたりの乗降客数の常用対数) を返す
+// 乗降客数は事業者ごとに集計されるため、同じ乗換駅では事業者ごとの最大値を合算する
+// (同じ事業者の路線別の駅には同じ値が入っているため、単純に足すと二重に数える)
+// 乗降客数のない駅は含めない (スコアは Strategy の中間値になる)
+func BustleRawScores(stations []*domain.Station, passengers map[int64]int) map[int64]float64 {
+	byName := make(map[string][]*domain.Station)
+	for _, s := range stations {
+		byName[s.Name] = append(byName[s.Name], s)
+	}
+
+	raw := make(map[int64]float64)
+	for _, s := range stations {
+		perOperator := make(map[string]int)
+		for _, other := range byName[s.Name] {
+			n, ok := passengers[other.ID]
+			if !ok || n <= 0 {
+				continue
+			}
+			if other.ID != s.ID && HaversineMeter(s.Lat, s.Lon, other.Lat, other.Lon) > bustleSameStationMeter {
+				continue
+			}
+			if n > perOperator[other.OrganizationCode] {
+				perOperator[other.OrganizationCode] = n
+			}
+		}
+		total := 0
+		for _, n := range perOperator {
+			total += n
+		}
+		if total > 0 {
+			raw[s.ID] = math.Log10(float64(total))
+		}
+	}
+	return raw
+}
@@ -0,0 +1,30 @@
+package service
+
+import (
+	"math"
+	"testing"
+
+	"github.com/gigaptera/hikkoshi-lens/backend/internal/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBustleRawScores(t *testing.T) {
+	stations := []*domain.Station{
+		// 同じ事業者の2路線 (同じ乗降客数) と別の事業者の乗換駅
+		{ID: 1, Name: "新宿", OrganizationCode: "jr", Lat: 35.6900, Lon: 139.7000},
+		{ID: 2, Name: "新宿", OrganizationCode: "jr", Lat: 35.6901, Lon: 139.7001},
+		{ID: 3, Name: "新宿", OrganizationCode: "odakyu", Lat: 35.6902, Lon: 139.6995},
+		// 同名の遠い駅は合算しない
+		{ID: 4, Name: "新宿", OrganizationCode: "other", Lat: 34.0, Lon: 135.0},
+		// 乗降客数なし
+		{ID: 5, Name: "赤嶺", OrganizationCode: "yui", Lat: 26.19, Lon: 127.66},
+	}
+	passengers := map[int64]int{1: 600000, 2: 600000, 3: 400000, 4: 1000}
+
+	raw := BustleRawScores(stations, passengers)
+	assert.InDelta(t, 6.0, raw[1], 1e-9)
+	assert.InDelta(t, 6.0, raw[2], 1e-9)
+	assert.InDelta(t, 6.0, raw[3], 1e-9)
+	assert.InDelta(t, math.Log10(1000), raw[4], 1e-9)
+	assert.NotContains(t, raw, int64(5))
+}
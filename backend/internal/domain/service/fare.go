@@ -0,0 +1,234 @@
+package service
+
+import (
+	"container/heap"
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/gigaptera/hikkoshi-lens/backend/internal/domain"
+)
+
+// commuteOperatorPenaltiesKm は事業者をまたぐ乗り換え1回あたりの重み (km換算)
+// 運賃は事業者ごとに初乗りから計算されるため、最短距離の経路が最安とは限らない
+// 重みを変えて複数の経路を探し、定期代が最も安いものを選ぶ
+var commuteOperatorPenaltiesKm = []float64{0, 5, 30}
+
+// FareTable は事業者ごとの距離帯別の運賃表
+type FareTable struct {
+	bands map[string][]*domain.FareBand
+}
+
+func NewFareTable(bands []*domain.FareBand) *FareTable {
+	t := &FareTable{bands: make(map[string][]*domain.FareBand)}
+	for _, b := range bands {
+		t.bands[b.OrganizationCode] = append(t.bands[b.OrganizationCode], b)
+	}
+	for _, list := range t.bands {
+		sort.Slice(list, func(i, j int) bool { return list[i].MinKm < list[j].MinKm })
+	}
+	return t
+}
+
+// Band は事業者 org で km 乗車した場合の運賃帯を返す (1km未満は切り上げ)
+// 運賃表がない・距離帯の範囲外の場合は nil
+func (t *FareTable) Band(org string, km float64) *domain.FareBand {
+	k := int(math.Ceil(km))
+	if k < 1 {
+		k = 1
+	}
+	list := t.bands[org]
+	i := sort.Search(len(list), func(i int) bool { return list[i].MaxKm >= k })
+	if i < len(list) && list[i].MinKm <= k {
+		return list[i]
+	}
+	return nil
+}
+
+// ValidateFareBands は取り込む運賃表を検証する
+// 事業者ごとに距離帯が重ならないこと、運賃が正であること、定期代が負でないことを確かめる
+func ValidateFareBands(bands []*domain.FareBand) error {
+	byOrg := make(map[string][]*domain.FareBand)
+	for _, b := range bands {
+		if b.OrganizationCode == "" {
+			return fmt.Errorf("organization_code is required")
+		}
+		if b.MinKm < 1 || b.MaxKm < b.MinKm {
+			return fmt.Errorf("%s: invalid distance band %d-%d km", b.OrganizationCode, b.MinKm, b.MaxKm)
+		}
+		if b.Fare <= 0 || b.Pass1M < 0 || b.Pass3M < 0 || b.Pass6M < 0 {
+			return fmt.Errorf("%s %d-%d km: fare must be positive and pass prices non-negative", b.OrganizationCode, b.MinKm, b.MaxKm)
+		}
+		byOrg[b.OrganizationCode] = append(byOrg[b.OrganizationCode], b)
+	}
+	for org, list := range byOrg {
+		sort.Slice(list, func(i, j int) bool { return list[i].MinKm < list[j].MinKm })
+		for i := 1; i < len(list); i++ {
+			if list[i].MinKm <= list[i-1].MaxKm {
+				return fmt.Errorf("%s: distance bands %d-%d km and %d-%d km overlap", org, list[i-1].MinKm, list[i-1].MaxKm, list[i].MinKm, list[i].MaxKm)
+			}
+		}
+	}
+	return nil
+}
+
+// NearestStation は (lat, lon) に最も近い駅のIDを返す (駅がない場合は false)
+func (g *TransitGraph) NearestStation(lat, lon float64) (int64, bool) {
+	best, bestDist := -1, math.Inf(1)
+	for i, s := range g.stations {
+		if d := HaversineMeter(lat, lon, s.Lat, s.Lon); d < bestDist {
+			best, bestDist = i, d
+		}
+	}
+	if best < 0 {
+		return 0, false
+	}
+	return g.stations[best].ID, true
+}
+
+// CommutePasses は勤務地の駅から MaxCommuteKm 以内の各駅について、最も安い定期券を返す
+// 勤務地の駅 (と乗り換えの徒歩だけで着く駅) は定期券なし (0円) とする
+// 経路上のいずれかの事業者の運賃表がない駅は結果に含めない
+func (g *TransitGraph) CommutePasses(workplaceStationID int64, fares *FareTable) map[int64]*domain.CommutePass {
+	src := -1
+	for i, s := range g.stations {
+		if s.ID == workplaceStationID {
+			src = i
+			break
+		}
+	}
+	result := make(map[int64]*domain.CommutePass)
+	if src < 0 {
+		return result
+	}
+
+	for _, penalty := range commuteOperatorPenaltiesKm {
+		prev := g.fareRoutes(src, penalty)
+		for i := range g.stations {
+			if i != src && prev[i] == nil {
+				continue
+			}
+			pass, ok := priceCommute(g.routeSegments(i, prev), fares)
+			if !ok {
+				continue
+			}
+			pass.WorkplaceStationID = workplaceStationID
+			id := g.stations[i].ID
+			if cur, found := result[id]; !found || pass.Monthly < cur.Monthly {
+				result[id] = pass
+			}
+		}
+	}
+	return result
+}
+
+// fareRoute は経路探索で各駅に入った直前のエッジ
+type fareRoute struct {
+	from int
+	edge transitEdge
+}
+
+// fareRoutes は src からの最短経路木を返す (重み: 乗車km + 事業者をまたぐ乗り換えごとに penalty)
+func (g *TransitGraph) fareRoutes(src int, penalty float64) []*fareRoute {
+	cost := make([]float64, len(g.stations))
+	km := make([]float64, len(g.stations))
+	for i := range cost {
+		cost[i] = math.Inf(1)
+	}
+	prev := make([]*fareRoute, len(g.stations))
+	cost[src] = 0
+
+	pq := &minutesQueue{{node: src, minutes: 0}}
+	for pq.Len() > 0 {
+		item := heap.Pop(pq).(queueItem)
+		if item.minutes > cost[item.node] {
+			continue
+		}
+		from := g.stations[item.node]
+		for _, e := range g.adj[item.node] {
+			c := item.minutes + e.km
+			if e.transfer && g.stations[e.to].OrganizationCode != from.OrganizationCode {
+				c += penalty
+			}
+			k := km[item.node] + e.km
+			if c < cost[e.to] && k <= domain.MaxCommuteKm {
+				cost[e.to] = c
+				km[e.to] = k
+				prev[e.to] = &fareRoute{from: item.node, edge: e}
+				heap.Push(pq, queueItem{node: e.to, minutes: c})
+			}
+		}
+	}
+	return prev
+}
+
+// fareSegment は同じ事業者に続けて乗車する区間
+type fareSegment struct {
+	org string
+	km  float64
+}
+
+// routeSegments は駅 i から勤務地までの経路を事業者ごとの区間に分ける
+// 乗り換えをはさんでも同じ事業者に乗り続ける場合は1つの区間とする (通算運賃)
+func (g *TransitGraph) routeSegments(i int, prev []*fareRoute) []fareSegment {
+	var segs []fareSegment
+	for r := prev[i]; r != nil; r = prev[r.from] {
+		if r.edge.transfer {
+			continue
+		}
+		org := g.stations[r.from].OrganizationCode
+		if n := len(segs); n > 0 && segs[n-1].org == org {
+			segs[n-1].km += r.edge.km
+			continue
+		}
+		segs = append(segs, fareSegment{org: org, km: r.edge.km})
+	}
+	return segs
+}
+
+// priceCommute は区間ごとの定期代を合算し、1か月あたりが最も安い有効期間を選ぶ
+func priceCommute(segs []fareSegment, fares *FareTable) (*domain.CommutePass, bool) {
+	pass := &domain.CommutePass{Operators: []string{}}
+	if len(segs) == 0 {
+		return pass, true
+	}
+
+	bands := make([]*domain.FareBand, len(segs))
+	var totalKm float64
+	for i, seg := range segs {
+		b := fares.Band(seg.org, seg.km)
+		if b == nil {
+			return nil, false
+		}
+		bands[i] = b
+		totalKm += seg.km
+		pass.Fare += b.Fare
+		pass.Operators = append(pass.Operators, seg.org)
+	}
+	pass.DistanceKm = math.Round(totalKm*10) / 10
+
+	found := false
+	for _, months := range domain.PassMonths {
+		price := 0
+		for _, b := range bands {
+			p := b.PassPrice(months)
+			if p == 0 {
+				price = 0
+				break
+			}
+			price += p
+		}
+		if price == 0 {
+			continue
+		}
+		monthly := (price + months - 1) / months
+		if !found || monthly < pass.Monthly {
+			pass.Months, pass.Price, pass.Monthly = months, price, monthly
+			found = true
+		}
+	}
+	if !found {
+		return nil, false
+	}
+	return pass, true
+}
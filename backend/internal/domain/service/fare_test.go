@@ -0,0 +1,107 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/gigaptera/hikkoshi-lens/backend/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// transitFixture の事業者 X, Y の運賃表
+func fareFixture() []*domain.FareBand {
+	return []*domain.FareBand{
+		{OrganizationCode: "X", MinKm: 1, MaxKm: 3, Fare: 150, Pass1M: 5000, Pass3M: 14000},
+		{OrganizationCode: "X", MinKm: 4, MaxKm: 10, Fare: 200, Pass1M: 6000, Pass3M: 17000, Pass6M: 32000},
+		{OrganizationCode: "Y", MinKm: 1, MaxKm: 3, Fare: 160, Pass1M: 5500, Pass3M: 15000},
+	}
+}
+
+func TestFareTable_Band(t *testing.T) {
+	fares := NewFareTable(fareFixture())
+
+	assert.Equal(t, 150, fares.Band("X", 0.4).Fare) // 1km未満は1km
+	assert.Equal(t, 150, fares.Band("X", 3.0).Fare)
+	assert.Equal(t, 200, fares.Band("X", 3.1).Fare) // 切り上げて4km
+	assert.Nil(t, fares.Band("X", 10.5))
+	assert.Nil(t, fares.Band("Z", 1))
+}
+
+func TestValidateFareBands(t *testing.T) {
+	assert.NoError(t, ValidateFareBands(fareFixture()))
+
+	overlap := append(fareFixture(), &domain.FareBand{OrganizationCode: "X", MinKm: 10, MaxKm: 20, Fare: 300})
+	assert.Error(t, ValidateFareBands(overlap))
+	assert.Error(t, ValidateFareBands([]*domain.FareBand{{OrganizationCode: "X", MinKm: 5, MaxKm: 4, Fare: 100}}))
+	assert.Error(t, ValidateFareBands([]*domain.FareBand{{OrganizationCode: "X", MinKm: 1, MaxKm: 4}}))
+	assert.Error(t, ValidateFareBands([]*domain.FareBand{{MinKm: 1, MaxKm: 4, Fare: 100}}))
+}
+
+func TestTransitGraph_CommutePasses(t *testing.T) {
+	g := NewTransitGraph(transitFixture(), DefaultTransitOptions())
+	passes := g.CommutePasses(1, NewFareTable(fareFixture()))
+
+	// 勤務地の駅は定期券なし
+	require.Contains(t, passes, int64(1))
+	assert.Equal(t, 0, passes[1].Monthly)
+	assert.Empty(t, passes[1].Operators)
+
+	// 約2km (3km帯): 3か月定期 14000 / 3 = 4667
+	require.Contains(t, passes, int64(2))
+	assert.Equal(t, 3, passes[2].Months)
+	assert.Equal(t, 4667, passes[2].Monthly)
+	assert.Equal(t, 150, passes[2].Fare)
+
+	// 約4km: 6か月定期が最も安い
+	require.Contains(t, passes, int64(3))
+	assert.Equal(t, 6, passes[3].Months)
+	assert.Equal(t, 5334, passes[3].Monthly)
+
+	// B線の駅: Y 2km + X 2km の連絡定期 (Y は6か月定期なし)
+	require.Contains(t, passes, int64(5))
+	assert.Equal(t, []string{"Y", "X"}, passes[5].Operators)
+	assert.Equal(t, 310, passes[5].Fare)
+	assert.Equal(t, 3, passes[5].Months)
+	assert.Equal(t, 9667, passes[5].Monthly)
+	assert.Equal(t, int64(1), passes[5].WorkplaceStationID)
+}
+
+func TestTransitGraph_CommutePassesMissingFare(t *testing.T) {
+	g := NewTransitGraph(transitFixture(), DefaultTransitOptions())
+	passes := g.CommutePasses(1, NewFareTable(fareFixture()[:2]))
+
+	assert.Contains(t, passes, int64(3))
+	assert.NotContains(t, passes, int64(5)) // Y の運賃表がない
+	assert.Empty(t, g.CommutePasses(99, NewFareTable(fareFixture())))
+}
+
+func TestTransitGraph_CommutePassesPrefersCheaperOperator(t *testing.T) {
+	// 勤務地から自宅まで、高い Y 線 (約3.2km) と安い X 線 (約3.5km) がある
+	stations := []*domain.Station{
+		{ID: 1, StationCode: "X01", OrganizationCode: "X", LineName: "X線", Name: "本町", Lat: 35.0, Lon: 139.0},
+		{ID: 2, StationCode: "X02", OrganizationCode: "X", LineName: "X線", Name: "東", Lat: 35.0, Lon: 139.0385},
+		{ID: 3, StationCode: "Y01", OrganizationCode: "Y", LineName: "Y線", Name: "本町", Lat: 35.0005, Lon: 139.0},
+		{ID: 4, StationCode: "Y02", OrganizationCode: "Y", LineName: "Y線", Name: "東", Lat: 35.0005, Lon: 139.035},
+	}
+	fares := NewFareTable([]*domain.FareBand{
+		{OrganizationCode: "X", MinKm: 1, MaxKm: 10, Fare: 200, Pass1M: 5000},
+		{OrganizationCode: "Y", MinKm: 1, MaxKm: 10, Fare: 300, Pass1M: 9000},
+	})
+
+	passes := NewTransitGraph(stations, DefaultTransitOptions()).CommutePasses(1, fares)
+	require.Contains(t, passes, int64(2))
+	// 距離が最短の Y 線経由ではなく X 線だけの経路
+	assert.Equal(t, []string{"X"}, passes[2].Operators)
+	assert.Equal(t, 5000, passes[2].Monthly)
+}
+
+func TestTransitGraph_NearestStation(t *testing.T) {
+	g := NewTransitGraph(transitFixture(), DefaultTransitOptions())
+
+	id, ok := g.NearestStation(35.0, 139.043)
+	assert.True(t, ok)
+	assert.Equal(t, int64(3), id)
+
+	_, ok = NewTransitGraph(nil, DefaultTransitOptions()).NearestStation(35.0, 139.0)
+	assert.False(t, ok)
+}
@@ -0,0 +1,208 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/gigaptera/hikkoshi-lens/backend/internal/domain"
+)
+
+// 解説文の判定に使う閾値
+const (
+	insightCheapRatio     = 0.9 // 路線中央値に対する家賃の比がこれ以下なら「安い」
+	insightExpensiveRatio = 1.1
+	insightHighScore      = 80.0
+	insightLowScore       = 30.0
+	insightHazardWarning  = 2   // 警戒以上
+	insightSteepSlope     = 5.0 // 平均勾配 (%) がこれ以上なら「坂が多い」
+	insightFlatSlope      = 2.0
+	insightLowland        = 2.0 // 周辺の低地からの比高 (m) がこれ未満なら浸水に注意
+)
+
+var hazardLabels = map[int]string{1: "注意", 2: "警戒", 3: "危険"}
+
+// TemplateInsightGenerator は駅データから決まった文面で解説文を作る (同じ入力なら常に同じ出力)
+// 住民の声は実際の口コミから作るべきものなので生成しない
+type TemplateInsightGenerator struct{}
+
+func NewTemplateInsightGenerator() *TemplateInsightGenerator {
+	return &TemplateInsightGenerator{}
+}
+
+func (g *TemplateInsightGenerator) Name() string {
+	return "template-v1"
+}
+
+func (g *TemplateInsightGenerator) Generate(ctx context.Context, in *domain.InsightInput) (*domain.AIInsight, error) {
+	var pros, cons []string
+	lineName := ""
+	if len(in.Lines) > 0 {
+		lineName = in.Lines[0]
+	}
+
+	// 家賃: 同じ路線の中央値と比べる
+	rent := in.Rents[domain.Layouts[0]]
+	trend := ""
+	if rent > 0 && in.LineMedian > 0 {
+		ratio := rent / in.LineMedian
+		switch {
+		case ratio <= insightCheapRatio:
+			pros = append(pros, fmt.Sprintf("1R〜1DKの家賃相場が%.1f万円で、%s沿線の中央値%.1f万円より安い", rent, lineName, in.LineMedian))
+			trend = fmt.Sprintf("%s沿線の中では家賃が安めの駅です", lineName)
+		case ratio >= insightExpensiveRatio:
+			cons = append(cons, fmt.Sprintf("1R〜1DKの家賃相場が%.1f万円で、%s沿線の中央値%.1f万円より高い", rent, lineName, in.LineMedian))
+			trend = fmt.Sprintf("%s沿線の中では家賃が高めの駅です", lineName)
+		default:
+			trend = fmt.Sprintf("%s沿線の中では平均的な家賃の駅です", lineName)
+		}
+	}
+
+	// 交通
+	if n := len(in.Lines); n >= 3 {
+		pros = append(pros, fmt.Sprintf("%d路線が利用でき、乗り換えに便利", n))
+	}
+
+	// 生活施設
+	if f, ok := in.Facilities[domain.POICategorySupermarket]; ok {
+		if f >= 3 {
+			pros = append(pros, fmt.Sprintf("徒歩圏にスーパーが%d軒ある", f))
+		} else if f == 0 {
+			cons = append(cons, "徒歩圏にスーパーがない")
+		}
+	}
+	if f := in.Facilities[domain.POICategoryHospital]; f > 0 {
+		pros = append(pros, "徒歩圏に病院がある")
+	}
+	if f := in.Facilities[domain.POICategoryPark]; f >= 3 {
+		pros = append(pros, fmt.Sprintf("徒歩圏に公園が%dか所ある", f))
+	}
+
+	// 治安
+	if s, ok := in.Scores["safety"]; ok {
+		if s >= insightHighScore {
+			pros = append(pros, fmt.Sprintf("治安スコアが%.0fと高い", s))
+		} else if s <= insightLowScore {
+			cons = append(cons, fmt.Sprintf("治安スコアが%.0fと低め", s))
+		}
+	}
+
+	// 災害リスク
+	if len(in.Hazards) > 0 {
+		flood, landslide := in.Hazards["flood"], in.Hazards["landslide"]
+		if flood >= insightHazardWarning {
+			cons = append(cons, fmt.Sprintf("洪水リスクが「%s」レベル", hazardLabels[flood]))
+		}
+		if landslide >= insightHazardWarning {
+			cons = append(cons, fmt.Sprintf("土砂災害リスクが「%s」レベル", hazardLabels[landslide]))
+		}
+		if flood == 0 && landslide == 0 {
+			pros = append(pros, "ハザードマップ上の洪水・土砂災害リスクが低い")
+		}
+	}
+
+	// 地形
+	if t := in.Terrain; t != nil {
+		switch {
+		case t.AvgSlope >= insightSteepSlope:
+			cons = append(cons, fmt.Sprintf("駅周辺は坂が多い (徒歩圏の平均勾配%.1f%%)", t.AvgSlope))
+		case t.AvgSlope <= insightFlatSlope:
+			pros = append(pros, "駅周辺は坂が少なく平坦")
+		}
+		if t.RelativeHeight < insightLowland {
+			cons = append(cons, "周辺で最も低い土地にあり、大雨の際は浸水に注意")
+		}
+	}
+
+	if len(pros) == 0 && len(cons) == 0 {
+		return nil, domain.ErrInsufficientInsightData
+	}
+	return &domain.AIInsight{
+		Summary: domain.AISummary{Pros: limitItems(pros), Cons: limitItems(cons)},
+		Trend:   trend,
+	}, nil
+}
+
+func limitItems(items []string) []string {
+	if items == nil {
+		return []string{}
+	}
+	if len(items) > domain.MaxInsightItems {
+		return items[:domain.MaxInsightItems]
+	}
+	return items
+}
+
+// SetInsightLineMedians は各駅の LineMedian に同じ路線の 1R〜1DK 家賃相場の中央値を設定する
+// 家賃データのある駅が2駅未満の路線では比較できないため設定しない
+func SetInsightLineMedians(inputs []*domain.InsightInput) {
+	rentsByLine := make(map[domain.LineKey][]float64)
+	for _, in := range inputs {
+		if rent := in.Rents[domain.Layouts[0]]; rent > 0 {
+			rentsByLine[in.LineKey] = append(rentsByLine[in.LineKey], rent)
+		}
+	}
+	for _, in := range inputs {
+		if rents := rentsByLine[in.LineKey]; len(rents) >= 2 {
+			in.LineMedian = median(rents)
+		}
+	}
+}
+
+// InsightSystemPrompt はLLMに与える指示
+// 材料にない事実 (店名・再開発・住民の声など) を作らせないことを最優先にする
+const InsightSystemPrompt = `あなたは賃貸物件探しを支援するサービスの編集者です。
+与えられた駅のデータ (JSON) だけを根拠に、一人暮らしの部屋探しをする人向けの駅の長所・短所を日本語で書いてください。
+
+制約:
+- データにない事実 (店名、再開発、イベント、住民の感想など) は書かない
+- 長所 (pros) と短所 (cons) はそれぞれ0〜5件、1件80文字以内の体言止めの短文
+- trend はデータから言える駅の特徴を1文 (200文字以内)。言えることがなければ空文字
+- 数値はデータの値をそのまま使う (家賃の単位は万円、スコアは0〜100、災害リスクは0:なし〜3:危険、地形の勾配は%、高さはm)
+
+次のJSONだけを出力してください:
+{"pros": ["..."], "cons": ["..."], "trend": "..."}`
+
+// BuildInsightPrompt はLLMに渡す駅データ (ユーザーメッセージ) を作る
+func BuildInsightPrompt(in *domain.InsightInput) string {
+	b, _ := json.MarshalIndent(in, "", "  ")
+	return "駅のデータ:\n" + string(b)
+}
+
+// ParseInsightOutput はLLMの出力 (JSON) を解説文に変換して検証する
+// コードブロックで囲まれた出力も受け付ける
+func ParseInsightOutput(content string) (*domain.AIInsight, error) {
+	content = strings.TrimSpace(content)
+	content = strings.TrimPrefix(content, "```json")
+	content = strings.TrimPrefix(content, "```")
+	content = strings.TrimSuffix(content, "```")
+
+	var out struct {
+		Pros  []string `json:"pros"`
+		Cons  []string `json:"cons"`
+		Trend string   `json:"trend"`
+	}
+	dec := json.NewDecoder(strings.NewReader(content))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&out); err != nil {
+		return nil, fmt.Errorf("%w: %v", domain.ErrInvalidInsight, err)
+	}
+
+	insight := &domain.AIInsight{
+		Summary: domain.AISummary{Pros: trimItems(out.Pros), Cons: trimItems(out.Cons)},
+		Trend:   strings.TrimSpace(out.Trend),
+	}
+	if err := domain.ValidateInsight(insight); err != nil {
+		return nil, err
+	}
+	return insight, nil
+}
+
+func trimItems(items []string) []string {
+	out := make([]string, 0, len(items))
+	for _, item := range items {
+		out = append(out, strings.TrimSpace(item))
+	}
+	return out
+}
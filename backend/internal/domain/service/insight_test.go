@@ -0,0 +1,111 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/gigaptera/hikkoshi-lens/backend/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func insightInput() *domain.InsightInput {
+	return &domain.InsightInput{
+		StationID:  1,
+		Name:       "池尻大橋",
+		Lines:      []string{"田園都市線"},
+		Scores:     map[string]float64{"safety": 85},
+		Rents:      map[string]float64{"1r_1k_1dk": 8.0},
+		LineMedian: 9.5,
+		Facilities: map[string]int{domain.POICategorySupermarket: 4, domain.POICategoryPark: 1},
+		Hazards:    map[string]int{"flood": 2, "landslide": 0},
+	}
+}
+
+func TestTemplateInsightGenerator_Generate(t *testing.T) {
+	g := NewTemplateInsightGenerator()
+	insight, err := g.Generate(context.Background(), insightInput())
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{
+		"1R〜1DKの家賃相場が8.0万円で、田園都市線沿線の中央値9.5万円より安い",
+		"徒歩圏にスーパーが4軒ある",
+		"治安スコアが85と高い",
+	}, insight.Summary.Pros)
+	assert.Equal(t, []string{"洪水リスクが「警戒」レベル"}, insight.Summary.Cons)
+	assert.Equal(t, "田園都市線沿線の中では家賃が安めの駅です", insight.Trend)
+	assert.Empty(t, insight.ResidentVoices.Positive)
+	assert.Empty(t, insight.ResidentVoices.Negative)
+	assert.NoError(t, domain.ValidateInsight(insight))
+
+	// 同じ入力なら同じ出力
+	again, err := g.Generate(context.Background(), insightInput())
+	require.NoError(t, err)
+	assert.Equal(t, insight, again)
+}
+
+func TestTemplateInsightGenerator_Terrain(t *testing.T) {
+	g := NewTemplateInsightGenerator()
+	in := &domain.InsightInput{StationID: 1, Name: "代官山", Terrain: &domain.StationTerrain{AvgSlope: 6.2, ElevationGain: 18, RelativeHeight: 12}}
+	insight, err := g.Generate(context.Background(), in)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"駅周辺は坂が多い (徒歩圏の平均勾配6.2%)"}, insight.Summary.Cons)
+
+	in.Terrain = &domain.StationTerrain{AvgSlope: 0.8, RelativeHeight: 0.5}
+	insight, err = g.Generate(context.Background(), in)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"駅周辺は坂が少なく平坦"}, insight.Summary.Pros)
+	assert.Equal(t, []string{"周辺で最も低い土地にあり、大雨の際は浸水に注意"}, insight.Summary.Cons)
+}
+
+func TestTemplateInsightGenerator_NoData(t *testing.T) {
+	_, err := NewTemplateInsightGenerator().Generate(context.Background(), &domain.InsightInput{StationID: 1, Name: "駅"})
+	assert.ErrorIs(t, err, domain.ErrInsufficientInsightData)
+}
+
+func TestSetInsightLineMedians(t *testing.T) {
+	line := domain.LineKey{OrganizationCode: "tokyu", LineName: "田園都市線"}
+	other := domain.LineKey{OrganizationCode: "keio", LineName: "井の頭線"}
+	inputs := []*domain.InsightInput{
+		{StationID: 1, LineKey: line, Rents: map[string]float64{"1r_1k_1dk": 8}},
+		{StationID: 2, LineKey: line, Rents: map[string]float64{"1r_1k_1dk": 10}},
+		{StationID: 3, LineKey: line, Rents: map[string]float64{"1r_1k_1dk": 12}},
+		{StationID: 4, LineKey: line},
+		{StationID: 5, LineKey: other, Rents: map[string]float64{"1r_1k_1dk": 9}},
+	}
+	SetInsightLineMedians(inputs)
+
+	assert.Equal(t, 10.0, inputs[0].LineMedian)
+	assert.Equal(t, 10.0, inputs[3].LineMedian)
+	// 1駅しかない路線は比較しない
+	assert.Zero(t, inputs[4].LineMedian)
+}
+
+func TestInsightInput_Hash(t *testing.T) {
+	a, b := insightInput(), insightInput()
+	assert.Equal(t, a.Hash(), b.Hash())
+
+	b.Rents["1r_1k_1dk"] = 8.1
+	assert.NotEqual(t, a.Hash(), b.Hash())
+}
+
+func TestParseInsightOutput(t *testing.T) {
+	insight, err := ParseInsightOutput("```json\n{\"pros\": [\" 駅前にスーパーがある \"], \"cons\": [], \"trend\": \"家賃は安め\"}\n```")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"駅前にスーパーがある"}, insight.Summary.Pros)
+	assert.Equal(t, "家賃は安め", insight.Trend)
+
+	invalid := []string{
+		`not json`,
+		`{"pros": ["a"], "voices": ["作り話"]}`,
+		`{"pros": [], "cons": []}`,
+		`{"pros": [""]}`,
+		`{"pros": ["` + strings.Repeat("長", domain.MaxInsightItemLength+1) + `"]}`,
+		`{"pros": ["a", "b", "c", "d", "e", "f"]}`,
+	}
+	for _, content := range invalid {
+		_, err := ParseInsightOutput(content)
+		assert.ErrorIs(t, err, domain.ErrInvalidInsight, content)
+	}
+}
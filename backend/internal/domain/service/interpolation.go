@@ -0,0 +1,102 @@
+package service
+
+import (
+	"math"
+	"sort"
+)
+
+// IDWPoint は補間元の観測点
+type IDWPoint struct {
+	Lat, Lon float64
+	Value    float64
+}
+
+// IDWInterpolator は逆距離加重 (IDW) で任意地点の値を推定する
+// 全国のセル×全駅の総当たりを避けるため、観測点を経緯度のバケットに分けて近傍だけを探索する
+type IDWInterpolator struct {
+	power         float64
+	maxDistMeter  float64
+	maxNeighbors  int
+	bucketDeg     float64
+	buckets       map[[2]int][]IDWPoint
+	searchBuckets int
+}
+
+// NewIDWInterpolator は補間器を作成する
+//   - power: 距離の重みの指数 (2が一般的)
+//   - maxDistMeter: これより遠い観測点は使わない。範囲内に1点もなければ推定しない
+//   - maxNeighbors: 近い順に使う観測点の最大数 (0以下なら範囲内のすべて)
+func NewIDWInterpolator(points []IDWPoint, power, maxDistMeter float64, maxNeighbors int) *IDWInterpolator {
+	// バケットの大きさは最大距離程度にする (緯度1度 ≒ 111km)
+	bucketDeg := math.Max(maxDistMeter/111000, 0.01)
+	it := &IDWInterpolator{
+		power:        power,
+		maxDistMeter: maxDistMeter,
+		maxNeighbors: maxNeighbors,
+		bucketDeg:    bucketDeg,
+		buckets:      make(map[[2]int][]IDWPoint),
+		// 経度方向は高緯度ほど1度が短くなるため、日本の北端 (45度) でも範囲を覆えるよう2つ隣まで探す
+		searchBuckets: 2,
+	}
+	for _, p := range points {
+		k := it.bucketKey(p.Lat, p.Lon)
+		it.buckets[k] = append(it.buckets[k], p)
+	}
+	return it
+}
+
+func (it *IDWInterpolator) bucketKey(lat, lon float64) [2]int {
+	return [2]int{int(math.Floor(lat / it.bucketDeg)), int(math.Floor(lon / it.bucketDeg))}
+}
+
+// Interpolate は地点の推定値を返す。範囲内に観測点がなければ ok=false
+// 観測点と同じ位置 (1m未満) ではその観測値をそのまま返す
+func (it *IDWInterpolator) Interpolate(lat, lon float64) (value float64, ok bool) {
+	type neighbor struct {
+		dist  float64
+		value float64
+	}
+	var neighbors []neighbor
+
+	center := it.bucketKey(lat, lon)
+	for di := -it.searchBuckets; di <= it.searchBuckets; di++ {
+		for dj := -it.searchBuckets; dj <= it.searchBuckets; dj++ {
+			for _, p := range it.buckets[[2]int{center[0] + di, center[1] + dj}] {
+				d := HaversineMeter(lat, lon, p.Lat, p.Lon)
+				if d <= it.maxDistMeter {
+					neighbors = append(neighbors, neighbor{dist: d, value: p.Value})
+				}
+			}
+		}
+	}
+	if len(neighbors) == 0 {
+		return 0, false
+	}
+
+	sort.Slice(neighbors, func(i, j int) bool { return neighbors[i].dist < neighbors[j].dist })
+	if neighbors[0].dist < 1 {
+		return neighbors[0].value, true
+	}
+	if it.maxNeighbors > 0 && len(neighbors) > it.maxNeighbors {
+		neighbors = neighbors[:it.maxNeighbors]
+	}
+
+	var sumW, sumWV float64
+	for _, n := range neighbors {
+		w := 1 / math.Pow(n.dist, it.power)
+		sumW += w
+		sumWV += w * n.value
+	}
+	return sumWV / sumW, true
+}
+
+// HaversineMeter は2地点間の大円距離 (m) を返す
+func HaversineMeter(lat1, lon1, lat2, lon2 float64) float64 {
+	const earthRadius = 6371000.0
+	rad := math.Pi / 180
+	dLat := (lat2 - lat1) * rad
+	dLon := (lon2 - lon1) * rad
+	a := math.Sin(dLat/2)*math.Sin(dLat/2) +
+		math.Cos(lat1*rad)*math.Cos(lat2*rad)*math.Sin(dLon/2)*math.Sin(dLon/2)
+	return 2 * earthRadius * math.Asin(math.Sqrt(a))
+}
@@ -0,0 +1,53 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIDWInterpolator_Interpolate(t *testing.T) {
+	// 東京駅 (10万円) と 約1.1km 東の地点 (6万円)
+	points := []IDWPoint{
+		{Lat: 35.6812, Lon: 139.7671, Value: 10},
+		{Lat: 35.6812, Lon: 139.7792, Value: 6},
+	}
+	it := NewIDWInterpolator(points, 2, 5000, 8)
+
+	// 観測点上ではその値
+	v, ok := it.Interpolate(35.6812, 139.7671)
+	assert.True(t, ok)
+	assert.Equal(t, 10.0, v)
+
+	// 中点では平均
+	v, ok = it.Interpolate(35.6812, (139.7671+139.7792)/2)
+	assert.True(t, ok)
+	assert.InDelta(t, 8.0, v, 0.01)
+
+	// 近い方の値に寄る
+	v, ok = it.Interpolate(35.6812, 139.7700)
+	assert.True(t, ok)
+	assert.Greater(t, v, 8.0)
+	assert.Less(t, v, 10.0)
+
+	// 範囲外 (約50km離れた地点) は推定しない
+	_, ok = it.Interpolate(35.2, 139.7671)
+	assert.False(t, ok)
+}
+
+func TestIDWInterpolator_MaxNeighbors(t *testing.T) {
+	points := []IDWPoint{
+		{Lat: 35.0, Lon: 139.0, Value: 1},
+		{Lat: 35.0, Lon: 139.01, Value: 100}, // 約900m
+	}
+	it := NewIDWInterpolator(points, 2, 5000, 1)
+
+	v, ok := it.Interpolate(35.0, 139.001)
+	assert.True(t, ok)
+	assert.Equal(t, 1.0, v) // 最も近い1点だけを使う
+}
+
+func TestHaversineMeter(t *testing.T) {
+	// 東京駅 - 新宿駅 ≒ 6.2km
+	assert.InDelta(t, 6200, HaversineMeter(35.6812, 139.7671, 35.6896, 139.7006), 150)
+}
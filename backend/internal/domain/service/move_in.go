@@ -0,0 +1,128 @@
+package service
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+
+	"github.com/gigaptera/hikkoshi-lens/backend/internal/domain"
+)
+
+//go:embed move_in_costs.json
+var defaultMoveInConfig []byte
+
+// DefaultMoveInConfig は組み込みの初期費用の見積もり設定を返す
+func DefaultMoveInConfig() domain.MoveInConfig {
+	cfg, err := ParseMoveInConfig(defaultMoveInConfig)
+	if err != nil {
+		panic(fmt.Sprintf("invalid embedded move-in config: %v", err))
+	}
+	return cfg
+}
+
+// ParseMoveInConfig はJSONの見積もり設定を読み、既定値 (prefecture_code 0) の有無や負の値を検証する
+func ParseMoveInConfig(data []byte) (domain.MoveInConfig, error) {
+	var cfg domain.MoveInConfig
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return cfg, err
+	}
+	if cfg.TaxRate < 0 || cfg.Moving.IncludedKm < 0 || cfg.Moving.RoadFactor < 1 {
+		return cfg, fmt.Errorf("tax_rate and included_km must be non-negative and road_factor at least 1")
+	}
+	seen := make(map[int]bool)
+	for _, c := range cfg.Customs {
+		if c.PrefectureCode < 0 || c.PrefectureCode > 47 {
+			return cfg, fmt.Errorf("invalid prefecture_code %d", c.PrefectureCode)
+		}
+		if seen[c.PrefectureCode] {
+			return cfg, fmt.Errorf("duplicate prefecture_code %d", c.PrefectureCode)
+		}
+		seen[c.PrefectureCode] = true
+		if c.DepositMonths < 0 || c.KeyMoneyMonths < 0 || c.BrokerageMonths < 0 || c.BrokerageMonths > 1 ||
+			c.GuaranteeRatio < 0 || c.AdvanceRentMonths < 0 || c.FireInsurance < 0 || c.KeyExchange < 0 {
+			return cfg, fmt.Errorf("prefecture_code %d: values must be non-negative (brokerage_months at most 1)", c.PrefectureCode)
+		}
+	}
+	if !seen[0] {
+		return cfg, fmt.Errorf("default customs (prefecture_code 0) is required")
+	}
+	for layout, r := range cfg.Moving.Rates {
+		if r.Base < 0 || r.PerKm < 0 {
+			return cfg, fmt.Errorf("moving rate %q: values must be non-negative", layout)
+		}
+	}
+	return cfg, nil
+}
+
+// MoveInEstimator は家賃相場と都道府県の慣習から初期費用を見積もる
+type MoveInEstimator struct {
+	cfg     domain.MoveInConfig
+	customs map[int]domain.MoveInCustom
+}
+
+func NewMoveInEstimator(cfg domain.MoveInConfig) *MoveInEstimator {
+	e := &MoveInEstimator{cfg: cfg, customs: make(map[int]domain.MoveInCustom)}
+	for _, c := range cfg.Customs {
+		e.customs[c.PrefectureCode] = c
+	}
+	return e
+}
+
+// Custom は都道府県の慣習を返す (一覧にない場合は既定値)
+func (e *MoveInEstimator) Custom(prefectureCode int) domain.MoveInCustom {
+	if c, ok := e.customs[prefectureCode]; ok {
+		return c
+	}
+	return e.customs[0]
+}
+
+// Estimate は駅の家賃 (万円) から初期費用の内訳を見積もる
+// 引越し元の座標がある場合は、駅までの道のり (直線距離 × road_factor) で引越し料金を加える
+func (e *MoveInEstimator) Estimate(station *domain.Station, rentManYen float64, q domain.MoveInQuery) *domain.MoveInEstimate {
+	rent := float64(int(rentManYen*10000 + 0.5))
+	c := e.Custom(station.PrefectureCode)
+
+	est := &domain.MoveInEstimate{Rent: int(rent), PrefectureCode: station.PrefectureCode}
+	add := func(item domain.MoveInItem) {
+		est.Items = append(est.Items, item)
+		est.Total += item.Amount
+	}
+	add(domain.MoveInItem{Key: domain.MoveInDeposit, Label: "敷金", Amount: yen(rent * c.DepositMonths), Basis: monthsBasis(c.DepositMonths), Refundable: true})
+	add(domain.MoveInItem{Key: domain.MoveInKeyMoney, Label: "礼金", Amount: yen(rent * c.KeyMoneyMonths), Basis: monthsBasis(c.KeyMoneyMonths)})
+	add(domain.MoveInItem{
+		Key:    domain.MoveInBrokerage,
+		Label:  "仲介手数料",
+		Amount: yen(rent * c.BrokerageMonths * (1 + e.cfg.TaxRate)),
+		Basis:  fmt.Sprintf("%s + 消費税%s%%", monthsBasis(c.BrokerageMonths), strconv.FormatFloat(e.cfg.TaxRate*100, 'f', -1, 64)),
+	})
+	add(domain.MoveInItem{Key: domain.MoveInGuarantee, Label: "保証会社", Amount: yen(rent * c.GuaranteeRatio), Basis: fmt.Sprintf("家賃の%s%%", strconv.FormatFloat(c.GuaranteeRatio*100, 'f', -1, 64))})
+	add(domain.MoveInItem{Key: domain.MoveInAdvanceRent, Label: "前家賃", Amount: yen(rent * c.AdvanceRentMonths), Basis: monthsBasis(c.AdvanceRentMonths)})
+	add(domain.MoveInItem{Key: domain.MoveInFireInsurance, Label: "火災保険", Amount: c.FireInsurance, Basis: "2年契約の目安"})
+	add(domain.MoveInItem{Key: domain.MoveInKeyExchange, Label: "鍵交換", Amount: c.KeyExchange, Basis: "目安"})
+
+	if rate, ok := e.cfg.Moving.Rates[q.Layout]; ok && q.HasOrigin {
+		km := HaversineMeter(q.FromLat, q.FromLon, station.Lat, station.Lon) / 1000 * e.cfg.Moving.RoadFactor
+		est.MovingDistanceKm = math.Round(km*10) / 10
+		amount := float64(rate.Base) + math.Max(0, km-e.cfg.Moving.IncludedKm)*float64(rate.PerKm)
+		add(domain.MoveInItem{
+			Key:    domain.MoveInMoving,
+			Label:  "引越し業者",
+			Amount: int(math.Round(amount/100) * 100),
+			Basis:  fmt.Sprintf("道のり約%skm (%skmまで基本料金)", strconv.FormatFloat(est.MovingDistanceKm, 'f', -1, 64), strconv.FormatFloat(e.cfg.Moving.IncludedKm, 'f', -1, 64)),
+		})
+	}
+	return est
+}
+
+func yen(v float64) int {
+	return int(math.Round(v))
+}
+
+func monthsBasis(months float64) string {
+	if months == 0 {
+		return "なし"
+	}
+	return "家賃の" + strconv.FormatFloat(months, 'f', -1, 64) + "か月分"
+}
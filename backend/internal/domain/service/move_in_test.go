@@ -0,0 +1,101 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/gigaptera/hikkoshi-lens/backend/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDefaultMoveInConfig(t *testing.T) {
+	cfg := DefaultMoveInConfig()
+	assert.NotEmpty(t, cfg.Customs)
+	for _, layout := range domain.Layouts {
+		assert.Contains(t, cfg.Moving.Rates, layout)
+	}
+}
+
+func TestParseMoveInConfig_Invalid(t *testing.T) {
+	for _, data := range []string{
+		`{"customs": [{"prefecture_code": 13}], "moving": {"road_factor": 1.3}}`,                                                   // 既定値なし
+		`{"customs": [{"prefecture_code": 0}, {"prefecture_code": 0}], "moving": {"road_factor": 1.3}}`,                            // 重複
+		`{"customs": [{"prefecture_code": 0, "brokerage_months": 2}], "moving": {"road_factor": 1.3}}`,                             // 法定上限超え
+		`{"customs": [{"prefecture_code": 0}], "moving": {"road_factor": 0.5}}`,                                                    // 直線距離より短い
+		`{"customs": [{"prefecture_code": 0}], "moving": {"road_factor": 1.3, "rates": {"1r_1k_1dk": {"base": -1, "per_km": 0}}}}`, // 負の料金
+		`not json`,
+	} {
+		_, err := ParseMoveInConfig([]byte(data))
+		assert.Error(t, err, data)
+	}
+}
+
+func testMoveInEstimator() *MoveInEstimator {
+	return NewMoveInEstimator(domain.MoveInConfig{
+		TaxRate: 0.1,
+		Customs: []domain.MoveInCustom{
+			{PrefectureCode: 0, DepositMonths: 1, KeyMoneyMonths: 1, BrokerageMonths: 1, GuaranteeRatio: 0.5, AdvanceRentMonths: 1, FireInsurance: 20000, KeyExchange: 15000},
+			{PrefectureCode: 27, DepositMonths: 2, KeyMoneyMonths: 0, BrokerageMonths: 0.5, GuaranteeRatio: 0.5, AdvanceRentMonths: 1, FireInsurance: 18000},
+		},
+		Moving: domain.MovingCostModel{
+			IncludedKm: 10,
+			RoadFactor: 1.5,
+			Rates:      map[string]domain.MovingRate{"1r_1k_1dk": {Base: 40000, PerKm: 100}},
+		},
+	})
+}
+
+func moveInAmounts(est *domain.MoveInEstimate) map[string]int {
+	amounts := make(map[string]int)
+	for _, item := range est.Items {
+		amounts[item.Key] = item.Amount
+	}
+	return amounts
+}
+
+func TestMoveInEstimator_Estimate(t *testing.T) {
+	e := testMoveInEstimator()
+	station := &domain.Station{PrefectureCode: 13, Lat: 35.0, Lon: 139.0}
+
+	est := e.Estimate(station, 8.5, domain.MoveInQuery{Layout: "1r_1k_1dk"})
+	a := moveInAmounts(est)
+	assert.Equal(t, 85000, est.Rent)
+	assert.Equal(t, 85000, a[domain.MoveInDeposit])
+	assert.Equal(t, 85000, a[domain.MoveInKeyMoney])
+	assert.Equal(t, 93500, a[domain.MoveInBrokerage]) // 消費税込み
+	assert.Equal(t, 42500, a[domain.MoveInGuarantee])
+	assert.NotContains(t, a, domain.MoveInMoving) // 引越し元の指定なし
+	assert.Equal(t, 85000*3+93500+42500+20000+15000, est.Total)
+	assert.True(t, est.Items[0].Refundable)
+}
+
+func TestMoveInEstimator_PrefectureCustoms(t *testing.T) {
+	e := testMoveInEstimator()
+
+	est := e.Estimate(&domain.Station{PrefectureCode: 27}, 6, domain.MoveInQuery{Layout: "1r_1k_1dk"})
+	a := moveInAmounts(est)
+	assert.Equal(t, 120000, a[domain.MoveInDeposit])
+	assert.Equal(t, 0, a[domain.MoveInKeyMoney])
+	assert.Equal(t, 33000, a[domain.MoveInBrokerage])
+	assert.Equal(t, "なし", est.Items[1].Basis)
+}
+
+func TestMoveInEstimator_Moving(t *testing.T) {
+	e := testMoveInEstimator()
+	station := &domain.Station{PrefectureCode: 13, Lat: 35.0, Lon: 139.0}
+
+	// 約20km (道のり約30km): 基本料金 + 20km分
+	q := domain.MoveInQuery{Layout: "1r_1k_1dk", HasOrigin: true, FromLat: 35.18, FromLon: 139.0}
+	est := e.Estimate(station, 8, q)
+	require.Contains(t, moveInAmounts(est), domain.MoveInMoving)
+	assert.InDelta(t, 30.0, est.MovingDistanceKm, 0.1)
+	assert.Equal(t, 42000, moveInAmounts(est)[domain.MoveInMoving])
+
+	// 近距離は基本料金のみ
+	q.FromLat = 35.01
+	assert.Equal(t, 40000, moveInAmounts(e.Estimate(station, 8, q))[domain.MoveInMoving])
+
+	// 料金のない間取りは引越し料金を含めない
+	q.Layout = "4ldk"
+	assert.NotContains(t, moveInAmounts(e.Estimate(station, 8, q)), domain.MoveInMoving)
+}
@@ -0,0 +1,28 @@
+package service
+
+import (
+	"sort"
+
+	"github.com/gigaptera/hikkoshi-lens/backend/internal/domain"
+)
+
+// NightSafetyScores は集計結果から night_safety 軸のスコアを作る (駅ID順)
+// 全国の駅の相対値ではなく街灯の密度・交番までの距離の絶対的な基準で評価するため、正規化しない
+func NightSafetyScores(rows map[int64]*domain.StationNightSafety, version string) []*domain.StationScore {
+	scores := make([]*domain.StationScore, 0, len(rows))
+	for stationID, n := range rows {
+		v, ok := n.Score()
+		if !ok {
+			continue
+		}
+		scores = append(scores, &domain.StationScore{
+			StationID:       stationID,
+			Axis:            domain.NightSafetyScoreAxis,
+			RawScore:        v,
+			NormalizedScore: v,
+			DataVersion:     version,
+		})
+	}
+	sort.Slice(scores, func(i, j int) bool { return scores[i].StationID < scores[j].StationID })
+	return scores
+}
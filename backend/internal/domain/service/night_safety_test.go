@@ -0,0 +1,54 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/gigaptera/hikkoshi-lens/backend/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStationNightSafety_Score(t *testing.T) {
+	value := func(v float64) *float64 { return &v }
+
+	cases := []struct {
+		name   string
+		n      *domain.StationNightSafety
+		want   float64
+		wantOK bool
+	}{
+		{"明るく交番が近い", &domain.StationNightSafety{StreetLampDensity: value(400), NearestPoliceMeter: value(200)}, 100, true},
+		{"街灯が半分・交番が中間", &domain.StationNightSafety{StreetLampDensity: value(150), NearestPoliceMeter: value(900)}, 50, true},
+		{"暗く交番が遠い", &domain.StationNightSafety{StreetLampDensity: value(0), NearestPoliceMeter: value(2000)}, 0, true},
+		// 街灯のデータがない地域は交番の近さだけで評価する
+		{"街灯なし", &domain.StationNightSafety{NearestPoliceMeter: value(300)}, 100, true},
+		{"データなし", &domain.StationNightSafety{}, 0, false},
+		{"未集計", nil, 0, false},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, ok := tc.n.Score()
+			assert.Equal(t, tc.wantOK, ok)
+			assert.InDelta(t, tc.want, got, 0.01)
+		})
+	}
+}
+
+func TestNightSafetyScores(t *testing.T) {
+	value := func(v float64) *float64 { return &v }
+	rows := map[int64]*domain.StationNightSafety{
+		2: {StationID: 2, StreetLampDensity: value(300), NearestPoliceMeter: value(1500)},
+		1: {StationID: 1, NearestPoliceMeter: value(300)},
+		3: {StationID: 3},
+	}
+
+	scores := NightSafetyScores(rows, "v1")
+	require.Len(t, scores, 2)
+	assert.Equal(t, int64(1), scores[0].StationID)
+	assert.Equal(t, 100.0, scores[0].NormalizedScore)
+	assert.Equal(t, int64(2), scores[1].StationID)
+	assert.Equal(t, 50.0, scores[1].RawScore)
+	assert.Equal(t, 50.0, scores[1].NormalizedScore)
+	assert.Equal(t, domain.NightSafetyScoreAxis, scores[1].Axis)
+	assert.Equal(t, "v1", scores[1].DataVersion)
+}
@@ -0,0 +1,138 @@
+package service
+
+import (
+	_ "embed"
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/gigaptera/hikkoshi-lens/backend/internal/domain"
+)
+
+//go:embed banned_words.txt
+var defaultBannedWords string
+
+// 審査時の注意点 (受け付けはするが、審査画面で確認してもらう)
+const (
+	ReviewFlagURL      = "url"      // URLを含む (宣伝の可能性)
+	ReviewFlagHTML     = "html"     // HTMLタグらしき文字列を含む
+	ReviewFlagContact  = "contact"  // メールアドレス・電話番号を含む (個人情報の可能性)
+	ReviewFlagRepeated = "repeated" // 同じ文字の繰り返しが多い
+)
+
+var (
+	reviewURLPattern     = regexp.MustCompile(`(?i)(https?://|www\.)`)
+	reviewHTMLPattern    = regexp.MustCompile(`<\s*/?\s*[a-zA-Z!]`)
+	reviewContactPattern = regexp.MustCompile(`[\w.+-]+@[\w-]+\.[\w.]+|0\d{1,4}-?\d{1,4}-?\d{3,4}`)
+)
+
+// reviewRepeatLimit は同じ文字がこれ以上続くと repeated とする
+const reviewRepeatLimit = 10
+
+// ReviewFilter は口コミの本文を検査する
+// 長さ・制御文字・禁止語に当たるものは受け付けず、URLなど判断が必要なものは注意点を付けて審査に回す
+type ReviewFilter struct {
+	bannedWords []string // normalizeReviewText 済み
+}
+
+func NewReviewFilter(bannedWords []string) *ReviewFilter {
+	f := &ReviewFilter{}
+	for _, w := range bannedWords {
+		if w = normalizeReviewText(w); w != "" {
+			f.bannedWords = append(f.bannedWords, w)
+		}
+	}
+	return f
+}
+
+// DefaultBannedWords は組み込みの禁止語を返す
+func DefaultBannedWords() []string {
+	return ParseBannedWords(defaultBannedWords)
+}
+
+// ParseBannedWords は1行1語の禁止語リストを読み取る (# から始まる行と空行は無視する)
+func ParseBannedWords(data string) []string {
+	var words []string
+	for _, line := range strings.Split(data, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		words = append(words, line)
+	}
+	return words
+}
+
+// Check は本文1つを検査し、審査時の注意点を返す
+// 受け付けない場合は domain.ErrInvalidReview (形式・長さ) か domain.ErrReviewRejected (禁止語)
+func (f *ReviewFilter) Check(text string) ([]string, error) {
+	if !utf8.ValidString(text) {
+		return nil, fmt.Errorf("%w: text is not valid UTF-8", domain.ErrInvalidReview)
+	}
+	for _, r := range text {
+		if unicode.IsControl(r) && r != '\n' && r != '\r' && r != '\t' {
+			return nil, fmt.Errorf("%w: text contains control characters", domain.ErrInvalidReview)
+		}
+	}
+
+	normalized := normalizeReviewText(text)
+	if n := utf8.RuneCountInString(normalized); n < domain.MinReviewTextLength || n > domain.MaxReviewTextLength {
+		return nil, fmt.Errorf("%w: text must be %d-%d characters", domain.ErrInvalidReview, domain.MinReviewTextLength, domain.MaxReviewTextLength)
+	}
+	for _, w := range f.bannedWords {
+		if strings.Contains(normalized, w) {
+			return nil, domain.ErrReviewRejected
+		}
+	}
+
+	var flags []string
+	if reviewURLPattern.MatchString(normalized) {
+		flags = append(flags, ReviewFlagURL)
+	}
+	if reviewHTMLPattern.MatchString(normalized) {
+		flags = append(flags, ReviewFlagHTML)
+	}
+	if reviewContactPattern.MatchString(normalized) {
+		flags = append(flags, ReviewFlagContact)
+	}
+	if hasRepeatedRune(normalized, reviewRepeatLimit) {
+		flags = append(flags, ReviewFlagRepeated)
+	}
+	return flags, nil
+}
+
+// normalizeReviewText は禁止語・長さの判定用に本文を正規化する
+// ゼロ幅文字・ソフトハイフンを除き、全角英数記号を半角に、英字を小文字に揃える (全角で書いた語、ゼロ幅文字を挟んだ語を禁止語に当てるため)
+func normalizeReviewText(s string) string {
+	var b strings.Builder
+	for _, r := range strings.TrimSpace(s) {
+		switch {
+		case r == '\u200b' || r == '\u200c' || r == '\u200d' || r == '\u2060' || r == '\ufeff' || r == '\u00ad':
+			continue
+		case r >= '\uff01' && r <= '\uff5e':
+			r -= '\uff01' - '!'
+		case r == '\u3000':
+			r = ' '
+		}
+		b.WriteRune(unicode.ToLower(r))
+	}
+	return strings.TrimSpace(b.String())
+}
+
+func hasRepeatedRune(s string, limit int) bool {
+	var prev rune
+	count := 0
+	for _, r := range s {
+		if r == prev {
+			count++
+		} else {
+			prev, count = r, 1
+		}
+		if count >= limit && !unicode.IsSpace(r) {
+			return true
+		}
+	}
+	return false
+}
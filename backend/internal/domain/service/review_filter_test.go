@@ -0,0 +1,67 @@
+package service
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/gigaptera/hikkoshi-lens/backend/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReviewFilter_Check(t *testing.T) {
+	f := NewReviewFilter(DefaultBannedWords())
+
+	flags, err := f.Check("駅前のスーパーが24時間営業で便利")
+	require.NoError(t, err)
+	assert.Empty(t, flags)
+
+	cases := []struct {
+		text string
+		flag string
+	}{
+		{"詳しくは https://example.com/ad を参照", ReviewFlagURL},
+		{"<b>おすすめ</b>の駅です", ReviewFlagHTML},
+		{"部屋探しは 03-1234-5678 まで", ReviewFlagContact},
+		{"連絡は foo@example.com まで", ReviewFlagContact},
+		{"最高ーーーーーーーーーーーーー", ReviewFlagRepeated},
+	}
+	for _, c := range cases {
+		flags, err := f.Check(c.text)
+		require.NoError(t, err, c.text)
+		assert.Contains(t, flags, c.flag, c.text)
+	}
+}
+
+func TestReviewFilter_Rejects(t *testing.T) {
+	f := NewReviewFilter(append(DefaultBannedWords(), "NGワード"))
+
+	invalid := []string{
+		"短い",
+		strings.Repeat("長", domain.MaxReviewTextLength+1),
+		"制御文字\x00を含む口コミ",
+		"不正な\xffバイト列の口コミ",
+	}
+	for _, text := range invalid {
+		_, err := f.Check(text)
+		assert.ErrorIs(t, err, domain.ErrInvalidReview, text)
+	}
+
+	// 全角・大文字・ゼロ幅文字で書いても禁止語に当てる
+	rejected := []string{
+		"大家は死ねばいいと思う",
+		"ＦＵＣＫな駅だと思います",
+		"死\u200bねと言われた",
+		"ここはngワードの例です",
+	}
+	for _, text := range rejected {
+		_, err := f.Check(text)
+		assert.ErrorIs(t, err, domain.ErrReviewRejected, text)
+	}
+}
+
+func TestParseBannedWords(t *testing.T) {
+	words := ParseBannedWords("# コメント\n\n  spam  \nスパム\n")
+	assert.Equal(t, []string{"spam", "スパム"}, words)
+	assert.NotEmpty(t, DefaultBannedWords())
+}
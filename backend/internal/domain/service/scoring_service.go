@@ -1,12 +1,33 @@
 package service
 
 import (
+	"fmt"
+	"math"
 	"sort"
 
 	"github.com/gigaptera/hikkoshi-lens/backend/internal/domain"
 	"github.com/gigaptera/hikkoshi-lens/backend/internal/domain/score"
 )
 
+// precomputedAxes は駅単体のデータだけで決まる軸
+// cmd/scores recompute が station_scores に正規化済みの値を保存し、
+// 検索時は Station.AxisScores の値をそのまま使う（未計算の駅は中間値 NeutralAxisScore）
+// access(勤務地からの距離)と rent(選択された間取りの家賃)はリクエストごとに計算する
+var precomputedAxes = []string{"facility", "safety", "disaster"}
+
+// externalAxes は cmd/scores 以外が station_scores に保存する軸
+// review は口コミの審査時、bustle は乗降客数の取り込み時、hilliness は地形の計算時、
+// night_safety は街灯・交番の取り込み時に更新し、スコアのない駅は Strategy の中間値を使う (optionalAxes を除く)
+var externalAxes = []string{domain.ReviewScoreAxis, domain.BustleScoreAxis, domain.HillinessScoreAxis, domain.NightSafetyScoreAxis}
+
+// optionalAxes は値のない駅を中間値で埋めない外部の軸
+// 集計していない駅は ScoreDetails に含めず、その軸の重みも駅の合計スコアの計算から除く
+var optionalAxes = []string{domain.NightSafetyScoreAxis}
+
+// NeutralAxisScore は事前計算の軸で値のない駅に使う中間値
+// Strategy の生スコアは正規化前のスケール (1-5 など) のため、0-100 の値と混ぜない
+const NeutralAxisScore = 50.0
+
 type ScoringService struct {
 	strategies map[string]score.Strategy
 }
@@ -24,6 +45,10 @@ func NewScoringService() *ScoringService {
 		score.NewFacilityScore(),
 		score.NewSafetyScore(),
 		score.NewDisasterScore(),
+		score.NewReviewScore(),
+		score.NewBustleScore(),
+		score.NewHillinessScore(),
+		score.NewNightSafetyScore(),
 	}
 
 	for _, strat := range strategies {
@@ -35,11 +60,13 @@ func NewScoringService() *ScoringService {
 
 // Weights map: key matches Strategy.Name() (e.g. "access", "rent")
 // If a weight is missing, it defaults to 0.
+// 負の重みはその軸のスコアが低い駅を優先する (bustle を負にすると静かな駅が上位になる)
+// optionalAxes の値がない駅は、その軸を除いた重みで合計する (重みを付けた軸の値が全てない場合は 0)
 func (s *ScoringService) CalculateScores(stations []*domain.Station, weights map[string]int) {
 	// Normalize weights so they sum to 1.0 (or keep as is and divide by sum).
 	totalWeight := 0.0
 	for _, w := range weights {
-		totalWeight += float64(w)
+		totalWeight += math.Abs(float64(w))
 	}
 
 	// If no weights provided or sum is 0, use default weights.
@@ -55,29 +82,44 @@ func (s *ScoringService) CalculateScores(stations []*domain.Station, weights map
 	for _, station := range stations {
 		station.ScoreDetails = make(map[string]float64)
 		weightedSum := 0.0
+		stationWeight := totalWeight
 
 		for name, strategy := range s.strategies {
-			// Calculate raw score (0-5, 0-100 etc. Strategy returns float64)
-			// Need to normalize strategy output to 0-100 scale if it isn't already?
-			// AccessScore returns 0-100. RentScore returns 1-5.
-			// Standardize to 0-100.
-
-			rawVal := strategy.Calculate(station)
-			normalizedVal := rawVal
+			// 全ての軸を 0-100 で揃える
+			// - 事前計算・外部の軸: station_scores の値 (事前計算の軸は min-max 正規化済み)
+			// - 事前計算の軸で値のない駅: 中間値 (Strategy の生スコアは 1-5 などのスケールのため使わない)
+			// - access・rent などリクエストごとの軸と値のない外部の軸: Strategy が 0-100 で返す値
+			// - optionalAxes で値のない駅: スコアなし (詳細に含めず、重みからも除く)
+
+			var normalizedVal float64
+			if v, ok := station.AxisScores[name]; ok && s.isPrecomputed(name) {
+				normalizedVal = v
+			} else if isBatchAxis(name) {
+				normalizedVal = NeutralAxisScore
+			} else if isOptionalAxis(name) {
+				if w, ok := weights[name]; ok {
+					stationWeight -= math.Abs(float64(w))
+				}
+				continue
+			} else {
+				normalizedVal = strategy.Calculate(station)
+			}
 
 			// Store detail
 			station.ScoreDetails[name] = normalizedVal
 
-			// Apply Weight
+			// 正の重みはスコアの高い駅、負の重みはスコアの低い駅 (100 - v) を |w| で加点する
 			w, ok := weights[name]
-			if ok {
+			if ok && w >= 0 {
 				weightedSum += normalizedVal * float64(w)
+			} else if ok {
+				weightedSum += (100 - normalizedVal) * float64(-w)
 			}
 		}
 
 		// Final score = Weighted Sum / Total Weight
-		if totalWeight > 0 {
-			station.TotalScore = weightedSum / totalWeight
+		if stationWeight > 0 {
+			station.TotalScore = weightedSum / stationWeight
 		} else {
 			station.TotalScore = 0
 		}
@@ -88,3 +130,101 @@ func (s *ScoringService) CalculateScores(stations []*domain.Station, weights map
 		return stations[i].TotalScore > stations[j].TotalScore
 	})
 }
+
+// PrecomputedAxes は station_scores に事前計算する軸の一覧を返す
+func (s *ScoringService) PrecomputedAxes() []string {
+	return append([]string(nil), precomputedAxes...)
+}
+
+func (s *ScoringService) isPrecomputed(name string) bool {
+	for _, axes := range [][]string{precomputedAxes, externalAxes} {
+		for _, axis := range axes {
+			if axis == name {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// isBatchAxis は cmd/scores recompute が計算する (Strategy が生スコアを返す) 軸かどうか
+func isBatchAxis(name string) bool {
+	for _, axis := range precomputedAxes {
+		if axis == name {
+			return true
+		}
+	}
+	return false
+}
+
+func isOptionalAxis(name string) bool {
+	for _, axis := range optionalAxes {
+		if axis == name {
+			return true
+		}
+	}
+	return false
+}
+
+// RecomputeAxis は事前計算の1軸について全駅の生スコアを計算し、全駅で正規化した station_scores の行を返す
+// 軸の入力データを取り込んだコマンドが、cmd/scores recompute を待たずにその軸を更新するのに使う
+func (s *ScoringService) RecomputeAxis(axis string, stations []*domain.Station, version string) ([]*domain.StationScore, error) {
+	strategy, ok := s.strategies[axis]
+	if !ok || !isBatchAxis(axis) {
+		return nil, fmt.Errorf("not a precomputed axis: %s", axis)
+	}
+	raw := make(map[int64]float64, len(stations))
+	for _, station := range stations {
+		raw[station.ID] = strategy.Calculate(station)
+	}
+	normalized := NormalizeAxisScores(raw)
+	scores := make([]*domain.StationScore, 0, len(stations))
+	for _, station := range stations {
+		scores = append(scores, &domain.StationScore{
+			StationID:       station.ID,
+			Axis:            axis,
+			RawScore:        raw[station.ID],
+			NormalizedScore: normalized[station.ID],
+			DataVersion:     version,
+		})
+	}
+	return scores, nil
+}
+
+// CalculateRawAxisScores は事前計算対象の軸について生のスコアを計算する (バッチ用)
+func (s *ScoringService) CalculateRawAxisScores(station *domain.Station) map[string]float64 {
+	raw := make(map[string]float64, len(precomputedAxes))
+	for _, axis := range precomputedAxes {
+		strategy, ok := s.strategies[axis]
+		if !ok {
+			continue
+		}
+		raw[axis] = strategy.Calculate(station)
+	}
+	return raw
+}
+
+// NormalizeAxisScores は1軸分の生スコア (station_id -> raw) を全駅でmin-max正規化し 0-100 に揃える
+// Strategyごとにスケールが異なる (1-5, 0-100 など) ため、重み付けの前に正規化が必要
+// 全駅が同じ値の場合は差がつかないので 50 とする
+func NormalizeAxisScores(raw map[int64]float64) map[int64]float64 {
+	normalized := make(map[int64]float64, len(raw))
+	if len(raw) == 0 {
+		return normalized
+	}
+
+	minVal, maxVal := math.Inf(1), math.Inf(-1)
+	for _, v := range raw {
+		minVal = math.Min(minVal, v)
+		maxVal = math.Max(maxVal, v)
+	}
+
+	for id, v := range raw {
+		if maxVal == minVal {
+			normalized[id] = 50
+			continue
+		}
+		normalized[id] = (v - minVal) / (maxVal - minVal) * 100
+	}
+	return normalized
+}
@@ -0,0 +1,194 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/gigaptera/hikkoshi-lens/backend/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestCalculateScores_UsesPrecomputedAxes は事前計算済みの軸スコアが優先されることを確認する
+func TestCalculateScores_UsesPrecomputedAxes(t *testing.T) {
+	svc := NewScoringService()
+
+	precomputed := &domain.Station{
+		ID:         1,
+		AxisScores: map[string]float64{"facility": 90, "safety": 10, "disaster": 55},
+	}
+	fallback := &domain.Station{ID: 2}
+
+	svc.CalculateScores([]*domain.Station{precomputed, fallback}, map[string]int{"facility": 100})
+
+	assert.Equal(t, 90.0, precomputed.ScoreDetails["facility"])
+	assert.Equal(t, 10.0, precomputed.ScoreDetails["safety"])
+	assert.Equal(t, 90.0, precomputed.TotalScore)
+
+	// 未計算の駅は正規化済みの値と同じ 0-100 のスケールの中間値になる
+	for _, axis := range svc.PrecomputedAxes() {
+		assert.Equal(t, 50.0, fallback.ScoreDetails[axis], axis)
+	}
+	assert.Equal(t, 50.0, fallback.TotalScore)
+}
+
+// TestCalculateScores_AccessIsRequestSpecific はaccessが事前計算値ではなく距離から計算されることを確認する
+func TestCalculateScores_AccessIsRequestSpecific(t *testing.T) {
+	svc := NewScoringService()
+
+	station := &domain.Station{
+		ID:         1,
+		Distance:   0,
+		AxisScores: map[string]float64{"access": 0},
+	}
+	svc.CalculateScores([]*domain.Station{station}, map[string]int{"access": 100})
+
+	assert.Equal(t, 100.0, station.ScoreDetails["access"])
+}
+
+// TestNormalizeAxisScores はmin-max正規化のテスト
+func TestNormalizeAxisScores(t *testing.T) {
+	normalized := NormalizeAxisScores(map[int64]float64{1: 3.0, 2: 4.0, 3: 5.0})
+
+	assert.Equal(t, 0.0, normalized[1])
+	assert.Equal(t, 50.0, normalized[2])
+	assert.Equal(t, 100.0, normalized[3])
+
+	flat := NormalizeAxisScores(map[int64]float64{1: 4.5, 2: 4.5})
+	assert.Equal(t, 50.0, flat[1])
+	assert.Empty(t, NormalizeAxisScores(nil))
+}
+
+// TestCalculateScores_ReviewAxis は口コミのスコアがない駅が中間値になることを確認する
+func TestCalculateScores_ReviewAxis(t *testing.T) {
+	svc := NewScoringService()
+
+	reviewed := &domain.Station{ID: 1, AxisScores: map[string]float64{"review": 90}}
+	unreviewed := &domain.Station{ID: 2}
+	svc.CalculateScores([]*domain.Station{unreviewed, reviewed}, map[string]int{"review": 100})
+
+	assert.Equal(t, 90.0, reviewed.TotalScore)
+	assert.Equal(t, 50.0, unreviewed.TotalScore)
+	// review は cmd/scores の再計算対象ではない
+	assert.NotContains(t, svc.PrecomputedAxes(), "review")
+}
+
+// TestCalculateScores_NegativeWeight は負の重みでスコアの低い (静かな) 駅が上位になることを確認する
+func TestCalculateScores_NegativeWeight(t *testing.T) {
+	svc := NewScoringService()
+
+	busy := &domain.Station{ID: 1, AxisScores: map[string]float64{"bustle": 90}}
+	quiet := &domain.Station{ID: 2, AxisScores: map[string]float64{"bustle": 20}}
+	unknown := &domain.Station{ID: 3}
+
+	stations := []*domain.Station{busy, quiet, unknown}
+	svc.CalculateScores(stations, map[string]int{"bustle": 100})
+	assert.Equal(t, []int64{1, 3, 2}, []int64{stations[0].ID, stations[1].ID, stations[2].ID})
+
+	svc.CalculateScores(stations, map[string]int{"bustle": -100})
+	assert.Equal(t, []int64{2, 3, 1}, []int64{stations[0].ID, stations[1].ID, stations[2].ID})
+	assert.Equal(t, 80.0, quiet.TotalScore)
+	assert.Equal(t, 50.0, unknown.TotalScore)
+	// 詳細のスコアは重みの正負によらずそのまま
+	assert.Equal(t, 20.0, quiet.ScoreDetails["bustle"])
+}
+
+// TestCalculateScores_LowlandPenalty は周辺より低い駅の disaster 軸の生スコアが下がることを確認する
+func TestCalculateScores_LowlandPenalty(t *testing.T) {
+	svc := NewScoringService()
+
+	hill := &domain.Station{ID: 4, Terrain: &domain.StationTerrain{RelativeHeight: 20}}
+	lowland := &domain.Station{ID: 8, Terrain: &domain.StationTerrain{RelativeHeight: 0}}
+	unknown := &domain.Station{ID: 12}
+	raw := func(s *domain.Station) float64 { return svc.CalculateRawAxisScores(s)["disaster"] }
+
+	assert.Equal(t, raw(hill), raw(unknown))
+	assert.Less(t, raw(lowland), raw(hill))
+	// hilliness は cmd/import/terrain が保存する
+	assert.NotContains(t, svc.PrecomputedAxes(), domain.HillinessScoreAxis)
+}
+
+// TestCalculateScores_DisasterAccess は避難場所・救急病院が遠い駅の disaster 軸の生スコアが下がることを確認する
+func TestCalculateScores_DisasterAccess(t *testing.T) {
+	svc := NewScoringService()
+	meters := func(v float64) *float64 { return &v }
+
+	near := &domain.Station{ID: 4, DisasterAccess: &domain.StationDisasterAccess{NearestShelterMeter: meters(300), NearestEmergencyHospitalMeter: meters(1500)}}
+	farShelter := &domain.Station{ID: 8, DisasterAccess: &domain.StationDisasterAccess{NearestShelterMeter: meters(1500), NearestEmergencyHospitalMeter: meters(1500)}}
+	farBoth := &domain.Station{ID: 12, DisasterAccess: &domain.StationDisasterAccess{NearestShelterMeter: meters(3000), NearestEmergencyHospitalMeter: meters(12000)}}
+	unknown := &domain.Station{ID: 16, DisasterAccess: &domain.StationDisasterAccess{}}
+	raw := func(s *domain.Station) float64 { return svc.CalculateRawAxisScores(s)["disaster"] }
+
+	assert.Equal(t, raw(near), raw(unknown))
+	assert.Less(t, raw(farShelter), raw(near))
+	assert.Less(t, raw(farBoth), raw(farShelter))
+}
+
+// TestCalculateScores_NightSafety は街灯が少ない駅・交番が遠い駅の safety 軸の生スコアが下がることを確認する
+func TestCalculateScores_NightSafety(t *testing.T) {
+	svc := NewScoringService()
+	value := func(v float64) *float64 { return &v }
+
+	bright := &domain.Station{ID: 5, NightSafety: &domain.StationNightSafety{StreetLampDensity: value(400), NearestPoliceMeter: value(200)}}
+	dark := &domain.Station{ID: 10, NightSafety: &domain.StationNightSafety{StreetLampDensity: value(50), NearestPoliceMeter: value(200)}}
+	darkFar := &domain.Station{ID: 15, NightSafety: &domain.StationNightSafety{StreetLampDensity: value(50), NearestPoliceMeter: value(2000)}}
+	// 街灯のデータがない地域は減点しない
+	unknown := &domain.Station{ID: 20, NightSafety: &domain.StationNightSafety{NearestPoliceMeter: value(200)}}
+	raw := func(s *domain.Station) float64 { return svc.CalculateRawAxisScores(s)["safety"] }
+
+	assert.Equal(t, raw(bright), raw(unknown))
+	assert.Less(t, raw(dark), raw(bright))
+	assert.Less(t, raw(darkFar), raw(dark))
+}
+
+// TestCalculateScores_NightSafetyAxis は夜間の安全性が safety 軸とは別に ScoreDetails に入り、
+// 集計していない駅は中間値で埋めずにスコアなしとして扱うことを確認する
+func TestCalculateScores_NightSafetyAxis(t *testing.T) {
+	svc := NewScoringService()
+
+	lit := &domain.Station{ID: 1, AxisScores: map[string]float64{"safety": 70, domain.NightSafetyScoreAxis: 85}}
+	unknown := &domain.Station{ID: 2, AxisScores: map[string]float64{"safety": 70}}
+	svc.CalculateScores([]*domain.Station{lit, unknown}, map[string]int{"safety": 100})
+
+	assert.Equal(t, 85.0, lit.ScoreDetails[domain.NightSafetyScoreAxis])
+	assert.NotContains(t, unknown.ScoreDetails, domain.NightSafetyScoreAxis)
+	// 既定では合計は safety 軸 (夜間の成分を含む) のみで決まる
+	assert.Equal(t, 70.0, lit.TotalScore)
+	assert.Equal(t, 70.0, unknown.TotalScore)
+
+	// w_night_safety を指定した場合、集計していない駅はその重みを除いて合計する
+	svc.CalculateScores([]*domain.Station{lit, unknown}, map[string]int{"safety": 50, domain.NightSafetyScoreAxis: 50})
+	assert.Equal(t, 77.5, lit.TotalScore)
+	assert.Equal(t, 70.0, unknown.TotalScore)
+
+	// 重みを付けた軸の値が全てない駅は 0 (下位に並ぶ)
+	stations := []*domain.Station{unknown, lit}
+	svc.CalculateScores(stations, map[string]int{domain.NightSafetyScoreAxis: 100})
+	assert.Equal(t, 0.0, unknown.TotalScore)
+	assert.Equal(t, []int64{1, 2}, []int64{stations[0].ID, stations[1].ID})
+
+	// night_safety は cmd/import/osm_pois・koban が保存する
+	assert.NotContains(t, svc.PrecomputedAxes(), domain.NightSafetyScoreAxis)
+}
+
+// TestRecomputeAxis は1軸分のスコアを全駅で正規化して返すことを確認する
+func TestRecomputeAxis(t *testing.T) {
+	svc := NewScoringService()
+	value := func(v float64) *float64 { return &v }
+
+	// ID%5 が同じ駅は夜間の安全性の差だけが safety 軸の差になる
+	bright := &domain.Station{ID: 5, NightSafety: &domain.StationNightSafety{StreetLampDensity: value(400), NearestPoliceMeter: value(200)}}
+	dark := &domain.Station{ID: 10, NightSafety: &domain.StationNightSafety{StreetLampDensity: value(0), NearestPoliceMeter: value(2000)}}
+
+	scores, err := svc.RecomputeAxis("safety", []*domain.Station{bright, dark}, "v1")
+	require.NoError(t, err)
+	require.Len(t, scores, 2)
+	assert.Equal(t, 100.0, scores[0].NormalizedScore)
+	assert.Equal(t, 0.0, scores[1].NormalizedScore)
+	assert.InDelta(t, 1.0, scores[0].RawScore-scores[1].RawScore, 1e-9)
+	assert.Equal(t, "safety", scores[1].Axis)
+	assert.Equal(t, "v1", scores[1].DataVersion)
+
+	// 外部の軸は cmd/scores の計算対象ではない
+	_, err = svc.RecomputeAxis(domain.NightSafetyScoreAxis, []*domain.Station{bright}, "v1")
+	assert.Error(t, err)
+}
@@ -0,0 +1,144 @@
+package service
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/gigaptera/hikkoshi-lens/backend/internal/domain"
+)
+
+//go:embed tag_rules.json
+var defaultTagRules []byte
+
+// DefaultTagRules は組み込みのタグ判定ルールを返す
+func DefaultTagRules() []domain.TagRule {
+	rules, err := ParseTagRules(defaultTagRules)
+	if err != nil {
+		panic(fmt.Sprintf("invalid embedded tag rules: %v", err))
+	}
+	return rules
+}
+
+// ParseTagRules はJSONのルール定義を読み、演算子・タグ名の重複などを検証する
+func ParseTagRules(data []byte) ([]domain.TagRule, error) {
+	var rules []domain.TagRule
+	if err := json.Unmarshal(data, &rules); err != nil {
+		return nil, err
+	}
+	seen := make(map[string]bool)
+	for i, r := range rules {
+		if r.Tag == "" {
+			return nil, fmt.Errorf("rule %d: tag is required", i)
+		}
+		if seen[r.Tag] {
+			return nil, fmt.Errorf("rule %d: duplicate tag %q", i, r.Tag)
+		}
+		seen[r.Tag] = true
+		if len(r.All) == 0 {
+			return nil, fmt.Errorf("rule %q: at least one condition is required", r.Tag)
+		}
+		for _, c := range r.All {
+			if c.Fact == "" {
+				return nil, fmt.Errorf("rule %q: fact is required", r.Tag)
+			}
+			if _, ok := tagOps[c.Op]; !ok {
+				return nil, fmt.Errorf("rule %q: unknown op %q", r.Tag, c.Op)
+			}
+		}
+	}
+	return rules, nil
+}
+
+var tagOps = map[string]func(v, threshold float64) bool{
+	"lt":  func(v, t float64) bool { return v < t },
+	"lte": func(v, t float64) bool { return v <= t },
+	"gt":  func(v, t float64) bool { return v > t },
+	"gte": func(v, t float64) bool { return v >= t },
+	"eq":  func(v, t float64) bool { return v == t },
+}
+
+// TagEngine は宣言的なルールで駅にタグを付ける
+type TagEngine struct {
+	rules []domain.TagRule
+}
+
+// NewTagEngine はルールを定義順に評価するエンジンを作る (ルールは ParseTagRules で検証済みであること)
+func NewTagEngine(rules []domain.TagRule) *TagEngine {
+	return &TagEngine{rules: rules}
+}
+
+// Evaluate は条件をすべて満たすルールのタグを定義順に返す
+// 指標がない (データ未取得の) 条件は満たさないものとする
+func (e *TagEngine) Evaluate(values map[string]float64) []string {
+	var tags []string
+	for _, r := range e.rules {
+		if matchAll(r.All, values) {
+			tags = append(tags, r.Tag)
+		}
+	}
+	return tags
+}
+
+// StationTags は全駅のタグを判定する。路線中央値との比 (rent_line_ratio) はここで計算して facts に追加する
+func (e *TagEngine) StationTags(facts []*domain.TagFacts, version string) []*domain.StationTag {
+	AddRentLineRatio(facts)
+
+	position := make(map[string]int, len(e.rules))
+	for i, r := range e.rules {
+		position[r.Tag] = i
+	}
+
+	var tags []*domain.StationTag
+	for _, f := range facts {
+		for _, tag := range e.Evaluate(f.Values) {
+			tags = append(tags, &domain.StationTag{StationID: f.StationID, Tag: tag, Position: position[tag], DataVersion: version})
+		}
+	}
+	return tags
+}
+
+// AddRentLineRatio は代表家賃を同じ路線の駅の中央値で割った値を設定する
+// 家賃データのある駅が2駅未満の路線では比較できないため設定しない
+func AddRentLineRatio(facts []*domain.TagFacts) {
+	rentsByLine := make(map[domain.LineKey][]float64)
+	for _, f := range facts {
+		if rent, ok := f.Values[domain.TagFactRent]; ok && rent > 0 {
+			rentsByLine[f.LineKey] = append(rentsByLine[f.LineKey], rent)
+		}
+	}
+	medians := make(map[domain.LineKey]float64, len(rentsByLine))
+	for key, rents := range rentsByLine {
+		if len(rents) >= 2 {
+			medians[key] = median(rents)
+		}
+	}
+	for _, f := range facts {
+		rent, ok := f.Values[domain.TagFactRent]
+		m := medians[f.LineKey]
+		if ok && rent > 0 && m > 0 {
+			f.Values[domain.TagFactRentLineRatio] = rent / m
+		}
+	}
+}
+
+func matchAll(conds []domain.TagCondition, values map[string]float64) bool {
+	for _, c := range conds {
+		v, ok := values[c.Fact]
+		if !ok || !tagOps[c.Op](v, c.Value) {
+			return false
+		}
+	}
+	return true
+}
+
+func median(values []float64) float64 {
+	sorted := append([]float64(nil), values...)
+	sort.Float64s(sorted)
+	n := len(sorted)
+	if n%2 == 1 {
+		return sorted[n/2]
+	}
+	return (sorted[n/2-1] + sorted[n/2]) / 2
+}
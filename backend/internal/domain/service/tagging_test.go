@@ -0,0 +1,87 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/gigaptera/hikkoshi-lens/backend/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDefaultTagRules(t *testing.T) {
+	rules := DefaultTagRules()
+	require.NotEmpty(t, rules)
+	tags := make([]string, len(rules))
+	for i, r := range rules {
+		tags[i] = r.Tag
+	}
+	assert.Contains(t, tags, "コスパ良好")
+	assert.Contains(t, tags, "学生街")
+	assert.Contains(t, tags, "交通便利")
+	assert.Contains(t, tags, "水害注意")
+}
+
+func TestParseTagRules_Invalid(t *testing.T) {
+	cases := []string{
+		`[{"tag": "", "all": [{"fact": "line_count", "op": "gte", "value": 3}]}]`,
+		`[{"tag": "交通便利", "all": []}]`,
+		`[{"tag": "交通便利", "all": [{"fact": "line_count", "op": ">=", "value": 3}]}]`,
+		`[{"tag": "A", "all": [{"fact": "x", "op": "gt", "value": 1}]}, {"tag": "A", "all": [{"fact": "y", "op": "gt", "value": 1}]}]`,
+		`{"tag": "A"}`,
+	}
+	for _, c := range cases {
+		_, err := ParseTagRules([]byte(c))
+		assert.Error(t, err, c)
+	}
+}
+
+func TestTagEngine_Evaluate(t *testing.T) {
+	rules, err := ParseTagRules([]byte(`[
+		{"tag": "交通便利", "all": [{"fact": "line_count", "op": "gte", "value": 3}]},
+		{"tag": "静かな学生街", "all": [
+			{"fact": "university_count", "op": "gte", "value": 2},
+			{"fact": "restaurant_count", "op": "lt", "value": 10}
+		]}
+	]`))
+	require.NoError(t, err)
+	e := NewTagEngine(rules)
+
+	assert.Equal(t, []string{"交通便利", "静かな学生街"}, e.Evaluate(map[string]float64{
+		"line_count": 3, "university_count": 2, "restaurant_count": 5,
+	}))
+	assert.Equal(t, []string{"交通便利"}, e.Evaluate(map[string]float64{
+		"line_count": 4, "university_count": 2, "restaurant_count": 10,
+	}))
+	// 指標がなければ条件を満たさない
+	assert.Empty(t, e.Evaluate(map[string]float64{"university_count": 3}))
+}
+
+func TestTagEngine_StationTags(t *testing.T) {
+	lineA := domain.LineKey{OrganizationCode: "X", LineName: "A線"}
+	lineB := domain.LineKey{OrganizationCode: "Y", LineName: "B線"}
+	facts := []*domain.TagFacts{
+		{StationID: 1, LineKey: lineA, Values: map[string]float64{domain.TagFactRent: 8.0}},
+		{StationID: 2, LineKey: lineA, Values: map[string]float64{domain.TagFactRent: 10.0}},
+		{StationID: 3, LineKey: lineA, Values: map[string]float64{domain.TagFactRent: 12.0, domain.TagFactFloodRisk: 3}},
+		{StationID: 4, LineKey: lineA, Values: map[string]float64{}},
+		// 家賃データのある駅が1駅だけの路線は比較しない
+		{StationID: 5, LineKey: lineB, Values: map[string]float64{domain.TagFactRent: 5.0}},
+	}
+
+	tags := NewTagEngine(DefaultTagRules()).StationTags(facts, "v1")
+
+	// 中央値 10.0 に対して 8.0 は 0.8
+	assert.InDelta(t, 0.8, facts[0].Values[domain.TagFactRentLineRatio], 1e-9)
+	assert.InDelta(t, 1.2, facts[2].Values[domain.TagFactRentLineRatio], 1e-9)
+	assert.NotContains(t, facts[4].Values, domain.TagFactRentLineRatio)
+
+	got := make(map[int64][]string)
+	for _, tag := range tags {
+		assert.Equal(t, "v1", tag.DataVersion)
+		got[tag.StationID] = append(got[tag.StationID], tag.Tag)
+	}
+	assert.Equal(t, map[int64][]string{
+		1: {"コスパ良好"},
+		3: {"水害注意"},
+	}, got)
+}
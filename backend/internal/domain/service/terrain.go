@@ -0,0 +1,227 @@
+package service
+
+import (
+	"math"
+
+	"github.com/gigaptera/hikkoshi-lens/backend/internal/domain"
+)
+
+// metersPerDegree は緯度1度あたりの距離 (m)。地形のサンプリング位置の計算に使う概算値
+const metersPerDegree = 111000.0
+
+// 平坦さスコアの基準: 平均勾配と上りの累計がこの値以上で、それぞれの成分が0点になる
+const (
+	hillinessMaxSlope = 8.0  // % (車いすで上れる勾配の目安の約1.5倍)
+	hillinessMaxGain  = 30.0 // m (建物10階分程度)
+)
+
+// minTerrainNetworkMeter は徒歩ネットワークで計算するのに必要な経路の総延長 (m)
+// 駅が孤立した通路に吸着した場合など、これより短ければ放射状の計算に切り替える
+const minTerrainNetworkMeter = 500.0
+
+// TerrainOptions は駅周辺の地形の計算条件
+type TerrainOptions struct {
+	RadiusMeter        float64 // 徒歩圏の半径 (経路距離)
+	SampleMeter        float64 // 標高を読む間隔
+	Directions         int     // 放射状に計算する場合の方向の数
+	LowlandRadiusMeter float64 // 周辺の低地を探す範囲
+}
+
+// DefaultTerrainOptions は徒歩10分 (800m) 圏を 25m 間隔 (DEM のズーム15の約5画素) で調べる
+func DefaultTerrainOptions() TerrainOptions {
+	return TerrainOptions{RadiusMeter: 800, SampleMeter: 25, Directions: 8, LowlandRadiusMeter: 1000}
+}
+
+// terrainProfile は経路に沿った標高の変化の集計
+type terrainProfile struct {
+	length  float64 // 標高を読めた区間の総延長 (m)
+	absRise float64 // 上り下りの高さの合計 (m)
+}
+
+func (p *terrainProfile) slope() float64 {
+	if p.length == 0 {
+		return 0
+	}
+	return p.absRise / p.length * 100
+}
+
+// ComputeTerrain は駅周辺の地形を計算する
+// walk がある場合は駅から徒歩圏の経路 (最短経路木) に沿って、ない場合は駅から放射状の直線に沿って標高を読む
+// 駅の標高が読めない場合は ok=false
+func ComputeTerrain(station *domain.Station, dem domain.ElevationModel, walk *WalkGraph, opts TerrainOptions) (*domain.StationTerrain, bool) {
+	elevation, ok := dem.Elevation(station.Lat, station.Lon)
+	if !ok {
+		return nil, false
+	}
+
+	method := domain.TerrainMethodRadial
+	var profile terrainProfile
+	var gain float64
+	if walk != nil {
+		profile, gain = networkTerrain(walk, station, dem, opts)
+		if profile.length >= minTerrainNetworkMeter {
+			method = domain.TerrainMethodNetwork
+		}
+	}
+	if method == domain.TerrainMethodRadial {
+		profile, gain = radialTerrain(station, dem, opts)
+	}
+	if profile.length == 0 {
+		return nil, false
+	}
+
+	return &domain.StationTerrain{
+		StationID:      station.ID,
+		Elevation:      round1(elevation),
+		AvgSlope:       round1(profile.slope()),
+		ElevationGain:  round1(gain),
+		RelativeHeight: round1(relativeHeight(station, elevation, dem, opts.LowlandRadiusMeter)),
+		RadiusMeter:    int(opts.RadiusMeter),
+		Method:         method,
+	}, true
+}
+
+// networkTerrain は駅からの最短経路木の各エッジの標高の変化を集計する
+// 上りの累計は、駅から RadiusMeter の 3/4 以上離れたノードまでの経路の行き・帰りの上りの大きい方の平均
+func networkTerrain(g *WalkGraph, station *domain.Station, dem domain.ElevationModel, opts TerrainOptions) (terrainProfile, float64) {
+	var profile terrainProfile
+	start, _, ok := g.snap(domain.Location{Lat: station.Lat, Lon: station.Lon})
+	if !ok {
+		return profile, 0
+	}
+
+	parent := make(map[int32]int32)
+	type climb struct{ up, down float64 }
+	climbs := map[int32]climb{start: {}}
+	var gainSum float64
+	var gainCount int
+	g.dijkstra(start, opts.RadiusMeter, parent, func(node int32, meters float64) bool {
+		if node == start {
+			return true
+		}
+		// 近い順に訪れるため、親ノードは集計済み
+		p := parent[node]
+		c := climbs[p]
+		length, rises := sampleSegment(dem, g.lats[p], g.lons[p], g.lats[node], g.lons[node], opts.SampleMeter)
+		if rises != nil {
+			profile.length += length
+			for _, dh := range rises {
+				profile.absRise += math.Abs(dh)
+				if dh > 0 {
+					c.up += dh
+				} else {
+					c.down -= dh
+				}
+			}
+		}
+		climbs[node] = c
+		if meters >= opts.RadiusMeter*0.75 {
+			gainSum += math.Max(c.up, c.down)
+			gainCount++
+		}
+		return true
+	})
+	if gainCount == 0 {
+		return profile, 0
+	}
+	return profile, gainSum / float64(gainCount)
+}
+
+// radialTerrain は駅から Directions 方向の直線に沿って標高の変化を集計する
+// 標高が読めない地点 (海上など) に達したら、その方向はそこで打ち切る
+func radialTerrain(station *domain.Station, dem domain.ElevationModel, opts TerrainOptions) (terrainProfile, float64) {
+	var profile terrainProfile
+	var gainSum float64
+	var rays int
+	for d := 0; d < opts.Directions; d++ {
+		bearing := 2 * math.Pi * float64(d) / float64(opts.Directions)
+		lat, lon := offsetPoint(station.Lat, station.Lon, bearing, opts.RadiusMeter)
+		length, rises := sampleSegment(dem, station.Lat, station.Lon, lat, lon, opts.SampleMeter)
+		if length == 0 {
+			continue
+		}
+		var up, down float64
+		for _, dh := range rises {
+			profile.absRise += math.Abs(dh)
+			if dh > 0 {
+				up += dh
+			} else {
+				down -= dh
+			}
+		}
+		profile.length += length
+		gainSum += math.Max(up, down)
+		rays++
+	}
+	if rays == 0 {
+		return profile, 0
+	}
+	return profile, gainSum / float64(rays)
+}
+
+// sampleSegment は2点間の直線を stepMeter 以下の間隔で区切り、標高を読めた区間の長さと各区間の標高差を返す
+// 始点の標高が読めない場合は rises=nil、途中で読めなくなった場合はそこまでを返す
+func sampleSegment(dem domain.ElevationModel, lat1, lon1, lat2, lon2, stepMeter float64) (float64, []float64) {
+	prev, ok := dem.Elevation(lat1, lon1)
+	if !ok {
+		return 0, nil
+	}
+	total := HaversineMeter(lat1, lon1, lat2, lon2)
+	n := int(math.Ceil(total / stepMeter))
+	if n < 1 {
+		n = 1
+	}
+	rises := make([]float64, 0, n)
+	var length float64
+	for i := 1; i <= n; i++ {
+		f := float64(i) / float64(n)
+		h, ok := dem.Elevation(lat1+(lat2-lat1)*f, lon1+(lon2-lon1)*f)
+		if !ok {
+			break
+		}
+		rises = append(rises, h-prev)
+		length += total / float64(n)
+		prev = h
+	}
+	return length, rises
+}
+
+// relativeHeight は駅の標高と、周辺 (radiusMeter 以内、16方向を100m間隔) の最も低い地点との差 (0以上)
+// 周辺の最低地点は多くの場合川や水路沿いのため、川からの比高の目安になる
+func relativeHeight(station *domain.Station, elevation float64, dem domain.ElevationModel, radiusMeter float64) float64 {
+	lowest := elevation
+	for d := 0; d < 16; d++ {
+		bearing := 2 * math.Pi * float64(d) / 16
+		for m := 100.0; m <= radiusMeter; m += 100 {
+			lat, lon := offsetPoint(station.Lat, station.Lon, bearing, m)
+			if h, ok := dem.Elevation(lat, lon); ok && h < lowest {
+				lowest = h
+			}
+		}
+	}
+	return elevation - lowest
+}
+
+// offsetPoint は地点から方位 bearing (北から時計回り、ラジアン) に meters 離れた地点を返す
+func offsetPoint(lat, lon, bearing, meters float64) (float64, float64) {
+	dLat := meters * math.Cos(bearing) / metersPerDegree
+	dLon := meters * math.Sin(bearing) / (metersPerDegree * math.Cos(lat*math.Pi/180))
+	return lat + dLat, lon + dLon
+}
+
+// HillinessScore は地形から平坦さのスコア (0-100、平坦なほど高い) を求める
+// 平均勾配と上りの累計を半分ずつ評価する。全国の駅の相対値ではなく絶対的な基準で評価する
+// (平野部の駅が大半のため、相対値にすると少しの坂でも大きく減点されるため)
+func HillinessScore(t *domain.StationTerrain) float64 {
+	slope := clamp01(1 - t.AvgSlope/hillinessMaxSlope)
+	gain := clamp01(1 - t.ElevationGain/hillinessMaxGain)
+	return round1(100 * (0.5*slope + 0.5*gain))
+}
+
+func clamp01(v float64) float64 {
+	return math.Max(0, math.Min(1, v))
+}
+
+func round1(v float64) float64 {
+	return math.Round(v*10) / 10
+}
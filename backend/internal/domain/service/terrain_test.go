@@ -0,0 +1,96 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/gigaptera/hikkoshi-lens/backend/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// funcElevation は関数で標高を返す ElevationModel
+type funcElevation func(lat, lon float64) (float64, bool)
+
+func (f funcElevation) Elevation(lat, lon float64) (float64, bool) { return f(lat, lon) }
+
+func flatElevation(lat, lon float64) (float64, bool) { return 10, true }
+
+// northSlope は北に向かって grade (%) で上る斜面
+func northSlope(grade float64) funcElevation {
+	return func(lat, lon float64) (float64, bool) {
+		return 10 + (lat-35.0)*metersPerDegree*grade/100, true
+	}
+}
+
+func TestComputeTerrain_Flat(t *testing.T) {
+	station := &domain.Station{ID: 1, Lat: 35.0, Lon: 139.0}
+	terrain, ok := ComputeTerrain(station, funcElevation(flatElevation), nil, DefaultTerrainOptions())
+	require.True(t, ok)
+
+	assert.Equal(t, int64(1), terrain.StationID)
+	assert.Equal(t, domain.TerrainMethodRadial, terrain.Method)
+	assert.Equal(t, 10.0, terrain.Elevation)
+	assert.Equal(t, 0.0, terrain.AvgSlope)
+	assert.Equal(t, 0.0, terrain.ElevationGain)
+	assert.Equal(t, 0.0, terrain.RelativeHeight)
+	assert.Equal(t, 100.0, HillinessScore(terrain))
+}
+
+func TestComputeTerrain_RadialSlope(t *testing.T) {
+	station := &domain.Station{ID: 1, Lat: 35.0, Lon: 139.0}
+	terrain, ok := ComputeTerrain(station, northSlope(5), nil, DefaultTerrainOptions())
+	require.True(t, ok)
+
+	// 南北は5%、東西は0%、斜め4方向は約3.5%: 平均は約3%
+	assert.InDelta(t, 3.0, terrain.AvgSlope, 0.1)
+	// 800m で 40m 上る (下る) 方向が2つ、約28m が4つ、0m が2つ
+	assert.InDelta(t, (2*40+4*28.3)/8, terrain.ElevationGain, 0.5)
+	// 1km 南は 50m 低い
+	assert.InDelta(t, 50, terrain.RelativeHeight, 0.5)
+	assert.Less(t, HillinessScore(terrain), 60.0)
+}
+
+func TestComputeTerrain_Network(t *testing.T) {
+	g := NewWalkGraph(testWalkNetwork())
+	station := &domain.Station{ID: 1, Lat: 35.0, Lon: 139.0}
+	terrain, ok := ComputeTerrain(station, northSlope(5), g, DefaultTerrainOptions())
+	require.True(t, ok)
+
+	assert.Equal(t, domain.TerrainMethodNetwork, terrain.Method)
+	// 格子の南西の角: 北向きの道は5%、東向きの道は0%
+	assert.Greater(t, terrain.AvgSlope, 1.0)
+	assert.Less(t, terrain.AvgSlope, 5.0)
+	// 徒歩圏の端までは上りだけ
+	assert.Greater(t, terrain.ElevationGain, 0.0)
+	assert.LessOrEqual(t, terrain.ElevationGain, 40.5)
+
+	// ネットワークから離れた駅は放射状に計算する
+	far := &domain.Station{ID: 2, Lat: 36.0, Lon: 139.0}
+	terrain, ok = ComputeTerrain(far, northSlope(5), g, DefaultTerrainOptions())
+	require.True(t, ok)
+	assert.Equal(t, domain.TerrainMethodRadial, terrain.Method)
+}
+
+func TestComputeTerrain_NoElevation(t *testing.T) {
+	station := &domain.Station{ID: 1, Lat: 35.0, Lon: 139.0}
+	sea := funcElevation(func(lat, lon float64) (float64, bool) { return 0, false })
+	_, ok := ComputeTerrain(station, sea, nil, DefaultTerrainOptions())
+	assert.False(t, ok)
+
+	// 東側が海: 海上の地点は飛ばす
+	coast := funcElevation(func(lat, lon float64) (float64, bool) {
+		if lon > 139.0 {
+			return 0, false
+		}
+		return 10, true
+	})
+	terrain, ok := ComputeTerrain(station, coast, nil, DefaultTerrainOptions())
+	require.True(t, ok)
+	assert.Equal(t, 0.0, terrain.AvgSlope)
+}
+
+func TestHillinessScore(t *testing.T) {
+	assert.Equal(t, 100.0, HillinessScore(&domain.StationTerrain{}))
+	assert.Equal(t, 50.0, HillinessScore(&domain.StationTerrain{AvgSlope: 4, ElevationGain: 15}))
+	assert.Equal(t, 0.0, HillinessScore(&domain.StationTerrain{AvgSlope: 12, ElevationGain: 60}))
+}
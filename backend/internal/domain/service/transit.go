@@ -0,0 +1,198 @@
+package service
+
+import (
+	"container/heap"
+	"math"
+	"sort"
+
+	"github.com/gigaptera/hikkoshi-lens/backend/internal/domain"
+)
+
+// TransitOptions は路線グラフの所要時間の見積もり条件
+// 時刻表を持たないため、駅間距離と表定速度から所要時間を概算する
+type TransitOptions struct {
+	SpeedKmh               float64 // 表定速度 (停車時間込みの平均速度)
+	DwellMinutes           float64 // 1駅ごとの停車時間
+	TransferPenaltyMinutes float64 // 乗り換え1回あたりの待ち時間
+	TransferRadiusMeter    float64 // 別路線の駅を乗り換え可能とみなす距離 (同名駅はこの2倍まで)
+	AccessRadiusMeter      float64 // 出発地から徒歩で乗車できる駅の最大距離
+	MaxSegmentMeter        float64 // これより離れた隣接駅は (データ欠落とみなして) つながない
+}
+
+func DefaultTransitOptions() TransitOptions {
+	return TransitOptions{
+		SpeedKmh:               40,
+		DwellMinutes:           0.5,
+		TransferPenaltyMinutes: 5,
+		TransferRadiusMeter:    250,
+		AccessRadiusMeter:      1500,
+		MaxSegmentMeter:        20000,
+	}
+}
+
+// ReachableStation は出発地から到達できる駅と所要時間 (分)
+type ReachableStation struct {
+	StationID int64
+	Name      string
+	Lat, Lon  float64
+	Minutes   float64
+}
+
+type transitEdge struct {
+	to       int
+	minutes  float64
+	km       float64 // 乗車距離 (乗り換えは 0)
+	transfer bool
+}
+
+// TransitGraph は駅をノード、同一路線の隣接駅と乗り換えをエッジとする所要時間のグラフ
+type TransitGraph struct {
+	opts     TransitOptions
+	stations []*domain.Station
+	adj      [][]transitEdge
+}
+
+// NewTransitGraph は駅一覧からグラフを作る
+// 路線ごとの駅の並びは駅コード順 (GetByLine と同じ) を使う
+func NewTransitGraph(stations []*domain.Station, opts TransitOptions) *TransitGraph {
+	g := &TransitGraph{opts: opts, stations: stations, adj: make([][]transitEdge, len(stations))}
+
+	// 1. 同一路線の隣接駅
+	byLine := make(map[domain.LineKey][]int)
+	for i, s := range stations {
+		key := domain.LineKey{OrganizationCode: s.OrganizationCode, LineName: s.LineName}
+		byLine[key] = append(byLine[key], i)
+	}
+	for _, idx := range byLine {
+		sort.Slice(idx, func(a, b int) bool {
+			sa, sb := stations[idx[a]], stations[idx[b]]
+			if sa.StationCode != sb.StationCode {
+				return sa.StationCode < sb.StationCode
+			}
+			return sa.ID < sb.ID
+		})
+		for k := 1; k < len(idx); k++ {
+			a, b := stations[idx[k-1]], stations[idx[k]]
+			d := HaversineMeter(a.Lat, a.Lon, b.Lat, b.Lon)
+			if d > opts.MaxSegmentMeter {
+				continue
+			}
+			g.addEdge(idx[k-1], idx[k], transitEdge{minutes: d/1000/opts.SpeedKmh*60 + opts.DwellMinutes, km: d / 1000})
+		}
+	}
+
+	// 2. 乗り換え (近くにある別路線の駅)
+	cell := opts.TransferRadiusMeter * 2 / 111000
+	buckets := make(map[[2]int][]int)
+	key := func(lat, lon float64) [2]int {
+		return [2]int{int(math.Floor(lat / cell)), int(math.Floor(lon / cell))}
+	}
+	for i, s := range stations {
+		k := key(s.Lat, s.Lon)
+		buckets[k] = append(buckets[k], i)
+	}
+	for i, s := range stations {
+		k := key(s.Lat, s.Lon)
+		for di := -2; di <= 2; di++ {
+			for dj := -2; dj <= 2; dj++ {
+				for _, j := range buckets[[2]int{k[0] + di, k[1] + dj}] {
+					if j <= i {
+						continue
+					}
+					t := stations[j]
+					if t.OrganizationCode == s.OrganizationCode && t.LineName == s.LineName {
+						continue
+					}
+					d := HaversineMeter(s.Lat, s.Lon, t.Lat, t.Lon)
+					limit := opts.TransferRadiusMeter
+					if t.Name == s.Name {
+						limit *= 2
+					}
+					if d > limit {
+						continue
+					}
+					g.addEdge(i, j, transitEdge{minutes: opts.TransferPenaltyMinutes + float64(domain.EstimateWalkMinutes(d)), transfer: true})
+				}
+			}
+		}
+	}
+	return g
+}
+
+func (g *TransitGraph) addEdge(a, b int, e transitEdge) {
+	e.to = b
+	g.adj[a] = append(g.adj[a], e)
+	e.to = a
+	g.adj[b] = append(g.adj[b], e)
+}
+
+// Reachable は出発地から maxMinutes 以内に到達できる駅を所要時間の短い順に返す
+// 出発地から AccessRadiusMeter 以内の駅へは徒歩 (EstimateWalkMinutes) で向かう
+func (g *TransitGraph) Reachable(lat, lon, maxMinutes float64) []ReachableStation {
+	dist := make([]float64, len(g.stations))
+	for i := range dist {
+		dist[i] = math.Inf(1)
+	}
+
+	pq := &minutesQueue{}
+	for i, s := range g.stations {
+		d := HaversineMeter(lat, lon, s.Lat, s.Lon)
+		if d > g.opts.AccessRadiusMeter {
+			continue
+		}
+		m := float64(domain.EstimateWalkMinutes(d))
+		if m < dist[i] && m <= maxMinutes {
+			dist[i] = m
+			heap.Push(pq, queueItem{node: i, minutes: m})
+		}
+	}
+
+	// Dijkstra
+	for pq.Len() > 0 {
+		item := heap.Pop(pq).(queueItem)
+		if item.minutes > dist[item.node] {
+			continue
+		}
+		for _, e := range g.adj[item.node] {
+			m := item.minutes + e.minutes
+			if m < dist[e.to] && m <= maxMinutes {
+				dist[e.to] = m
+				heap.Push(pq, queueItem{node: e.to, minutes: m})
+			}
+		}
+	}
+
+	var result []ReachableStation
+	for i, m := range dist {
+		if math.IsInf(m, 1) {
+			continue
+		}
+		s := g.stations[i]
+		result = append(result, ReachableStation{StationID: s.ID, Name: s.Name, Lat: s.Lat, Lon: s.Lon, Minutes: m})
+	}
+	sort.Slice(result, func(a, b int) bool {
+		if result[a].Minutes != result[b].Minutes {
+			return result[a].Minutes < result[b].Minutes
+		}
+		return result[a].StationID < result[b].StationID
+	})
+	return result
+}
+
+type queueItem struct {
+	node    int
+	minutes float64
+}
+
+type minutesQueue []queueItem
+
+func (q minutesQueue) Len() int            { return len(q) }
+func (q minutesQueue) Less(i, j int) bool  { return q[i].minutes < q[j].minutes }
+func (q minutesQueue) Swap(i, j int)       { q[i], q[j] = q[j], q[i] }
+func (q *minutesQueue) Push(x interface{}) { *q = append(*q, x.(queueItem)) }
+func (q *minutesQueue) Pop() interface{} {
+	old := *q
+	item := old[len(old)-1]
+	*q = old[:len(old)-1]
+	return item
+}
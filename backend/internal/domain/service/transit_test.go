@@ -0,0 +1,58 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/gigaptera/hikkoshi-lens/backend/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// 東西に並ぶA線の3駅 (約2km間隔) と、A線の2駅目で乗り換えるB線の2駅
+func transitFixture() []*domain.Station {
+	return []*domain.Station{
+		{ID: 1, StationCode: "A01", OrganizationCode: "X", LineName: "A線", Name: "一丁目", Lat: 35.0, Lon: 139.00},
+		{ID: 2, StationCode: "A02", OrganizationCode: "X", LineName: "A線", Name: "中央", Lat: 35.0, Lon: 139.022},
+		{ID: 3, StationCode: "A03", OrganizationCode: "X", LineName: "A線", Name: "三丁目", Lat: 35.0, Lon: 139.044},
+		{ID: 4, StationCode: "B01", OrganizationCode: "Y", LineName: "B線", Name: "中央", Lat: 35.0005, Lon: 139.022},
+		{ID: 5, StationCode: "B02", OrganizationCode: "Y", LineName: "B線", Name: "北", Lat: 35.018, Lon: 139.022},
+	}
+}
+
+func TestTransitGraph_Reachable(t *testing.T) {
+	g := NewTransitGraph(transitFixture(), DefaultTransitOptions())
+
+	// 1番駅の目の前から出発
+	reach := g.Reachable(35.0, 139.0, 60)
+	require.Len(t, reach, 5)
+
+	minutes := make(map[int64]float64)
+	for _, r := range reach {
+		minutes[r.StationID] = r.Minutes
+	}
+
+	assert.Equal(t, 0.0, minutes[1])
+	// 約2km / 40km/h = 3分 + 停車0.5分
+	assert.InDelta(t, 3.5, minutes[2], 0.2)
+	assert.InDelta(t, 7.0, minutes[3], 0.3)
+	// 中央駅で乗り換え (待ち5分 + 徒歩1分)
+	assert.InDelta(t, minutes[2]+6, minutes[4], 0.01)
+	assert.Greater(t, minutes[5], minutes[4])
+
+	// 所要時間順
+	assert.Equal(t, int64(1), reach[0].StationID)
+}
+
+func TestTransitGraph_ReachableLimit(t *testing.T) {
+	g := NewTransitGraph(transitFixture(), DefaultTransitOptions())
+
+	reach := g.Reachable(35.0, 139.0, 5)
+	var ids []int64
+	for _, r := range reach {
+		ids = append(ids, r.StationID)
+	}
+	assert.Equal(t, []int64{1, 2}, ids)
+
+	// 徒歩圏に駅がなければどこにも行けない
+	assert.Empty(t, g.Reachable(36.0, 139.0, 60))
+}
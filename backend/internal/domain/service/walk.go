@@ -0,0 +1,205 @@
+package service
+
+import (
+	"container/heap"
+	"math"
+
+	"github.com/gigaptera/hikkoshi-lens/backend/internal/domain"
+)
+
+const (
+	// walkGridDegree は最寄りノード検索の格子の大きさ (緯度経度、約200m)
+	walkGridDegree = 0.002
+	// minWalkComponentNodes はこれより小さい連結成分 (敷地内の通路など) を最寄りノードの候補にしない
+	// 孤立した通路に吸着すると、すぐ近くの目的地にも経路が見つからなくなるため
+	minWalkComponentNodes = 50
+)
+
+type walkCell struct{ x, y int32 }
+
+// WalkGraph は徒歩ネットワークの道路距離のグラフ (domain.WalkRouter)
+// 隣接リストは CSR 形式 (offsets[i]〜offsets[i+1] が ノード i のエッジ) で持ち、全国規模でもメモリを抑える
+type WalkGraph struct {
+	lats, lons []float64
+	offsets    []int32
+	targets    []int32
+	meters     []float32
+	grid       map[walkCell][]int32
+}
+
+// NewWalkGraph は徒歩ネットワークからグラフを作る
+func NewWalkGraph(net *domain.WalkNetwork) *WalkGraph {
+	n := len(net.Lats)
+	g := &WalkGraph{lats: net.Lats, lons: net.Lons, offsets: make([]int32, n+1), grid: make(map[walkCell][]int32)}
+
+	for _, e := range net.Edges {
+		if e[0] == e[1] {
+			continue
+		}
+		g.offsets[e[0]+1]++
+		g.offsets[e[1]+1]++
+	}
+	for i := 0; i < n; i++ {
+		g.offsets[i+1] += g.offsets[i]
+	}
+	g.targets = make([]int32, g.offsets[n])
+	g.meters = make([]float32, g.offsets[n])
+	next := append([]int32(nil), g.offsets[:n]...)
+	for _, e := range net.Edges {
+		a, b := e[0], e[1]
+		if a == b {
+			continue
+		}
+		m := float32(HaversineMeter(g.lats[a], g.lons[a], g.lats[b], g.lons[b]))
+		g.targets[next[a]], g.meters[next[a]] = b, m
+		next[a]++
+		g.targets[next[b]], g.meters[next[b]] = a, m
+		next[b]++
+	}
+
+	for _, comp := range g.components() {
+		if len(comp) < minWalkComponentNodes {
+			continue
+		}
+		for _, i := range comp {
+			c := walkCellOf(g.lats[i], g.lons[i])
+			g.grid[c] = append(g.grid[c], i)
+		}
+	}
+	return g
+}
+
+// components は連結成分ごとのノード番号を返す
+func (g *WalkGraph) components() [][]int32 {
+	seen := make([]bool, len(g.lats))
+	var comps [][]int32
+	var stack []int32
+	for start := range g.lats {
+		if seen[start] {
+			continue
+		}
+		seen[start] = true
+		comp := []int32{int32(start)}
+		stack = append(stack[:0], int32(start))
+		for len(stack) > 0 {
+			v := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			for k := g.offsets[v]; k < g.offsets[v+1]; k++ {
+				if t := g.targets[k]; !seen[t] {
+					seen[t] = true
+					comp = append(comp, t)
+					stack = append(stack, t)
+				}
+			}
+		}
+		comps = append(comps, comp)
+	}
+	return comps
+}
+
+func walkCellOf(lat, lon float64) walkCell {
+	return walkCell{x: int32(math.Floor(lon / walkGridDegree)), y: int32(math.Floor(lat / walkGridDegree))}
+}
+
+// snap は地点に最も近いノードと、そこまでの直線距離 (m) を返す (domain.MaxWalkSnapMeter 以内)
+func (g *WalkGraph) snap(p domain.Location) (int32, float64, bool) {
+	c := walkCellOf(p.Lat, p.Lon)
+	best, bestDist := int32(-1), domain.MaxWalkSnapMeter
+	// 格子の1辺 (約200m) は MaxWalkSnapMeter より短いため、周囲2マスまで調べる
+	for dy := int32(-2); dy <= 2; dy++ {
+		for dx := int32(-2); dx <= 2; dx++ {
+			for _, i := range g.grid[walkCell{x: c.x + dx, y: c.y + dy}] {
+				if d := HaversineMeter(p.Lat, p.Lon, g.lats[i], g.lons[i]); d <= bestDist {
+					best, bestDist = i, d
+				}
+			}
+		}
+	}
+	return best, bestDist, best >= 0
+}
+
+// WalkMeters は from から各地点までの道路距離を求める
+// 地点と最寄りノードの間は直線で歩くものとし、from から1回の Dijkstra (maxMeter で打ち切り) で全地点を求める
+func (g *WalkGraph) WalkMeters(from domain.Location, targets []domain.Location, maxMeter float64) []float64 {
+	result := make([]float64, len(targets))
+	for i := range result {
+		result[i] = -1
+	}
+	start, startOffset, ok := g.snap(from)
+	if !ok {
+		return result
+	}
+
+	type goal struct {
+		index  int
+		offset float64
+	}
+	goals := make(map[int32][]goal)
+	for i, t := range targets {
+		if node, offset, ok := g.snap(t); ok {
+			goals[node] = append(goals[node], goal{index: i, offset: offset})
+		}
+	}
+	if len(goals) == 0 {
+		return result
+	}
+
+	g.dijkstra(start, maxMeter-startOffset, nil, func(node int32, meters float64) bool {
+		for _, gl := range goals[node] {
+			if total := startOffset + meters + gl.offset; total <= maxMeter {
+				result[gl.index] = total
+			}
+		}
+		delete(goals, node)
+		return len(goals) > 0
+	})
+	return result
+}
+
+// dijkstra は start から limit (m) 以内のノードを近い順に visit に渡す (visit が false を返したら打ち切る)
+// parent が nil でなければ、各ノードの最短経路の1つ前のノードを記録する
+func (g *WalkGraph) dijkstra(start int32, limit float64, parent map[int32]int32, visit func(node int32, meters float64) bool) {
+	dist := map[int32]float64{start: 0}
+	pq := &walkQueue{{node: start}}
+	for pq.Len() > 0 {
+		item := heap.Pop(pq).(walkItem)
+		if item.meters > dist[item.node] {
+			continue
+		}
+		if !visit(item.node, item.meters) {
+			return
+		}
+		for k := g.offsets[item.node]; k < g.offsets[item.node+1]; k++ {
+			t := g.targets[k]
+			m := item.meters + float64(g.meters[k])
+			if m > limit {
+				continue
+			}
+			if d, ok := dist[t]; !ok || m < d {
+				dist[t] = m
+				if parent != nil {
+					parent[t] = item.node
+				}
+				heap.Push(pq, walkItem{node: t, meters: m})
+			}
+		}
+	}
+}
+
+type walkItem struct {
+	node   int32
+	meters float64
+}
+
+type walkQueue []walkItem
+
+func (q walkQueue) Len() int            { return len(q) }
+func (q walkQueue) Less(i, j int) bool  { return q[i].meters < q[j].meters }
+func (q walkQueue) Swap(i, j int)       { q[i], q[j] = q[j], q[i] }
+func (q *walkQueue) Push(x interface{}) { *q = append(*q, x.(walkItem)) }
+func (q *walkQueue) Pop() interface{} {
+	old := *q
+	item := old[len(old)-1]
+	*q = old[:len(old)-1]
+	return item
+}
@@ -0,0 +1,77 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/gigaptera/hikkoshi-lens/backend/internal/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+// walkStep は格子の間隔 (緯度方向に約100m)
+const walkStep = 0.0009
+
+// testWalkNetwork は10×10の格子状の道路を作る
+// x=4 と x=5 の間は川で、y=0 の橋でしか渡れない
+func testWalkNetwork() *domain.WalkNetwork {
+	net := &domain.WalkNetwork{}
+	id := func(x, y int) int32 { return int32(y*10 + x) }
+	for y := 0; y < 10; y++ {
+		for x := 0; x < 10; x++ {
+			net.Lats = append(net.Lats, 35.0+float64(y)*walkStep)
+			net.Lons = append(net.Lons, 139.0+float64(x)*walkStep)
+			if x < 9 && (x != 4 || y == 0) {
+				net.Edges = append(net.Edges, [2]int32{id(x, y), id(x+1, y)})
+			}
+			if y < 9 {
+				net.Edges = append(net.Edges, [2]int32{id(x, y), id(x, y+1)})
+			}
+		}
+	}
+	// 格子から離れた小さな通路 (敷地内の通路など)
+	base := int32(len(net.Lats))
+	net.Lats = append(net.Lats, 35.0+9*walkStep+0.0012, 35.0+9*walkStep+0.0013)
+	net.Lons = append(net.Lons, 139.0, 139.0)
+	net.Edges = append(net.Edges, [2]int32{base, base + 1})
+	return net
+}
+
+func gridPoint(x, y float64) domain.Location {
+	return domain.Location{Lat: 35.0 + y*walkStep, Lon: 139.0 + x*walkStep}
+}
+
+func TestWalkGraph_WalkMeters(t *testing.T) {
+	g := NewWalkGraph(testWalkNetwork())
+
+	from := gridPoint(0, 0)
+	got := g.WalkMeters(from, []domain.Location{
+		gridPoint(0, 3), // 直進 (約300m)
+		gridPoint(4, 9), // 川の手前
+		gridPoint(5, 9), // 川の向こう: 橋 (y=0) を渡る
+	}, 5000)
+
+	// 格子1マスの距離 (経度方向は緯度方向より短い)
+	north := HaversineMeter(from.Lat, from.Lon, gridPoint(0, 1).Lat, from.Lon)
+	east := HaversineMeter(from.Lat, from.Lon, from.Lat, gridPoint(1, 0).Lon)
+	assert.InDelta(t, 3*north, got[0], 1)
+	assert.InDelta(t, 4*east+9*north, got[1], 1)
+	assert.InDelta(t, 5*east+9*north, got[2], 1) // 橋は始点側にあるため遠回りにならない
+
+	// 川の向こう側から橋を渡って戻る (直線なら1マス)
+	back := g.WalkMeters(gridPoint(5, 9), []domain.Location{gridPoint(4, 9)}, 5000)
+	assert.InDelta(t, 18*north+east, back[0], 1)
+}
+
+func TestWalkGraph_Unreachable(t *testing.T) {
+	g := NewWalkGraph(testWalkNetwork())
+	from := gridPoint(0, 0)
+
+	got := g.WalkMeters(from, []domain.Location{
+		gridPoint(9, 9),                     // 上限を超える
+		{Lat: 36.0, Lon: 140.0},             // 道路から離れている
+		gridPoint(0, 9+0.0012/walkStep+0.5), // 小さな通路の近く: 格子には吸着しない
+	}, 1000)
+	assert.Equal(t, []float64{-1, -1, -1}, got)
+
+	// 出発地が道路から離れている
+	assert.Equal(t, []float64{-1}, g.WalkMeters(domain.Location{Lat: 36.0, Lon: 140.0}, []domain.Location{from}, 1000))
+}
@@ -0,0 +1,67 @@
+package domain
+
+import (
+	"context"
+	"time"
+
+	"github.com/uptrace/bun"
+)
+
+const (
+	// MaxShortlistNameLength は候補リスト名の最大文字数
+	MaxShortlistNameLength = 100
+	// MaxNoteLength は駅ごとのメモの最大文字数
+	MaxNoteLength = 1000
+)
+
+// Favorite はお気に入りの駅
+type Favorite struct {
+	bun.BaseModel `bun:"table:favorites,alias:fav"`
+	UserID        int64     `bun:"user_id,pk" json:"-"`
+	StationID     int64     `bun:"station_id,pk" json:"station_id"`
+	Note          string    `bun:"note,notnull" json:"note"`
+	CreatedAt     time.Time `bun:"created_at,nullzero,notnull,default:current_timestamp" json:"created_at"`
+	UpdatedAt     time.Time `bun:"updated_at,nullzero,notnull,default:current_timestamp" json:"updated_at"`
+	Station       *Station  `bun:"-" json:"station,omitempty"` // 現在のスコア・家賃相場付き
+}
+
+// Shortlist は名前付きの候補リスト
+type Shortlist struct {
+	bun.BaseModel `bun:"table:shortlists,alias:sl"`
+	ID            int64            `bun:"id,pk,autoincrement" json:"id"`
+	UserID        int64            `bun:"user_id,notnull" json:"-"`
+	Name          string           `bun:"name,notnull" json:"name"`
+	CreatedAt     time.Time        `bun:"created_at,nullzero,notnull,default:current_timestamp" json:"created_at"`
+	UpdatedAt     time.Time        `bun:"updated_at,nullzero,notnull,default:current_timestamp" json:"updated_at"`
+	ItemCount     int              `bun:"item_count,scanonly" json:"item_count"`
+	Items         []*ShortlistItem `bun:"-" json:"items,omitempty"`
+}
+
+type ShortlistItem struct {
+	bun.BaseModel `bun:"table:shortlist_items,alias:sli"`
+	ShortlistID   int64     `bun:"shortlist_id,pk" json:"-"`
+	StationID     int64     `bun:"station_id,pk" json:"station_id"`
+	Note          string    `bun:"note,notnull" json:"note"`
+	CreatedAt     time.Time `bun:"created_at,nullzero,notnull,default:current_timestamp" json:"created_at"`
+	UpdatedAt     time.Time `bun:"updated_at,nullzero,notnull,default:current_timestamp" json:"updated_at"`
+	Station       *Station  `bun:"-" json:"station,omitempty"` // 現在のスコア・家賃相場付き
+}
+
+// ShortlistRepository はお気に入りと候補リストの保存先
+// 他人のリスト・存在しない行を指定した場合は sql.ErrNoRows を返す
+type ShortlistRepository interface {
+	ListFavorites(ctx context.Context, userID int64) ([]*Favorite, error)
+	UpsertFavorite(ctx context.Context, fav *Favorite) error
+	DeleteFavorite(ctx context.Context, userID, stationID int64) error
+
+	// ListShortlists は利用者の候補リストを駅数 (ItemCount) 付きで返す
+	ListShortlists(ctx context.Context, userID int64) ([]*Shortlist, error)
+	GetShortlist(ctx context.Context, userID, shortlistID int64) (*Shortlist, error)
+	CreateShortlist(ctx context.Context, list *Shortlist) error
+	RenameShortlist(ctx context.Context, userID, shortlistID int64, name string) error
+	DeleteShortlist(ctx context.Context, userID, shortlistID int64) error
+
+	ListShortlistItems(ctx context.Context, shortlistID int64) ([]*ShortlistItem, error)
+	UpsertShortlistItem(ctx context.Context, item *ShortlistItem) error
+	DeleteShortlistItem(ctx context.Context, shortlistID, stationID int64) error
+}
@@ -16,10 +16,16 @@ type Station struct {
 	Name             string             `bun:"name,notnull" json:"name"`
 	PrefectureCode   int                `bun:"prefecture_code,notnull" json:"prefecture_code"`
 	Location         string             `bun:"location,type:geography(POINT,4326)" json:"location"` // PostGIS Point
-	Distance         float64            `bun:"distance,scanonly" json:"distance,omitempty"`         // 検索時の距離(m)
-	TotalScore       float64            `bun:"-" json:"total_score"`                                // 総合スコア (DBには保存しない)
-	RentAvg          float64            `bun:"-" json:"rent_avg,omitempty"`                         // フィルター条件に合致する家賃相場
-	ScoreDetails     map[string]float64 `bun:"-" json:"score_details,omitempty"`                    // スコア内訳
+	Lat              float64            `bun:"lat,scanonly" json:"lat"`
+	Lon              float64            `bun:"lon,scanonly" json:"lon"`
+	Distance         float64            `bun:"distance,scanonly" json:"distance,omitempty"` // 検索時の距離(m)
+	WalkDistance     float64            `bun:"-" json:"walk_distance,omitempty"`            // 検索地点からの道路距離(m)。徒歩ネットワークを読み込んだ場合のみ
+	WalkMinutes      int                `bun:"-" json:"walk_minutes,omitempty"`             // 検索地点からの徒歩分数 (domain.MaxWalkAccessMeter 以内の駅のみ)
+	TotalScore       float64            `bun:"-" json:"total_score"`                        // 総合スコア (DBには保存しない)
+	RentAvg          float64            `bun:"-" json:"rent_avg,omitempty"`                 // フィルター条件に合致する家賃相場
+	ScoreDetails     map[string]float64 `bun:"-" json:"score_details,omitempty"`            // スコア内訳
+	AxisScores       map[string]float64 `bun:"-" json:"-"`                                  // station_scoresの事前計算済み軸スコア
+	Tags             []string           `bun:"-" json:"tags,omitempty"`                     // station_tagsの事前計算済みタグ
 	Address          string             `bun:"address" json:"address"`
 
 	// 家賃補助関連フィールド
@@ -27,6 +33,17 @@ type Station struct {
 	SourceStation   string `bun:"-" json:"source_station,omitempty"`    // どの最寄り駅から含まれたか
 	StopsFromSource int    `bun:"-" json:"stops_from_source,omitempty"` // 最寄り駅から何駅目か
 
+	// 月額総額 (monthly_cost=true で検索した場合のみ)
+	MonthlyCost *MonthlyCost `bun:"-" json:"monthly_cost,omitempty"`
+	// 初期費用の見積もり (駅の比較で move_in=true の場合のみ)
+	MoveInCost *MoveInEstimate `bun:"-" json:"move_in_cost,omitempty"`
+	// 駅周辺の地形と最寄りの避難場所・救急病院までの距離 (cmd/scores recompute が disaster 軸の計算に使う)
+	Terrain        *StationTerrain        `bun:"-" json:"-"`
+	DisasterAccess *StationDisasterAccess `bun:"-" json:"-"`
+	// 街灯の密度と最寄りの交番までの距離 (cmd/scores recompute と NightSafetyUsecase が safety 軸の計算に使う)
+	// 検索結果では ScoreDetails["night_safety"]、駅の詳細では StationDetail.NightSafety で返す
+	NightSafety *StationNightSafety `bun:"-" json:"-"`
+
 	// Relations or calculated fields
 	Lines        []Line         `bun:"rel:has-many,join:id=station_id" json:"lines,omitempty"`
 	MarketPrices []*MarketPrice `bun:"rel:has-many,join:id=station_id" json:"market_prices,omitempty"`
@@ -40,21 +57,36 @@ type Line struct {
 	LineName  string `bun:"line_name,notnull" json:"line_name"`
 }
 
+// StationFilter は駅検索の条件
+// 保存した検索条件 (saved_searches.filter) にはJSONで保存する
 type StationFilter struct {
-	RadiusMeter     int
-	MinRent         float64
-	MaxRent         float64
-	BuildingType    string
-	Layout          string
-	Weights         map[string]int
-	CalculateScores bool // If true, calculate scores; if false, return raw data only
+	RadiusMeter     int            `json:"radius_meter"`
+	MinRent         float64        `json:"min_rent,omitempty"`
+	MaxRent         float64        `json:"max_rent,omitempty"`
+	BuildingType    string         `json:"building_type,omitempty"`
+	Layout          string         `json:"layout,omitempty"`
+	Weights         map[string]int `json:"weights,omitempty"`
+	CalculateScores bool           `json:"calculate_scores"` // If true, calculate scores; if false, return raw data only
 	// 家賃補助関連
-	SubsidyType  string // "none" or "from_workplace"
-	SubsidyRange int    // 最寄り駅から前後何駅まで（デフォルト3）
+	SubsidyType  string `json:"subsidy_type,omitempty"`  // "none" or "from_workplace"
+	SubsidyRange int    `json:"subsidy_range,omitempty"` // 最寄り駅から前後何駅まで（デフォルト3）
+	// すべて持つ駅に絞り込むタグ (station_tags)
+	Tags []string `json:"tags,omitempty"`
+}
+
+// LineKey は事業者コードと路線名で路線を識別する
+type LineKey struct {
+	OrganizationCode string
+	LineName         string
 }
 
 type StationRepository interface {
 	GetNearby(ctx context.Context, lat, lon float64, filter StationFilter) ([]*Station, error)
 	GetStation(ctx context.Context, id int64) (*Station, error)
 	GetByLine(ctx context.Context, organizationCode, lineName string) ([]*Station, error)
+	// GetByLines は複数路線の駅を1回のクエリで取得する。各路線の駅は GetByLine と同じ順序
+	GetByLines(ctx context.Context, keys []LineKey) (map[LineKey][]*Station, error)
+	ListAll(ctx context.Context) ([]*Station, error)
+	// GetByIDs は指定した駅を家賃相場付きで返す (存在しないIDは結果に含めない)
+	GetByIDs(ctx context.Context, ids []int64) ([]*Station, error)
 }
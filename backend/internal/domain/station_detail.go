@@ -7,9 +7,19 @@ type StationDetail struct {
 	Lines          []string       `json:"lines"`
 	Tags           []string       `json:"tags"`
 	AIInsight      AIInsight      `json:"ai_insight"`
+	Reviews        ReviewSummary  `json:"reviews"` // 承認済み口コミの集計
 	Score          DetailScore    `json:"score"`
 	MarketPrice    MarketData     `json:"market_price"`
 	AffiliateLinks AffiliateLinks `json:"affiliate_links"`
+	// 1日あたりの乗降客数 (国土数値情報 S12)。未登録の駅は null
+	DailyPassengers *int            `json:"daily_passengers"`
+	PassengersYear  int             `json:"passengers_year,omitempty"` // 乗降客数の集計年度
+	Terrain         *StationTerrain `json:"terrain,omitempty"`         // 駅周辺の坂・標高 (地形を計算した駅のみ)
+	NearestShelters []*POI          `json:"nearest_shelters"`          // 最寄りの避難場所 (近い順)
+	// 街灯の密度と最寄りの交番までの距離 (夜間の安全性を集計した駅のみ、safety 軸の夜間の成分)
+	NightSafety *StationNightSafety `json:"night_safety,omitempty"`
+	MonthlyCost *MonthlyCost        `json:"monthly_cost,omitempty"` // workplace_station_id を指定した場合のみ
+	MoveInCost  *MoveInEstimate     `json:"move_in_cost,omitempty"` // move_in=true を指定した場合のみ
 }
 
 type AIInsight struct {
@@ -29,9 +39,11 @@ type ResidentVoices struct {
 	Negative []string `json:"negative"`
 }
 
+// DetailScore は駅の詳細のスコア (0-100、検索と同じく station_scores の値から計算する)
 type DetailScore struct {
-	Total float64    `json:"total"`
-	Radar RadarScore `json:"radar"`
+	Total   float64            `json:"total"`
+	Radar   RadarScore         `json:"radar"`
+	Details map[string]float64 `json:"details"` // 軸ごとのスコア (検索結果の score_details と同じ)
 }
 
 type RadarScore struct {
@@ -43,12 +55,13 @@ type RadarScore struct {
 }
 
 type MarketData struct {
-	Prices             map[string]float64 `json:"prices"` // "1R": 7.5, "1LDK": 12.0
+	Prices             map[string]float64 `json:"prices"` // 間取りごとの家賃相場 (建物種別の平均、万円)
 	NeighborComparison NeighborComparison `json:"neighbor_comparison"`
 }
 
 type NeighborComparison struct {
-	NextStationDiff float64 `json:"next_station_diff"` // +3000 or -2000
+	// 同じ路線の隣の駅と比べた平均家賃の差 (円、この駅の方が高ければ正)。どちらかの相場がない場合は 0
+	NextStationDiff float64 `json:"next_station_diff"`
 	PrevStationDiff float64 `json:"prev_station_diff"`
 }
 
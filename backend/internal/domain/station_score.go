@@ -0,0 +1,30 @@
+package domain
+
+import (
+	"context"
+	"time"
+
+	"github.com/uptrace/bun"
+)
+
+// StationScore は駅単体のデータだけで決まる軸スコアの事前計算結果
+// 勤務地や間取りに依存しない軸 (facility, safety, disaster, review) のみを保存する
+type StationScore struct {
+	bun.BaseModel   `bun:"table:station_scores,alias:ss"`
+	StationID       int64     `bun:"station_id,pk" json:"station_id"`
+	Axis            string    `bun:"axis,pk" json:"axis"`
+	RawScore        float64   `bun:"raw_score,notnull" json:"raw_score"`
+	NormalizedScore float64   `bun:"normalized_score,notnull" json:"normalized_score"` // 0-100
+	DataVersion     string    `bun:"data_version,notnull" json:"data_version"`
+	UpdatedAt       time.Time `bun:"updated_at,nullzero,notnull,default:current_timestamp" json:"updated_at"`
+}
+
+type StationScoreRepository interface {
+	// GetByStationIDs は station_id -> axis -> normalized_score のマップを返す
+	GetByStationIDs(ctx context.Context, stationIDs []int64) (map[int64]map[string]float64, error)
+	Upsert(ctx context.Context, scores []*StationScore) error
+	// Delete は駅の1軸分のスコアを削除する (該当がなくてもエラーにしない)
+	Delete(ctx context.Context, stationID int64, axis string) error
+	// NotifyUpdated は station_scores の更新を通知し、APIサーバーのキャッシュを破棄させる
+	NotifyUpdated(ctx context.Context) error
+}
@@ -0,0 +1,66 @@
+package domain
+
+import (
+	"context"
+	"time"
+
+	"github.com/uptrace/bun"
+)
+
+// タグ判定に使う駅の指標 (TagRule の条件で参照する名前)
+// データがない指標は条件を満たさないものとして扱う
+const (
+	TagFactRent           = "rent"             // 代表家賃 (1R〜1DK の建物種別平均、万円)
+	TagFactRentLineRatio  = "rent_line_ratio"  // 代表家賃 ÷ 同じ路線の駅の中央値
+	TagFactLineCount      = "line_count"       // 乗り入れ路線数 (同名で500m以内の駅を同じ駅とみなす)
+	TagFactUniversities   = "university_count" // 1km以内の大学・専門学校
+	TagFactSupermarkets   = "supermarket_count"
+	TagFactRestaurants    = "restaurant_count"
+	TagFactParks          = "park_count"
+	TagFactFloodRisk      = "flood_risk"     // 0:なし 〜 3:危険
+	TagFactLandslideRisk  = "landslide_risk" // 0:なし 〜 3:危険
+	TagFactEarthquakeRisk = "earthquake_risk"
+	TagFactFacilityScore  = "facility_score" // station_scores の正規化スコア (0-100)
+	TagFactSafetyScore    = "safety_score"
+	TagFactDisasterScore  = "disaster_score"
+)
+
+// TagFacts は1駅分のタグ判定用の指標
+type TagFacts struct {
+	StationID int64
+	LineKey   LineKey
+	Values    map[string]float64
+}
+
+// TagCondition は「指標 演算子 値」の条件 (演算子: lt, lte, gt, gte, eq)
+type TagCondition struct {
+	Fact  string  `json:"fact"`
+	Op    string  `json:"op"`
+	Value float64 `json:"value"`
+}
+
+// TagRule は条件をすべて満たす駅に付けるタグ
+type TagRule struct {
+	Tag         string         `json:"tag"`
+	Description string         `json:"description,omitempty"`
+	All         []TagCondition `json:"all"`
+}
+
+// StationTag は事前計算した駅のタグ (cmd/tags で再計算する)
+type StationTag struct {
+	bun.BaseModel `bun:"table:station_tags,alias:st"`
+	StationID     int64     `bun:"station_id,pk" json:"station_id"`
+	Tag           string    `bun:"tag,pk" json:"tag"`
+	Position      int       `bun:"position,notnull" json:"-"` // ルールの定義順 (表示順)
+	DataVersion   string    `bun:"data_version,notnull" json:"data_version"`
+	UpdatedAt     time.Time `bun:"updated_at,nullzero,notnull,default:current_timestamp" json:"updated_at"`
+}
+
+type TagRepository interface {
+	// ListFacts は全駅のタグ判定用の指標を返す (路線中央値との比など駅をまたぐ指標は含まない)
+	ListFacts(ctx context.Context) ([]*TagFacts, error)
+	// ReplaceAll は全駅のタグを置き換える
+	ReplaceAll(ctx context.Context, tags []*StationTag) error
+	// GetByStationIDs は station_id -> タグ (ルールの定義順) のマップを返す
+	GetByStationIDs(ctx context.Context, stationIDs []int64) (map[int64][]string, error)
+}
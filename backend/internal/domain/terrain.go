@@ -0,0 +1,49 @@
+package domain
+
+import (
+	"context"
+	"time"
+
+	"github.com/uptrace/bun"
+)
+
+// HillinessScoreAxis は駅周辺の坂の少なさの軸 (cmd/import/terrain が station_scores に保存する)
+// 平坦な駅ほどスコアが高い
+const HillinessScoreAxis = "hilliness"
+
+// 地形の計算方法
+const (
+	TerrainMethodNetwork = "network" // 徒歩ネットワークの経路に沿って計算
+	TerrainMethodRadial  = "radial"  // 駅から放射状の直線に沿って計算 (徒歩ネットワークがない場合)
+)
+
+// ElevationModel は地点の標高 (m) を返す。データのない地点 (海上・範囲外) は ok=false
+type ElevationModel interface {
+	Elevation(lat, lon float64) (float64, bool)
+}
+
+// StationTerrain は駅周辺の徒歩経路の起伏 (国土地理院の数値標高モデルから計算)
+type StationTerrain struct {
+	bun.BaseModel `bun:"table:station_terrain,alias:stt"`
+
+	StationID int64   `bun:"station_id,pk" json:"-"`
+	Elevation float64 `bun:"elevation,notnull" json:"elevation"` // 駅の標高 (m)
+	// 経路の平均勾配 (%)。上り下りを区別しない距離加重平均
+	AvgSlope float64 `bun:"avg_slope,notnull" json:"avg_slope"`
+	// 駅と徒歩圏の端の間の経路の上りの累計 (m) の平均 (行き・帰りのうち登る高さの大きい方)
+	ElevationGain float64 `bun:"elevation_gain,notnull" json:"elevation_gain"`
+	// 周辺で最も低い地点からの駅の高さ (m)。川沿いの低地からの比高の目安で、小さいほど浸水しやすい
+	RelativeHeight float64   `bun:"relative_height,notnull" json:"relative_height"`
+	RadiusMeter    int       `bun:"radius_meter,notnull" json:"radius"`
+	Method         string    `bun:"method,notnull" json:"method"`
+	DataVersion    string    `bun:"data_version,notnull" json:"-"`
+	UpdatedAt      time.Time `bun:"updated_at,nullzero,notnull,default:current_timestamp" json:"-"`
+}
+
+type TerrainRepository interface {
+	Upsert(ctx context.Context, rows []*StationTerrain) error
+	// ListAll は全駅の地形を返す (station_id -> 地形)
+	ListAll(ctx context.Context) (map[int64]*StationTerrain, error)
+	// Get は駅の地形を返す。未計算の場合は sql.ErrNoRows
+	Get(ctx context.Context, stationID int64) (*StationTerrain, error)
+}
@@ -0,0 +1,37 @@
+package domain
+
+import (
+	"context"
+	"fmt"
+)
+
+// MaxTileZoom はタイルAPIで受け付ける最大ズームレベル
+const MaxTileZoom = 18
+
+// TileCoord はWebメルカトルのXYZタイル座標
+type TileCoord struct {
+	Z, X, Y int
+}
+
+// Validate はズームレベルの範囲内でタイル座標が有効かを確認する
+func (t TileCoord) Validate() error {
+	if t.Z < 0 || t.Z > MaxTileZoom {
+		return fmt.Errorf("zoom must be between 0 and %d", MaxTileZoom)
+	}
+	n := 1 << t.Z
+	if t.X < 0 || t.X >= n || t.Y < 0 || t.Y >= n {
+		return fmt.Errorf("tile %d/%d/%d is out of range", t.Z, t.X, t.Y)
+	}
+	return nil
+}
+
+func (t TileCoord) String() string {
+	return fmt.Sprintf("%d/%d/%d", t.Z, t.X, t.Y)
+}
+
+type StationTileRepository interface {
+	// GetInTile はタイル範囲内の駅を返す。filter の建物種別・間取りが両方指定されていれば該当する家賃相場も読み込む
+	GetInTile(ctx context.Context, tile TileCoord, filter StationFilter) ([]*Station, error)
+	// RenderStations は計算済みのスコア・家賃を属性に持つ駅をMapbox Vector Tileにエンコードする
+	RenderStations(ctx context.Context, tile TileCoord, stations []*Station) ([]byte, error)
+}
@@ -0,0 +1,86 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/uptrace/bun"
+)
+
+var (
+	// ErrInvalidToken はマジックリンク・セッションのトークンが存在しない・期限切れ・使用済みの場合のエラー
+	ErrInvalidToken = errors.New("invalid or expired token")
+	// ErrLoginTokenLimitByEmail は同じメールアドレスへのトークンが MaxMagicLinksPerEmail に達している場合のエラー
+	ErrLoginTokenLimitByEmail = errors.New("login token limit reached for email")
+	// ErrLoginTokenLimitByClient は同じ要求元からのトークンが MaxMagicLinksPerClient に達している場合のエラー
+	ErrLoginTokenLimitByClient = errors.New("login token limit reached for client")
+)
+
+// ログインリンクの送信数の制限 (ログイン不要のAPIのため、メールの大量送信に使われないようにする)
+const (
+	// MagicLinkRateWindow は送信数を数える期間
+	MagicLinkRateWindow = time.Hour
+	// MaxMagicLinksPerEmail は同じメールアドレスに MagicLinkRateWindow あたりに送れる件数
+	MaxMagicLinksPerEmail = 3
+	// MaxMagicLinksPerClient は同じ要求元から MagicLinkRateWindow あたりに要求できる件数
+	MaxMagicLinksPerClient = 10
+)
+
+type User struct {
+	bun.BaseModel `bun:"table:users,alias:u"`
+	ID            int64      `bun:"id,pk,autoincrement" json:"id"`
+	Email         string     `bun:"email,notnull,unique" json:"email"`
+	CreatedAt     time.Time  `bun:"created_at,nullzero,notnull,default:current_timestamp" json:"created_at"`
+	LastLoginAt   *time.Time `bun:"last_login_at" json:"last_login_at,omitempty"`
+}
+
+// LoginToken はマジックリンクのワンタイムトークン (平文はメールにだけ載せ、DBにはハッシュを保存する)
+type LoginToken struct {
+	bun.BaseModel `bun:"table:login_tokens,alias:lt"`
+	TokenHash     string     `bun:"token_hash,pk"`
+	Email         string     `bun:"email,notnull"`
+	ClientHash    string     `bun:"client_hash,notnull"` // 要求元 (IPアドレス) のHMAC
+	ExpiresAt     time.Time  `bun:"expires_at,notnull"`
+	UsedAt        *time.Time `bun:"used_at"`
+	CreatedAt     time.Time  `bun:"created_at,nullzero,notnull,default:current_timestamp"`
+}
+
+type Session struct {
+	bun.BaseModel `bun:"table:sessions,alias:ses"`
+	TokenHash     string    `bun:"token_hash,pk"`
+	UserID        int64     `bun:"user_id,notnull"`
+	ExpiresAt     time.Time `bun:"expires_at,notnull"`
+	CreatedAt     time.Time `bun:"created_at,nullzero,notnull,default:current_timestamp"`
+}
+
+type UserRepository interface {
+	// GetOrCreateByEmail はメールアドレスの利用者を返す (初回ログインなら作成する)
+	GetOrCreateByEmail(ctx context.Context, email string) (*User, error)
+	// CreateLoginTokenWithinLimit は since 以降に作成したトークンの数がメールアドレスごと・要求元ごとの上限
+	// (MaxMagicLinksPerEmail・MaxMagicLinksPerClient) 未満の場合だけトークンを保存する
+	// 数の確認と保存はメールアドレス・要求元ごとに排他して行い、同時の要求でも上限を超えない
+	// 上限に達している場合は ErrLoginTokenLimitByEmail または ErrLoginTokenLimitByClient
+	CreateLoginTokenWithinLimit(ctx context.Context, token *LoginToken, since time.Time) error
+	// DeleteLoginTokensExpiredBefore は before より前に期限切れになったトークン (使用済みを含む) を削除する
+	DeleteLoginTokensExpiredBefore(ctx context.Context, before time.Time) (int64, error)
+	// ConsumeLoginToken は未使用・期限内のトークンを使用済みにしてメールアドレスを返す
+	// 該当がなければ ErrInvalidToken
+	ConsumeLoginToken(ctx context.Context, tokenHash string) (string, error)
+	CreateSession(ctx context.Context, session *Session) error
+	// GetSessionUser は期限内のセッションの利用者を返す。該当がなければ ErrInvalidToken
+	GetSessionUser(ctx context.Context, tokenHash string) (*User, error)
+	DeleteSession(ctx context.Context, tokenHash string) error
+}
+
+// MailMessage は送信するメール (本文はプレーンテキスト)
+type MailMessage struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+// MailSender はメールの送信手段 (SMTP、開発用のログ出力など)
+type MailSender interface {
+	Send(ctx context.Context, msg MailMessage) error
+}
@@ -0,0 +1,25 @@
+package domain
+
+// 徒歩ネットワークで経路を探す範囲
+const (
+	// MaxWalkSnapMeter は地点から最寄りの道路ノードまでの距離の上限 (これより遠い地点は経路なし)
+	MaxWalkSnapMeter = 250.0
+	// MaxWalkAccessMeter は勤務地から駅まで徒歩の経路を求める道路距離の上限
+	// これより遠い駅は電車で通う距離のため、直線距離から推定する
+	MaxWalkAccessMeter = 4000.0
+)
+
+// WalkNetwork はOSMの歩行可能な道路から作った徒歩ネットワーク (cmd/api の起動時に読み込む)
+// ノードは道路の構成点、エッジは同じ道路の隣り合う構成点の組
+type WalkNetwork struct {
+	Lats  []float64
+	Lons  []float64
+	Edges [][2]int32 // ノード番号の組 (向きなし)
+}
+
+// WalkRouter は徒歩経路の道路距離を求める
+type WalkRouter interface {
+	// WalkMeters は from から各 targets までの道路距離 (m) を返す
+	// maxMeter を超える地点や、道路から離れていて経路が求まらない地点は -1
+	WalkMeters(from Location, targets []Location, maxMeter float64) []float64
+}
@@ -0,0 +1,135 @@
+// Package alert は保存した検索の変化を利用者に届ける通知手段 (domain.Notifier の実装)
+package alert
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/gigaptera/hikkoshi-lens/backend/internal/domain"
+)
+
+// LogNotifier は通知内容をログに出すだけの既定の通知手段 (ローカル開発用)
+type LogNotifier struct{}
+
+func (n *LogNotifier) Notify(ctx context.Context, alert *domain.SearchAlert) error {
+	log.Printf("[alert] search=%d user=%d %s", alert.Search.ID, alert.Search.UserID, summary(alert))
+	return nil
+}
+
+// EmailNotifier は検索条件の持ち主にメールで通知する
+type EmailNotifier struct {
+	Mailer domain.MailSender
+}
+
+func (n *EmailNotifier) Notify(ctx context.Context, alert *domain.SearchAlert) error {
+	if alert.Search.UserEmail == "" {
+		return errors.New("saved search has no user email")
+	}
+	return n.Mailer.Send(ctx, domain.MailMessage{
+		To:      alert.Search.UserEmail,
+		Subject: fmt.Sprintf("【引越しレンズ】「%s」の検索結果が変わりました", alert.Search.Name),
+		Body:    mailBody(alert),
+	})
+}
+
+// WebhookNotifier は検索条件に登録された URL に SearchAlert を JSON で POST する
+// 利用者が任意の URL を登録できるため、https 以外と内部ネットワーク宛ての接続は拒否する
+type WebhookNotifier struct {
+	Client *http.Client
+}
+
+// NewWebhookNotifier は内部ネットワーク宛ての接続を拒否する WebhookNotifier を返す
+func NewWebhookNotifier(timeout time.Duration) *WebhookNotifier {
+	dialer := &net.Dialer{Timeout: timeout, Control: denyPrivate}
+	transport := &http.Transport{
+		DialContext:         dialer.DialContext,
+		TLSHandshakeTimeout: timeout,
+	}
+	return &WebhookNotifier{Client: &http.Client{
+		Timeout:   timeout,
+		Transport: transport,
+		// リダイレクト先で内部ネットワークに誘導されないよう追わない
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}}
+}
+
+func (n *WebhookNotifier) Notify(ctx context.Context, alert *domain.SearchAlert) error {
+	if !domain.IsValidWebhookURL(alert.Search.WebhookURL) {
+		return errors.New("webhook URL must be an absolute https URL")
+	}
+	body, err := json.Marshal(alert)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, alert.Search.WebhookURL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "hikkoshi-lens-alerts/1.0")
+
+	resp, err := n.Client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook responded with status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// denyPrivate は名前解決後の接続先がループバック・プライベート・リンクローカルなら接続を拒否する
+func denyPrivate(network, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	ip := net.ParseIP(host)
+	if ip == nil {
+		return fmt.Errorf("unexpected address %q", address)
+	}
+	if ip.IsLoopback() || ip.IsPrivate() || ip.IsUnspecified() || ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() || ip.IsMulticast() {
+		return fmt.Errorf("webhook to non-public address %s is not allowed", ip)
+	}
+	return nil
+}
+
+func summary(alert *domain.SearchAlert) string {
+	counts := make(map[string]int)
+	for _, c := range alert.Changes {
+		counts[c.Kind]++
+	}
+	return fmt.Sprintf("new=%d rent_down=%d rent_up=%d", counts[domain.SearchChangeNew], counts[domain.SearchChangeRentDown], counts[domain.SearchChangeRentUp])
+}
+
+func mailBody(alert *domain.SearchAlert) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "保存した検索「%s」の結果が変わりました。\n\n", alert.Search.Name)
+	for _, c := range alert.Changes {
+		switch c.Kind {
+		case domain.SearchChangeNew:
+			if c.NewRent > 0 {
+				fmt.Fprintf(&b, "・%s駅が条件に合うようになりました (家賃相場 %.1f万円)\n", c.StationName, c.NewRent)
+			} else {
+				fmt.Fprintf(&b, "・%s駅が条件に合うようになりました\n", c.StationName)
+			}
+		case domain.SearchChangeRentDown:
+			fmt.Fprintf(&b, "・%s駅の家賃相場が下がりました (%.1f万円 → %.1f万円)\n", c.StationName, c.OldRent, c.NewRent)
+		case domain.SearchChangeRentUp:
+			fmt.Fprintf(&b, "・%s駅の家賃相場が上がりました (%.1f万円 → %.1f万円)\n", c.StationName, c.OldRent, c.NewRent)
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,74 @@
+package alert
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gigaptera/hikkoshi-lens/backend/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testAlert(webhookURL string) *domain.SearchAlert {
+	return &domain.SearchAlert{
+		Search: &domain.SavedSearch{ID: 1, Name: "渋谷通勤", UserEmail: "taro@example.com", WebhookURL: webhookURL},
+		Changes: []domain.SearchChange{
+			{Kind: domain.SearchChangeNew, StationID: 12, StationName: "駒場東大前", NewRent: 9.9},
+			{Kind: domain.SearchChangeRentDown, StationID: 11, StationName: "三軒茶屋", OldRent: 9.5, NewRent: 9.1},
+		},
+	}
+}
+
+type recordingMailer struct {
+	sent []domain.MailMessage
+}
+
+func (m *recordingMailer) Send(ctx context.Context, msg domain.MailMessage) error {
+	m.sent = append(m.sent, msg)
+	return nil
+}
+
+func TestEmailNotifier(t *testing.T) {
+	mailer := &recordingMailer{}
+	require.NoError(t, (&EmailNotifier{Mailer: mailer}).Notify(context.Background(), testAlert("")))
+
+	require.Len(t, mailer.sent, 1)
+	assert.Equal(t, "taro@example.com", mailer.sent[0].To)
+	assert.Contains(t, mailer.sent[0].Subject, "渋谷通勤")
+	assert.Contains(t, mailer.sent[0].Body, "駒場東大前駅が条件に合うようになりました (家賃相場 9.9万円)")
+	assert.Contains(t, mailer.sent[0].Body, "三軒茶屋駅の家賃相場が下がりました (9.5万円 → 9.1万円)")
+}
+
+func TestWebhookNotifier(t *testing.T) {
+	var got domain.SearchAlert
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&got))
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer srv.Close()
+
+	// テストサーバーはループバックで待ち受けるため、接続先の制限がないクライアントを使う
+	n := &WebhookNotifier{Client: srv.Client()}
+	require.NoError(t, n.Notify(context.Background(), testAlert(srv.URL)))
+	assert.Equal(t, "渋谷通勤", got.Search.Name)
+	assert.Len(t, got.Changes, 2)
+
+	// https 以外は送らない
+	assert.Error(t, n.Notify(context.Background(), testAlert("http://example.com/hook")))
+}
+
+func TestWebhookNotifier_DeniesPrivateAddress(t *testing.T) {
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("request to loopback address must not be sent")
+	}))
+	defer srv.Close()
+
+	err := NewWebhookNotifier(time.Second).Notify(context.Background(), testAlert(srv.URL))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "not allowed")
+}
@@ -0,0 +1,59 @@
+package cache
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestLRU_EvictsLeastRecentlyUsed はサイズ上限で最も古いエントリが追い出されることを確認する
+func TestLRU_EvictsLeastRecentlyUsed(t *testing.T) {
+	c := NewLRU[string, int](0, 2)
+	c.Set("a", 1)
+	c.Set("b", 2)
+	c.Get("a") // aを最近使用にする
+	c.Set("c", 3)
+
+	_, ok := c.Get("b")
+	assert.False(t, ok)
+	v, ok := c.Get("a")
+	assert.True(t, ok)
+	assert.Equal(t, 1, v)
+
+	stats := c.Stats()
+	assert.Equal(t, uint64(1), stats.Evictions)
+	assert.Equal(t, 2, stats.Entries)
+}
+
+// TestLRU_TTL は期限切れのエントリがミスになることを確認する
+func TestLRU_TTL(t *testing.T) {
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	c := NewLRU[string, int](time.Minute, 10)
+	c.now = func() time.Time { return now }
+
+	c.Set("a", 1)
+	_, ok := c.Get("a")
+	assert.True(t, ok)
+
+	now = now.Add(2 * time.Minute)
+	_, ok = c.Get("a")
+	assert.False(t, ok)
+
+	stats := c.Stats()
+	assert.Equal(t, uint64(1), stats.Hits)
+	assert.Equal(t, uint64(1), stats.Misses)
+	assert.Equal(t, uint64(1), stats.Expirations)
+	assert.Equal(t, 0.5, stats.HitRatio())
+}
+
+// TestLRU_Purge は無効化で全件削除されることを確認する
+func TestLRU_Purge(t *testing.T) {
+	c := NewLRU[string, int](0, 0)
+	c.Set("a", 1)
+	c.Set("b", 2)
+	c.Purge()
+
+	assert.Equal(t, 0, c.Len())
+	assert.Equal(t, uint64(1), c.Stats().Purges)
+}
@@ -0,0 +1,121 @@
+// Package gsi は国土地理院の配布データを読み込む
+package gsi
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"math"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/gigaptera/hikkoshi-lens/backend/internal/infrastructure/cache"
+)
+
+// demTileSize は標高タイルの1辺の画素数
+const demTileSize = 256
+
+// ParseDEMTile は標高タイル (テキスト形式) を読む
+// 256行 × 256列のカンマ区切りの標高 (m) で、"e" はデータなし (NaN にする)
+func ParseDEMTile(r io.Reader) ([]float32, error) {
+	values := make([]float32, 0, demTileSize*demTileSize)
+	sc := bufio.NewScanner(r)
+	sc.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	rows := 0
+	for sc.Scan() {
+		line := strings.TrimSpace(sc.Text())
+		if line == "" {
+			continue
+		}
+		cols := strings.Split(line, ",")
+		if len(cols) != demTileSize {
+			return nil, fmt.Errorf("row %d: %d columns (want %d)", rows+1, len(cols), demTileSize)
+		}
+		for _, c := range cols {
+			c = strings.TrimSpace(c)
+			if c == "e" {
+				values = append(values, float32(math.NaN()))
+				continue
+			}
+			v, err := strconv.ParseFloat(c, 32)
+			if err != nil {
+				return nil, fmt.Errorf("row %d: %w", rows+1, err)
+			}
+			values = append(values, float32(v))
+		}
+		rows++
+	}
+	if err := sc.Err(); err != nil {
+		return nil, err
+	}
+	if rows != demTileSize {
+		return nil, fmt.Errorf("%d rows (want %d)", rows, demTileSize)
+	}
+	return values, nil
+}
+
+type demTileKey struct{ x, y int }
+
+// DEMTiles はディレクトリに置いた標高タイル ({zoom}/{x}/{y}.txt) から標高を返す (domain.ElevationModel)
+// dem5a (5mメッシュ) はズーム15、dem10b (10mメッシュ) はズーム14で配布されている
+// 読み込んだタイルは maxTiles 枚まで保持する (1枚あたり約256KB)
+type DEMTiles struct {
+	dir   string
+	zoom  int
+	tiles *cache.LRU[demTileKey, []float32]
+}
+
+func NewDEMTiles(dir string, zoom, maxTiles int) *DEMTiles {
+	return &DEMTiles{dir: dir, zoom: zoom, tiles: cache.NewLRU[demTileKey, []float32](0, maxTiles)}
+}
+
+// Elevation は地点を含む画素の標高を返す。タイルがない・データなしの画素は ok=false
+func (d *DEMTiles) Elevation(lat, lon float64) (float64, bool) {
+	px, py := pixel(lat, lon, d.zoom)
+	key := demTileKey{x: px / demTileSize, y: py / demTileSize}
+	tile, err := d.tile(key)
+	if err != nil || tile == nil {
+		return 0, false
+	}
+	v := tile[(py%demTileSize)*demTileSize+px%demTileSize]
+	if math.IsNaN(float64(v)) {
+		return 0, false
+	}
+	return float64(v), true
+}
+
+// tile はタイルを読み込む。ファイルのないタイル (海上など) は nil として覚えておく
+func (d *DEMTiles) tile(key demTileKey) ([]float32, error) {
+	if t, ok := d.tiles.Get(key); ok {
+		return t, nil
+	}
+	path := filepath.Join(d.dir, strconv.Itoa(d.zoom), strconv.Itoa(key.x), strconv.Itoa(key.y)+".txt")
+	f, err := os.Open(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		d.tiles.Set(key, nil)
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	t, err := ParseDEMTile(f)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	d.tiles.Set(key, t)
+	return t, nil
+}
+
+// pixel は緯度経度をズーム z の全体画素座標 (ウェブメルカトル) にする
+func pixel(lat, lon float64, z int) (int, int) {
+	n := float64(int(1)<<z) * demTileSize
+	x := (lon + 180) / 360 * n
+	latRad := lat * math.Pi / 180
+	y := (1 - math.Log(math.Tan(latRad)+1/math.Cos(latRad))/math.Pi) / 2 * n
+	return int(math.Floor(x)), int(math.Floor(y))
+}
@@ -0,0 +1,72 @@
+package gsi
+
+import (
+	"math"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// demTileText は画素 (列 c, 行 r) の標高を f(c, r) とするタイルを作る (f が負ならデータなし)
+func demTileText(f func(c, r int) float64) string {
+	var b strings.Builder
+	for r := 0; r < demTileSize; r++ {
+		for c := 0; c < demTileSize; c++ {
+			if c > 0 {
+				b.WriteByte(',')
+			}
+			if v := f(c, r); v < 0 {
+				b.WriteString("e")
+			} else {
+				b.WriteString(strconv.FormatFloat(v, 'f', 2, 64))
+			}
+		}
+		b.WriteByte('\n')
+	}
+	return b.String()
+}
+
+func TestParseDEMTile(t *testing.T) {
+	values, err := ParseDEMTile(strings.NewReader(demTileText(func(c, r int) float64 { return float64(r) + 0.5 })))
+	require.NoError(t, err)
+	require.Len(t, values, demTileSize*demTileSize)
+	assert.InDelta(t, 10.5, values[10*demTileSize+3], 1e-6)
+
+	// データなし
+	values, err = ParseDEMTile(strings.NewReader(demTileText(func(c, r int) float64 { return -1 })))
+	require.NoError(t, err)
+	assert.True(t, math.IsNaN(float64(values[0])))
+
+	_, err = ParseDEMTile(strings.NewReader("1,2,3\n"))
+	assert.Error(t, err)
+	_, err = ParseDEMTile(strings.NewReader(strings.Replace(demTileText(func(c, r int) float64 { return 1 }), "1.00", "x", 1)))
+	assert.Error(t, err)
+}
+
+func TestDEMTiles_Elevation(t *testing.T) {
+	const zoom = 15
+	lat, lon := 35.6812, 139.7671
+	px, py := pixel(lat, lon, zoom)
+	tx, ty := px/demTileSize, py/demTileSize
+
+	dir := t.TempDir()
+	tileDir := filepath.Join(dir, strconv.Itoa(zoom), strconv.Itoa(tx))
+	require.NoError(t, os.MkdirAll(tileDir, 0o755))
+	// 列番号を標高にする
+	text := demTileText(func(c, r int) float64 { return float64(c) })
+	require.NoError(t, os.WriteFile(filepath.Join(tileDir, strconv.Itoa(ty)+".txt"), []byte(text), 0o644))
+
+	dem := NewDEMTiles(dir, zoom, 4)
+	v, ok := dem.Elevation(lat, lon)
+	require.True(t, ok)
+	assert.Equal(t, float64(px%demTileSize), v)
+
+	// タイルのない地点 (海上など)
+	_, ok = dem.Elevation(35.0, 141.5)
+	assert.False(t, ok)
+}
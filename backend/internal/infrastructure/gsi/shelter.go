@@ -0,0 +1,109 @@
+package gsi
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/gigaptera/hikkoshi-lens/backend/internal/domain"
+)
+
+// ShelterSource は pois.source に保存する「指定緊急避難場所データ」由来の識別子
+const ShelterSource = "gsi_shelter"
+
+// 指定緊急避難場所データ (CSV) の列名
+const (
+	shelterColumnID      = "共通ID"
+	shelterColumnName    = "施設・場所名"
+	shelterColumnAddress = "住所"
+	shelterColumnLat     = "緯度"
+	shelterColumnLon     = "経度"
+)
+
+// shelterHazards は対応する災害種別の列と tags に保存する名前 (列の並び順)
+var shelterHazards = []struct{ column, name string }{
+	{"洪水", "flood"},
+	{"崖崩れ、土石流及び地滑り", "landslide"},
+	{"高潮", "storm_surge"},
+	{"地震", "earthquake"},
+	{"津波", "tsunami"},
+	{"大規模な火事", "fire"},
+	{"内水氾濫", "inland_flood"},
+	{"火山現象", "volcano"},
+}
+
+// ReadShelterCSV は国土地理院の「指定緊急避難場所データ」(CSV、UTF-8) を避難場所のPOIとして読む
+// 対応する災害種別は tags["hazards"] にカンマ区切りで保存する (列の値が 1・○・◎ のもの)
+// 共通IDや座標のない行は数だけ返す
+func ReadShelterCSV(r io.Reader) ([]*domain.POI, int, error) {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+	header, err := cr.Read()
+	if err != nil {
+		return nil, 0, fmt.Errorf("read header: %w", err)
+	}
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		columns[strings.TrimSpace(strings.TrimPrefix(name, "\ufeff"))] = i // BOM付きのCSV
+	}
+	for _, required := range []string{shelterColumnID, shelterColumnName, shelterColumnLat, shelterColumnLon} {
+		if _, ok := columns[required]; !ok {
+			return nil, 0, fmt.Errorf("missing column %q", required)
+		}
+	}
+
+	var pois []*domain.POI
+	skipped := 0
+	for line := 2; ; line++ {
+		row, err := cr.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, 0, fmt.Errorf("line %d: %w", line, err)
+		}
+		value := func(column string) string {
+			if i, ok := columns[column]; ok && i < len(row) {
+				return strings.TrimSpace(row[i])
+			}
+			return ""
+		}
+
+		id := value(shelterColumnID)
+		lat, latErr := strconv.ParseFloat(value(shelterColumnLat), 64)
+		lon, lonErr := strconv.ParseFloat(value(shelterColumnLon), 64)
+		if id == "" || latErr != nil || lonErr != nil || lat == 0 || lon == 0 {
+			skipped++
+			continue
+		}
+
+		tags := make(map[string]string)
+		if addr := value(shelterColumnAddress); addr != "" {
+			tags["address"] = addr
+		}
+		var hazards []string
+		for _, h := range shelterHazards {
+			switch value(h.column) {
+			case "1", "○", "◎":
+				hazards = append(hazards, h.name)
+			}
+		}
+		if len(hazards) > 0 {
+			tags["hazards"] = strings.Join(hazards, ",")
+		}
+
+		pois = append(pois, &domain.POI{
+			Source:   ShelterSource,
+			SourceID: id,
+			Category: domain.POICategoryShelter,
+			Name:     value(shelterColumnName),
+			Location: fmt.Sprintf("POINT(%f %f)", lon, lat),
+			Tags:     tags,
+			Lat:      lat,
+			Lon:      lon,
+		})
+	}
+	return pois, skipped, nil
+}
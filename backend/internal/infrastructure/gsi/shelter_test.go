@@ -0,0 +1,37 @@
+package gsi
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/gigaptera/hikkoshi-lens/backend/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const shelterCSV = "\ufeff都道府県名及び市町村名,NO,共通ID,施設・場所名,住所,洪水,崖崩れ、土石流及び地滑り,高潮,地震,津波,大規模な火事,内水氾濫,火山現象,指定避難所との住所同一,緯度,経度,備考\n" +
+	"東京都渋谷区,1,131130001,代々木公園,東京都渋谷区代々木神園町,,,,1,,1,,,,35.671,139.694,\n" +
+	"東京都渋谷区,2,131130002,区立中学校,東京都渋谷区本町,1,,,1,,,1,,1,35.684,139.681,\n" +
+	"東京都渋谷区,3,,座標だけ,,,,,1,,,,,,35.6,139.6,\n" +
+	"東京都渋谷区,4,131130004,座標なし,,,,,1,,,,,,,,\n"
+
+func TestReadShelterCSV(t *testing.T) {
+	pois, skipped, err := ReadShelterCSV(strings.NewReader(shelterCSV))
+	require.NoError(t, err)
+	assert.Equal(t, 2, skipped)
+	require.Len(t, pois, 2)
+
+	p := pois[0]
+	assert.Equal(t, ShelterSource, p.Source)
+	assert.Equal(t, "131130001", p.SourceID)
+	assert.Equal(t, domain.POICategoryShelter, p.Category)
+	assert.Equal(t, "代々木公園", p.Name)
+	assert.Equal(t, "POINT(139.694000 35.671000)", p.Location)
+	assert.Equal(t, map[string]string{"address": "東京都渋谷区代々木神園町", "hazards": "earthquake,fire"}, p.Tags)
+	assert.Equal(t, "flood,earthquake,inland_flood", pois[1].Tags["hazards"])
+}
+
+func TestReadShelterCSV_MissingColumn(t *testing.T) {
+	_, _, err := ReadShelterCSV(strings.NewReader("名称,緯度,経度\n公園,35.6,139.6\n"))
+	assert.ErrorContains(t, err, "共通ID")
+}
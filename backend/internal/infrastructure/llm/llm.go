@@ -0,0 +1,112 @@
+// Package llm はHTTPのLLM APIで駅の解説文を生成する (domain.InsightGenerator の実装)
+package llm
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gigaptera/hikkoshi-lens/backend/internal/domain"
+	"github.com/gigaptera/hikkoshi-lens/backend/internal/domain/service"
+)
+
+// InsightGenerator は OpenAI 互換の Chat Completions API (POST {Endpoint}/chat/completions) を呼ぶ
+// 出力は JSON モードで受け取り、service.ParseInsightOutput で形式・文字数を検証する
+type InsightGenerator struct {
+	Endpoint string // 例: https://api.openai.com/v1
+	APIKey   string
+	Model    string
+	Client   *http.Client
+}
+
+func NewInsightGenerator(endpoint, apiKey, model string, timeout time.Duration) *InsightGenerator {
+	return &InsightGenerator{
+		Endpoint: strings.TrimSuffix(endpoint, "/"),
+		APIKey:   apiKey,
+		Model:    model,
+		Client:   &http.Client{Timeout: timeout},
+	}
+}
+
+func (g *InsightGenerator) Name() string {
+	return "llm:" + g.Model
+}
+
+type chatMessage struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+type chatRequest struct {
+	Model          string            `json:"model"`
+	Messages       []chatMessage     `json:"messages"`
+	Temperature    float64           `json:"temperature"`
+	ResponseFormat map[string]string `json:"response_format"`
+}
+
+type chatResponse struct {
+	Choices []struct {
+		Message chatMessage `json:"message"`
+	} `json:"choices"`
+	Error *struct {
+		Message string `json:"message"`
+	} `json:"error,omitempty"`
+}
+
+func (g *InsightGenerator) Generate(ctx context.Context, in *domain.InsightInput) (*domain.AIInsight, error) {
+	if !in.HasData() {
+		return nil, domain.ErrInsufficientInsightData
+	}
+	body, err := json.Marshal(chatRequest{
+		Model: g.Model,
+		Messages: []chatMessage{
+			{Role: "system", Content: service.InsightSystemPrompt},
+			{Role: "user", Content: service.BuildInsightPrompt(in)},
+		},
+		Temperature:    0.2,
+		ResponseFormat: map[string]string{"type": "json_object"},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, g.Endpoint+"/chat/completions", bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if g.APIKey != "" {
+		req.Header.Set("Authorization", "Bearer "+g.APIKey)
+	}
+
+	resp, err := g.Client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return nil, err
+	}
+	var out chatResponse
+	if err := json.Unmarshal(data, &out); err != nil {
+		return nil, fmt.Errorf("LLM responded with status %d: %w", resp.StatusCode, err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		if out.Error != nil {
+			return nil, fmt.Errorf("LLM responded with status %d: %s", resp.StatusCode, out.Error.Message)
+		}
+		return nil, fmt.Errorf("LLM responded with status %d", resp.StatusCode)
+	}
+	if len(out.Choices) == 0 {
+		return nil, errors.New("LLM returned no choices")
+	}
+	return service.ParseInsightOutput(out.Choices[0].Message.Content)
+}
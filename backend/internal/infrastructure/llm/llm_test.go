@@ -0,0 +1,91 @@
+package llm
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gigaptera/hikkoshi-lens/backend/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// stubServer は Chat Completions API の代わりに content を返すサーバー
+func stubServer(t *testing.T, status int, content string, got *chatRequest) *httptest.Server {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "/v1/chat/completions", r.URL.Path)
+		assert.Equal(t, "Bearer test-key", r.Header.Get("Authorization"))
+		if got != nil {
+			require.NoError(t, json.NewDecoder(r.Body).Decode(got))
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		if status != http.StatusOK {
+			json.NewEncoder(w).Encode(map[string]any{"error": map[string]string{"message": content}})
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]any{
+			"choices": []map[string]any{{"message": map[string]string{"role": "assistant", "content": content}}},
+		})
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func input() *domain.InsightInput {
+	return &domain.InsightInput{
+		StationID: 1,
+		Name:      "池尻大橋",
+		Lines:     []string{"田園都市線"},
+		Rents:     map[string]float64{"1r_1k_1dk": 8.0},
+	}
+}
+
+func TestInsightGenerator_Generate(t *testing.T) {
+	var req chatRequest
+	srv := stubServer(t, http.StatusOK, `{"pros": ["1R〜1DKの家賃相場が8.0万円"], "cons": [], "trend": ""}`, &req)
+	g := NewInsightGenerator(srv.URL+"/v1/", "test-key", "test-model", 5*time.Second)
+
+	insight, err := g.Generate(context.Background(), input())
+	require.NoError(t, err)
+	assert.Equal(t, []string{"1R〜1DKの家賃相場が8.0万円"}, insight.Summary.Pros)
+	assert.Equal(t, "llm:test-model", g.Name())
+
+	// 駅の実データをプロンプトに含める
+	assert.Equal(t, "test-model", req.Model)
+	assert.Equal(t, "json_object", req.ResponseFormat["type"])
+	require.Len(t, req.Messages, 2)
+	assert.Equal(t, "system", req.Messages[0].Role)
+	assert.True(t, strings.Contains(req.Messages[1].Content, "池尻大橋"))
+	assert.True(t, strings.Contains(req.Messages[1].Content, `"1r_1k_1dk": 8`))
+}
+
+func TestInsightGenerator_InvalidOutput(t *testing.T) {
+	srv := stubServer(t, http.StatusOK, `{"pros": ["a"], "resident_voices": ["作り話"]}`, nil)
+	g := NewInsightGenerator(srv.URL+"/v1", "test-key", "test-model", 5*time.Second)
+
+	_, err := g.Generate(context.Background(), input())
+	assert.ErrorIs(t, err, domain.ErrInvalidInsight)
+}
+
+func TestInsightGenerator_ErrorStatus(t *testing.T) {
+	srv := stubServer(t, http.StatusTooManyRequests, "rate limited", nil)
+	g := NewInsightGenerator(srv.URL+"/v1", "test-key", "test-model", 5*time.Second)
+
+	_, err := g.Generate(context.Background(), input())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "429")
+	assert.Contains(t, err.Error(), "rate limited")
+}
+
+func TestInsightGenerator_NoData(t *testing.T) {
+	g := NewInsightGenerator("http://127.0.0.1:0", "test-key", "test-model", time.Second)
+	_, err := g.Generate(context.Background(), &domain.InsightInput{StationID: 1, Name: "駅"})
+	assert.ErrorIs(t, err, domain.ErrInsufficientInsightData)
+}
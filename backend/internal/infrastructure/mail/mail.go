@@ -0,0 +1,66 @@
+package mail
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"log"
+	"mime"
+	"net"
+	"net/smtp"
+	"time"
+
+	"github.com/gigaptera/hikkoshi-lens/backend/internal/domain"
+)
+
+// NewSender は設定に応じた送信手段を返す
+// kind が "smtp" 以外 (未設定を含む) の場合は送信せずログに出す開発用の LogSender
+func NewSender(kind, addr, username, password, from string) domain.MailSender {
+	if kind == "smtp" {
+		return &SMTPSender{Addr: addr, Username: username, Password: password, From: from}
+	}
+	return &LogSender{}
+}
+
+// LogSender はメールを送らずに内容をログに出す (ローカル開発・テスト用)
+type LogSender struct{}
+
+func (s *LogSender) Send(ctx context.Context, msg domain.MailMessage) error {
+	log.Printf("[mail] to=%s subject=%q\n%s", msg.To, msg.Subject, msg.Body)
+	return nil
+}
+
+// SMTPSender はSMTPサーバー経由で送信する (STARTTLSはサーバーが対応していれば使われる)
+type SMTPSender struct {
+	Addr     string // host:port
+	Username string // 空なら認証しない
+	Password string
+	From     string
+}
+
+func (s *SMTPSender) Send(ctx context.Context, msg domain.MailMessage) error {
+	var auth smtp.Auth
+	if s.Username != "" {
+		host, _, err := net.SplitHostPort(s.Addr)
+		if err != nil {
+			return fmt.Errorf("invalid SMTP address %q: %w", s.Addr, err)
+		}
+		auth = smtp.PlainAuth("", s.Username, s.Password, host)
+	}
+	return smtp.SendMail(s.Addr, auth, s.From, []string{msg.To}, buildMessage(s.From, msg, time.Now()))
+}
+
+// buildMessage はUTF-8のプレーンテキストメールを組み立てる
+func buildMessage(from string, msg domain.MailMessage, now time.Time) []byte {
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "From: %s\r\n", from)
+	fmt.Fprintf(&b, "To: %s\r\n", msg.To)
+	fmt.Fprintf(&b, "Subject: %s\r\n", mime.BEncoding.Encode("UTF-8", msg.Subject))
+	fmt.Fprintf(&b, "Date: %s\r\n", now.Format(time.RFC1123Z))
+	b.WriteString("MIME-Version: 1.0\r\n")
+	b.WriteString("Content-Type: text/plain; charset=UTF-8\r\n")
+	b.WriteString("Content-Transfer-Encoding: 8bit\r\n")
+	b.WriteString("\r\n")
+	b.WriteString(msg.Body)
+	return b.Bytes()
+}
@@ -0,0 +1,29 @@
+package mail
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gigaptera/hikkoshi-lens/backend/internal/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewSender(t *testing.T) {
+	assert.IsType(t, &LogSender{}, NewSender("", "", "", "", ""))
+	assert.IsType(t, &SMTPSender{}, NewSender("smtp", "localhost:25", "", "", "noreply@example.com"))
+}
+
+func TestBuildMessage(t *testing.T) {
+	now := time.Date(2026, 10, 19, 9, 0, 0, 0, time.UTC)
+	msg := string(buildMessage("noreply@example.com", domain.MailMessage{
+		To:      "user@example.com",
+		Subject: "ログインリンク",
+		Body:    "こちらからログインしてください",
+	}, now))
+
+	assert.Contains(t, msg, "To: user@example.com\r\n")
+	assert.Contains(t, msg, "Subject: =?UTF-8?b?")
+	assert.Contains(t, msg, "Content-Type: text/plain; charset=UTF-8\r\n")
+	assert.True(t, strings.HasSuffix(msg, "\r\n\r\nこちらからログインしてください"))
+}
@@ -0,0 +1,82 @@
+package migrate
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	"github.com/gigaptera/hikkoshi-lens/backend/internal/domain"
+	"github.com/uptrace/bun"
+)
+
+// Models はマイグレーション後のスキーマと一致しているべきBunモデルの一覧
+// テーブルを持つモデルを追加したらここにも登録する
+var Models = []interface{}{
+	(*domain.Station)(nil),
+	(*domain.Line)(nil),
+	(*domain.MarketPrice)(nil),
+	(*domain.StationScore)(nil),
+	(*domain.POI)(nil),
+	(*domain.Facility)(nil),
+	(*domain.GridCell)(nil),
+	(*domain.GridCellMetric)(nil),
+	(*domain.StationExternalID)(nil),
+	(*domain.AffiliateClick)(nil),
+	(*domain.User)(nil),
+	(*domain.LoginToken)(nil),
+	(*domain.Session)(nil),
+	(*domain.Favorite)(nil),
+	(*domain.Shortlist)(nil),
+	(*domain.ShortlistItem)(nil),
+	(*domain.SavedSearch)(nil),
+	(*domain.SavedSearchResult)(nil),
+	(*domain.SearchPermalink)(nil),
+	(*domain.StationTag)(nil),
+	(*domain.StationInsight)(nil),
+	(*domain.Review)(nil),
+	(*domain.FareBand)(nil),
+	(*domain.StationPassengers)(nil),
+	(*domain.StationTerrain)(nil),
+	(*domain.StationDisasterAccess)(nil),
+	(*domain.StationNightSafety)(nil),
+}
+
+// CheckModels はBunモデルのテーブル・カラムがDBに存在するかを確認し、
+// 不一致の内容を返す（一致していれば空）
+// seederはテーブルを作らずデータ投入だけを行うため、事前にこのチェックでマイグレーション漏れを検出する
+func CheckModels(ctx context.Context, db *bun.DB, models ...interface{}) ([]string, error) {
+	var problems []string
+	for _, model := range models {
+		typ := reflect.TypeOf(model)
+		for typ.Kind() == reflect.Ptr {
+			typ = typ.Elem()
+		}
+		table := db.Table(typ)
+
+		var columns []string
+		err := db.NewRaw(`
+			SELECT column_name
+			FROM information_schema.columns
+			WHERE table_schema = current_schema() AND table_name = ?`, table.Name).
+			Scan(ctx, &columns)
+		if err != nil {
+			return nil, err
+		}
+		if len(columns) == 0 {
+			problems = append(problems, fmt.Sprintf("%s: table %q does not exist", typ.Name(), table.Name))
+			continue
+		}
+
+		existing := make(map[string]bool, len(columns))
+		for _, c := range columns {
+			existing[c] = true
+		}
+		// scanonly・リレーションのフィールドは table.Fields に含まれない
+		for _, f := range table.Fields {
+			if !existing[f.Name] {
+				problems = append(problems, fmt.Sprintf("%s.%s: column %s.%s does not exist", typ.Name(), f.GoName, table.Name, f.Name))
+			}
+		}
+	}
+	return problems, nil
+}
@@ -0,0 +1,153 @@
+package migrate
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io/fs"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Migration は1ファイル分のマイグレーション
+type Migration struct {
+	Version       int64  // ファイル名先頭のタイムスタンプ (例: 20231231000000)
+	Name          string // ファイル名 (拡張子なし)
+	Up            []string
+	Down          []string
+	NoTransaction bool // -- +goose NO TRANSACTION (CREATE INDEX CONCURRENTLY など)
+}
+
+var fileNamePattern = regexp.MustCompile(`^(\d+)_([a-z0-9_]+)\.sql$`)
+
+// Load はfsys直下の *.sql を読み込み、バージョン順に並べて返す
+// goose のアノテーションがないファイルやバージョン重複はエラーにする
+func Load(fsys fs.FS) ([]*Migration, error) {
+	entries, err := fs.ReadDir(fsys, ".")
+	if err != nil {
+		return nil, err
+	}
+
+	var migrations []*Migration
+	seen := make(map[int64]string)
+	for _, e := range entries {
+		if e.IsDir() || path.Ext(e.Name()) != ".sql" {
+			continue
+		}
+		m := fileNamePattern.FindStringSubmatch(e.Name())
+		if m == nil {
+			return nil, fmt.Errorf("invalid migration file name %q (expected <version>_<name>.sql)", e.Name())
+		}
+		version, err := strconv.ParseInt(m[1], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid migration version in %q: %w", e.Name(), err)
+		}
+		if prev, ok := seen[version]; ok {
+			return nil, fmt.Errorf("duplicate migration version %d: %s, %s", version, prev, e.Name())
+		}
+		seen[version] = e.Name()
+
+		content, err := fs.ReadFile(fsys, e.Name())
+		if err != nil {
+			return nil, err
+		}
+		migration, err := Parse(content)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", e.Name(), err)
+		}
+		migration.Version = version
+		migration.Name = strings.TrimSuffix(e.Name(), ".sql")
+		migrations = append(migrations, migration)
+	}
+
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].Version < migrations[j].Version
+	})
+	return migrations, nil
+}
+
+// Parse は goose 形式のSQLをUp/Downの文に分割する
+// StatementBegin/End の間は1文として扱い、それ以外は行末の ; で区切る
+func Parse(content []byte) (*Migration, error) {
+	m := &Migration{}
+
+	var section *[]string
+	var buf strings.Builder
+	inBlock := false
+	hasUp := false
+
+	flush := func() {
+		stmt := strings.TrimSpace(buf.String())
+		buf.Reset()
+		if section != nil && !isCommentOnly(stmt) {
+			*section = append(*section, stmt)
+		}
+	}
+
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := scanner.Text()
+		trimmed := strings.TrimSpace(line)
+
+		if strings.HasPrefix(trimmed, "-- +goose") {
+			switch strings.TrimSpace(strings.TrimPrefix(trimmed, "-- +goose")) {
+			case "Up":
+				flush()
+				section, hasUp = &m.Up, true
+			case "Down":
+				flush()
+				section = &m.Down
+			case "StatementBegin":
+				flush()
+				inBlock = true
+			case "StatementEnd":
+				flush()
+				inBlock = false
+			case "NO TRANSACTION":
+				m.NoTransaction = true
+			default:
+				return nil, fmt.Errorf("unknown annotation %q", trimmed)
+			}
+			continue
+		}
+
+		if section == nil {
+			if trimmed != "" && !strings.HasPrefix(trimmed, "--") {
+				return nil, fmt.Errorf("statement outside of -- +goose Up/Down section")
+			}
+			continue
+		}
+
+		buf.WriteString(line)
+		buf.WriteString("\n")
+		if !inBlock && strings.HasSuffix(trimmed, ";") && !strings.HasPrefix(trimmed, "--") {
+			flush()
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if inBlock {
+		return nil, fmt.Errorf("missing -- +goose StatementEnd")
+	}
+	flush()
+
+	if !hasUp {
+		return nil, fmt.Errorf("missing -- +goose Up annotation")
+	}
+	return m, nil
+}
+
+func isCommentOnly(stmt string) bool {
+	for _, line := range strings.Split(stmt, "\n") {
+		line = strings.TrimSpace(line)
+		if line != "" && !strings.HasPrefix(line, "--") {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,97 @@
+package migrate
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/gigaptera/hikkoshi-lens/backend/migrations"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestLoad_EmbeddedMigrations は埋め込みのマイグレーションがすべて読み込めることを確認する
+func TestLoad_EmbeddedMigrations(t *testing.T) {
+	loaded, err := Load(migrations.FS)
+	require.NoError(t, err)
+	require.NotEmpty(t, loaded)
+
+	for i, m := range loaded {
+		assert.NotEmpty(t, m.Up, "%s has no Up statements", m.Name)
+		assert.NotEmpty(t, m.Down, "%s has no Down statements", m.Name)
+		if i > 0 {
+			assert.Greater(t, m.Version, loaded[i-1].Version)
+		}
+	}
+}
+
+// TestParse_SplitsStatements はStatementBegin/Endの内外での文の区切り方を確認する
+func TestParse_SplitsStatements(t *testing.T) {
+	m, err := Parse([]byte(`-- +goose Up
+CREATE TABLE a (id INT);
+-- comment only
+CREATE TABLE b (
+    id INT
+);
+
+-- +goose StatementBegin
+CREATE FUNCTION f() RETURNS INT AS $$
+BEGIN
+    RETURN 1;
+END;
+$$ LANGUAGE plpgsql;
+-- +goose StatementEnd
+
+-- +goose Down
+DROP TABLE b;
+DROP TABLE a;
+`))
+	require.NoError(t, err)
+
+	require.Len(t, m.Up, 3)
+	assert.True(t, strings.HasPrefix(m.Up[0], "CREATE TABLE a"))
+	assert.Contains(t, m.Up[1], "CREATE TABLE b")
+	assert.Contains(t, m.Up[2], "RETURN 1;")
+	assert.Contains(t, m.Up[2], "LANGUAGE plpgsql;")
+	assert.Equal(t, []string{"DROP TABLE b;", "DROP TABLE a;"}, m.Down)
+	assert.False(t, m.NoTransaction)
+}
+
+// TestParse_RequiresAnnotations はgooseアノテーションのないファイルをエラーにすることを確認する
+func TestParse_RequiresAnnotations(t *testing.T) {
+	_, err := Parse([]byte("DROP TABLE IF EXISTS market_prices;\n"))
+	assert.Error(t, err)
+
+	_, err = Parse([]byte("-- +goose Up\n-- +goose StatementBegin\nSELECT 1;\n"))
+	assert.Error(t, err)
+
+	m, err := Parse([]byte("-- +goose NO TRANSACTION\n-- +goose Up\nCREATE INDEX CONCURRENTLY i ON t (c);\n"))
+	require.NoError(t, err)
+	assert.True(t, m.NoTransaction)
+}
+
+// TestLoad_RejectsDuplicateVersions はバージョン重複をエラーにすることを確認する
+func TestLoad_RejectsDuplicateVersions(t *testing.T) {
+	fsys := fstest.MapFS{
+		"20240101000000_a.sql": {Data: []byte("-- +goose Up\nSELECT 1;\n")},
+		"20240101000000_b.sql": {Data: []byte("-- +goose Up\nSELECT 2;\n")},
+	}
+	_, err := Load(fsys)
+	assert.Error(t, err)
+}
+
+// TestCreate はテンプレートから読み込み可能なファイルが作られることを確認する
+func TestCreate(t *testing.T) {
+	dir := t.TempDir()
+	path, err := Create(dir, "Add Station Tags", time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC))
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(dir, "20260102030405_add_station_tags.sql"), path)
+
+	loaded, err := Load(os.DirFS(dir))
+	require.NoError(t, err)
+	require.Len(t, loaded, 1)
+	assert.Equal(t, int64(20260102030405), loaded[0].Version)
+}
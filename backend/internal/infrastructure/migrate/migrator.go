@@ -0,0 +1,211 @@
+package migrate
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/uptrace/bun"
+)
+
+// VersionTable は適用済みマイグレーションを記録するテーブル
+const VersionTable = "schema_migrations"
+
+// Status はマイグレーション1件の適用状況
+type Status struct {
+	Migration *Migration
+	AppliedAt *time.Time // 未適用なら nil
+}
+
+type Migrator struct {
+	db         *bun.DB
+	migrations []*Migration
+}
+
+func New(db *bun.DB, migrations []*Migration) *Migrator {
+	return &Migrator{db: db, migrations: migrations}
+}
+
+// Init はバージョン管理テーブルを作成する
+func (m *Migrator) Init(ctx context.Context) error {
+	_, err := m.db.ExecContext(ctx, `
+		CREATE TABLE IF NOT EXISTS `+VersionTable+` (
+			version BIGINT PRIMARY KEY,
+			name TEXT NOT NULL,
+			applied_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+		)`)
+	return err
+}
+
+func (m *Migrator) applied(ctx context.Context) (map[int64]time.Time, error) {
+	var rows []struct {
+		Version   int64     `bun:"version"`
+		AppliedAt time.Time `bun:"applied_at"`
+	}
+	if err := m.db.NewRaw("SELECT version, applied_at FROM "+VersionTable).Scan(ctx, &rows); err != nil {
+		return nil, err
+	}
+	applied := make(map[int64]time.Time, len(rows))
+	for _, r := range rows {
+		applied[r.Version] = r.AppliedAt
+	}
+	return applied, nil
+}
+
+// Status は全マイグレーションの適用状況をバージョン順に返す
+func (m *Migrator) Status(ctx context.Context) ([]Status, error) {
+	if err := m.Init(ctx); err != nil {
+		return nil, err
+	}
+	applied, err := m.applied(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	statuses := make([]Status, len(m.migrations))
+	for i, mig := range m.migrations {
+		statuses[i] = Status{Migration: mig}
+		if at, ok := applied[mig.Version]; ok {
+			at := at
+			statuses[i].AppliedAt = &at
+		}
+	}
+	return statuses, nil
+}
+
+// Up は未適用のマイグレーションをバージョン順にすべて適用し、適用したものを返す
+// 各マイグレーションは記録の挿入と同じトランザクションで実行するため、失敗時は何も残らない
+func (m *Migrator) Up(ctx context.Context) ([]*Migration, error) {
+	statuses, err := m.Status(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var done []*Migration
+	for _, st := range statuses {
+		if st.AppliedAt != nil {
+			continue
+		}
+		mig := st.Migration
+		err := m.run(ctx, mig, mig.Up, func(db bun.IDB) error {
+			_, err := db.NewRaw("INSERT INTO "+VersionTable+" (version, name) VALUES (?, ?)", mig.Version, mig.Name).Exec(ctx)
+			return err
+		})
+		if err != nil {
+			return done, fmt.Errorf("migration %s failed: %w", mig.Name, err)
+		}
+		done = append(done, mig)
+	}
+	return done, nil
+}
+
+// Down は最後に適用したマイグレーションを1件だけ取り消す
+func (m *Migrator) Down(ctx context.Context) (*Migration, error) {
+	statuses, err := m.Status(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := len(statuses) - 1; i >= 0; i-- {
+		if statuses[i].AppliedAt == nil {
+			continue
+		}
+		mig := statuses[i].Migration
+		if len(mig.Down) == 0 {
+			return nil, fmt.Errorf("migration %s has no -- +goose Down section", mig.Name)
+		}
+		err := m.run(ctx, mig, mig.Down, func(db bun.IDB) error {
+			_, err := db.NewRaw("DELETE FROM "+VersionTable+" WHERE version = ?", mig.Version).Exec(ctx)
+			return err
+		})
+		if err != nil {
+			return nil, fmt.Errorf("rollback %s failed: %w", mig.Name, err)
+		}
+		return mig, nil
+	}
+	return nil, sql.ErrNoRows
+}
+
+// Baseline はversion以下のマイグレーションを実行せずに適用済みとして記録する
+// マイグレーション導入前にseederで作られた既存DBを管理下に置くために使う
+func (m *Migrator) Baseline(ctx context.Context, version int64) ([]*Migration, error) {
+	statuses, err := m.Status(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var marked []*Migration
+	for _, st := range statuses {
+		if st.AppliedAt != nil || st.Migration.Version > version {
+			continue
+		}
+		_, err := m.db.NewRaw("INSERT INTO "+VersionTable+" (version, name) VALUES (?, ?)",
+			st.Migration.Version, st.Migration.Name).Exec(ctx)
+		if err != nil {
+			return marked, err
+		}
+		marked = append(marked, st.Migration)
+	}
+	return marked, nil
+}
+
+// run はマイグレーションの文を実行し、record で適用記録を更新する
+// SQLはBunのクエリフォーマッタを通さず (? をプレースホルダとして解釈させず) そのまま実行する
+func (m *Migrator) run(ctx context.Context, mig *Migration, statements []string, record func(db bun.IDB) error) error {
+	if mig.NoTransaction {
+		for _, stmt := range statements {
+			if _, err := m.db.DB.ExecContext(ctx, stmt); err != nil {
+				return err
+			}
+		}
+		return record(m.db)
+	}
+
+	return m.db.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		for _, stmt := range statements {
+			if _, err := tx.Tx.ExecContext(ctx, stmt); err != nil {
+				return err
+			}
+		}
+		return record(tx)
+	})
+}
+
+var createNamePattern = regexp.MustCompile(`[^a-z0-9]+`)
+
+const migrationTemplate = `-- +goose Up
+-- +goose StatementBegin
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+-- +goose StatementEnd
+`
+
+// Create はdirに空のマイグレーションファイルを作成し、そのパスを返す
+func Create(dir, name string, now time.Time) (string, error) {
+	slug := strings.Trim(createNamePattern.ReplaceAllString(strings.ToLower(name), "_"), "_")
+	if slug == "" {
+		return "", errors.New("migration name is required")
+	}
+
+	path := filepath.Join(dir, fmt.Sprintf("%s_%s.sql", now.UTC().Format("20060102150405"), slug))
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o644)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	if _, err := f.WriteString(migrationTemplate); err != nil {
+		return "", err
+	}
+	return path, nil
+}
@@ -0,0 +1,100 @@
+package mlit
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/gigaptera/hikkoshi-lens/backend/internal/domain"
+)
+
+// HospitalSource は pois.source に保存する国土数値情報「医療機関」(P04) 由来の識別子
+const HospitalSource = "mlit_p04"
+
+// P04 (医療機関) の属性名
+const (
+	p04Name    = "P04_002"
+	p04Address = "P04_003"
+)
+
+// P04Layout は救急医療の指定を表す属性の対応 (配布年版で列の番号が異なる)
+type P04Layout struct {
+	EmergencyColumn    string // 救急告示病院
+	DisasterBaseColumn string // 災害拠点病院
+}
+
+// DefaultP04Layout は P04-20 (2020年版) の対応
+func DefaultP04Layout() P04Layout {
+	return P04Layout{EmergencyColumn: "P04_009", DisasterBaseColumn: "P04_010"}
+}
+
+// ReadP04GeoJSON はGeoJSON (FeatureCollection) から救急告示病院・災害拠点病院を救急病院のPOIとして読む
+// 区分コードが 1 (救急告示・基幹災害拠点) か 2 (地域災害拠点) のものを指定ありとし、
+// 指定のない医療機関・座標のない地物は数だけ返す
+// P04 には施設のIDがないため、名称と所在地のハッシュを source_id にする
+func ReadP04GeoJSON(r io.Reader, layout P04Layout) ([]*domain.POI, int, error) {
+	var fc struct {
+		Features []struct {
+			Properties map[string]interface{} `json:"properties"`
+			Geometry   *struct {
+				Type        string    `json:"type"`
+				Coordinates []float64 `json:"coordinates"`
+			} `json:"geometry"`
+		} `json:"features"`
+	}
+	dec := json.NewDecoder(r)
+	dec.UseNumber()
+	if err := dec.Decode(&fc); err != nil {
+		return nil, 0, fmt.Errorf("decode geojson: %w", err)
+	}
+
+	var pois []*domain.POI
+	skipped := 0
+	for _, f := range fc.Features {
+		props := make(map[string]string, len(f.Properties))
+		for k, v := range f.Properties {
+			if v != nil {
+				props[k] = strings.TrimSpace(fmt.Sprint(v))
+			}
+		}
+		emergency := p04Designated(props[layout.EmergencyColumn])
+		disasterBase := p04Designated(props[layout.DisasterBaseColumn])
+		g := f.Geometry
+		if (!emergency && !disasterBase) || g == nil || g.Type != "Point" || len(g.Coordinates) < 2 {
+			skipped++
+			continue
+		}
+
+		name, address := props[p04Name], props[p04Address]
+		sum := sha1.Sum([]byte(name + "|" + address))
+		tags := map[string]string{}
+		if address != "" {
+			tags["address"] = address
+		}
+		if emergency {
+			tags["emergency"] = "yes"
+		}
+		if disasterBase {
+			tags["disaster_base"] = "yes"
+		}
+		lon, lat := g.Coordinates[0], g.Coordinates[1]
+		pois = append(pois, &domain.POI{
+			Source:   HospitalSource,
+			SourceID: hex.EncodeToString(sum[:8]),
+			Category: domain.POICategoryEmergencyHospital,
+			Name:     name,
+			Location: fmt.Sprintf("POINT(%f %f)", lon, lat),
+			Tags:     tags,
+			Lat:      lat,
+			Lon:      lon,
+		})
+	}
+	return pois, skipped, nil
+}
+
+func p04Designated(code string) bool {
+	return code == "1" || code == "2"
+}
@@ -0,0 +1,43 @@
+package mlit
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/gigaptera/hikkoshi-lens/backend/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const p04GeoJSON = `{"type": "FeatureCollection", "features": [
+  {"type": "Feature", "geometry": {"type": "Point", "coordinates": [139.70, 35.69]},
+    "properties": {"P04_001": 1, "P04_002": "中央病院", "P04_003": "東京都新宿区1-1", "P04_009": 1, "P04_010": 1}},
+  {"type": "Feature", "geometry": {"type": "Point", "coordinates": [139.71, 35.68]},
+    "properties": {"P04_001": 1, "P04_002": "地域病院", "P04_003": "東京都新宿区2-2", "P04_009": 9, "P04_010": 2}},
+  {"type": "Feature", "geometry": {"type": "Point", "coordinates": [139.72, 35.67]},
+    "properties": {"P04_001": 2, "P04_002": "診療所", "P04_003": "東京都新宿区3-3", "P04_009": 9, "P04_010": 9}},
+  {"type": "Feature", "geometry": null,
+    "properties": {"P04_001": 1, "P04_002": "座標なし", "P04_009": 1}}
+]}`
+
+func TestReadP04GeoJSON(t *testing.T) {
+	pois, skipped, err := ReadP04GeoJSON(strings.NewReader(p04GeoJSON), DefaultP04Layout())
+	require.NoError(t, err)
+	assert.Equal(t, 2, skipped)
+	require.Len(t, pois, 2)
+
+	p := pois[0]
+	assert.Equal(t, HospitalSource, p.Source)
+	assert.Equal(t, domain.POICategoryEmergencyHospital, p.Category)
+	assert.Equal(t, "中央病院", p.Name)
+	assert.Equal(t, "POINT(139.700000 35.690000)", p.Location)
+	assert.Equal(t, map[string]string{"address": "東京都新宿区1-1", "emergency": "yes", "disaster_base": "yes"}, p.Tags)
+	assert.Len(t, p.SourceID, 16)
+	assert.Equal(t, map[string]string{"address": "東京都新宿区2-2", "disaster_base": "yes"}, pois[1].Tags)
+
+	// 同じ名称・所在地なら同じ source_id (再取り込みで更新される)
+	again, _, err := ReadP04GeoJSON(strings.NewReader(p04GeoJSON), DefaultP04Layout())
+	require.NoError(t, err)
+	assert.Equal(t, p.SourceID, again[0].SourceID)
+	assert.NotEqual(t, p.SourceID, pois[1].SourceID)
+}
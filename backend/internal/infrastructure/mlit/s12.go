@@ -0,0 +1,156 @@
+// Package mlit は国土数値情報 (国土交通省) の配布データを読み込む
+package mlit
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// S12 (駅別乗降客数) の属性名
+const (
+	s12StationName = "S12_001"
+	s12StationCode = "S12_001c" // N02 (鉄道) の駅コードと同じ体系で、stations.station_code に対応する
+	s12Operator    = "S12_002"
+	s12Line        = "S12_003"
+	// s12FirstYearColumn は最初の年度の1列目 (重複コード) の番号
+	// 以降は年度ごとに 重複コード, データ有無コード, 備考, 乗降客数 の4列が続く
+	s12FirstYearColumn = 6
+	s12ColumnsPerYear  = 4
+	// s12HasData はデータ有無コードの「データあり」
+	s12HasData = "1"
+)
+
+// S12Layout は年度の列の対応
+type S12Layout struct {
+	FirstYear int // S12_006〜S12_009 の年度 (配布年版で異なる)
+	Year      int // 取り込む年度。0 はデータのある最新の年度
+}
+
+// DefaultS12Layout は2011年度から収録している版 (S12-22 以降) の対応
+func DefaultS12Layout() S12Layout {
+	return S12Layout{FirstYear: 2011}
+}
+
+// PassengerRecord は1路線・1駅の1日あたりの乗降客数
+type PassengerRecord struct {
+	StationCode string
+	StationName string
+	Operator    string
+	Line        string
+	Year        int
+	Passengers  int
+}
+
+// ReadS12GeoJSON はGeoJSON (FeatureCollection) の properties から乗降客数を読む
+// 駅コードがない・対象の年度にデータがない地物は数だけ返す
+func ReadS12GeoJSON(r io.Reader, layout S12Layout) ([]PassengerRecord, int, error) {
+	var fc struct {
+		Features []struct {
+			Properties map[string]interface{} `json:"properties"`
+		} `json:"features"`
+	}
+	dec := json.NewDecoder(r)
+	dec.UseNumber()
+	if err := dec.Decode(&fc); err != nil {
+		return nil, 0, fmt.Errorf("decode geojson: %w", err)
+	}
+
+	var records []PassengerRecord
+	skipped := 0
+	for _, f := range fc.Features {
+		props := make(map[string]string, len(f.Properties))
+		for k, v := range f.Properties {
+			if v != nil {
+				props[k] = fmt.Sprint(v)
+			}
+		}
+		rec, ok := layout.parse(props)
+		if !ok {
+			skipped++
+			continue
+		}
+		records = append(records, rec)
+	}
+	return records, skipped, nil
+}
+
+// ReadS12CSV はシェープファイルの属性をそのまま書き出したCSV (1行目が S12_001 などの属性名) を読む
+func ReadS12CSV(r io.Reader, layout S12Layout) ([]PassengerRecord, int, error) {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+	header, err := cr.Read()
+	if err != nil {
+		return nil, 0, fmt.Errorf("read header: %w", err)
+	}
+	if len(header) > 0 {
+		header[0] = strings.TrimPrefix(header[0], "\ufeff") // BOM付きのCSV
+	}
+
+	var records []PassengerRecord
+	skipped := 0
+	for line := 2; ; line++ {
+		row, err := cr.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, 0, fmt.Errorf("line %d: %w", line, err)
+		}
+		props := make(map[string]string, len(header))
+		for i, name := range header {
+			if i < len(row) {
+				props[strings.TrimSpace(name)] = row[i]
+			}
+		}
+		rec, ok := layout.parse(props)
+		if !ok {
+			skipped++
+			continue
+		}
+		records = append(records, rec)
+	}
+	return records, skipped, nil
+}
+
+// parse は1地物の属性から対象年度の乗降客数を取り出す
+// 年度の指定がない場合は、データ有無コードが「あり」で乗降客数が正の最新の年度を使う
+func (l S12Layout) parse(props map[string]string) (PassengerRecord, bool) {
+	rec := PassengerRecord{
+		StationCode: strings.TrimSpace(props[s12StationCode]),
+		StationName: strings.TrimSpace(props[s12StationName]),
+		Operator:    strings.TrimSpace(props[s12Operator]),
+		Line:        strings.TrimSpace(props[s12Line]),
+	}
+	if rec.StationCode == "" {
+		return rec, false
+	}
+
+	for i := 0; ; i++ {
+		col := s12FirstYearColumn + i*s12ColumnsPerYear
+		passengersCol := s12Column(col + 3)
+		if _, ok := props[passengersCol]; !ok {
+			break
+		}
+		year := l.FirstYear + i
+		if l.Year != 0 && year != l.Year {
+			continue
+		}
+		if strings.TrimSpace(props[s12Column(col+1)]) != s12HasData {
+			continue
+		}
+		n, err := strconv.Atoi(strings.TrimSpace(props[passengersCol]))
+		if err != nil || n <= 0 {
+			continue
+		}
+		rec.Year, rec.Passengers = year, n
+	}
+	return rec, rec.Passengers > 0
+}
+
+func s12Column(n int) string {
+	return fmt.Sprintf("S12_%03d", n)
+}
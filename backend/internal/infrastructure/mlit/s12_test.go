@@ -0,0 +1,49 @@
+package mlit
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// 2011〜2013年度の3年度分 (S12_006〜S12_017) を持つ地物
+const s12GeoJSON = `{"type": "FeatureCollection", "features": [
+  {"type": "Feature", "properties": {"S12_001": "新宿", "S12_001c": "003700", "S12_002": "東日本旅客鉄道", "S12_003": "山手線",
+    "S12_006": 1, "S12_007": 1, "S12_008": null, "S12_009": 1500000,
+    "S12_010": 1, "S12_011": 1, "S12_012": null, "S12_013": 1520000,
+    "S12_014": 1, "S12_015": 2, "S12_016": null, "S12_017": 0}},
+  {"type": "Feature", "properties": {"S12_001": "新駅", "S12_001c": "009999",
+    "S12_006": 1, "S12_007": 2, "S12_008": null, "S12_009": 0}},
+  {"type": "Feature", "properties": {"S12_001": "コードなし", "S12_006": 1, "S12_007": 1, "S12_008": null, "S12_009": 100}}
+]}`
+
+func TestReadS12GeoJSON_LatestYear(t *testing.T) {
+	records, skipped, err := ReadS12GeoJSON(strings.NewReader(s12GeoJSON), DefaultS12Layout())
+	require.NoError(t, err)
+	assert.Equal(t, 2, skipped) // データなし・駅コードなし
+	require.Len(t, records, 1)
+	// 2013年度はデータ有無コードが「なし」なので2012年度を使う
+	assert.Equal(t, PassengerRecord{StationCode: "003700", StationName: "新宿", Operator: "東日本旅客鉄道", Line: "山手線", Year: 2012, Passengers: 1520000}, records[0])
+}
+
+func TestReadS12GeoJSON_FixedYear(t *testing.T) {
+	records, _, err := ReadS12GeoJSON(strings.NewReader(s12GeoJSON), S12Layout{FirstYear: 2011, Year: 2011})
+	require.NoError(t, err)
+	require.Len(t, records, 1)
+	assert.Equal(t, 1500000, records[0].Passengers)
+}
+
+func TestReadS12CSV(t *testing.T) {
+	data := "\ufeffS12_001,S12_001c,S12_002,S12_003,S12_006,S12_007,S12_008,S12_009\n" +
+		"赤嶺,100101,沖縄都市モノレール,沖縄都市モノレール線,1,1,,4500\n" +
+		"不明,100102,沖縄都市モノレール,沖縄都市モノレール線,1,1,,abc\n"
+	records, skipped, err := ReadS12CSV(strings.NewReader(data), DefaultS12Layout())
+	require.NoError(t, err)
+	assert.Equal(t, 1, skipped)
+	require.Len(t, records, 1)
+	assert.Equal(t, "100101", records[0].StationCode)
+	assert.Equal(t, 2011, records[0].Year)
+	assert.Equal(t, 4500, records[0].Passengers)
+}
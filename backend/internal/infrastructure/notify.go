@@ -0,0 +1,46 @@
+package infrastructure
+
+import (
+	"context"
+	"log"
+
+	"github.com/uptrace/bun"
+	"github.com/uptrace/bun/driver/pgdriver"
+)
+
+// DataUpdatedChannel はインポーター・バッチがデータ更新を通知するNOTIFYチャンネル
+// ペイロードには更新したテーブル名 (market_prices, station_scores など) を入れる
+const DataUpdatedChannel = "hikkoshi_data_updated"
+
+// NotifyDataUpdated はAPIサーバーなどの購読者にテーブル更新を通知する
+func NotifyDataUpdated(ctx context.Context, db *bun.DB, table string) error {
+	return pgdriver.Notify(ctx, db, DataUpdatedChannel, table)
+}
+
+// ListenDataUpdated はデータ更新通知を購読し、受信するたびに onUpdate を呼ぶ
+// 購読はctxがキャンセルされるまでバックグラウンドで続く
+func ListenDataUpdated(ctx context.Context, db *bun.DB, onUpdate func(table string)) error {
+	ln := pgdriver.NewListener(db)
+	if err := ln.Listen(ctx, DataUpdatedChannel); err != nil {
+		ln.Close()
+		return err
+	}
+
+	go func() {
+		defer ln.Close()
+		ch := ln.Channel()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case n, ok := <-ch:
+				if !ok {
+					return
+				}
+				log.Printf("Data updated: %s", n.Payload)
+				onUpdate(n.Payload)
+			}
+		}
+	}()
+	return nil
+}
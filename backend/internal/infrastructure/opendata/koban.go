@@ -0,0 +1,113 @@
+// Package opendata は自治体・都道府県警察が公開するオープンデータ (推奨データセット形式の CSV など) を読む
+package opendata
+
+import (
+	"crypto/sha1"
+	"encoding/csv"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/gigaptera/hikkoshi-lens/backend/internal/domain"
+)
+
+// KobanSource は pois.source に保存する交番・駐在所一覧 (オープンデータ) 由来の識別子
+const KobanSource = "koban_csv"
+
+// KobanLayout は交番・駐在所一覧の CSV の列名 (公開元によって異なる)
+// IDColumn が空またはIDが空の行は、名称と住所のハッシュを source_id にする
+type KobanLayout struct {
+	IDColumn      string
+	NameColumn    string
+	AddressColumn string
+	LatColumn     string
+	LonColumn     string
+}
+
+// DefaultKobanLayout は自治体標準オープンデータセットに近い列名
+func DefaultKobanLayout() KobanLayout {
+	return KobanLayout{IDColumn: "ID", NameColumn: "名称", AddressColumn: "住所", LatColumn: "緯度", LonColumn: "経度"}
+}
+
+// kobanKinds は名称に含まれる語と tags["kind"] に保存する種別 (先に一致したもの)
+var kobanKinds = []struct{ word, kind string }{
+	{"警察署", "station"},
+	{"駐在所", "chuzaisho"},
+	{"交番", "koban"},
+}
+
+// ReadKobanCSV は交番・駐在所・警察署の一覧 (CSV、UTF-8) を警察のPOIとして読む
+// 種別は名称から判定して tags["kind"] に保存する。名称や座標のない行は数だけ返す
+func ReadKobanCSV(r io.Reader, layout KobanLayout) ([]*domain.POI, int, error) {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+	header, err := cr.Read()
+	if err != nil {
+		return nil, 0, fmt.Errorf("read header: %w", err)
+	}
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		columns[strings.TrimSpace(strings.TrimPrefix(name, "\ufeff"))] = i // BOM付きのCSV
+	}
+	for _, required := range []string{layout.NameColumn, layout.LatColumn, layout.LonColumn} {
+		if _, ok := columns[required]; !ok {
+			return nil, 0, fmt.Errorf("missing column %q", required)
+		}
+	}
+
+	var pois []*domain.POI
+	skipped := 0
+	for line := 2; ; line++ {
+		row, err := cr.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, 0, fmt.Errorf("line %d: %w", line, err)
+		}
+		value := func(column string) string {
+			if i, ok := columns[column]; ok && column != "" && i < len(row) {
+				return strings.TrimSpace(row[i])
+			}
+			return ""
+		}
+
+		name, address := value(layout.NameColumn), value(layout.AddressColumn)
+		lat, latErr := strconv.ParseFloat(value(layout.LatColumn), 64)
+		lon, lonErr := strconv.ParseFloat(value(layout.LonColumn), 64)
+		if name == "" || latErr != nil || lonErr != nil || lat == 0 || lon == 0 {
+			skipped++
+			continue
+		}
+
+		id := value(layout.IDColumn)
+		if id == "" {
+			sum := sha1.Sum([]byte(name + "|" + address))
+			id = hex.EncodeToString(sum[:8])
+		}
+		tags := make(map[string]string)
+		if address != "" {
+			tags["address"] = address
+		}
+		for _, k := range kobanKinds {
+			if strings.Contains(name, k.word) {
+				tags["kind"] = k.kind
+				break
+			}
+		}
+
+		pois = append(pois, &domain.POI{
+			Source:   KobanSource,
+			SourceID: id,
+			Category: domain.POICategoryPolice,
+			Name:     name,
+			Location: fmt.Sprintf("POINT(%f %f)", lon, lat),
+			Tags:     tags,
+			Lat:      lat,
+			Lon:      lon,
+		})
+	}
+	return pois, skipped, nil
+}
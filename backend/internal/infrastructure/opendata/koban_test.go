@@ -0,0 +1,50 @@
+package opendata
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/gigaptera/hikkoshi-lens/backend/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const kobanCSV = "\ufeffID,名称,住所,緯度,経度\n" +
+	"1,渋谷駅前交番,東京都渋谷区道玄坂一丁目,35.6590,139.7005\n" +
+	",代々木警察署,東京都渋谷区西原一丁目,35.6760,139.6790\n" +
+	"3,山間駐在所,,35.7,139.2\n" +
+	"4,座標なし,,,\n" +
+	"5,,東京都渋谷区,35.6,139.6\n"
+
+func TestReadKobanCSV(t *testing.T) {
+	pois, skipped, err := ReadKobanCSV(strings.NewReader(kobanCSV), DefaultKobanLayout())
+	require.NoError(t, err)
+	assert.Equal(t, 2, skipped)
+	require.Len(t, pois, 3)
+
+	p := pois[0]
+	assert.Equal(t, KobanSource, p.Source)
+	assert.Equal(t, "1", p.SourceID)
+	assert.Equal(t, domain.POICategoryPolice, p.Category)
+	assert.Equal(t, "渋谷駅前交番", p.Name)
+	assert.Equal(t, "POINT(139.700500 35.659000)", p.Location)
+	assert.Equal(t, map[string]string{"address": "東京都渋谷区道玄坂一丁目", "kind": "koban"}, p.Tags)
+
+	// IDのない行は名称と住所のハッシュ
+	assert.Len(t, pois[1].SourceID, 16)
+	assert.Equal(t, "station", pois[1].Tags["kind"])
+	assert.Equal(t, map[string]string{"kind": "chuzaisho"}, pois[2].Tags)
+}
+
+func TestReadKobanCSV_Layout(t *testing.T) {
+	csv := "施設名,所在地,lat,lng\n新宿駅東口交番,東京都新宿区新宿三丁目,35.6910,139.7010\n"
+	layout := KobanLayout{NameColumn: "施設名", AddressColumn: "所在地", LatColumn: "lat", LonColumn: "lng"}
+	pois, skipped, err := ReadKobanCSV(strings.NewReader(csv), layout)
+	require.NoError(t, err)
+	assert.Equal(t, 0, skipped)
+	require.Len(t, pois, 1)
+	assert.Equal(t, "東京都新宿区新宿三丁目", pois[0].Tags["address"])
+
+	_, _, err = ReadKobanCSV(strings.NewReader(csv), DefaultKobanLayout())
+	assert.ErrorContains(t, err, "名称")
+}
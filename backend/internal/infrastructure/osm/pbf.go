@@ -0,0 +1,217 @@
+package osm
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/gigaptera/hikkoshi-lens/backend/internal/domain"
+	"github.com/paulmach/osm"
+	"github.com/paulmach/osm/osmpbf"
+)
+
+// Source は pois.source に保存するOSM由来の識別子
+const Source = "osm"
+
+// Stats は取り込み結果の件数
+// 対象外の要素やエラーを「施設0件」と区別できるように分けて数える
+type Stats struct {
+	Nodes            int64 // 対象カテゴリのノード
+	Ways             int64 // 対象カテゴリのway (重心を計算できたもの)
+	WaysMissingNodes int64 // 構成ノードが抽出範囲外などで座標を得られなかったway
+	Skipped          int64 // 対象タグを持つがカテゴリ外・位置不正などで除外した要素
+}
+
+// Opener はPBFファイルを開く。wayの座標解決のためファイルを2回読むので、io.Reader ではなく関数で受け取る
+type Opener func() (io.ReadCloser, error)
+
+// ScanPOIs はOSMのPBF抽出ファイルを走査し、対象カテゴリのPOIを fn に渡す
+//   - 1回目: タグ付きノードをそのままPOIとして出力し、対象wayの構成ノードIDを記録する
+//   - 2回目: 記録したノードの座標だけを読み込み、wayの重心をPOIとして出力する
+//
+// 全ノードの座標を保持しないため、全国規模のファイルでもメモリ使用量は対象wayの規模に比例する
+func ScanPOIs(ctx context.Context, open Opener, procs int, fn func(*domain.POI) error) (Stats, error) {
+	c := newCollector()
+
+	// 1回目: ノードとway
+	err := scan(ctx, open, procs, func(s *osmpbf.Scanner) {
+		s.SkipRelations = true
+		s.FilterNode = func(n *osm.Node) bool { return hasPOITag(n.Tags) || hasNodeOnlyPOITag(n.Tags) }
+		s.FilterWay = func(w *osm.Way) bool { return hasPOITag(w.Tags) }
+	}, func(obj osm.Object) error {
+		switch o := obj.(type) {
+		case *osm.Node:
+			if poi := c.addNode(o); poi != nil {
+				return fn(poi)
+			}
+		case *osm.Way:
+			c.addWay(o)
+		}
+		return nil
+	})
+	if err != nil {
+		return c.stats, fmt.Errorf("scan nodes and ways: %w", err)
+	}
+
+	if len(c.pending) == 0 {
+		return c.stats, nil
+	}
+
+	// 2回目: wayの構成ノードの座標
+	// FilterNode はデコーダから並列に呼ばれるが、needed はこの時点で読み取り専用
+	err = scan(ctx, open, procs, func(s *osmpbf.Scanner) {
+		s.SkipWays = true
+		s.SkipRelations = true
+		s.FilterNode = func(n *osm.Node) bool { _, ok := c.needed[n.ID]; return ok }
+	}, func(obj osm.Object) error {
+		if n, ok := obj.(*osm.Node); ok {
+			c.coords[n.ID] = [2]float64{n.Lon, n.Lat}
+		}
+		return nil
+	})
+	if err != nil {
+		return c.stats, fmt.Errorf("scan way nodes: %w", err)
+	}
+
+	for _, poi := range c.resolveWays() {
+		if err := fn(poi); err != nil {
+			return c.stats, err
+		}
+	}
+	return c.stats, nil
+}
+
+func scan(ctx context.Context, open Opener, procs int, configure func(*osmpbf.Scanner), fn func(osm.Object) error) error {
+	f, err := open()
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	s := osmpbf.New(ctx, f, procs)
+	defer s.Close()
+	configure(s)
+
+	for s.Scan() {
+		if err := fn(s.Object()); err != nil {
+			return err
+		}
+	}
+	return s.Err()
+}
+
+func hasPOITag(tags osm.Tags) bool {
+	for _, t := range tags {
+		if domain.IsPOITagKey(t.Key) {
+			return true
+		}
+	}
+	return false
+}
+
+func hasNodeOnlyPOITag(tags osm.Tags) bool {
+	for _, t := range tags {
+		if domain.IsNodeOnlyPOITag(t.Key, t.Value) {
+			return true
+		}
+	}
+	return false
+}
+
+type pendingWay struct {
+	poi   *domain.POI
+	nodes []osm.NodeID
+}
+
+// collector はPBFの要素からPOIを組み立てる (ファイル読み込みと分離してテストできるようにしている)
+type collector struct {
+	stats   Stats
+	pending []pendingWay
+	needed  map[osm.NodeID]struct{}
+	coords  map[osm.NodeID][2]float64 // [lon, lat]
+}
+
+func newCollector() *collector {
+	return &collector{
+		needed: make(map[osm.NodeID]struct{}),
+		coords: make(map[osm.NodeID][2]float64),
+	}
+}
+
+func newPOI(sourceID string, tags osm.Tags) *domain.POI {
+	m := tags.Map()
+	category, ok := domain.ClassifyPOI(m)
+	if !ok {
+		return nil
+	}
+	return &domain.POI{
+		Source:   Source,
+		SourceID: sourceID,
+		Category: category,
+		Name:     m["name"],
+		Tags:     m,
+	}
+}
+
+func (c *collector) addNode(n *osm.Node) *domain.POI {
+	poi := newPOI(fmt.Sprintf("node/%d", n.ID), n.Tags)
+	if poi == nil || !validCoord(n.Lon, n.Lat) {
+		c.stats.Skipped++
+		return nil
+	}
+	poi.Location = pointWKT(n.Lon, n.Lat)
+	c.stats.Nodes++
+	return poi
+}
+
+func (c *collector) addWay(w *osm.Way) {
+	poi := newPOI(fmt.Sprintf("way/%d", w.ID), w.Tags)
+	if poi == nil || len(w.Nodes) == 0 {
+		c.stats.Skipped++
+		return
+	}
+
+	ids := w.Nodes.NodeIDs()
+	// 閉じたway (建物・公園の外周) は始点と終点が同じノードなので重心計算では1回だけ数える
+	if len(ids) > 1 && ids[0] == ids[len(ids)-1] {
+		ids = ids[:len(ids)-1]
+	}
+	for _, id := range ids {
+		c.needed[id] = struct{}{}
+	}
+	c.pending = append(c.pending, pendingWay{poi: poi, nodes: ids})
+}
+
+// resolveWays は読み込んだノード座標から各wayの重心を計算する
+// 構成ノードが一部欠けている場合は得られたノードだけで重心を取り、1つもなければ除外する
+func (c *collector) resolveWays() []*domain.POI {
+	pois := make([]*domain.POI, 0, len(c.pending))
+	for _, pw := range c.pending {
+		var sumLon, sumLat float64
+		var n int
+		for _, id := range pw.nodes {
+			if p, ok := c.coords[id]; ok {
+				sumLon += p[0]
+				sumLat += p[1]
+				n++
+			}
+		}
+		if n == 0 {
+			c.stats.WaysMissingNodes++
+			continue
+		}
+		pw.poi.Location = pointWKT(sumLon/float64(n), sumLat/float64(n))
+		c.stats.Ways++
+		pois = append(pois, pw.poi)
+	}
+	c.pending = nil
+	return pois
+}
+
+func validCoord(lon, lat float64) bool {
+	return lon >= -180 && lon <= 180 && lat >= -90 && lat <= 90 && !(lon == 0 && lat == 0)
+}
+
+func pointWKT(lon, lat float64) string {
+	return fmt.Sprintf("POINT(%f %f)", lon, lat)
+}
@@ -0,0 +1,92 @@
+package osm
+
+import (
+	"testing"
+
+	"github.com/gigaptera/hikkoshi-lens/backend/internal/domain"
+	"github.com/paulmach/osm"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCollector_AddNode(t *testing.T) {
+	c := newCollector()
+
+	poi := c.addNode(&osm.Node{ID: 1, Lat: 35.68, Lon: 139.76, Tags: osm.Tags{
+		{Key: "shop", Value: "convenience"},
+		{Key: "name", Value: "ローソン 東京駅前店"},
+	}})
+	require.NotNil(t, poi)
+	assert.Equal(t, Source, poi.Source)
+	assert.Equal(t, "node/1", poi.SourceID)
+	assert.Equal(t, domain.POICategoryConvenience, poi.Category)
+	assert.Equal(t, "ローソン 東京駅前店", poi.Name)
+	assert.Equal(t, "POINT(139.760000 35.680000)", poi.Location)
+
+	// 対象キーを持つがカテゴリ外
+	assert.Nil(t, c.addNode(&osm.Node{ID: 2, Lat: 35.68, Lon: 139.76, Tags: osm.Tags{{Key: "shop", Value: "bakery"}}}))
+	// 位置が不正
+	assert.Nil(t, c.addNode(&osm.Node{ID: 3, Tags: osm.Tags{{Key: "amenity", Value: "hospital"}}}))
+
+	assert.Equal(t, Stats{Nodes: 1, Skipped: 2}, c.stats)
+}
+
+func TestCollector_WayCentroid(t *testing.T) {
+	c := newCollector()
+
+	// 閉じたway (始点 = 終点) の公園
+	c.addWay(&osm.Way{ID: 10, Tags: osm.Tags{{Key: "leisure", Value: "park"}, {Key: "name", Value: "日比谷公園"}},
+		Nodes: osm.WayNodes{{ID: 1}, {ID: 2}, {ID: 3}, {ID: 4}, {ID: 1}}})
+	// 構成ノードが抽出範囲外のway
+	c.addWay(&osm.Way{ID: 11, Tags: osm.Tags{{Key: "amenity", Value: "hospital"}},
+		Nodes: osm.WayNodes{{ID: 100}, {ID: 101}}})
+
+	assert.Len(t, c.needed, 6)
+
+	c.coords[1] = [2]float64{139.0, 35.0}
+	c.coords[2] = [2]float64{139.2, 35.0}
+	c.coords[3] = [2]float64{139.2, 35.2}
+	c.coords[4] = [2]float64{139.0, 35.2}
+
+	pois := c.resolveWays()
+	require.Len(t, pois, 1)
+	assert.Equal(t, "way/10", pois[0].SourceID)
+	assert.Equal(t, domain.POICategoryPark, pois[0].Category)
+	// 始点が二重に数えられていれば重心がずれる
+	assert.Equal(t, "POINT(139.100000 35.100000)", pois[0].Location)
+
+	assert.Equal(t, int64(1), c.stats.Ways)
+	assert.Equal(t, int64(1), c.stats.WaysMissingNodes)
+}
+
+func TestClassifyPOI(t *testing.T) {
+	tests := []struct {
+		tags     map[string]string
+		category string
+		ok       bool
+	}{
+		{map[string]string{"shop": "supermarket"}, domain.POICategorySupermarket, true},
+		{map[string]string{"amenity": "pharmacy"}, domain.POICategoryDrugstore, true},
+		{map[string]string{"shop": "chemist"}, domain.POICategoryDrugstore, true},
+		{map[string]string{"leisure": "fitness_centre"}, domain.POICategoryGym, true},
+		{map[string]string{"amenity": "college"}, domain.POICategoryUniversity, true},
+		{map[string]string{"highway": "street_lamp"}, domain.POICategoryStreetLamp, true},
+		{map[string]string{"highway": "crossing"}, "", false},
+		{map[string]string{"amenity": "bench"}, "", false},
+		{map[string]string{}, "", false},
+	}
+	for _, tt := range tests {
+		category, ok := domain.ClassifyPOI(tt.tags)
+		assert.Equal(t, tt.ok, ok, "%v", tt.tags)
+		assert.Equal(t, tt.category, category, "%v", tt.tags)
+	}
+}
+
+func TestPOITagFilter(t *testing.T) {
+	// 街灯はノードだけを値まで見て対象にする (highway のwayは道路のため走査しない)
+	lamp := osm.Tags{{Key: "highway", Value: "street_lamp"}}
+	assert.False(t, hasPOITag(lamp))
+	assert.True(t, hasNodeOnlyPOITag(lamp))
+	assert.False(t, hasNodeOnlyPOITag(osm.Tags{{Key: "highway", Value: "residential"}}))
+	assert.True(t, hasPOITag(osm.Tags{{Key: "amenity", Value: "police"}}))
+}
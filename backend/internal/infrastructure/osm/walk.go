@@ -0,0 +1,150 @@
+package osm
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/gigaptera/hikkoshi-lens/backend/internal/domain"
+	"github.com/paulmach/osm"
+	"github.com/paulmach/osm/osmpbf"
+)
+
+// walkHighways は歩行できる道路の highway タグ (高速道路・工事中などは含めない)
+var walkHighways = map[string]bool{
+	"footway": true, "path": true, "pedestrian": true, "steps": true, "corridor": true,
+	"living_street": true, "residential": true, "service": true, "unclassified": true, "road": true, "track": true,
+	"tertiary": true, "tertiary_link": true, "secondary": true, "secondary_link": true,
+	"primary": true, "primary_link": true, "trunk": true, "trunk_link": true, "cycleway": true,
+}
+
+// WalkStats は徒歩ネットワークの読み込み結果の件数
+type WalkStats struct {
+	Ways          int64 // 歩行できる道路のway
+	Nodes         int64 // ネットワークのノード
+	Edges         int64
+	MissingNodes  int64 // 抽出範囲外などで座標を得られなかった構成ノード
+	ExcludedByTag int64 // highway はあるが歩行できない (foot=no など) way
+}
+
+// ScanWalkNetwork はOSMのPBF抽出ファイルから歩行できる道路を読み込み、徒歩ネットワークを作る
+//   - 1回目: 歩行できる道路のwayと構成ノードIDを記録する
+//   - 2回目: 記録したノードの座標だけを読み込む
+func ScanWalkNetwork(ctx context.Context, open Opener, procs int) (*domain.WalkNetwork, WalkStats, error) {
+	c := newWalkCollector()
+
+	err := scan(ctx, open, procs, func(s *osmpbf.Scanner) {
+		s.SkipNodes = true
+		s.SkipRelations = true
+		s.FilterWay = func(w *osm.Way) bool { return w.Tags.Find("highway") != "" }
+	}, func(obj osm.Object) error {
+		if w, ok := obj.(*osm.Way); ok {
+			c.addWay(w)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, c.stats, fmt.Errorf("scan ways: %w", err)
+	}
+
+	// FilterNode はデコーダから並列に呼ばれるが、needed はこの時点で読み取り専用
+	err = scan(ctx, open, procs, func(s *osmpbf.Scanner) {
+		s.SkipWays = true
+		s.SkipRelations = true
+		s.FilterNode = func(n *osm.Node) bool { _, ok := c.needed[n.ID]; return ok }
+	}, func(obj osm.Object) error {
+		if n, ok := obj.(*osm.Node); ok {
+			c.addNode(n)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, c.stats, fmt.Errorf("scan way nodes: %w", err)
+	}
+
+	return c.build(), c.stats, nil
+}
+
+// isWalkable は歩行者が通れる道路かどうかを返す
+func isWalkable(tags osm.Tags) bool {
+	if !walkHighways[tags.Find("highway")] {
+		return false
+	}
+	switch tags.Find("foot") {
+	case "yes", "designated", "permissive":
+		return true
+	case "no", "private":
+		return false
+	}
+	switch tags.Find("access") {
+	case "no", "private":
+		return false
+	}
+	return true
+}
+
+// walkCollector はPBFの要素から徒歩ネットワークを組み立てる (ファイル読み込みと分離してテストできるようにしている)
+type walkCollector struct {
+	stats  WalkStats
+	ways   [][]osm.NodeID
+	needed map[osm.NodeID]struct{}
+	index  map[osm.NodeID]int32 // 座標を得られた構成ノード -> ネットワークのノード番号
+	lats   []float64
+	lons   []float64
+}
+
+func newWalkCollector() *walkCollector {
+	return &walkCollector{needed: make(map[osm.NodeID]struct{}), index: make(map[osm.NodeID]int32)}
+}
+
+func (c *walkCollector) addWay(w *osm.Way) {
+	if !isWalkable(w.Tags) {
+		c.stats.ExcludedByTag++
+		return
+	}
+	if len(w.Nodes) < 2 {
+		return
+	}
+	ids := w.Nodes.NodeIDs()
+	for _, id := range ids {
+		c.needed[id] = struct{}{}
+	}
+	c.ways = append(c.ways, ids)
+	c.stats.Ways++
+}
+
+func (c *walkCollector) addNode(n *osm.Node) {
+	if !validCoord(n.Lon, n.Lat) {
+		return
+	}
+	if _, ok := c.needed[n.ID]; !ok {
+		return
+	}
+	if _, ok := c.index[n.ID]; ok {
+		return
+	}
+	c.index[n.ID] = int32(len(c.lats))
+	c.lats = append(c.lats, n.Lat)
+	c.lons = append(c.lons, n.Lon)
+}
+
+// build は隣り合う構成ノードをエッジにする。座標のないノードの前後はつながない
+func (c *walkCollector) build() *domain.WalkNetwork {
+	net := &domain.WalkNetwork{Lats: c.lats, Lons: c.lons}
+	for _, ids := range c.ways {
+		prev := int32(-1)
+		for _, id := range ids {
+			cur, ok := c.index[id]
+			if !ok {
+				c.stats.MissingNodes++
+				cur = -1
+			} else if prev >= 0 && prev != cur {
+				net.Edges = append(net.Edges, [2]int32{prev, cur})
+			}
+			prev = cur
+		}
+	}
+	c.ways, c.needed = nil, nil
+	c.stats.Nodes = int64(len(net.Lats))
+	c.stats.Edges = int64(len(net.Edges))
+	return net
+}
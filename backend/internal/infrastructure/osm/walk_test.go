@@ -0,0 +1,45 @@
+package osm
+
+import (
+	"testing"
+
+	"github.com/paulmach/osm"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsWalkable(t *testing.T) {
+	testCases := []struct {
+		tags osm.Tags
+		want bool
+	}{
+		{osm.Tags{{Key: "highway", Value: "residential"}}, true},
+		{osm.Tags{{Key: "highway", Value: "footway"}}, true},
+		{osm.Tags{{Key: "highway", Value: "motorway"}}, false},
+		{osm.Tags{{Key: "highway", Value: "trunk"}, {Key: "foot", Value: "no"}}, false},
+		{osm.Tags{{Key: "highway", Value: "service"}, {Key: "access", Value: "private"}}, false},
+		{osm.Tags{{Key: "highway", Value: "service"}, {Key: "access", Value: "private"}, {Key: "foot", Value: "yes"}}, true},
+		{osm.Tags{{Key: "building", Value: "yes"}}, false},
+	}
+	for _, tc := range testCases {
+		assert.Equal(t, tc.want, isWalkable(tc.tags), tc.tags.Map())
+	}
+}
+
+func TestWalkCollector_Build(t *testing.T) {
+	c := newWalkCollector()
+	road := osm.Tags{{Key: "highway", Value: "residential"}}
+	c.addWay(&osm.Way{ID: 1, Tags: road, Nodes: osm.WayNodes{{ID: 1}, {ID: 2}, {ID: 3}}})
+	// ノード2で交差する道路 (ノード5は抽出範囲外)
+	c.addWay(&osm.Way{ID: 2, Tags: road, Nodes: osm.WayNodes{{ID: 4}, {ID: 2}, {ID: 5}, {ID: 6}}})
+	c.addWay(&osm.Way{ID: 3, Tags: osm.Tags{{Key: "highway", Value: "motorway"}}, Nodes: osm.WayNodes{{ID: 7}, {ID: 8}}})
+
+	for _, id := range []osm.NodeID{1, 2, 3, 4, 6, 7} {
+		c.addNode(&osm.Node{ID: id, Lat: 35 + float64(id)*0.001, Lon: 139})
+	}
+	net := c.build()
+
+	// ノード7は歩行できない道路のみの構成ノードなので読み込まない
+	assert.Len(t, net.Lats, 5)
+	assert.Equal(t, [][2]int32{{0, 1}, {1, 2}, {3, 1}}, net.Edges)
+	assert.Equal(t, WalkStats{Ways: 2, Nodes: 5, Edges: 3, MissingNodes: 1, ExcludedByTag: 1}, c.stats)
+}
@@ -0,0 +1,56 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/gigaptera/hikkoshi-lens/backend/internal/domain"
+	"github.com/uptrace/bun"
+)
+
+type affiliateRepository struct {
+	db *bun.DB
+}
+
+func NewAffiliateRepository(db *bun.DB) domain.AffiliateRepository {
+	return &affiliateRepository{db: db}
+}
+
+func (r *affiliateRepository) GetExternalIDs(ctx context.Context, stationID int64) (map[string]*domain.StationExternalID, error) {
+	var ids []*domain.StationExternalID
+	err := r.db.NewSelect().
+		Model(&ids).
+		Where("sei.station_id = ?", stationID).
+		Scan(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make(map[string]*domain.StationExternalID, len(ids))
+	for _, id := range ids {
+		result[id.Source] = id
+	}
+	return result, nil
+}
+
+func (r *affiliateRepository) UpsertExternalIDs(ctx context.Context, ids []*domain.StationExternalID) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	_, err := r.db.NewInsert().
+		Model(&ids).
+		On("CONFLICT (station_id, source) DO UPDATE").
+		Set("area = EXCLUDED.area").
+		Set("external_id = EXCLUDED.external_id").
+		Set("updated_at = current_timestamp").
+		Exec(ctx)
+	return err
+}
+
+func (r *affiliateRepository) LogClick(ctx context.Context, click *domain.AffiliateClick) error {
+	_, err := r.db.NewInsert().
+		Model(click).
+		ExcludeColumn("id", "created_at").
+		Returning("id, created_at").
+		Exec(ctx)
+	return err
+}
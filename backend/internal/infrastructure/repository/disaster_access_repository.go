@@ -0,0 +1,54 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/gigaptera/hikkoshi-lens/backend/internal/domain"
+	"github.com/uptrace/bun"
+)
+
+type disasterAccessRepository struct {
+	db *bun.DB
+}
+
+func NewDisasterAccessRepository(db *bun.DB) domain.DisasterAccessRepository {
+	return &disasterAccessRepository{db: db}
+}
+
+func (r *disasterAccessRepository) Refresh(ctx context.Context) (int64, error) {
+	// 駅ごとに最も近い1件を空間インデックスの近傍検索 (<->) で求める
+	res, err := r.db.NewRaw(`
+		INSERT INTO station_disaster_access (station_id, nearest_shelter_meter, nearest_emergency_hospital_meter, updated_at)
+		SELECT
+			s.id,
+			(SELECT ST_Distance(p.location, s.location) FROM pois p
+				WHERE p.category = ? ORDER BY p.location <-> s.location LIMIT 1),
+			(SELECT ST_Distance(p.location, s.location) FROM pois p
+				WHERE p.category = ? ORDER BY p.location <-> s.location LIMIT 1),
+			CURRENT_TIMESTAMP
+		FROM stations s
+		WHERE s.location IS NOT NULL
+		ON CONFLICT (station_id) DO UPDATE SET
+			nearest_shelter_meter = EXCLUDED.nearest_shelter_meter,
+			nearest_emergency_hospital_meter = EXCLUDED.nearest_emergency_hospital_meter,
+			updated_at = EXCLUDED.updated_at`,
+		domain.POICategoryShelter,
+		domain.POICategoryEmergencyHospital,
+	).Exec(ctx)
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
+
+func (r *disasterAccessRepository) ListAll(ctx context.Context) (map[int64]*domain.StationDisasterAccess, error) {
+	var rows []*domain.StationDisasterAccess
+	if err := r.db.NewSelect().Model(&rows).Scan(ctx); err != nil {
+		return nil, err
+	}
+	result := make(map[int64]*domain.StationDisasterAccess, len(rows))
+	for _, row := range rows {
+		result[row.StationID] = row
+	}
+	return result, nil
+}
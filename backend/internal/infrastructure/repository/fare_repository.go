@@ -0,0 +1,47 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/gigaptera/hikkoshi-lens/backend/internal/domain"
+	"github.com/uptrace/bun"
+)
+
+type fareRepository struct {
+	db *bun.DB
+}
+
+func NewFareRepository(db *bun.DB) domain.FareRepository {
+	return &fareRepository{db: db}
+}
+
+func (r *fareRepository) ListBands(ctx context.Context) ([]*domain.FareBand, error) {
+	var bands []*domain.FareBand
+	err := r.db.NewSelect().
+		Model(&bands).
+		Order("fb.organization_code", "fb.min_km").
+		Scan(ctx)
+	return bands, err
+}
+
+func (r *fareRepository) ReplaceOperators(ctx context.Context, bands []*domain.FareBand) error {
+	if len(bands) == 0 {
+		return nil
+	}
+	seen := make(map[string]bool)
+	var orgs []string
+	for _, b := range bands {
+		if !seen[b.OrganizationCode] {
+			seen[b.OrganizationCode] = true
+			orgs = append(orgs, b.OrganizationCode)
+		}
+	}
+
+	return r.db.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		if _, err := tx.NewDelete().Model((*domain.FareBand)(nil)).Where("organization_code IN (?)", bun.In(orgs)).Exec(ctx); err != nil {
+			return err
+		}
+		_, err := tx.NewInsert().Model(&bands).ExcludeColumn("updated_at").Exec(ctx)
+		return err
+	})
+}
@@ -0,0 +1,224 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/gigaptera/hikkoshi-lens/backend/internal/domain"
+	"github.com/uptrace/bun"
+)
+
+type gridRepository struct {
+	db *bun.DB
+}
+
+func NewGridRepository(db *bun.DB) domain.GridRepository {
+	return &gridRepository{db: db}
+}
+
+func (r *gridRepository) CreateCells(ctx context.Context, spec domain.GridSpec, stationRadiusMeter int) (int64, error) {
+	gridFunc := "ST_HexagonGrid"
+	if spec.Shape == domain.GridShapeSquare {
+		gridFunc = "ST_SquareGrid"
+	}
+
+	// 駅全体の範囲をWebメルカトル (EPSG:3857) でセルに分割する
+	// メルカトルの1単位は緯度が高いほど実距離より短くなるため、範囲中心の緯度で割り戻して実距離の大きさに揃える
+	// 駅から離れたセル (海・山間部) は作らない
+	res, err := r.db.NewRaw(`
+		WITH extent AS (
+			SELECT
+				ST_Transform(ST_SetSRID(ST_Extent(location::geometry), 4326), 3857) AS env,
+				cos(radians(ST_Y(ST_Centroid(ST_SetSRID(ST_Extent(location::geometry), 4326))))) AS scale
+			FROM stations
+			WHERE location IS NOT NULL
+		),
+		cells AS (
+			SELECT g.i, g.j, ST_Transform(g.geom, 4326) AS geom
+			FROM extent e, ?(? / e.scale, e.env) AS g
+		)
+		INSERT INTO grid_cells (grid, shape, size_m, i, j, geom, centroid)
+		SELECT ?, ?, ?, c.i, c.j, c.geom, ST_Centroid(c.geom)::geography
+		FROM cells c
+		WHERE EXISTS (
+			SELECT 1 FROM stations s
+			WHERE ST_DWithin(s.location, ST_Centroid(c.geom)::geography, ?)
+		)
+		ON CONFLICT (grid, i, j) DO NOTHING`,
+		bun.Safe(gridFunc), spec.SizeMeter,
+		spec.Name(), spec.Shape, spec.SizeMeter,
+		stationRadiusMeter,
+	).Exec(ctx)
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
+
+func (r *gridRepository) ListCells(ctx context.Context, grid string) ([]*domain.GridCell, error) {
+	var cells []*domain.GridCell
+	err := r.db.NewSelect().
+		Model(&cells).
+		Column("gc.id", "gc.grid", "gc.shape", "gc.size_m", "gc.i", "gc.j").
+		ColumnExpr("ST_Y(gc.centroid::geometry) AS lat, ST_X(gc.centroid::geometry) AS lon").
+		Where("gc.grid = ?", grid).
+		OrderExpr("gc.id ASC").
+		Scan(ctx)
+	return cells, err
+}
+
+func (r *gridRepository) StationSamples(ctx context.Context) ([]*domain.GridSample, error) {
+	var rows []struct {
+		ID     int64    `bun:"id"`
+		Lat    float64  `bun:"lat"`
+		Lon    float64  `bun:"lon"`
+		Hazard *float64 `bun:"hazard"`
+		Safety *float64 `bun:"safety"`
+	}
+	err := r.db.NewRaw(`
+		SELECT
+			s.id,
+			ST_Y(s.location::geometry) AS lat,
+			ST_X(s.location::geometry) AS lon,
+			GREATEST(dr.flood_risk_level, dr.landslide_risk_level, dr.earthquake_risk_level)::float8 AS hazard,
+			ss.normalized_score AS safety
+		FROM stations s
+		LEFT JOIN disaster_risks dr ON dr.station_id = s.id
+		LEFT JOIN station_scores ss ON ss.station_id = s.id AND ss.axis = 'safety'
+		WHERE s.location IS NOT NULL
+		ORDER BY s.id`).Scan(ctx, &rows)
+	if err != nil {
+		return nil, err
+	}
+
+	samples := make([]*domain.GridSample, len(rows))
+	byID := make(map[int64]*domain.GridSample, len(rows))
+	for i, row := range rows {
+		s := &domain.GridSample{StationID: row.ID, Lat: row.Lat, Lon: row.Lon, Values: make(map[string]float64)}
+		if row.Hazard != nil {
+			s.Values[domain.GridMetricHazardLevel] = *row.Hazard
+		}
+		if row.Safety != nil {
+			s.Values[domain.GridMetricSafetyScore] = *row.Safety
+		}
+		samples[i] = s
+		byID[row.ID] = s
+	}
+
+	// 間取りごとの家賃は建物種別を平均する
+	var rents []struct {
+		StationID int64   `bun:"station_id"`
+		Layout    string  `bun:"layout"`
+		Rent      float64 `bun:"rent"`
+	}
+	err = r.db.NewRaw(`
+		SELECT station_id, layout, AVG(avg_rent)::float8 AS rent
+		FROM market_prices
+		WHERE avg_rent > 0
+		GROUP BY station_id, layout`).Scan(ctx, &rents)
+	if err != nil {
+		return nil, err
+	}
+	for _, rent := range rents {
+		if s, ok := byID[rent.StationID]; ok {
+			s.Values[domain.GridRentMetric(rent.Layout)] = rent.Rent
+		}
+	}
+	return samples, nil
+}
+
+func (r *gridRepository) RefreshPOIDensity(ctx context.Context, grid, metric string, categories []string, version string) (int64, error) {
+	res, err := r.db.NewRaw(`
+		INSERT INTO grid_cell_metrics (cell_id, metric, value, data_version, updated_at)
+		SELECT
+			c.id, ?,
+			COUNT(p.id) / NULLIF(ST_Area(c.geom::geography) / 1e6, 0),
+			?, CURRENT_TIMESTAMP
+		FROM grid_cells c
+		LEFT JOIN pois p ON p.category IN (?) AND ST_Intersects(p.location, c.geom::geography)
+		WHERE c.grid = ? AND EXISTS (SELECT 1 FROM pois WHERE category IN (?))
+		GROUP BY c.id
+		ON CONFLICT (cell_id, metric) DO UPDATE SET
+			value = EXCLUDED.value,
+			data_version = EXCLUDED.data_version,
+			updated_at = EXCLUDED.updated_at`,
+		metric, version, bun.In(categories), grid, bun.In(categories),
+	).Exec(ctx)
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
+
+func (r *gridRepository) RefreshPOIDistance(ctx context.Context, grid, metric string, categories []string, version string) (int64, error) {
+	res, err := r.db.NewRaw(`
+		INSERT INTO grid_cell_metrics (cell_id, metric, value, data_version, updated_at)
+		SELECT c.id, ?, n.meter, ?, CURRENT_TIMESTAMP
+		FROM grid_cells c
+		CROSS JOIN LATERAL (
+			SELECT ST_Distance(p.location, c.centroid) AS meter FROM pois p
+			WHERE p.category IN (?)
+			ORDER BY p.location <-> c.centroid LIMIT 1
+		) n
+		WHERE c.grid = ?
+		ON CONFLICT (cell_id, metric) DO UPDATE SET
+			value = EXCLUDED.value,
+			data_version = EXCLUDED.data_version,
+			updated_at = EXCLUDED.updated_at`,
+		metric, version, bun.In(categories), grid,
+	).Exec(ctx)
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
+
+func (r *gridRepository) UpsertMetrics(ctx context.Context, metrics []*domain.GridCellMetric) error {
+	if len(metrics) == 0 {
+		return nil
+	}
+	_, err := r.db.NewInsert().
+		Model(&metrics).
+		On("CONFLICT (cell_id, metric) DO UPDATE").
+		Set("value = EXCLUDED.value").
+		Set("data_version = EXCLUDED.data_version").
+		Set("updated_at = current_timestamp").
+		Exec(ctx)
+	return err
+}
+
+func (r *gridRepository) GetFeatures(ctx context.Context, grid string, bbox domain.BBox, metric string, limit int) ([]*domain.Feature, error) {
+	var rows []struct {
+		ID       int64   `bun:"id"`
+		Geometry string  `bun:"geometry"`
+		Value    float64 `bun:"value"`
+	}
+	err := r.db.NewRaw(`
+		SELECT c.id, ST_AsGeoJSON(c.geom, 6) AS geometry, m.value
+		FROM grid_cells c
+		JOIN grid_cell_metrics m ON m.cell_id = c.id AND m.metric = ?
+		WHERE c.grid = ? AND c.geom && ST_MakeEnvelope(?, ?, ?, ?, 4326)
+		ORDER BY c.id
+		LIMIT ?`,
+		metric, grid, bbox.MinLon, bbox.MinLat, bbox.MaxLon, bbox.MaxLat, limit,
+	).Scan(ctx, &rows)
+	if err != nil {
+		return nil, err
+	}
+
+	features := make([]*domain.Feature, len(rows))
+	for i, row := range rows {
+		geom := new(domain.Geometry)
+		if err := json.Unmarshal([]byte(row.Geometry), geom); err != nil {
+			return nil, fmt.Errorf("decode geometry of cell %d: %w", row.ID, err)
+		}
+		features[i] = &domain.Feature{
+			Type:       "Feature",
+			ID:         row.ID,
+			Geometry:   geom,
+			Properties: map[string]interface{}{"metric": metric, "value": row.Value},
+		}
+	}
+	return features, nil
+}
@@ -0,0 +1,196 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/gigaptera/hikkoshi-lens/backend/internal/domain"
+	"github.com/uptrace/bun"
+)
+
+type insightRepository struct {
+	db *bun.DB
+}
+
+func NewInsightRepository(db *bun.DB) domain.InsightRepository {
+	return &insightRepository{db: db}
+}
+
+func (r *insightRepository) ListInputs(ctx context.Context) ([]*domain.InsightInput, error) {
+	// 駅と乗り入れ路線 (同名で500m以内の駅。自駅の路線を先頭にする)
+	var stations []struct {
+		ID               int64    `bun:"id"`
+		Name             string   `bun:"name"`
+		OrganizationCode string   `bun:"organization_code"`
+		LineName         string   `bun:"line_name"`
+		Lines            []string `bun:"lines,array"`
+	}
+	err := r.db.NewRaw(`
+		SELECT
+			s.id, s.name, s.organization_code, s.line_name,
+			ARRAY(
+				SELECT s2.line_name
+				FROM stations s2
+				WHERE s2.name = s.name AND ST_DWithin(s2.location, s.location, ?)
+				GROUP BY s2.line_name
+				ORDER BY bool_or(s2.id = s.id) DESC, s2.line_name
+			) AS lines
+		FROM stations s
+		WHERE s.location IS NOT NULL
+		ORDER BY s.id`, tagSameStationMeter).Scan(ctx, &stations)
+	if err != nil {
+		return nil, err
+	}
+
+	inputs := make([]*domain.InsightInput, len(stations))
+	byID := make(map[int64]*domain.InsightInput, len(stations))
+	for i, s := range stations {
+		inputs[i] = &domain.InsightInput{
+			StationID: s.ID,
+			LineKey:   domain.LineKey{OrganizationCode: s.OrganizationCode, LineName: s.LineName},
+			Name:      s.Name,
+			Lines:     s.Lines,
+		}
+		byID[s.ID] = inputs[i]
+	}
+
+	// 間取りごとの家賃相場 (建物種別の平均)
+	var rents []struct {
+		StationID int64   `bun:"station_id"`
+		Layout    string  `bun:"layout"`
+		Rent      float64 `bun:"rent"`
+	}
+	err = r.db.NewRaw(`
+		SELECT station_id, layout, ROUND(AVG(avg_rent)::numeric, 2)::float8 AS rent
+		FROM market_prices
+		WHERE avg_rent > 0
+		GROUP BY station_id, layout`).Scan(ctx, &rents)
+	if err != nil {
+		return nil, err
+	}
+	for _, rent := range rents {
+		if in, ok := byID[rent.StationID]; ok {
+			if in.Rents == nil {
+				in.Rents = make(map[string]float64)
+			}
+			in.Rents[rent.Layout] = rent.Rent
+		}
+	}
+
+	// 徒歩圏の施設数
+	var facilities []*domain.Facility
+	if err := r.db.NewSelect().Model(&facilities).Scan(ctx); err != nil {
+		return nil, err
+	}
+	for _, f := range facilities {
+		if in, ok := byID[f.StationID]; ok {
+			in.Facilities = map[string]int{
+				domain.POICategorySupermarket: f.SupermarketsCount,
+				domain.POICategoryConvenience: f.ConvenienceStoresCount,
+				domain.POICategoryHospital:    f.HospitalsCount,
+				domain.POICategoryDrugstore:   f.DrugstoresCount,
+				domain.POICategoryRestaurant:  f.RestaurantsCount,
+				domain.POICategoryGym:         f.GymsCount,
+				domain.POICategoryPark:        f.ParksCount,
+			}
+		}
+	}
+
+	// 災害リスク
+	var hazards []struct {
+		StationID  int64 `bun:"station_id"`
+		Flood      int   `bun:"flood_risk_level"`
+		Landslide  int   `bun:"landslide_risk_level"`
+		Earthquake int   `bun:"earthquake_risk_level"`
+	}
+	err = r.db.NewRaw(`
+		SELECT station_id,
+			COALESCE(flood_risk_level, 0) AS flood_risk_level,
+			COALESCE(landslide_risk_level, 0) AS landslide_risk_level,
+			COALESCE(earthquake_risk_level, 0) AS earthquake_risk_level
+		FROM disaster_risks`).Scan(ctx, &hazards)
+	if err != nil {
+		return nil, err
+	}
+	for _, h := range hazards {
+		if in, ok := byID[h.StationID]; ok {
+			in.Hazards = map[string]int{"flood": h.Flood, "landslide": h.Landslide, "earthquake": h.Earthquake}
+		}
+	}
+
+	// 地形
+	var terrains []*domain.StationTerrain
+	if err := r.db.NewSelect().Model(&terrains).Scan(ctx); err != nil {
+		return nil, err
+	}
+	for _, t := range terrains {
+		if in, ok := byID[t.StationID]; ok {
+			in.Terrain = t
+		}
+	}
+
+	// 事前計算済みスコア
+	var scores []*domain.StationScore
+	if err := r.db.NewSelect().Model(&scores).Column("station_id", "axis", "normalized_score").Scan(ctx); err != nil {
+		return nil, err
+	}
+	for _, sc := range scores {
+		if in, ok := byID[sc.StationID]; ok {
+			if in.Scores == nil {
+				in.Scores = make(map[string]float64)
+			}
+			in.Scores[sc.Axis] = sc.NormalizedScore
+		}
+	}
+
+	// タグ
+	var tags []*domain.StationTag
+	if err := r.db.NewSelect().Model(&tags).Column("station_id", "tag").OrderExpr("st.station_id, st.position").Scan(ctx); err != nil {
+		return nil, err
+	}
+	for _, t := range tags {
+		if in, ok := byID[t.StationID]; ok {
+			in.Tags = append(in.Tags, t.Tag)
+		}
+	}
+	return inputs, nil
+}
+
+func (r *insightRepository) ListStates(ctx context.Context) (map[int64]domain.StationInsight, error) {
+	var rows []*domain.StationInsight
+	err := r.db.NewSelect().
+		Model(&rows).
+		Column("station_id", "input_hash", "generator").
+		Scan(ctx)
+	if err != nil {
+		return nil, err
+	}
+	states := make(map[int64]domain.StationInsight, len(rows))
+	for _, row := range rows {
+		states[row.StationID] = *row
+	}
+	return states, nil
+}
+
+func (r *insightRepository) Upsert(ctx context.Context, insight *domain.StationInsight) error {
+	_, err := r.db.NewInsert().
+		Model(insight).
+		On("CONFLICT (station_id) DO UPDATE").
+		Set("insight = EXCLUDED.insight").
+		Set("input_hash = EXCLUDED.input_hash").
+		Set("generator = EXCLUDED.generator").
+		Set("generated_at = EXCLUDED.generated_at").
+		Exec(ctx)
+	return err
+}
+
+func (r *insightRepository) Get(ctx context.Context, stationID int64) (*domain.StationInsight, error) {
+	insight := new(domain.StationInsight)
+	err := r.db.NewSelect().
+		Model(insight).
+		Where("si.station_id = ?", stationID).
+		Scan(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return insight, nil
+}
@@ -0,0 +1,53 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/gigaptera/hikkoshi-lens/backend/internal/domain"
+	"github.com/uptrace/bun"
+	"github.com/uptrace/bun/dialect/pgdialect"
+)
+
+type isochroneRepository struct {
+	db *bun.DB
+}
+
+func NewIsochroneRepository(db *bun.DB) domain.IsochroneRepository {
+	return &isochroneRepository{db: db}
+}
+
+func (r *isochroneRepository) UnionCircles(ctx context.Context, circles []domain.WalkCircle) (*domain.Geometry, error) {
+	if len(circles) == 0 {
+		return nil, nil
+	}
+
+	n := len(circles)
+	lons := make([]float64, n)
+	lats := make([]float64, n)
+	radii := make([]float64, n)
+	for i, c := range circles {
+		lons[i], lats[i], radii[i] = c.Lon, c.Lat, c.RadiusMeter
+	}
+
+	// geography でメートル単位の円を作り、geometry に戻して結合する
+	// 円周は8分割 (quad_segs) で十分。頂点数を抑えて応答を小さくする
+	var geojson string
+	err := r.db.NewRaw(`
+		SELECT ST_AsGeoJSON(ST_Union(
+			ST_Buffer(ST_SetSRID(ST_MakePoint(c.lon, c.lat), 4326)::geography, c.radius, 'quad_segs=8')::geometry
+		), 6)
+		FROM unnest(?::float8[], ?::float8[], ?::float8[]) AS c(lon, lat, radius)`,
+		pgdialect.Array(lons), pgdialect.Array(lats), pgdialect.Array(radii),
+	).Scan(ctx, &geojson)
+	if err != nil {
+		return nil, err
+	}
+
+	geom := new(domain.Geometry)
+	if err := json.Unmarshal([]byte(geojson), geom); err != nil {
+		return nil, fmt.Errorf("decode isochrone geometry: %w", err)
+	}
+	return geom, nil
+}
@@ -0,0 +1,74 @@
+package repository
+
+import (
+	"context"
+	"math"
+
+	"github.com/gigaptera/hikkoshi-lens/backend/internal/domain"
+	"github.com/uptrace/bun"
+)
+
+type nightSafetyRepository struct {
+	db *bun.DB
+}
+
+func NewNightSafetyRepository(db *bun.DB) domain.NightSafetyRepository {
+	return &nightSafetyRepository{db: db}
+}
+
+func (r *nightSafetyRepository) Refresh(ctx context.Context, radiusMeter int) (int64, error) {
+	areaKm2 := math.Pi * float64(radiusMeter) * float64(radiusMeter) / 1e6
+	// 街灯はOSMでの登録が地域によって偏るため、1本もない場合は「0本」ではなく「データなし」(NULL) にする
+	res, err := r.db.NewRaw(`
+		WITH lamps AS (SELECT EXISTS (SELECT 1 FROM pois WHERE category = ?) AS present)
+		INSERT INTO station_night_safety (station_id, street_lamp_density, nearest_police_meter, radius_meter, updated_at)
+		SELECT
+			s.id,
+			CASE WHEN lamps.present THEN
+				(SELECT COUNT(*) FROM pois p
+					WHERE p.category = ? AND ST_DWithin(p.location, s.location, ?)) / ?::float8
+			END,
+			(SELECT ST_Distance(p.location, s.location) FROM pois p
+				WHERE p.category = ? ORDER BY p.location <-> s.location LIMIT 1),
+			?, CURRENT_TIMESTAMP
+		FROM stations s, lamps
+		WHERE s.location IS NOT NULL
+		ON CONFLICT (station_id) DO UPDATE SET
+			street_lamp_density = EXCLUDED.street_lamp_density,
+			nearest_police_meter = EXCLUDED.nearest_police_meter,
+			radius_meter = EXCLUDED.radius_meter,
+			updated_at = EXCLUDED.updated_at`,
+		domain.POICategoryStreetLamp,
+		domain.POICategoryStreetLamp, radiusMeter, areaKm2,
+		domain.POICategoryPolice,
+		radiusMeter,
+	).Exec(ctx)
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
+
+func (r *nightSafetyRepository) Get(ctx context.Context, stationID int64) (*domain.StationNightSafety, error) {
+	row := new(domain.StationNightSafety)
+	err := r.db.NewSelect().
+		Model(row).
+		Where("sns.station_id = ?", stationID).
+		Scan(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return row, nil
+}
+
+func (r *nightSafetyRepository) ListAll(ctx context.Context) (map[int64]*domain.StationNightSafety, error) {
+	var rows []*domain.StationNightSafety
+	if err := r.db.NewSelect().Model(&rows).Scan(ctx); err != nil {
+		return nil, err
+	}
+	result := make(map[int64]*domain.StationNightSafety, len(rows))
+	for _, row := range rows {
+		result[row.StationID] = row
+	}
+	return result, nil
+}
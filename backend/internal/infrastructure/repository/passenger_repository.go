@@ -0,0 +1,61 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/gigaptera/hikkoshi-lens/backend/internal/domain"
+	"github.com/uptrace/bun"
+)
+
+type passengerRepository struct {
+	db *bun.DB
+}
+
+func NewPassengerRepository(db *bun.DB) domain.PassengerRepository {
+	return &passengerRepository{db: db}
+}
+
+func (r *passengerRepository) Upsert(ctx context.Context, rows []*domain.StationPassengers) error {
+	if len(rows) == 0 {
+		return nil
+	}
+	_, err := r.db.NewInsert().
+		Model(&rows).
+		ExcludeColumn("created_at", "updated_at").
+		On("CONFLICT (station_id) DO UPDATE").
+		Set("daily_passengers = EXCLUDED.daily_passengers").
+		Set("passengers_year = EXCLUDED.passengers_year").
+		Set("passengers_version = EXCLUDED.passengers_version").
+		Set("updated_at = current_timestamp").
+		Exec(ctx)
+	return err
+}
+
+func (r *passengerRepository) ListAll(ctx context.Context) (map[int64]int, error) {
+	var rows []*domain.StationPassengers
+	err := r.db.NewSelect().
+		Model(&rows).
+		Column("station_id", "daily_passengers").
+		Where("sd.daily_passengers IS NOT NULL").
+		Scan(ctx)
+	if err != nil {
+		return nil, err
+	}
+	result := make(map[int64]int, len(rows))
+	for _, row := range rows {
+		result[row.StationID] = *row.DailyPassengers
+	}
+	return result, nil
+}
+
+func (r *passengerRepository) Get(ctx context.Context, stationID int64) (*domain.StationPassengers, error) {
+	row := new(domain.StationPassengers)
+	err := r.db.NewSelect().
+		Model(row).
+		Where("sd.station_id = ?", stationID).
+		Scan(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return row, nil
+}
@@ -0,0 +1,71 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/gigaptera/hikkoshi-lens/backend/internal/domain"
+	"github.com/uptrace/bun"
+)
+
+type permalinkRepository struct {
+	db *bun.DB
+}
+
+func NewPermalinkRepository(db *bun.DB) domain.PermalinkRepository {
+	return &permalinkRepository{db: db}
+}
+
+func (r *permalinkRepository) Create(ctx context.Context, link *domain.SearchPermalink) error {
+	return affected(r.db.NewInsert().
+		Model(link).
+		ExcludeColumn("view_count", "last_viewed_at", "created_at").
+		On("CONFLICT (code) DO NOTHING").
+		Returning("*").
+		Exec(ctx))
+}
+
+func (r *permalinkRepository) View(ctx context.Context, code string, now time.Time) (*domain.SearchPermalink, error) {
+	link := new(domain.SearchPermalink)
+	err := r.db.NewUpdate().
+		Model(link).
+		Set("view_count = view_count + 1").
+		Set("last_viewed_at = ?", now).
+		Where("code = ?", code).
+		Where("expires_at > ?", now).
+		Returning("*").
+		Scan(ctx)
+	if err == nil {
+		return link, nil
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		return nil, err
+	}
+
+	// 更新できなかった理由 (存在しない / 期限切れ) を区別する
+	exists, err := r.db.NewSelect().
+		Model((*domain.SearchPermalink)(nil)).
+		Where("code = ?", code).
+		Exists(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if exists {
+		return nil, domain.ErrPermalinkExpired
+	}
+	return nil, sql.ErrNoRows
+}
+
+func (r *permalinkRepository) DeleteExpired(ctx context.Context, before time.Time) (int, error) {
+	res, err := r.db.NewDelete().
+		Model((*domain.SearchPermalink)(nil)).
+		Where("expires_at < ?", before).
+		Exec(ctx)
+	if err != nil {
+		return 0, err
+	}
+	n, err := res.RowsAffected()
+	return int(n), err
+}
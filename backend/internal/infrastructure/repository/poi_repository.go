@@ -0,0 +1,144 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/gigaptera/hikkoshi-lens/backend/internal/domain"
+	"github.com/uptrace/bun"
+)
+
+type poiRepository struct {
+	db *bun.DB
+}
+
+func NewPOIRepository(db *bun.DB) domain.POIRepository {
+	return &poiRepository{db: db}
+}
+
+func (r *poiRepository) GetNearStation(ctx context.Context, stationID int64, filter domain.POIFilter) ([]*domain.POI, error) {
+	categoryCond := bun.SafeQuery("TRUE")
+	if len(filter.Categories) > 0 {
+		categoryCond = bun.SafeQuery("p.category IN (?)", bun.In(filter.Categories))
+	}
+
+	// カテゴリごとに距離順の連番を振り、上位 Limit 件ずつに絞る
+	var pois []*domain.POI
+	err := r.db.NewRaw(`
+		SELECT id, source, source_id, category, name, lat, lon, distance
+		FROM (
+			SELECT
+				p.id, p.source, p.source_id, p.category, p.name,
+				ST_Y(p.location::geometry) AS lat,
+				ST_X(p.location::geometry) AS lon,
+				ST_Distance(p.location, s.location) AS distance,
+				ROW_NUMBER() OVER (PARTITION BY p.category ORDER BY ST_Distance(p.location, s.location), p.id) AS rn
+			FROM pois p
+			JOIN stations s ON s.id = ?
+			WHERE ST_DWithin(p.location, s.location, ?) AND ?
+		) ranked
+		WHERE rn <= ?
+		ORDER BY category, distance`,
+		stationID, filter.RadiusMeter, categoryCond, filter.Limit,
+	).Scan(ctx, &pois)
+	if err != nil {
+		return nil, err
+	}
+	return pois, nil
+}
+
+func (r *poiRepository) GetNearest(ctx context.Context, stationID int64, category string, limit int) ([]*domain.POI, error) {
+	var pois []*domain.POI
+	err := r.db.NewRaw(`
+		SELECT
+			p.id, p.source, p.source_id, p.category, p.name, p.tags,
+			ST_Y(p.location::geometry) AS lat,
+			ST_X(p.location::geometry) AS lon,
+			ST_Distance(p.location, s.location) AS distance
+		FROM pois p
+		JOIN stations s ON s.id = ?
+		WHERE p.category = ?
+		ORDER BY p.location <-> s.location, p.id
+		LIMIT ?`,
+		stationID, category, limit,
+	).Scan(ctx, &pois)
+	if err != nil {
+		return nil, err
+	}
+	return pois, nil
+}
+
+func (r *poiRepository) Upsert(ctx context.Context, pois []*domain.POI) error {
+	if len(pois) == 0 {
+		return nil
+	}
+	_, err := r.db.NewInsert().
+		Model(&pois).
+		ExcludeColumn("id").
+		On("CONFLICT (source, source_id) DO UPDATE").
+		Set("category = EXCLUDED.category").
+		Set("name = EXCLUDED.name").
+		Set("location = EXCLUDED.location").
+		Set("tags = EXCLUDED.tags").
+		Set("updated_at = current_timestamp").
+		Exec(ctx)
+	return err
+}
+
+func (r *poiRepository) DeleteStale(ctx context.Context, source string, before time.Time) (int64, error) {
+	res, err := r.db.NewDelete().
+		Model((*domain.POI)(nil)).
+		Where("source = ?", source).
+		Where("updated_at < ?", before).
+		Exec(ctx)
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
+
+func (r *poiRepository) RefreshFacilities(ctx context.Context, radiusMeter int) (int64, error) {
+	// 全駅 × 半径内のPOIを1回の空間結合で集計する (idx_pois_location を使用)
+	// POIが1件もない駅も 0 件として行を作る
+	res, err := r.db.NewRaw(`
+		INSERT INTO facilities (
+			station_id, supermarkets_count, convenience_stores_count, hospitals_count,
+			drugstores_count, restaurants_count, gyms_count, parks_count, updated_at
+		)
+		SELECT
+			s.id,
+			COUNT(p.id) FILTER (WHERE p.category = ?),
+			COUNT(p.id) FILTER (WHERE p.category = ?),
+			COUNT(p.id) FILTER (WHERE p.category = ?),
+			COUNT(p.id) FILTER (WHERE p.category = ?),
+			COUNT(p.id) FILTER (WHERE p.category = ?),
+			COUNT(p.id) FILTER (WHERE p.category = ?),
+			COUNT(p.id) FILTER (WHERE p.category = ?),
+			CURRENT_TIMESTAMP
+		FROM stations s
+		LEFT JOIN pois p ON ST_DWithin(p.location, s.location, ?)
+		WHERE s.location IS NOT NULL
+		GROUP BY s.id
+		ON CONFLICT (station_id) DO UPDATE SET
+			supermarkets_count = EXCLUDED.supermarkets_count,
+			convenience_stores_count = EXCLUDED.convenience_stores_count,
+			hospitals_count = EXCLUDED.hospitals_count,
+			drugstores_count = EXCLUDED.drugstores_count,
+			restaurants_count = EXCLUDED.restaurants_count,
+			gyms_count = EXCLUDED.gyms_count,
+			parks_count = EXCLUDED.parks_count,
+			updated_at = EXCLUDED.updated_at`,
+		domain.POICategorySupermarket,
+		domain.POICategoryConvenience,
+		domain.POICategoryHospital,
+		domain.POICategoryDrugstore,
+		domain.POICategoryRestaurant,
+		domain.POICategoryGym,
+		domain.POICategoryPark,
+		radiusMeter,
+	).Exec(ctx)
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
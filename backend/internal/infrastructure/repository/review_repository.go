@@ -0,0 +1,125 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/gigaptera/hikkoshi-lens/backend/internal/domain"
+	"github.com/uptrace/bun"
+)
+
+type reviewRepository struct {
+	db *bun.DB
+}
+
+func NewReviewRepository(db *bun.DB) domain.ReviewRepository {
+	return &reviewRepository{db: db}
+}
+
+func (r *reviewRepository) Create(ctx context.Context, review *domain.Review) error {
+	_, err := r.db.NewInsert().
+		Model(review).
+		ExcludeColumn("created_at").
+		Returning("id, created_at").
+		Exec(ctx)
+	return err
+}
+
+func (r *reviewRepository) CountByClientSince(ctx context.Context, clientHash string, stationID int64, since time.Time) (int, error) {
+	q := r.db.NewSelect().
+		Model((*domain.Review)(nil)).
+		Where("rv.client_hash = ?", clientHash).
+		Where("rv.created_at >= ?", since)
+	if stationID != 0 {
+		q = q.Where("rv.station_id = ?", stationID)
+	}
+	return q.Count(ctx)
+}
+
+func (r *reviewRepository) ListApproved(ctx context.Context, stationID int64, limit int) ([]*domain.Review, error) {
+	reviews := make([]*domain.Review, 0)
+	err := r.db.NewSelect().
+		Model(&reviews).
+		Where("rv.station_id = ?", stationID).
+		Where("rv.status = ?", domain.ReviewStatusApproved).
+		OrderExpr("rv.created_at DESC, rv.id DESC").
+		Limit(limit).
+		Scan(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return reviews, nil
+}
+
+func (r *reviewRepository) Summary(ctx context.Context, stationID int64) (*domain.ReviewSummary, error) {
+	summary := &domain.ReviewSummary{Ratings: make(map[string]float64)}
+	count, err := r.db.NewSelect().
+		Model((*domain.Review)(nil)).
+		Where("rv.station_id = ?", stationID).
+		Where("rv.status = ?", domain.ReviewStatusApproved).
+		Count(ctx)
+	if err != nil {
+		return nil, err
+	}
+	summary.Count = count
+	if count == 0 {
+		return summary, nil
+	}
+
+	var axes []struct {
+		Axis  string  `bun:"axis"`
+		Avg   float64 `bun:"avg"`
+		Count int     `bun:"count"`
+	}
+	err = r.db.NewRaw(`
+		SELECT r.key AS axis, AVG(r.value::int)::float8 AS avg, COUNT(*) AS count
+		FROM station_reviews rv, jsonb_each_text(rv.ratings) r
+		WHERE rv.station_id = ? AND rv.status = ?
+		GROUP BY r.key`, stationID, domain.ReviewStatusApproved).Scan(ctx, &axes)
+	if err != nil {
+		return nil, err
+	}
+
+	// 全体の平均は評価の件数で重み付けする (評価の少ない軸に引っ張られないように)
+	sum, n := 0.0, 0
+	for _, a := range axes {
+		summary.Ratings[a.Axis] = a.Avg
+		sum += a.Avg * float64(a.Count)
+		n += a.Count
+	}
+	if n > 0 {
+		summary.Overall = sum / float64(n)
+	}
+	return summary, nil
+}
+
+func (r *reviewRepository) ListByStatus(ctx context.Context, status string, limit, offset int) ([]*domain.Review, error) {
+	reviews := make([]*domain.Review, 0)
+	err := r.db.NewSelect().
+		Model(&reviews).
+		Where("rv.status = ?", status).
+		OrderExpr("rv.created_at ASC, rv.id ASC").
+		Limit(limit).
+		Offset(offset).
+		Scan(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return reviews, nil
+}
+
+func (r *reviewRepository) Moderate(ctx context.Context, id int64, status, note string, at time.Time) (*domain.Review, error) {
+	review := new(domain.Review)
+	err := r.db.NewUpdate().
+		Model(review).
+		Set("status = ?", status).
+		Set("moderation_note = ?", note).
+		Set("moderated_at = ?", at).
+		Where("id = ?", id).
+		Returning("*").
+		Scan(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return review, nil
+}
@@ -0,0 +1,110 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/gigaptera/hikkoshi-lens/backend/internal/domain"
+	"github.com/uptrace/bun"
+)
+
+type savedSearchRepository struct {
+	db *bun.DB
+}
+
+func NewSavedSearchRepository(db *bun.DB) domain.SavedSearchRepository {
+	return &savedSearchRepository{db: db}
+}
+
+func (r *savedSearchRepository) List(ctx context.Context, userID int64) ([]*domain.SavedSearch, error) {
+	searches := []*domain.SavedSearch{}
+	err := r.db.NewSelect().
+		Model(&searches).
+		Where("sv.user_id = ?", userID).
+		OrderExpr("sv.created_at ASC, sv.id ASC").
+		Scan(ctx)
+	return searches, err
+}
+
+func (r *savedSearchRepository) Get(ctx context.Context, userID, id int64) (*domain.SavedSearch, error) {
+	search := new(domain.SavedSearch)
+	err := r.db.NewSelect().
+		Model(search).
+		Where("sv.id = ?", id).
+		Where("sv.user_id = ?", userID).
+		Scan(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return search, nil
+}
+
+func (r *savedSearchRepository) Count(ctx context.Context, userID int64) (int, error) {
+	return r.db.NewSelect().
+		Model((*domain.SavedSearch)(nil)).
+		Where("sv.user_id = ?", userID).
+		Count(ctx)
+}
+
+func (r *savedSearchRepository) Create(ctx context.Context, search *domain.SavedSearch) error {
+	_, err := r.db.NewInsert().
+		Model(search).
+		ExcludeColumn("id", "last_run_at", "created_at", "updated_at").
+		Returning("*").
+		Exec(ctx)
+	return err
+}
+
+func (r *savedSearchRepository) Delete(ctx context.Context, userID, id int64) error {
+	return affected(r.db.NewDelete().
+		Model((*domain.SavedSearch)(nil)).
+		Where("id = ?", id).
+		Where("user_id = ?", userID).
+		Exec(ctx))
+}
+
+func (r *savedSearchRepository) ListAll(ctx context.Context) ([]*domain.SavedSearch, error) {
+	searches := []*domain.SavedSearch{}
+	err := r.db.NewSelect().
+		Model(&searches).
+		ColumnExpr("sv.*").
+		ColumnExpr("u.email AS user_email").
+		Join("JOIN users AS u ON u.id = sv.user_id").
+		OrderExpr("sv.id ASC").
+		Scan(ctx)
+	return searches, err
+}
+
+func (r *savedSearchRepository) GetResults(ctx context.Context, searchID int64) ([]*domain.SavedSearchResult, error) {
+	results := []*domain.SavedSearchResult{}
+	err := r.db.NewSelect().
+		Model(&results).
+		Where("svr.saved_search_id = ?", searchID).
+		OrderExpr("svr.station_id ASC").
+		Scan(ctx)
+	return results, err
+}
+
+func (r *savedSearchRepository) ReplaceResults(ctx context.Context, searchID int64, results []*domain.SavedSearchResult) error {
+	return r.db.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		if _, err := tx.NewDelete().
+			Model((*domain.SavedSearchResult)(nil)).
+			Where("saved_search_id = ?", searchID).
+			Exec(ctx); err != nil {
+			return err
+		}
+		if len(results) > 0 {
+			for _, res := range results {
+				res.SavedSearchID = searchID
+			}
+			if _, err := tx.NewInsert().Model(&results).Exec(ctx); err != nil {
+				return err
+			}
+		}
+		_, err := tx.NewUpdate().
+			Model((*domain.SavedSearch)(nil)).
+			Set("last_run_at = CURRENT_TIMESTAMP").
+			Where("id = ?", searchID).
+			Exec(ctx)
+		return err
+	})
+}
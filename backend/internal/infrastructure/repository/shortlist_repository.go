@@ -0,0 +1,146 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/gigaptera/hikkoshi-lens/backend/internal/domain"
+	"github.com/uptrace/bun"
+)
+
+type shortlistRepository struct {
+	db *bun.DB
+}
+
+func NewShortlistRepository(db *bun.DB) domain.ShortlistRepository {
+	return &shortlistRepository{db: db}
+}
+
+// affected は更新・削除の対象がなかった場合に sql.ErrNoRows を返す
+func affected(res sql.Result, err error) error {
+	if err != nil {
+		return err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+func (r *shortlistRepository) ListFavorites(ctx context.Context, userID int64) ([]*domain.Favorite, error) {
+	favorites := []*domain.Favorite{}
+	err := r.db.NewSelect().
+		Model(&favorites).
+		Where("fav.user_id = ?", userID).
+		OrderExpr("fav.created_at ASC, fav.station_id ASC").
+		Scan(ctx)
+	return favorites, err
+}
+
+func (r *shortlistRepository) UpsertFavorite(ctx context.Context, fav *domain.Favorite) error {
+	_, err := r.db.NewInsert().
+		Model(fav).
+		ExcludeColumn("created_at", "updated_at").
+		On("CONFLICT (user_id, station_id) DO UPDATE").
+		Set("note = EXCLUDED.note").
+		Set("updated_at = CURRENT_TIMESTAMP").
+		Returning("*").
+		Exec(ctx)
+	return err
+}
+
+func (r *shortlistRepository) DeleteFavorite(ctx context.Context, userID, stationID int64) error {
+	return affected(r.db.NewDelete().
+		Model((*domain.Favorite)(nil)).
+		Where("user_id = ?", userID).
+		Where("station_id = ?", stationID).
+		Exec(ctx))
+}
+
+func (r *shortlistRepository) ListShortlists(ctx context.Context, userID int64) ([]*domain.Shortlist, error) {
+	lists := []*domain.Shortlist{}
+	err := r.db.NewSelect().
+		Model(&lists).
+		ColumnExpr("sl.*").
+		ColumnExpr("(SELECT COUNT(*) FROM shortlist_items sli WHERE sli.shortlist_id = sl.id) AS item_count").
+		Where("sl.user_id = ?", userID).
+		OrderExpr("sl.created_at ASC, sl.id ASC").
+		Scan(ctx)
+	return lists, err
+}
+
+func (r *shortlistRepository) GetShortlist(ctx context.Context, userID, shortlistID int64) (*domain.Shortlist, error) {
+	list := new(domain.Shortlist)
+	err := r.db.NewSelect().
+		Model(list).
+		ColumnExpr("sl.*").
+		ColumnExpr("(SELECT COUNT(*) FROM shortlist_items sli WHERE sli.shortlist_id = sl.id) AS item_count").
+		Where("sl.id = ?", shortlistID).
+		Where("sl.user_id = ?", userID).
+		Scan(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+func (r *shortlistRepository) CreateShortlist(ctx context.Context, list *domain.Shortlist) error {
+	_, err := r.db.NewInsert().
+		Model(list).
+		ExcludeColumn("id", "created_at", "updated_at").
+		Returning("*").
+		Exec(ctx)
+	return err
+}
+
+func (r *shortlistRepository) RenameShortlist(ctx context.Context, userID, shortlistID int64, name string) error {
+	return affected(r.db.NewUpdate().
+		Model((*domain.Shortlist)(nil)).
+		Set("name = ?", name).
+		Set("updated_at = CURRENT_TIMESTAMP").
+		Where("id = ?", shortlistID).
+		Where("user_id = ?", userID).
+		Exec(ctx))
+}
+
+func (r *shortlistRepository) DeleteShortlist(ctx context.Context, userID, shortlistID int64) error {
+	return affected(r.db.NewDelete().
+		Model((*domain.Shortlist)(nil)).
+		Where("id = ?", shortlistID).
+		Where("user_id = ?", userID).
+		Exec(ctx))
+}
+
+func (r *shortlistRepository) ListShortlistItems(ctx context.Context, shortlistID int64) ([]*domain.ShortlistItem, error) {
+	items := []*domain.ShortlistItem{}
+	err := r.db.NewSelect().
+		Model(&items).
+		Where("sli.shortlist_id = ?", shortlistID).
+		OrderExpr("sli.created_at ASC, sli.station_id ASC").
+		Scan(ctx)
+	return items, err
+}
+
+func (r *shortlistRepository) UpsertShortlistItem(ctx context.Context, item *domain.ShortlistItem) error {
+	_, err := r.db.NewInsert().
+		Model(item).
+		ExcludeColumn("created_at", "updated_at").
+		On("CONFLICT (shortlist_id, station_id) DO UPDATE").
+		Set("note = EXCLUDED.note").
+		Set("updated_at = CURRENT_TIMESTAMP").
+		Returning("*").
+		Exec(ctx)
+	return err
+}
+
+func (r *shortlistRepository) DeleteShortlistItem(ctx context.Context, shortlistID, stationID int64) error {
+	return affected(r.db.NewDelete().
+		Model((*domain.ShortlistItem)(nil)).
+		Where("shortlist_id = ?", shortlistID).
+		Where("station_id = ?", stationID).
+		Exec(ctx))
+}
@@ -2,7 +2,6 @@ package repository
 
 import (
 	"context"
-	"fmt"
 	"log"
 
 	"github.com/gigaptera/hikkoshi-lens/backend/internal/domain"
@@ -21,14 +20,17 @@ func (r *stationRepository) GetNearby(ctx context.Context, lat, lon float64, fil
 	var stations []*domain.Station
 
 	// PostGIS ST_DWithin query
-	pointWKT := fmt.Sprintf("POINT(%f %f)", lon, lat)
+	// 検索地点は geography として1度だけ組み立て、s.location (geography) とそのまま比較する
+	// (location側をキャストしないことで idx_stations_location のGISTインデックスが使われる)
+	point := bun.SafeQuery("ST_SetSRID(ST_MakePoint(?, ?), 4326)::geography", lon, lat)
 
 	q := r.db.NewSelect().
 		Model(&stations).
 		Column("s.id", "s.station_code", "s.organization_code", "s.line_name", "s.name", "s.prefecture_code", "s.address").
-		ColumnExpr("ST_AsText(location) AS location").
-		ColumnExpr("ST_Distance(location, ST_GeogFromText(?)) AS distance", pointWKT).
-		Where("ST_DWithin(location, ST_GeogFromText(?), ?)", pointWKT, filter.RadiusMeter)
+		ColumnExpr("ST_AsText(s.location) AS location").
+		ColumnExpr("ST_Y(s.location::geometry) AS lat, ST_X(s.location::geometry) AS lon").
+		ColumnExpr("ST_Distance(s.location, ?) AS distance", point).
+		Where("ST_DWithin(s.location, ?, ?)", point, filter.RadiusMeter)
 
 	// Apply Rent Filters via Join
 	// 建物種別と間取りが両方指定されている場合のみMarketPricesをロード
@@ -91,7 +93,8 @@ func (r *stationRepository) GetStation(ctx context.Context, id int64) (*domain.S
 	err := r.db.NewSelect().
 		Model(station).
 		Column("id", "station_code", "organization_code", "line_name", "name", "prefecture_code", "address").
-		ColumnExpr("ST_AsText(location) AS location").
+		ColumnExpr("ST_AsText(s.location) AS location").
+		ColumnExpr("ST_Y(s.location::geometry) AS lat, ST_X(s.location::geometry) AS lon").
 		Relation("MarketPrices").
 		Where("s.id = ?", id).
 		Scan(ctx)
@@ -106,9 +109,73 @@ func (r *stationRepository) GetByLine(ctx context.Context, organizationCode, lin
 	err := r.db.NewSelect().
 		Model(&stations).
 		Column("id", "station_code", "organization_code", "line_name", "name", "prefecture_code", "address").
-		ColumnExpr("ST_AsText(location) AS location").
+		ColumnExpr("ST_AsText(s.location) AS location").
+		ColumnExpr("ST_Y(s.location::geometry) AS lat, ST_X(s.location::geometry) AS lon").
 		Relation("MarketPrices").
 		Where("s.organization_code = ? AND s.line_name = ?", organizationCode, lineName).
+		OrderExpr("s.station_code ASC, s.id ASC"). // 駅コードは路線上の並び順
+		Scan(ctx)
+	return stations, err
+}
+
+func (r *stationRepository) GetByLines(ctx context.Context, keys []domain.LineKey) (map[domain.LineKey][]*domain.Station, error) {
+	result := make(map[domain.LineKey][]*domain.Station, len(keys))
+	if len(keys) == 0 {
+		return result, nil
+	}
+
+	pairs := make([][]string, 0, len(keys))
+	for _, k := range keys {
+		pairs = append(pairs, []string{k.OrganizationCode, k.LineName})
+	}
+
+	var stations []*domain.Station
+	err := r.db.NewSelect().
+		Model(&stations).
+		Column("id", "station_code", "organization_code", "line_name", "name", "prefecture_code", "address").
+		ColumnExpr("ST_AsText(s.location) AS location").
+		ColumnExpr("ST_Y(s.location::geometry) AS lat, ST_X(s.location::geometry) AS lon").
+		Relation("MarketPrices").
+		Where("(s.organization_code, s.line_name) IN (?)", bun.In(pairs)).
+		OrderExpr("s.station_code ASC, s.id ASC").
+		Scan(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, s := range stations {
+		key := domain.LineKey{OrganizationCode: s.OrganizationCode, LineName: s.LineName}
+		result[key] = append(result[key], s)
+	}
+	return result, nil
+}
+
+func (r *stationRepository) ListAll(ctx context.Context) ([]*domain.Station, error) {
+	var stations []*domain.Station
+	err := r.db.NewSelect().
+		Model(&stations).
+		Column("id", "station_code", "organization_code", "line_name", "name", "prefecture_code", "address").
+		ColumnExpr("ST_AsText(s.location) AS location").
+		ColumnExpr("ST_Y(s.location::geometry) AS lat, ST_X(s.location::geometry) AS lon").
+		Relation("MarketPrices").
+		OrderExpr("s.id ASC").
+		Scan(ctx)
+	return stations, err
+}
+
+func (r *stationRepository) GetByIDs(ctx context.Context, ids []int64) ([]*domain.Station, error) {
+	stations := []*domain.Station{}
+	if len(ids) == 0 {
+		return stations, nil
+	}
+	err := r.db.NewSelect().
+		Model(&stations).
+		Column("s.id", "s.station_code", "s.organization_code", "s.line_name", "s.name", "s.prefecture_code", "s.address").
+		ColumnExpr("ST_AsText(s.location) AS location").
+		ColumnExpr("ST_Y(s.location::geometry) AS lat, ST_X(s.location::geometry) AS lon").
+		Relation("MarketPrices").
+		Where("s.id IN (?)", bun.In(ids)).
+		OrderExpr("s.id ASC").
 		Scan(ctx)
 	return stations, err
 }
@@ -0,0 +1,70 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/gigaptera/hikkoshi-lens/backend/internal/domain"
+	"github.com/gigaptera/hikkoshi-lens/backend/internal/infrastructure"
+	"github.com/uptrace/bun"
+)
+
+type stationScoreRepository struct {
+	db *bun.DB
+}
+
+func NewStationScoreRepository(db *bun.DB) domain.StationScoreRepository {
+	return &stationScoreRepository{db: db}
+}
+
+func (r *stationScoreRepository) GetByStationIDs(ctx context.Context, stationIDs []int64) (map[int64]map[string]float64, error) {
+	result := make(map[int64]map[string]float64)
+	if len(stationIDs) == 0 {
+		return result, nil
+	}
+
+	var scores []*domain.StationScore
+	err := r.db.NewSelect().
+		Model(&scores).
+		Column("station_id", "axis", "normalized_score").
+		Where("ss.station_id IN (?)", bun.In(stationIDs)).
+		Scan(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, sc := range scores {
+		if result[sc.StationID] == nil {
+			result[sc.StationID] = make(map[string]float64)
+		}
+		result[sc.StationID][sc.Axis] = sc.NormalizedScore
+	}
+	return result, nil
+}
+
+func (r *stationScoreRepository) Upsert(ctx context.Context, scores []*domain.StationScore) error {
+	if len(scores) == 0 {
+		return nil
+	}
+	_, err := r.db.NewInsert().
+		Model(&scores).
+		On("CONFLICT (station_id, axis) DO UPDATE").
+		Set("raw_score = EXCLUDED.raw_score").
+		Set("normalized_score = EXCLUDED.normalized_score").
+		Set("data_version = EXCLUDED.data_version").
+		Set("updated_at = current_timestamp").
+		Exec(ctx)
+	return err
+}
+
+func (r *stationScoreRepository) Delete(ctx context.Context, stationID int64, axis string) error {
+	_, err := r.db.NewDelete().
+		Model((*domain.StationScore)(nil)).
+		Where("station_id = ?", stationID).
+		Where("axis = ?", axis).
+		Exec(ctx)
+	return err
+}
+
+func (r *stationScoreRepository) NotifyUpdated(ctx context.Context) error {
+	return infrastructure.NotifyDataUpdated(ctx, r.db, "station_scores")
+}
@@ -0,0 +1,102 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/gigaptera/hikkoshi-lens/backend/internal/domain"
+	"github.com/uptrace/bun"
+	"github.com/uptrace/bun/dialect/pgdialect"
+)
+
+const (
+	// mvtExtent はタイル内の座標の分解能、mvtBuffer はタイル境界をまたぐシンボルが切れないように含める余白 (どちらもタイル座標単位)
+	mvtExtent = 4096
+	mvtBuffer = 64
+	// mvtLayer はタイル内のレイヤー名 (フロントエンドの source-layer)
+	mvtLayer = "stations"
+)
+
+type stationTileRepository struct {
+	db *bun.DB
+}
+
+func NewStationTileRepository(db *bun.DB) domain.StationTileRepository {
+	return &stationTileRepository{db: db}
+}
+
+func (r *stationTileRepository) GetInTile(ctx context.Context, tile domain.TileCoord, filter domain.StationFilter) ([]*domain.Station, error) {
+	var stations []*domain.Station
+
+	// 余白分だけ広げたタイル範囲 (EPSG:3857) を 4326 に戻して idx_stations_location_geometry で絞り込む
+	margin := float64(mvtBuffer) / float64(mvtExtent)
+	q := r.db.NewSelect().
+		Model(&stations).
+		Column("s.id", "s.station_code", "s.organization_code", "s.line_name", "s.name", "s.prefecture_code").
+		ColumnExpr("ST_Y(s.location::geometry) AS lat, ST_X(s.location::geometry) AS lon").
+		Where("s.location::geometry && ST_Transform(ST_TileEnvelope(?, ?, ?, margin => ?), 4326)", tile.Z, tile.X, tile.Y, margin)
+
+	if filter.BuildingType != "" && filter.Layout != "" {
+		q = q.Relation("MarketPrices", func(q *bun.SelectQuery) *bun.SelectQuery {
+			return q.Where("building_type = ?", filter.BuildingType).Where("layout = ?", filter.Layout)
+		})
+	}
+
+	if err := q.OrderExpr("s.id ASC").Scan(ctx); err != nil {
+		return nil, err
+	}
+	return stations, nil
+}
+
+func (r *stationTileRepository) RenderStations(ctx context.Context, tile domain.TileCoord, stations []*domain.Station) ([]byte, error) {
+	// Goで計算したスコアを列ごとの配列で渡し、unnest で駅と結合してから ST_AsMVT でエンコードする
+	n := len(stations)
+	ids := make([]int64, n)
+	total := make([]float64, n)
+	rent := make([]float64, n)
+	axes := []string{"rent", "facility", "safety", "disaster"}
+	axisScores := make([][]float64, len(axes))
+	for i := range axisScores {
+		axisScores[i] = make([]float64, n)
+	}
+	for i, s := range stations {
+		ids[i] = s.ID
+		total[i] = s.TotalScore
+		rent[i] = s.RentAvg
+		for j, axis := range axes {
+			axisScores[j][i] = s.ScoreDetails[axis]
+		}
+	}
+
+	var tileData []byte
+	err := r.db.NewRaw(`
+		WITH data AS (
+			SELECT *
+			FROM unnest(?::bigint[], ?::float8[], ?::float8[], ?::float8[], ?::float8[], ?::float8[], ?::float8[])
+				AS d(id, total_score, rent_avg, score_rent, score_facility, score_safety, score_disaster)
+		),
+		features AS (
+			SELECT
+				d.id, s.name, s.line_name,
+				round(d.total_score::numeric, 1) AS total_score,
+				NULLIF(d.rent_avg, 0) AS rent_avg,
+				round(d.score_rent::numeric, 1) AS score_rent,
+				round(d.score_facility::numeric, 1) AS score_facility,
+				round(d.score_safety::numeric, 1) AS score_safety,
+				round(d.score_disaster::numeric, 1) AS score_disaster,
+				ST_AsMVTGeom(ST_Transform(s.location::geometry, 3857), ST_TileEnvelope(?, ?, ?), ?, ?, true) AS geom
+			FROM data d
+			JOIN stations s ON s.id = d.id
+		)
+		SELECT COALESCE(ST_AsMVT(features, ?, ?, 'geom', 'id'), ''::bytea)
+		FROM features
+		WHERE geom IS NOT NULL`,
+		pgdialect.Array(ids), pgdialect.Array(total), pgdialect.Array(rent),
+		pgdialect.Array(axisScores[0]), pgdialect.Array(axisScores[1]), pgdialect.Array(axisScores[2]), pgdialect.Array(axisScores[3]),
+		tile.Z, tile.X, tile.Y, mvtExtent, mvtBuffer,
+		mvtLayer, mvtExtent,
+	).Scan(ctx, &tileData)
+	if err != nil {
+		return nil, err
+	}
+	return tileData, nil
+}
@@ -0,0 +1,165 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/gigaptera/hikkoshi-lens/backend/internal/domain"
+	"github.com/uptrace/bun"
+)
+
+// タグ判定に使う集計範囲
+const (
+	tagSameStationMeter = 500  // 同名でこの距離以内の駅は乗換駅として同じ駅とみなす
+	tagUniversityMeter  = 1000 // 大学・専門学校を数える範囲
+)
+
+// tagScoreFacts は station_scores の軸と指標名の対応
+var tagScoreFacts = map[string]string{
+	"facility": domain.TagFactFacilityScore,
+	"safety":   domain.TagFactSafetyScore,
+	"disaster": domain.TagFactDisasterScore,
+}
+
+type tagRepository struct {
+	db *bun.DB
+}
+
+func NewTagRepository(db *bun.DB) domain.TagRepository {
+	return &tagRepository{db: db}
+}
+
+func (r *tagRepository) ListFacts(ctx context.Context) ([]*domain.TagFacts, error) {
+	var rows []struct {
+		ID               int64    `bun:"id"`
+		OrganizationCode string   `bun:"organization_code"`
+		LineName         string   `bun:"line_name"`
+		LineCount        int      `bun:"line_count"`
+		Universities     int      `bun:"university_count"`
+		Supermarkets     *int     `bun:"supermarkets_count"`
+		Restaurants      *int     `bun:"restaurants_count"`
+		Parks            *int     `bun:"parks_count"`
+		FloodRisk        *int     `bun:"flood_risk_level"`
+		LandslideRisk    *int     `bun:"landslide_risk_level"`
+		EarthquakeRisk   *int     `bun:"earthquake_risk_level"`
+		Rent             *float64 `bun:"rent"`
+	}
+	// 施設数は facilities (徒歩圏の集計済み件数)、大学は pois から数える
+	err := r.db.NewRaw(`
+		SELECT
+			s.id, s.organization_code, s.line_name,
+			(SELECT COUNT(DISTINCT s2.organization_code || ':' || s2.line_name)
+				FROM stations s2
+				WHERE s2.name = s.name AND ST_DWithin(s2.location, s.location, ?)) AS line_count,
+			(SELECT COUNT(*)
+				FROM pois p
+				WHERE p.category = ? AND ST_DWithin(p.location, s.location, ?)) AS university_count,
+			f.supermarkets_count, f.restaurants_count, f.parks_count,
+			dr.flood_risk_level, dr.landslide_risk_level, dr.earthquake_risk_level,
+			mp.rent
+		FROM stations s
+		LEFT JOIN facilities f ON f.station_id = s.id
+		LEFT JOIN disaster_risks dr ON dr.station_id = s.id
+		LEFT JOIN (
+			SELECT station_id, AVG(avg_rent)::float8 AS rent
+			FROM market_prices
+			WHERE layout = ? AND avg_rent > 0
+			GROUP BY station_id
+		) mp ON mp.station_id = s.id
+		WHERE s.location IS NOT NULL
+		ORDER BY s.id`,
+		tagSameStationMeter,
+		domain.POICategoryUniversity, tagUniversityMeter,
+		domain.Layouts[0],
+	).Scan(ctx, &rows)
+	if err != nil {
+		return nil, err
+	}
+
+	facts := make([]*domain.TagFacts, len(rows))
+	byID := make(map[int64]*domain.TagFacts, len(rows))
+	for i, row := range rows {
+		v := map[string]float64{
+			domain.TagFactLineCount:    float64(row.LineCount),
+			domain.TagFactUniversities: float64(row.Universities),
+		}
+		setInt := func(fact string, p *int) {
+			if p != nil {
+				v[fact] = float64(*p)
+			}
+		}
+		setInt(domain.TagFactSupermarkets, row.Supermarkets)
+		setInt(domain.TagFactRestaurants, row.Restaurants)
+		setInt(domain.TagFactParks, row.Parks)
+		setInt(domain.TagFactFloodRisk, row.FloodRisk)
+		setInt(domain.TagFactLandslideRisk, row.LandslideRisk)
+		setInt(domain.TagFactEarthquakeRisk, row.EarthquakeRisk)
+		if row.Rent != nil {
+			v[domain.TagFactRent] = *row.Rent
+		}
+
+		facts[i] = &domain.TagFacts{
+			StationID: row.ID,
+			LineKey:   domain.LineKey{OrganizationCode: row.OrganizationCode, LineName: row.LineName},
+			Values:    v,
+		}
+		byID[row.ID] = facts[i]
+	}
+
+	var scores []*domain.StationScore
+	err = r.db.NewSelect().
+		Model(&scores).
+		Column("station_id", "axis", "normalized_score").
+		Where("ss.axis IN (?)", bun.In([]string{"facility", "safety", "disaster"})).
+		Scan(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, sc := range scores {
+		if f, ok := byID[sc.StationID]; ok {
+			f.Values[tagScoreFacts[sc.Axis]] = sc.NormalizedScore
+		}
+	}
+	return facts, nil
+}
+
+func (r *tagRepository) ReplaceAll(ctx context.Context, tags []*domain.StationTag) error {
+	return r.db.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		if _, err := tx.NewDelete().Model((*domain.StationTag)(nil)).Where("TRUE").Exec(ctx); err != nil {
+			return err
+		}
+		const batchSize = 5000
+		for i := 0; i < len(tags); i += batchSize {
+			end := i + batchSize
+			if end > len(tags) {
+				end = len(tags)
+			}
+			batch := tags[i:end]
+			if _, err := tx.NewInsert().Model(&batch).ExcludeColumn("updated_at").Exec(ctx); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (r *tagRepository) GetByStationIDs(ctx context.Context, stationIDs []int64) (map[int64][]string, error) {
+	result := make(map[int64][]string)
+	if len(stationIDs) == 0 {
+		return result, nil
+	}
+
+	var tags []*domain.StationTag
+	err := r.db.NewSelect().
+		Model(&tags).
+		Column("station_id", "tag").
+		Where("st.station_id IN (?)", bun.In(stationIDs)).
+		OrderExpr("st.station_id, st.position").
+		Scan(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, t := range tags {
+		result[t.StationID] = append(result[t.StationID], t.Tag)
+	}
+	return result, nil
+}
@@ -0,0 +1,60 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/gigaptera/hikkoshi-lens/backend/internal/domain"
+	"github.com/uptrace/bun"
+)
+
+type terrainRepository struct {
+	db *bun.DB
+}
+
+func NewTerrainRepository(db *bun.DB) domain.TerrainRepository {
+	return &terrainRepository{db: db}
+}
+
+func (r *terrainRepository) Upsert(ctx context.Context, rows []*domain.StationTerrain) error {
+	if len(rows) == 0 {
+		return nil
+	}
+	_, err := r.db.NewInsert().
+		Model(&rows).
+		ExcludeColumn("updated_at").
+		On("CONFLICT (station_id) DO UPDATE").
+		Set("elevation = EXCLUDED.elevation").
+		Set("avg_slope = EXCLUDED.avg_slope").
+		Set("elevation_gain = EXCLUDED.elevation_gain").
+		Set("relative_height = EXCLUDED.relative_height").
+		Set("radius_meter = EXCLUDED.radius_meter").
+		Set("method = EXCLUDED.method").
+		Set("data_version = EXCLUDED.data_version").
+		Set("updated_at = current_timestamp").
+		Exec(ctx)
+	return err
+}
+
+func (r *terrainRepository) ListAll(ctx context.Context) (map[int64]*domain.StationTerrain, error) {
+	var rows []*domain.StationTerrain
+	if err := r.db.NewSelect().Model(&rows).Scan(ctx); err != nil {
+		return nil, err
+	}
+	result := make(map[int64]*domain.StationTerrain, len(rows))
+	for _, row := range rows {
+		result[row.StationID] = row
+	}
+	return result, nil
+}
+
+func (r *terrainRepository) Get(ctx context.Context, stationID int64) (*domain.StationTerrain, error) {
+	row := new(domain.StationTerrain)
+	err := r.db.NewSelect().
+		Model(row).
+		Where("stt.station_id = ?", stationID).
+		Scan(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return row, nil
+}
@@ -0,0 +1,132 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/gigaptera/hikkoshi-lens/backend/internal/domain"
+	"github.com/uptrace/bun"
+)
+
+type userRepository struct {
+	db *bun.DB
+}
+
+func NewUserRepository(db *bun.DB) domain.UserRepository {
+	return &userRepository{db: db}
+}
+
+func (r *userRepository) GetOrCreateByEmail(ctx context.Context, email string) (*domain.User, error) {
+	// 同時ログインでも1行になるよう ON CONFLICT で作成し、既存行はログイン時刻だけ更新する
+	user := &domain.User{Email: email}
+	_, err := r.db.NewInsert().
+		Model(user).
+		ExcludeColumn("id", "created_at").
+		Value("last_login_at", "CURRENT_TIMESTAMP").
+		On("CONFLICT (email) DO UPDATE").
+		Set("last_login_at = EXCLUDED.last_login_at").
+		Returning("*").
+		Exec(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+func (r *userRepository) CreateLoginTokenWithinLimit(ctx context.Context, token *domain.LoginToken, since time.Time) error {
+	return r.db.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		// 同じメールアドレス・要求元の要求をトランザクションの終わりまで待たせ、数えてから保存するまでの間に割り込ませない
+		// デッドロックしないよう、ロックは常にメールアドレス・要求元の順に取る
+		for _, key := range []string{"login_tokens:email:" + token.Email, "login_tokens:client:" + token.ClientHash} {
+			if _, err := tx.NewRaw("SELECT pg_advisory_xact_lock(hashtext(?))", key).Exec(ctx); err != nil {
+				return err
+			}
+		}
+
+		var counts struct {
+			ByEmail  int `bun:"by_email"`
+			ByClient int `bun:"by_client"`
+		}
+		err := tx.NewSelect().
+			Model((*domain.LoginToken)(nil)).
+			ColumnExpr("COUNT(*) FILTER (WHERE lt.email = ?) AS by_email", token.Email).
+			ColumnExpr("COUNT(*) FILTER (WHERE lt.client_hash = ?) AS by_client", token.ClientHash).
+			Where("lt.created_at >= ?", since).
+			WhereGroup(" AND ", func(q *bun.SelectQuery) *bun.SelectQuery {
+				return q.Where("lt.email = ?", token.Email).WhereOr("lt.client_hash = ?", token.ClientHash)
+			}).
+			Scan(ctx, &counts)
+		if err != nil {
+			return err
+		}
+		if counts.ByClient >= domain.MaxMagicLinksPerClient {
+			return domain.ErrLoginTokenLimitByClient
+		}
+		if counts.ByEmail >= domain.MaxMagicLinksPerEmail {
+			return domain.ErrLoginTokenLimitByEmail
+		}
+
+		_, err = tx.NewInsert().Model(token).ExcludeColumn("created_at").Exec(ctx)
+		return err
+	})
+}
+
+func (r *userRepository) DeleteLoginTokensExpiredBefore(ctx context.Context, before time.Time) (int64, error) {
+	res, err := r.db.NewDelete().
+		Model((*domain.LoginToken)(nil)).
+		Where("expires_at < ?", before).
+		Exec(ctx)
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
+
+func (r *userRepository) ConsumeLoginToken(ctx context.Context, tokenHash string) (string, error) {
+	// 使用済みにする更新と有効性の確認を1文で行い、同じリンクの二重使用を防ぐ
+	var email string
+	err := r.db.NewUpdate().
+		Model((*domain.LoginToken)(nil)).
+		Set("used_at = CURRENT_TIMESTAMP").
+		Where("token_hash = ?", tokenHash).
+		Where("used_at IS NULL").
+		Where("expires_at > CURRENT_TIMESTAMP").
+		Returning("email").
+		Scan(ctx, &email)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", domain.ErrInvalidToken
+	}
+	return email, err
+}
+
+func (r *userRepository) CreateSession(ctx context.Context, session *domain.Session) error {
+	_, err := r.db.NewInsert().Model(session).ExcludeColumn("created_at").Exec(ctx)
+	return err
+}
+
+func (r *userRepository) GetSessionUser(ctx context.Context, tokenHash string) (*domain.User, error) {
+	user := new(domain.User)
+	err := r.db.NewSelect().
+		Model(user).
+		Join("JOIN sessions AS ses ON ses.user_id = u.id").
+		Where("ses.token_hash = ?", tokenHash).
+		Where("ses.expires_at > CURRENT_TIMESTAMP").
+		Scan(ctx)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, domain.ErrInvalidToken
+	}
+	if err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+func (r *userRepository) DeleteSession(ctx context.Context, tokenHash string) error {
+	_, err := r.db.NewDelete().
+		Model((*domain.Session)(nil)).
+		Where("token_hash = ?", tokenHash).
+		Exec(ctx)
+	return err
+}
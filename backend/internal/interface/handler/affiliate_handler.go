@@ -0,0 +1,98 @@
+package handler
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/gigaptera/hikkoshi-lens/backend/internal/domain"
+	"github.com/gigaptera/hikkoshi-lens/backend/internal/usecase"
+	"github.com/labstack/echo/v4"
+)
+
+type AffiliateHandler struct {
+	u usecase.AffiliateUsecase
+}
+
+func NewAffiliateHandler(u usecase.AffiliateUsecase) *AffiliateHandler {
+	return &AffiliateHandler{u: u}
+}
+
+// Redirect はクリックを記録して外部サイトの駅の物件一覧へリダイレクトする
+// layout, min_rent, max_rent は送客先の検索条件に引き継ぐ
+func (h *AffiliateHandler) Redirect(c echo.Context) error {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid station ID"})
+	}
+	source := c.Param("source")
+	if !domain.IsAffiliateSource(source) {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid source"})
+	}
+	filter, err := parseAffiliateFilter(c)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+
+	target, err := h.u.Redirect(c.Request().Context(), id, source, filter, c.Request().Referer())
+	if errors.Is(err, sql.ErrNoRows) {
+		return c.JSON(http.StatusNotFound, map[string]string{"error": "Station not found"})
+	}
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	}
+
+	// クリックを毎回記録するため、ブラウザ・CDNにリダイレクトを保存させない
+	c.Response().Header().Set(echo.HeaderCacheControl, "no-store")
+	return c.Redirect(http.StatusFound, target)
+}
+
+// parseAffiliateFilter は送客先に引き継ぐ検索条件を読む (家賃は万円)
+func parseAffiliateFilter(c echo.Context) (domain.AffiliateFilter, error) {
+	var f domain.AffiliateFilter
+	if layout := c.QueryParam("layout"); layout != "" {
+		valid := false
+		for _, l := range domain.Layouts {
+			if l == layout {
+				valid = true
+				break
+			}
+		}
+		if !valid {
+			return f, errors.New("Invalid layout")
+		}
+		f.Layout = layout
+	}
+	for _, p := range []struct {
+		name string
+		dst  *float64
+	}{{"min_rent", &f.MinRent}, {"max_rent", &f.MaxRent}} {
+		s := c.QueryParam(p.name)
+		if s == "" {
+			continue
+		}
+		v, err := strconv.ParseFloat(s, 64)
+		if err != nil || v < 0 {
+			return f, errors.New("Invalid " + p.name)
+		}
+		*p.dst = v
+	}
+	return f, nil
+}
+
+// affiliateQuery は検索条件をリダイレクトAPIのクエリ文字列にする
+func affiliateQuery(f domain.AffiliateFilter) string {
+	q := url.Values{}
+	if f.Layout != "" {
+		q.Set("layout", f.Layout)
+	}
+	if f.MinRent > 0 {
+		q.Set("min_rent", strconv.FormatFloat(f.MinRent, 'f', -1, 64))
+	}
+	if f.MaxRent > 0 {
+		q.Set("max_rent", strconv.FormatFloat(f.MaxRent, 'f', -1, 64))
+	}
+	return q.Encode()
+}
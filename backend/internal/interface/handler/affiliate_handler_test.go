@@ -0,0 +1,97 @@
+package handler
+
+import (
+	"context"
+	"database/sql"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gigaptera/hikkoshi-lens/backend/internal/domain"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockAffiliateUsecase はAffiliateUsecaseのモック
+type MockAffiliateUsecase struct {
+	mock.Mock
+}
+
+func (m *MockAffiliateUsecase) Redirect(ctx context.Context, stationID int64, source string, filter domain.AffiliateFilter, referer string) (string, error) {
+	args := m.Called(ctx, stationID, source, filter, referer)
+	return args.String(0), args.Error(1)
+}
+
+func newAffiliateContext(e *echo.Echo, target, id, source string) (echo.Context, *httptest.ResponseRecorder) {
+	req := httptest.NewRequest(http.MethodGet, target, nil)
+	req.Header.Set("Referer", "https://hikkoshi-lens.example/stations/1")
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	c.SetParamNames("id", "source")
+	c.SetParamValues(id, source)
+	return c, rec
+}
+
+func TestAffiliateRedirect_Success(t *testing.T) {
+	e := echo.New()
+	mockUsecase := new(MockAffiliateUsecase)
+	handler := NewAffiliateHandler(mockUsecase)
+
+	filter := domain.AffiliateFilter{Layout: "1r_1k_1dk", MaxRent: 9.5}
+	target := "https://suumo.jp/chintai/tokyo/ek_20110/?ct=9.5&md=01&md=02&md=03"
+	mockUsecase.On("Redirect", mock.Anything, int64(1), "suumo", filter, "https://hikkoshi-lens.example/stations/1").Return(target, nil)
+
+	c, rec := newAffiliateContext(e, "/api/stations/1/affiliate/suumo?layout=1r_1k_1dk&max_rent=9.5", "1", "suumo")
+	err := handler.Redirect(c)
+
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusFound, rec.Code)
+	assert.Equal(t, target, rec.Header().Get(echo.HeaderLocation))
+	assert.Equal(t, "no-store", rec.Header().Get(echo.HeaderCacheControl))
+	mockUsecase.AssertExpectations(t)
+}
+
+func TestAffiliateRedirect_NotFound(t *testing.T) {
+	e := echo.New()
+	mockUsecase := new(MockAffiliateUsecase)
+	handler := NewAffiliateHandler(mockUsecase)
+
+	mockUsecase.On("Redirect", mock.Anything, int64(999), "homes", domain.AffiliateFilter{}, mock.Anything).Return("", sql.ErrNoRows)
+
+	c, rec := newAffiliateContext(e, "/api/stations/999/affiliate/homes", "999", "homes")
+	err := handler.Redirect(c)
+
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
+
+func TestAffiliateRedirect_InvalidParams(t *testing.T) {
+	tests := []struct {
+		name   string
+		id     string
+		source string
+		query  string
+		want   string
+	}{
+		{"bad id", "abc", "suumo", "", "Invalid station ID"},
+		{"unknown source", "1", "athome", "", "Invalid source"},
+		{"unknown layout", "1", "suumo", "?layout=5ldk", "Invalid layout"},
+		{"negative rent", "1", "suumo", "?min_rent=-1", "Invalid min_rent"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := echo.New()
+			mockUsecase := new(MockAffiliateUsecase)
+			handler := NewAffiliateHandler(mockUsecase)
+
+			c, rec := newAffiliateContext(e, "/api/stations/"+tt.id+"/affiliate/"+tt.source+tt.query, tt.id, tt.source)
+			err := handler.Redirect(c)
+
+			assert.NoError(t, err)
+			assert.Equal(t, http.StatusBadRequest, rec.Code)
+			assert.Contains(t, rec.Body.String(), tt.want)
+			mockUsecase.AssertNotCalled(t, "Redirect")
+		})
+	}
+}
@@ -0,0 +1,133 @@
+package handler
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/gigaptera/hikkoshi-lens/backend/internal/domain"
+	"github.com/gigaptera/hikkoshi-lens/backend/internal/usecase"
+	"github.com/labstack/echo/v4"
+)
+
+// contextKeyUser はRequireUserが認証済みの利用者を保存するキー
+const contextKeyUser = "user"
+
+type AuthHandler struct {
+	u usecase.AuthUsecase
+}
+
+func NewAuthHandler(u usecase.AuthUsecase) *AuthHandler {
+	return &AuthHandler{u: u}
+}
+
+type magicLinkRequest struct {
+	Email string `json:"email"`
+}
+
+type verifyRequest struct {
+	Token string `json:"token"`
+}
+
+// RequestMagicLink はログイン用のリンクをメールで送る
+// 登録済みかどうか・宛先ごとの送信状況を推測されないよう、形式が正しければ 202 を返す
+// (同じメールアドレスへの送信が上限に達した場合も送らずに 202)。同じ要求元からの要求が多すぎる場合だけ 429
+func (h *AuthHandler) RequestMagicLink(c echo.Context) error {
+	var req magicLinkRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid request body"})
+	}
+
+	err := h.u.RequestMagicLink(c.Request().Context(), req.Email, c.RealIP())
+	if errors.Is(err, usecase.ErrInvalidEmail) {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+	if errors.Is(err, usecase.ErrMagicLinkRateLimited) {
+		return c.JSON(http.StatusTooManyRequests, map[string]string{"error": err.Error()})
+	}
+	if err != nil {
+		log.Printf("Error sending magic link: %v", err)
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to send login link"})
+	}
+	return c.NoContent(http.StatusAccepted)
+}
+
+// VerifyMagicLink はリンクのトークンをセッションのトークンに交換する
+func (h *AuthHandler) VerifyMagicLink(c echo.Context) error {
+	var req verifyRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid request body"})
+	}
+
+	result, err := h.u.VerifyMagicLink(c.Request().Context(), req.Token)
+	if errors.Is(err, domain.ErrInvalidToken) {
+		return c.JSON(http.StatusUnauthorized, map[string]string{"error": err.Error()})
+	}
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	}
+	return c.JSON(http.StatusOK, result)
+}
+
+// Logout は現在のセッションを無効にする
+func (h *AuthHandler) Logout(c echo.Context) error {
+	if err := h.u.Logout(c.Request().Context(), bearerToken(c)); err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+// Me はログイン中の利用者を返す
+func (h *AuthHandler) Me(c echo.Context) error {
+	return c.JSON(http.StatusOK, currentUser(c))
+}
+
+// RequireUser は Authorization: Bearer <token> のセッションを確認し、利用者をコンテキストに保存するミドルウェア
+func RequireUser(u usecase.AuthUsecase) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			user, err := u.Authenticate(c.Request().Context(), bearerToken(c))
+			if errors.Is(err, domain.ErrInvalidToken) {
+				c.Response().Header().Set(echo.HeaderWWWAuthenticate, "Bearer")
+				return c.JSON(http.StatusUnauthorized, map[string]string{"error": "Login required"})
+			}
+			if err != nil {
+				return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+			}
+			c.Set(contextKeyUser, user)
+			return next(c)
+		}
+	}
+}
+
+// RequireAdmin は管理者のメールアドレスでログインしている場合だけ通すミドルウェア (RequireUser の後段に置く)
+func RequireAdmin(adminEmails []string) echo.MiddlewareFunc {
+	admins := make(map[string]bool, len(adminEmails))
+	for _, email := range adminEmails {
+		admins[strings.ToLower(strings.TrimSpace(email))] = true
+	}
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			user := currentUser(c)
+			if user == nil || !admins[strings.ToLower(user.Email)] {
+				return c.JSON(http.StatusForbidden, map[string]string{"error": "Admin only"})
+			}
+			return next(c)
+		}
+	}
+}
+
+func currentUser(c echo.Context) *domain.User {
+	user, _ := c.Get(contextKeyUser).(*domain.User)
+	return user
+}
+
+func bearerToken(c echo.Context) string {
+	auth := c.Request().Header.Get(echo.HeaderAuthorization)
+	const prefix = "Bearer "
+	if len(auth) > len(prefix) && strings.EqualFold(auth[:len(prefix)], prefix) {
+		return strings.TrimSpace(auth[len(prefix):])
+	}
+	return ""
+}
@@ -0,0 +1,55 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gigaptera/hikkoshi-lens/backend/internal/domain"
+	"github.com/gigaptera/hikkoshi-lens/backend/internal/usecase"
+	"github.com/labstack/echo/v4"
+)
+
+type BudgetHandler struct {
+	u usecase.BudgetUsecase
+}
+
+func NewBudgetHandler(u usecase.BudgetUsecase) *BudgetHandler {
+	return &BudgetHandler{u: u}
+}
+
+// Advise は額面の月給 (と賞与・年齢・都道府県) から手取りと家賃の目安を返す
+func (h *BudgetHandler) Advise(c echo.Context) error {
+	in := domain.BudgetInput{
+		FirstYear: c.QueryParam("first_year") == "true" || c.QueryParam("first_year") == "1",
+		TaxYear:   c.QueryParam("tax_year"),
+	}
+	salary, err := strconv.Atoi(c.QueryParam("monthly_salary"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid monthly_salary"})
+	}
+	in.MonthlySalary = salary
+	for _, p := range []struct {
+		name string
+		dst  *int
+	}{{"annual_bonus", &in.AnnualBonus}, {"age", &in.Age}, {"prefecture_code", &in.PrefectureCode}} {
+		s := c.QueryParam(p.name)
+		if s == "" {
+			continue
+		}
+		v, err := strconv.Atoi(s)
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid " + p.name})
+		}
+		*p.dst = v
+	}
+
+	advice, err := h.u.Advise(c.Request().Context(), in)
+	switch {
+	case errors.Is(err, domain.ErrInvalidBudget), errors.Is(err, domain.ErrUnknownTaxYear):
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	case err != nil:
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	}
+	return c.JSON(http.StatusOK, advice)
+}
@@ -0,0 +1,48 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gigaptera/hikkoshi-lens/backend/internal/domain"
+	"github.com/gigaptera/hikkoshi-lens/backend/internal/domain/service"
+	"github.com/gigaptera/hikkoshi-lens/backend/internal/usecase"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func budgetRequest(query string) *httptest.ResponseRecorder {
+	h := NewBudgetHandler(usecase.NewBudgetUsecase(service.NewBudgetAdvisor(service.DefaultTaxTables())))
+	req := httptest.NewRequest(http.MethodGet, "/api/budget?"+query, nil)
+	rec := httptest.NewRecorder()
+	_ = h.Advise(echo.New().NewContext(req, rec))
+	return rec
+}
+
+func TestBudget_Advise(t *testing.T) {
+	rec := budgetRequest("monthly_salary=220000&prefecture_code=13&age=22&first_year=true")
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	var advice domain.BudgetAdvice
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &advice))
+	assert.Equal(t, 0, advice.TakeHome.ResidentTax)
+	require.Len(t, advice.RentRanges, len(domain.BudgetRules))
+	assert.Equal(t, "standard", advice.Recommended)
+	assert.Greater(t, advice.RentRanges[1].MaxRent, advice.RentRanges[1].MinRent)
+}
+
+func TestBudget_Invalid(t *testing.T) {
+	for _, q := range []string{
+		"",
+		"monthly_salary=abc",
+		"monthly_salary=0",
+		"monthly_salary=220000&age=x",
+		"monthly_salary=220000&prefecture_code=99",
+		"monthly_salary=220000&tax_year=1999",
+	} {
+		assert.Equal(t, http.StatusBadRequest, budgetRequest(q).Code, q)
+	}
+}
@@ -0,0 +1,68 @@
+package handler
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gigaptera/hikkoshi-lens/backend/internal/domain"
+	"github.com/gigaptera/hikkoshi-lens/backend/internal/usecase"
+	"github.com/labstack/echo/v4"
+)
+
+type CompareHandler struct {
+	u usecase.CompareUsecase
+}
+
+func NewCompareHandler(u usecase.CompareUsecase) *CompareHandler {
+	return &CompareHandler{u: u}
+}
+
+// Compare は ids (カンマ区切り) の駅を並べて比較するための一覧を返す
+// workplace_station_id を指定すると月額総額、move_in=true を指定すると初期費用を付ける
+func (h *CompareHandler) Compare(c echo.Context) error {
+	var ids []int64
+	for _, part := range strings.Split(c.QueryParam("ids"), ",") {
+		id, err := strconv.ParseInt(strings.TrimSpace(part), 10, 64)
+		if err != nil || id <= 0 {
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid ids"})
+		}
+		ids = append(ids, id)
+	}
+
+	commute, _, err := parseCommuteQuery(c)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+	moveIn, withMoveIn, err := parseMoveInQuery(c)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+
+	q := domain.CompareQuery{
+		Weights:      parseWeights(c),
+		BuildingType: c.QueryParam("building_type"),
+		Layout:       c.QueryParam("layout"),
+	}
+	if commute.WorkplaceStationID != 0 {
+		q.Commute = &commute
+	}
+	if withMoveIn {
+		q.MoveIn = &moveIn
+	}
+
+	stations, err := h.u.Compare(c.Request().Context(), ids, q)
+	switch {
+	case errors.Is(err, domain.ErrInvalidCompare):
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "ids must be 2 to 5 distinct stations"})
+	case errors.Is(err, domain.ErrInvalidMoveIn), errors.Is(err, domain.ErrInvalidCommute):
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	case errors.Is(err, sql.ErrNoRows):
+		return c.JSON(http.StatusNotFound, map[string]string{"error": "Station not found"})
+	case err != nil:
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	}
+	return c.JSON(http.StatusOK, stations)
+}
@@ -0,0 +1,82 @@
+package handler
+
+import (
+	"context"
+	"database/sql"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gigaptera/hikkoshi-lens/backend/internal/domain"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockCompareUsecase struct {
+	mock.Mock
+}
+
+func (m *MockCompareUsecase) Compare(ctx context.Context, ids []int64, q domain.CompareQuery) ([]*domain.Station, error) {
+	args := m.Called(ctx, ids, q)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.Station), args.Error(1)
+}
+
+func compareRequest(h *CompareHandler, query string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, "/api/stations/compare?"+query, nil)
+	rec := httptest.NewRecorder()
+	_ = h.Compare(echo.New().NewContext(req, rec))
+	return rec
+}
+
+func TestCompare_Success(t *testing.T) {
+	u := new(MockCompareUsecase)
+	h := NewCompareHandler(u)
+
+	u.On("Compare", mock.Anything, []int64{3, 1}, mock.MatchedBy(func(q domain.CompareQuery) bool {
+		return q.Layout == "1r_1k_1dk" && q.BuildingType == "mansion" && q.Weights["rent"] == 80 &&
+			q.Commute != nil && q.Commute.WorkplaceStationID == 10 &&
+			q.MoveIn != nil && q.MoveIn.HasOrigin && q.MoveIn.FromLat == 35.1
+	})).Return([]*domain.Station{
+		{ID: 3, MoveInCost: &domain.MoveInEstimate{Rent: 80000, Total: 400000}},
+		{ID: 1},
+	}, nil)
+
+	rec := compareRequest(h, "ids=3,1&building_type=mansion&layout=1r_1k_1dk&w_rent=80&workplace_station_id=10&move_in=true&from_lat=35.1&from_lon=139.0")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"move_in_cost":{"rent":80000`)
+	u.AssertExpectations(t)
+}
+
+func TestCompare_WithoutOptions(t *testing.T) {
+	u := new(MockCompareUsecase)
+	h := NewCompareHandler(u)
+
+	u.On("Compare", mock.Anything, []int64{1, 2}, mock.MatchedBy(func(q domain.CompareQuery) bool {
+		return q.Commute == nil && q.MoveIn == nil
+	})).Return([]*domain.Station{{ID: 1}, {ID: 2}}, nil)
+
+	assert.Equal(t, http.StatusOK, compareRequest(h, "ids=1,2").Code)
+	u.AssertExpectations(t)
+}
+
+func TestCompare_Errors(t *testing.T) {
+	u := new(MockCompareUsecase)
+	h := NewCompareHandler(u)
+
+	assert.Equal(t, http.StatusBadRequest, compareRequest(h, "ids=1,abc").Code)
+	assert.Equal(t, http.StatusBadRequest, compareRequest(h, "").Code)
+	assert.Equal(t, http.StatusBadRequest, compareRequest(h, "ids=1,2&move_in=true&from_lat=35").Code)
+
+	u.On("Compare", mock.Anything, []int64{1}, mock.Anything).Return(nil, domain.ErrInvalidCompare)
+	assert.Equal(t, http.StatusBadRequest, compareRequest(h, "ids=1").Code)
+
+	u.On("Compare", mock.Anything, []int64{1, 2}, mock.Anything).Return(nil, domain.ErrInvalidMoveIn)
+	assert.Equal(t, http.StatusBadRequest, compareRequest(h, "ids=1,2&move_in=true").Code)
+
+	u.On("Compare", mock.Anything, []int64{1, 99}, mock.Anything).Return(nil, sql.ErrNoRows)
+	assert.Equal(t, http.StatusNotFound, compareRequest(h, "ids=1,99").Code)
+}
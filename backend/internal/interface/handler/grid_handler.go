@@ -0,0 +1,57 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gigaptera/hikkoshi-lens/backend/internal/domain"
+	"github.com/gigaptera/hikkoshi-lens/backend/internal/usecase"
+	"github.com/labstack/echo/v4"
+)
+
+type GridHandler struct {
+	u usecase.GridUsecase
+}
+
+func NewGridHandler(u usecase.GridUsecase) *GridHandler {
+	return &GridHandler{u: u}
+}
+
+// GetHeatmap は bbox 内のグリッドセルを指標の値付きのGeoJSONで返す
+func (h *GridHandler) GetHeatmap(c echo.Context) error {
+	bbox, err := domain.ParseBBox(c.QueryParam("bbox"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid bbox: " + err.Error()})
+	}
+
+	metric := c.QueryParam("metric")
+	valid := false
+	for _, m := range domain.GridMetrics() {
+		if m == metric {
+			valid = true
+			break
+		}
+	}
+	if !valid {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid metric"})
+	}
+
+	grid := c.QueryParam("grid")
+	if grid == "" {
+		grid = domain.DefaultGrid
+	}
+	if _, err := domain.ParseGridSpec(grid); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid grid"})
+	}
+
+	fc, err := h.u.GetHeatmap(c.Request().Context(), grid, bbox, metric)
+	if errors.Is(err, usecase.ErrTooManyCells) {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	}
+
+	c.Response().Header().Set(echo.HeaderCacheControl, "public, max-age=300")
+	return geoJSON(c, fc)
+}
@@ -0,0 +1,97 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gigaptera/hikkoshi-lens/backend/internal/domain"
+	"github.com/gigaptera/hikkoshi-lens/backend/internal/usecase"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockGridUsecase はGridUsecaseのモック
+type MockGridUsecase struct {
+	mock.Mock
+}
+
+func (m *MockGridUsecase) GetHeatmap(ctx context.Context, grid string, bbox domain.BBox, metric string) (*domain.FeatureCollection, error) {
+	args := m.Called(ctx, grid, bbox, metric)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.FeatureCollection), args.Error(1)
+}
+
+func TestGetHeatmap_Success(t *testing.T) {
+	e := echo.New()
+	mockUsecase := new(MockGridUsecase)
+	handler := NewGridHandler(mockUsecase)
+
+	bbox := domain.BBox{MinLon: 139.6, MinLat: 35.6, MaxLon: 139.8, MaxLat: 35.7}
+	fc := domain.NewFeatureCollection()
+	fc.Features = append(fc.Features, &domain.Feature{
+		Type:       "Feature",
+		ID:         int64(1),
+		Geometry:   &domain.Geometry{Type: "Polygon", Coordinates: [][][]float64{{{139.6, 35.6}, {139.61, 35.6}, {139.6, 35.61}, {139.6, 35.6}}}},
+		Properties: map[string]interface{}{"metric": "rent_1r_1k_1dk", "value": 8.5},
+	})
+	mockUsecase.On("GetHeatmap", mock.Anything, "hex_1000", bbox, "rent_1r_1k_1dk").Return(fc, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/grid?bbox=139.6,35.6,139.8,35.7&metric=rent_1r_1k_1dk", nil)
+	rec := httptest.NewRecorder()
+	err := handler.GetHeatmap(e.NewContext(req, rec))
+
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "application/geo+json", rec.Header().Get(echo.HeaderContentType))
+	assert.Contains(t, rec.Body.String(), `"value":8.5`)
+	mockUsecase.AssertExpectations(t)
+}
+
+func TestGetHeatmap_InvalidParams(t *testing.T) {
+	tests := []struct {
+		name  string
+		query string
+		want  string
+	}{
+		{"bbox reversed", "bbox=139.8,35.6,139.6,35.7&metric=hazard_level", "Invalid bbox"},
+		{"bbox missing value", "bbox=139.6,35.6,139.8&metric=hazard_level", "Invalid bbox"},
+		{"unknown metric", "bbox=139.6,35.6,139.8,35.7&metric=crime", "Invalid metric"},
+		{"unknown grid", "bbox=139.6,35.6,139.8,35.7&metric=hazard_level&grid=tri_500", "Invalid grid"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := echo.New()
+			mockUsecase := new(MockGridUsecase)
+			handler := NewGridHandler(mockUsecase)
+
+			req := httptest.NewRequest(http.MethodGet, "/api/grid?"+tt.query, nil)
+			rec := httptest.NewRecorder()
+			err := handler.GetHeatmap(e.NewContext(req, rec))
+
+			assert.NoError(t, err)
+			assert.Equal(t, http.StatusBadRequest, rec.Code)
+			assert.Contains(t, rec.Body.String(), tt.want)
+			mockUsecase.AssertNotCalled(t, "GetHeatmap", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestGetHeatmap_TooManyCells(t *testing.T) {
+	e := echo.New()
+	mockUsecase := new(MockGridUsecase)
+	handler := NewGridHandler(mockUsecase)
+	mockUsecase.On("GetHeatmap", mock.Anything, "hex_500", mock.Anything, "facility_density").Return(nil, usecase.ErrTooManyCells)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/grid?bbox=122,24,146,46&metric=facility_density&grid=hex_500", nil)
+	rec := httptest.NewRecorder()
+	err := handler.GetHeatmap(e.NewContext(req, rec))
+
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Contains(t, rec.Body.String(), "too many grid cells")
+}
@@ -0,0 +1,54 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gigaptera/hikkoshi-lens/backend/internal/domain"
+	"github.com/gigaptera/hikkoshi-lens/backend/internal/usecase"
+	"github.com/labstack/echo/v4"
+)
+
+type IsochroneHandler struct {
+	u usecase.IsochroneUsecase
+}
+
+func NewIsochroneHandler(u usecase.IsochroneUsecase) *IsochroneHandler {
+	return &IsochroneHandler{u: u}
+}
+
+// GetIsochrones は勤務地から 30/45/60分 などで住める範囲をGeoJSONのPolygonで返す
+func (h *IsochroneHandler) GetIsochrones(c echo.Context) error {
+	lat, err := strconv.ParseFloat(c.QueryParam("lat"), 64)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid lat"})
+	}
+	lon, err := strconv.ParseFloat(c.QueryParam("lon"), 64)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid lon"})
+	}
+
+	bands := domain.DefaultIsochroneBands
+	if s := c.QueryParam("bands"); s != "" {
+		bands = nil
+		for _, part := range strings.Split(s, ",") {
+			b, err := strconv.Atoi(strings.TrimSpace(part))
+			if err != nil || b <= 0 || b > domain.MaxIsochroneMinutes {
+				return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid bands"})
+			}
+			bands = append(bands, b)
+		}
+		if len(bands) > domain.MaxIsochroneBands {
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": "Too many bands"})
+		}
+	}
+
+	fc, err := h.u.GetIsochrones(c.Request().Context(), lat, lon, bands)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	}
+
+	c.Response().Header().Set(echo.HeaderCacheControl, "public, max-age=300")
+	return geoJSON(c, fc)
+}
@@ -0,0 +1,100 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gigaptera/hikkoshi-lens/backend/internal/domain"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockIsochroneUsecase はIsochroneUsecaseのモック
+type MockIsochroneUsecase struct {
+	mock.Mock
+}
+
+func (m *MockIsochroneUsecase) GetIsochrones(ctx context.Context, lat, lon float64, bands []int) (*domain.FeatureCollection, error) {
+	args := m.Called(ctx, lat, lon, bands)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.FeatureCollection), args.Error(1)
+}
+
+func (m *MockIsochroneUsecase) Invalidate(table string) {
+	m.Called(table)
+}
+
+func TestGetIsochrones_Success(t *testing.T) {
+	e := echo.New()
+	mockUsecase := new(MockIsochroneUsecase)
+	handler := NewIsochroneHandler(mockUsecase)
+
+	fc := domain.NewFeatureCollection()
+	fc.Features = append(fc.Features, &domain.Feature{
+		Type:       "Feature",
+		ID:         45,
+		Geometry:   &domain.Geometry{Type: "Polygon", Coordinates: [][][]float64{{{139.7, 35.6}, {139.71, 35.6}, {139.7, 35.61}, {139.7, 35.6}}}},
+		Properties: map[string]interface{}{"minutes": 45, "station_count": 12},
+	})
+	mockUsecase.On("GetIsochrones", mock.Anything, 35.68, 139.76, []int{20, 45}).Return(fc, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/isochrones?lat=35.68&lon=139.76&bands=20,45", nil)
+	rec := httptest.NewRecorder()
+	err := handler.GetIsochrones(e.NewContext(req, rec))
+
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "application/geo+json", rec.Header().Get(echo.HeaderContentType))
+	assert.Contains(t, rec.Body.String(), `"station_count":12`)
+	mockUsecase.AssertExpectations(t)
+}
+
+func TestGetIsochrones_DefaultBands(t *testing.T) {
+	e := echo.New()
+	mockUsecase := new(MockIsochroneUsecase)
+	handler := NewIsochroneHandler(mockUsecase)
+
+	mockUsecase.On("GetIsochrones", mock.Anything, 35.68, 139.76, []int{30, 45, 60}).Return(domain.NewFeatureCollection(), nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/isochrones?lat=35.68&lon=139.76", nil)
+	rec := httptest.NewRecorder()
+	err := handler.GetIsochrones(e.NewContext(req, rec))
+
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	mockUsecase.AssertExpectations(t)
+}
+
+func TestGetIsochrones_InvalidParams(t *testing.T) {
+	tests := []struct {
+		name  string
+		query string
+		want  string
+	}{
+		{"missing lat", "lon=139.76", "Invalid lat"},
+		{"bad band", "lat=35.68&lon=139.76&bands=30,abc", "Invalid bands"},
+		{"band too long", "lat=35.68&lon=139.76&bands=180", "Invalid bands"},
+		{"too many bands", "lat=35.68&lon=139.76&bands=10,20,30,40,50", "Too many bands"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := echo.New()
+			mockUsecase := new(MockIsochroneUsecase)
+			handler := NewIsochroneHandler(mockUsecase)
+
+			req := httptest.NewRequest(http.MethodGet, "/api/isochrones?"+tt.query, nil)
+			rec := httptest.NewRecorder()
+			err := handler.GetIsochrones(e.NewContext(req, rec))
+
+			assert.NoError(t, err)
+			assert.Equal(t, http.StatusBadRequest, rec.Code)
+			assert.Contains(t, rec.Body.String(), tt.want)
+			mockUsecase.AssertNotCalled(t, "GetIsochrones")
+		})
+	}
+}
@@ -0,0 +1,36 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gigaptera/hikkoshi-lens/backend/internal/infrastructure/cache"
+	"github.com/labstack/echo/v4"
+)
+
+// CacheStatsProvider はキャッシュ統計を返すコンポーネント
+type CacheStatsProvider interface {
+	Stats() cache.Stats
+}
+
+type MetricsHandler struct {
+	caches map[string]CacheStatsProvider
+}
+
+func NewMetricsHandler(caches map[string]CacheStatsProvider) *MetricsHandler {
+	return &MetricsHandler{caches: caches}
+}
+
+type cacheMetrics struct {
+	cache.Stats
+	HitRatio float64 `json:"hit_ratio"`
+}
+
+// CacheStats はキャッシュごとのヒット数・ミス数・ヒット率を返す
+func (h *MetricsHandler) CacheStats(c echo.Context) error {
+	res := make(map[string]cacheMetrics, len(h.caches))
+	for name, p := range h.caches {
+		s := p.Stats()
+		res[name] = cacheMetrics{Stats: s, HitRatio: s.HitRatio()}
+	}
+	return c.JSON(http.StatusOK, res)
+}
@@ -0,0 +1,21 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gigaptera/hikkoshi-lens/backend/internal/interface/openapi"
+	"github.com/labstack/echo/v4"
+)
+
+type OpenAPIHandler struct {
+	spec *openapi.Spec
+}
+
+func NewOpenAPIHandler(spec *openapi.Spec) *OpenAPIHandler {
+	return &OpenAPIHandler{spec: spec}
+}
+
+// Spec は埋め込みのOpenAPI仕様書をそのまま返す
+func (h *OpenAPIHandler) Spec(c echo.Context) error {
+	return c.JSONBlob(http.StatusOK, h.spec.Raw())
+}
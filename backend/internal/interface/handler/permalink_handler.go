@@ -0,0 +1,65 @@
+package handler
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gigaptera/hikkoshi-lens/backend/internal/domain"
+	"github.com/gigaptera/hikkoshi-lens/backend/internal/usecase"
+	"github.com/labstack/echo/v4"
+)
+
+// PermalinkHandler は検索条件の共有リンク (ログイン不要)
+type PermalinkHandler struct {
+	u usecase.PermalinkUsecase
+}
+
+func NewPermalinkHandler(u usecase.PermalinkUsecase) *PermalinkHandler {
+	return &PermalinkHandler{u: u}
+}
+
+type permalinkRequest struct {
+	Lat           float64              `json:"lat"`
+	Lon           float64              `json:"lon"`
+	Filter        domain.StationFilter `json:"filter"`
+	ExpiresInDays int                  `json:"expires_in_days"` // 0 なら30日
+}
+
+func (h *PermalinkHandler) Create(c echo.Context) error {
+	var req permalinkRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid request body"})
+	}
+	ttl := time.Duration(req.ExpiresInDays) * 24 * time.Hour
+	link, err := h.u.Create(c.Request().Context(), req.Lat, req.Lon, req.Filter, ttl)
+	if err != nil {
+		return permalinkError(c, err)
+	}
+	return c.JSON(http.StatusCreated, link)
+}
+
+// Resolve は保存した検索条件と、その条件で検索し直した駅を返す (開くたびに閲覧数が増える)
+func (h *PermalinkHandler) Resolve(c echo.Context) error {
+	result, err := h.u.Resolve(c.Request().Context(), c.Param("code"))
+	if err != nil {
+		return permalinkError(c, err)
+	}
+	// 閲覧数を数えるため共有キャッシュには載せない
+	c.Response().Header().Set("Cache-Control", "no-store")
+	return c.JSON(http.StatusOK, result)
+}
+
+func permalinkError(c echo.Context, err error) error {
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		return c.JSON(http.StatusNotFound, map[string]string{"error": "Not found"})
+	case errors.Is(err, domain.ErrPermalinkExpired):
+		return c.JSON(http.StatusGone, map[string]string{"error": "This link has expired"})
+	case errors.Is(err, usecase.ErrInvalidPermalink):
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	default:
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	}
+}
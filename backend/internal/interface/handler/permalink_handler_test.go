@@ -0,0 +1,89 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gigaptera/hikkoshi-lens/backend/internal/domain"
+	"github.com/gigaptera/hikkoshi-lens/backend/internal/usecase"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockPermalinkUsecase はPermalinkUsecaseのモック
+type MockPermalinkUsecase struct {
+	mock.Mock
+}
+
+func (m *MockPermalinkUsecase) Create(ctx context.Context, lat, lon float64, filter domain.StationFilter, ttl time.Duration) (*domain.SearchPermalink, error) {
+	args := m.Called(ctx, lat, lon, filter, ttl)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.SearchPermalink), args.Error(1)
+}
+
+func (m *MockPermalinkUsecase) Resolve(ctx context.Context, code string) (*usecase.PermalinkResult, error) {
+	args := m.Called(ctx, code)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*usecase.PermalinkResult), args.Error(1)
+}
+
+func newPermalinkServer(u *MockPermalinkUsecase) *echo.Echo {
+	e := echo.New()
+	h := NewPermalinkHandler(u)
+	e.POST("/api/permalinks", h.Create)
+	e.GET("/api/permalinks/:code", h.Resolve)
+	return e
+}
+
+func TestCreatePermalink(t *testing.T) {
+	u := new(MockPermalinkUsecase)
+	e := newPermalinkServer(u)
+
+	filter := domain.StationFilter{RadiusMeter: 800, Layout: "1ldk", Weights: map[string]int{"access": 90}}
+	u.On("Create", mock.Anything, 35.658, 139.7016, filter, 7*24*time.Hour).
+		Return(&domain.SearchPermalink{Code: "a1B2c3D4", URL: "http://localhost:3000/s/a1B2c3D4"}, nil)
+
+	body := `{"lat":35.658,"lon":139.7016,"filter":{"radius_meter":800,"layout":"1ldk","weights":{"access":90}},"expires_in_days":7}`
+	req := httptest.NewRequest(http.MethodPost, "/api/permalinks", strings.NewReader(body))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusCreated, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"code":"a1B2c3D4"`)
+	assert.Contains(t, rec.Body.String(), `"url":"http://localhost:3000/s/a1B2c3D4"`)
+	u.AssertExpectations(t)
+}
+
+func TestResolvePermalink(t *testing.T) {
+	u := new(MockPermalinkUsecase)
+	e := newPermalinkServer(u)
+	u.On("Resolve", mock.Anything, "a1B2c3D4").Return(&usecase.PermalinkResult{
+		Permalink: &domain.SearchPermalink{Code: "a1B2c3D4", ViewCount: 3},
+		Stations:  []*domain.Station{{ID: 10, Name: "池尻大橋"}},
+	}, nil)
+	u.On("Resolve", mock.Anything, "expired0").Return(nil, domain.ErrPermalinkExpired)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/permalinks/a1B2c3D4", nil)
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "no-store", rec.Header().Get("Cache-Control"))
+	assert.Contains(t, rec.Body.String(), `"view_count":3`)
+	assert.Contains(t, rec.Body.String(), `"name":"池尻大橋"`)
+
+	req = httptest.NewRequest(http.MethodGet, "/api/permalinks/expired0", nil)
+	rec = httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusGone, rec.Code)
+}
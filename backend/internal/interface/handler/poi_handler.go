@@ -0,0 +1,63 @@
+package handler
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gigaptera/hikkoshi-lens/backend/internal/domain"
+	"github.com/gigaptera/hikkoshi-lens/backend/internal/usecase"
+	"github.com/labstack/echo/v4"
+)
+
+// GeoJSONのContent-Type (RFC 7946)
+const mimeGeoJSON = "application/geo+json"
+
+type POIHandler struct {
+	u usecase.POIUsecase
+}
+
+func NewPOIHandler(u usecase.POIUsecase) *POIHandler {
+	return &POIHandler{u: u}
+}
+
+// GetStationPOIs は駅周辺の施設をカテゴリ別に返す
+// format=geojson の場合は地図レイヤー用にGeoJSONのFeatureCollectionを返す
+func (h *POIHandler) GetStationPOIs(c echo.Context) error {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid station ID"})
+	}
+
+	filter := domain.POIFilter{RadiusMeter: 800, Limit: 50}
+	if r, err := strconv.Atoi(c.QueryParam("radius")); err == nil && r > 0 {
+		filter.RadiusMeter = r
+	}
+	if l, err := strconv.Atoi(c.QueryParam("limit")); err == nil && l > 0 {
+		filter.Limit = l
+	}
+	if categories := c.QueryParam("categories"); categories != "" {
+		filter.Categories = strings.Split(categories, ",")
+	}
+
+	result, err := h.u.GetStationPOIs(c.Request().Context(), id, filter)
+	if errors.Is(err, sql.ErrNoRows) {
+		return c.JSON(http.StatusNotFound, map[string]string{"error": "Station not found"})
+	}
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	}
+
+	if c.QueryParam("format") == "geojson" {
+		return geoJSON(c, result.FeatureCollection())
+	}
+	return c.JSON(http.StatusOK, result)
+}
+
+func geoJSON(c echo.Context, fc *domain.FeatureCollection) error {
+	c.Response().Header().Set(echo.HeaderContentType, mimeGeoJSON)
+	c.Response().WriteHeader(http.StatusOK)
+	return c.Echo().JSONSerializer.Serialize(c, fc, "")
+}
@@ -0,0 +1,124 @@
+package handler
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gigaptera/hikkoshi-lens/backend/internal/domain"
+	"github.com/gigaptera/hikkoshi-lens/backend/internal/usecase"
+	"github.com/labstack/echo/v4"
+)
+
+// ReviewHandler は駅の口コミの投稿・一覧と、管理者による審査 (/api/admin 配下、RequireAdmin の後段)
+type ReviewHandler struct {
+	u usecase.ReviewUsecase
+}
+
+func NewReviewHandler(u usecase.ReviewUsecase) *ReviewHandler {
+	return &ReviewHandler{u: u}
+}
+
+type moderateReviewRequest struct {
+	Status string `json:"status"`
+	Note   string `json:"note"`
+}
+
+// publicReview は一般に公開する口コミ (審査のメモ・注意点は含めない)
+type publicReview struct {
+	ID        int64          `json:"id"`
+	Ratings   map[string]int `json:"ratings"`
+	Positive  string         `json:"positive"`
+	Negative  string         `json:"negative"`
+	CreatedAt time.Time      `json:"created_at"`
+}
+
+// Submit は口コミを投稿する。審査で承認されるまでは公開されない
+func (h *ReviewHandler) Submit(c echo.Context) error {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid station ID"})
+	}
+	var req domain.ReviewInput
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid request body"})
+	}
+
+	review, err := h.u.Submit(c.Request().Context(), id, req, c.RealIP())
+	if err != nil {
+		return reviewError(c, err)
+	}
+	return c.JSON(http.StatusAccepted, map[string]interface{}{
+		"id":     review.ID,
+		"status": review.Status,
+	})
+}
+
+// List は駅の承認済み口コミと集計を返す
+func (h *ReviewHandler) List(c echo.Context) error {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid station ID"})
+	}
+	limit, _ := strconv.Atoi(c.QueryParam("limit"))
+
+	result, err := h.u.List(c.Request().Context(), id, limit)
+	if err != nil {
+		return reviewError(c, err)
+	}
+	reviews := make([]publicReview, len(result.Reviews))
+	for i, r := range result.Reviews {
+		reviews[i] = publicReview{ID: r.ID, Ratings: r.Ratings, Positive: r.Positive, Negative: r.Negative, CreatedAt: r.CreatedAt}
+	}
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"summary": result.Summary,
+		"reviews": reviews,
+	})
+}
+
+// Queue は審査待ち (status 指定時はその状態) の口コミを古い順に返す
+func (h *ReviewHandler) Queue(c echo.Context) error {
+	limit, _ := strconv.Atoi(c.QueryParam("limit"))
+	offset, _ := strconv.Atoi(c.QueryParam("offset"))
+
+	reviews, err := h.u.Queue(c.Request().Context(), c.QueryParam("status"), limit, offset)
+	if err != nil {
+		return reviewError(c, err)
+	}
+	return c.JSON(http.StatusOK, reviews)
+}
+
+// Moderate は口コミを承認・却下する
+func (h *ReviewHandler) Moderate(c echo.Context) error {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid review ID"})
+	}
+	var req moderateReviewRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid request body"})
+	}
+
+	review, err := h.u.Moderate(c.Request().Context(), id, req.Status, req.Note)
+	if err != nil {
+		return reviewError(c, err)
+	}
+	return c.JSON(http.StatusOK, review)
+}
+
+func reviewError(c echo.Context, err error) error {
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		return c.JSON(http.StatusNotFound, map[string]string{"error": "Not found"})
+	case errors.Is(err, domain.ErrInvalidReview), errors.Is(err, usecase.ErrInvalidModeration):
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	case errors.Is(err, domain.ErrReviewRejected):
+		return c.JSON(http.StatusUnprocessableEntity, map[string]string{"error": "Review contains prohibited content"})
+	case errors.Is(err, usecase.ErrReviewRateLimited):
+		return c.JSON(http.StatusTooManyRequests, map[string]string{"error": err.Error()})
+	default:
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	}
+}
@@ -0,0 +1,151 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gigaptera/hikkoshi-lens/backend/internal/domain"
+	"github.com/gigaptera/hikkoshi-lens/backend/internal/usecase"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockReviewUsecase はReviewUsecaseのモック (テストで使うメソッドのみ)
+type MockReviewUsecase struct {
+	usecase.ReviewUsecase
+	mock.Mock
+}
+
+func (m *MockReviewUsecase) Submit(ctx context.Context, stationID int64, in domain.ReviewInput, client string) (*domain.Review, error) {
+	args := m.Called(ctx, stationID, in, client)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Review), args.Error(1)
+}
+
+func (m *MockReviewUsecase) List(ctx context.Context, stationID int64, limit int) (*usecase.StationReviews, error) {
+	args := m.Called(ctx, stationID, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*usecase.StationReviews), args.Error(1)
+}
+
+func (m *MockReviewUsecase) Moderate(ctx context.Context, id int64, status, note string) (*domain.Review, error) {
+	args := m.Called(ctx, id, status, note)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Review), args.Error(1)
+}
+
+func newReviewServer(u *MockReviewUsecase) *echo.Echo {
+	auth := new(MockAuthUsecase)
+	auth.On("Authenticate", mock.Anything, "admin").Return(&domain.User{ID: 1, Email: "Admin@example.com"}, nil)
+	auth.On("Authenticate", mock.Anything, "tok").Return(&domain.User{ID: 7, Email: "user@example.com"}, nil)
+	auth.On("Authenticate", mock.Anything, "").Return(nil, domain.ErrInvalidToken)
+
+	e := echo.New()
+	e.IPExtractor = echo.ExtractIPDirect()
+	h := NewReviewHandler(u)
+	e.POST("/api/stations/:id/reviews", h.Submit)
+	e.GET("/api/stations/:id/reviews", h.List)
+	admin := e.Group("/api/admin", RequireUser(auth), RequireAdmin([]string{"admin@example.com"}))
+	admin.PATCH("/reviews/:id", h.Moderate)
+	return e
+}
+
+func TestSubmitReview(t *testing.T) {
+	u := new(MockReviewUsecase)
+	e := newReviewServer(u)
+
+	u.On("Submit", mock.Anything, int64(3), domain.ReviewInput{
+		Ratings:  map[string]int{"safety": 4},
+		Positive: "夜も明るい",
+	}, "192.0.2.10").Return(&domain.Review{ID: 11, Status: domain.ReviewStatusPending}, nil)
+
+	req := httptest.NewRequest(http.MethodPost, "/api/stations/3/reviews", strings.NewReader(`{"ratings":{"safety":4},"positive":"夜も明るい"}`))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	req.Header.Set("X-Forwarded-For", "198.51.100.99") // 直接の接続元を使う
+	req.RemoteAddr = "192.0.2.10:51234"
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusAccepted, rec.Code)
+	assert.JSONEq(t, `{"id":11,"status":"pending"}`, rec.Body.String())
+	u.AssertExpectations(t)
+}
+
+func TestSubmitReview_Errors(t *testing.T) {
+	cases := []struct {
+		err  error
+		code int
+	}{
+		{fmt.Errorf("%w: rating must be 1-5", domain.ErrInvalidReview), http.StatusBadRequest},
+		{domain.ErrReviewRejected, http.StatusUnprocessableEntity},
+		{usecase.ErrReviewRateLimited, http.StatusTooManyRequests},
+	}
+	for _, c := range cases {
+		u := new(MockReviewUsecase)
+		e := newReviewServer(u)
+		u.On("Submit", mock.Anything, int64(3), mock.Anything, mock.Anything).Return(nil, c.err)
+
+		req := httptest.NewRequest(http.MethodPost, "/api/stations/3/reviews", strings.NewReader(`{"ratings":{"safety":4},"positive":"x"}`))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+
+		assert.Equal(t, c.code, rec.Code, c.err.Error())
+	}
+}
+
+func TestListReviews_HidesModeration(t *testing.T) {
+	u := new(MockReviewUsecase)
+	e := newReviewServer(u)
+	u.On("List", mock.Anything, int64(3), 10).Return(&usecase.StationReviews{
+		Summary: &domain.ReviewSummary{Count: 1, Ratings: map[string]float64{"safety": 4}, Overall: 4},
+		Reviews: []*domain.Review{{ID: 11, Positive: "夜も明るい", Status: domain.ReviewStatusApproved, ModerationNote: "内部メモ", Flags: []string{"url"}}},
+	}, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/stations/3/reviews?limit=10", nil)
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"positive":"夜も明るい"`)
+	assert.Contains(t, rec.Body.String(), `"count":1`)
+	assert.NotContains(t, rec.Body.String(), "内部メモ")
+	assert.NotContains(t, rec.Body.String(), "flags")
+}
+
+func TestModerateReview_AdminOnly(t *testing.T) {
+	u := new(MockReviewUsecase)
+	e := newReviewServer(u)
+	u.On("Moderate", mock.Anything, int64(11), domain.ReviewStatusApproved, "ok").
+		Return(&domain.Review{ID: 11, Status: domain.ReviewStatusApproved}, nil)
+
+	for _, c := range []struct {
+		token string
+		code  int
+	}{
+		{"", http.StatusUnauthorized},
+		{"tok", http.StatusForbidden},
+		{"admin", http.StatusOK},
+	} {
+		req := httptest.NewRequest(http.MethodPatch, "/api/admin/reviews/11", strings.NewReader(`{"status":"approved","note":"ok"}`))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		if c.token != "" {
+			req.Header.Set(echo.HeaderAuthorization, "Bearer "+c.token)
+		}
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		assert.Equal(t, c.code, rec.Code, c.token)
+	}
+	u.AssertNumberOfCalls(t, "Moderate", 1)
+}
@@ -0,0 +1,96 @@
+package handler
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gigaptera/hikkoshi-lens/backend/internal/domain"
+	"github.com/gigaptera/hikkoshi-lens/backend/internal/usecase"
+	"github.com/labstack/echo/v4"
+)
+
+// SavedSearchHandler はログイン中の利用者の保存した検索条件 (/api/me 配下、RequireUser の後段)
+type SavedSearchHandler struct {
+	u usecase.SavedSearchUsecase
+}
+
+func NewSavedSearchHandler(u usecase.SavedSearchUsecase) *SavedSearchHandler {
+	return &SavedSearchHandler{u: u}
+}
+
+type savedSearchRequest struct {
+	Name          string               `json:"name"`
+	Lat           float64              `json:"lat"`
+	Lon           float64              `json:"lon"`
+	Filter        domain.StationFilter `json:"filter"`
+	RentThreshold float64              `json:"rent_threshold"`
+	Channel       string               `json:"channel"`
+	WebhookURL    string               `json:"webhook_url"`
+}
+
+func (h *SavedSearchHandler) List(c echo.Context) error {
+	searches, err := h.u.List(c.Request().Context(), currentUser(c).ID)
+	if err != nil {
+		return savedSearchError(c, err)
+	}
+	return c.JSON(http.StatusOK, searches)
+}
+
+func (h *SavedSearchHandler) Create(c echo.Context) error {
+	var req savedSearchRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid request body"})
+	}
+	search, err := h.u.Create(c.Request().Context(), currentUser(c).ID, &domain.SavedSearch{
+		Name:          req.Name,
+		Lat:           req.Lat,
+		Lon:           req.Lon,
+		Filter:        req.Filter,
+		RentThreshold: req.RentThreshold,
+		Channel:       req.Channel,
+		WebhookURL:    req.WebhookURL,
+	})
+	if err != nil {
+		return savedSearchError(c, err)
+	}
+	return c.JSON(http.StatusCreated, search)
+}
+
+// Get は検索条件を前回の通知ジョブ実行時の結果付きで返す
+func (h *SavedSearchHandler) Get(c echo.Context) error {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid saved search ID"})
+	}
+	search, err := h.u.Get(c.Request().Context(), currentUser(c).ID, id)
+	if err != nil {
+		return savedSearchError(c, err)
+	}
+	return c.JSON(http.StatusOK, search)
+}
+
+func (h *SavedSearchHandler) Delete(c echo.Context) error {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid saved search ID"})
+	}
+	if err := h.u.Delete(c.Request().Context(), currentUser(c).ID, id); err != nil {
+		return savedSearchError(c, err)
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+func savedSearchError(c echo.Context, err error) error {
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		return c.JSON(http.StatusNotFound, map[string]string{"error": "Not found"})
+	case errors.Is(err, usecase.ErrInvalidSavedSearch):
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	case errors.Is(err, usecase.ErrTooManySavedSearches):
+		return c.JSON(http.StatusConflict, map[string]string{"error": err.Error()})
+	default:
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	}
+}
@@ -0,0 +1,107 @@
+package handler
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gigaptera/hikkoshi-lens/backend/internal/domain"
+	"github.com/gigaptera/hikkoshi-lens/backend/internal/usecase"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockSavedSearchUsecase はSavedSearchUsecaseのモック (テストで使うメソッドのみ)
+type MockSavedSearchUsecase struct {
+	usecase.SavedSearchUsecase
+	mock.Mock
+}
+
+func (m *MockSavedSearchUsecase) Create(ctx context.Context, userID int64, search *domain.SavedSearch) (*domain.SavedSearch, error) {
+	args := m.Called(ctx, userID, search)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.SavedSearch), args.Error(1)
+}
+
+func (m *MockSavedSearchUsecase) Get(ctx context.Context, userID, id int64) (*domain.SavedSearch, error) {
+	args := m.Called(ctx, userID, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.SavedSearch), args.Error(1)
+}
+
+func newSavedSearchServer(u *MockSavedSearchUsecase) *echo.Echo {
+	auth := new(MockAuthUsecase)
+	auth.On("Authenticate", mock.Anything, "tok").Return(&domain.User{ID: 7}, nil)
+
+	e := echo.New()
+	h := NewSavedSearchHandler(u)
+	me := e.Group("/api/me", RequireUser(auth))
+	me.POST("/saved-searches", h.Create)
+	me.GET("/saved-searches/:id", h.Get)
+	return e
+}
+
+func TestCreateSavedSearch(t *testing.T) {
+	u := new(MockSavedSearchUsecase)
+	e := newSavedSearchServer(u)
+
+	body := `{"name":"渋谷通勤","lat":35.658,"lon":139.7016,"filter":{"max_rent":10,"building_type":"mansion","layout":"1r_1k_1dk"},"channel":"email"}`
+	u.On("Create", mock.Anything, int64(7), mock.MatchedBy(func(s *domain.SavedSearch) bool {
+		return s.Name == "渋谷通勤" && s.Filter.MaxRent == 10 && s.Filter.Layout == "1r_1k_1dk" && s.Channel == "email"
+	})).Return(&domain.SavedSearch{ID: 5, Name: "渋谷通勤", RentThreshold: 0.3}, nil)
+
+	req := httptest.NewRequest(http.MethodPost, "/api/me/saved-searches", strings.NewReader(body))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	req.Header.Set(echo.HeaderAuthorization, "Bearer tok")
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusCreated, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"id":5`)
+	u.AssertExpectations(t)
+}
+
+func TestCreateSavedSearch_Errors(t *testing.T) {
+	cases := []struct {
+		err  error
+		code int
+	}{
+		{fmt.Errorf("%w: name must be 1-100 characters", usecase.ErrInvalidSavedSearch), http.StatusBadRequest},
+		{usecase.ErrTooManySavedSearches, http.StatusConflict},
+	}
+	for _, tc := range cases {
+		u := new(MockSavedSearchUsecase)
+		e := newSavedSearchServer(u)
+		u.On("Create", mock.Anything, int64(7), mock.Anything).Return(nil, tc.err)
+
+		req := httptest.NewRequest(http.MethodPost, "/api/me/saved-searches", strings.NewReader(`{"name":""}`))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		req.Header.Set(echo.HeaderAuthorization, "Bearer tok")
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+
+		assert.Equal(t, tc.code, rec.Code, tc.err.Error())
+	}
+}
+
+func TestGetSavedSearch_NotFound(t *testing.T) {
+	u := new(MockSavedSearchUsecase)
+	e := newSavedSearchServer(u)
+	u.On("Get", mock.Anything, int64(7), int64(99)).Return(nil, sql.ErrNoRows)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/me/saved-searches/99", nil)
+	req.Header.Set(echo.HeaderAuthorization, "Bearer tok")
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
@@ -0,0 +1,181 @@
+package handler
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gigaptera/hikkoshi-lens/backend/internal/domain"
+	"github.com/gigaptera/hikkoshi-lens/backend/internal/usecase"
+	"github.com/labstack/echo/v4"
+)
+
+// ShortlistHandler はログイン中の利用者のお気に入りと候補リスト (/api/me 配下、RequireUser の後段)
+type ShortlistHandler struct {
+	u usecase.ShortlistUsecase
+}
+
+func NewShortlistHandler(u usecase.ShortlistUsecase) *ShortlistHandler {
+	return &ShortlistHandler{u: u}
+}
+
+type noteRequest struct {
+	Note string `json:"note"`
+}
+
+type shortlistRequest struct {
+	Name string `json:"name"`
+}
+
+func (h *ShortlistHandler) ListFavorites(c echo.Context) error {
+	favorites, err := h.u.ListFavorites(c.Request().Context(), currentUser(c).ID, listFilter(c))
+	if err != nil {
+		return shortlistError(c, err)
+	}
+	return c.JSON(http.StatusOK, favorites)
+}
+
+func (h *ShortlistHandler) SaveFavorite(c echo.Context) error {
+	stationID, err := strconv.ParseInt(c.Param("station_id"), 10, 64)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid station ID"})
+	}
+	var req noteRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid request body"})
+	}
+
+	fav, err := h.u.SaveFavorite(c.Request().Context(), currentUser(c).ID, stationID, req.Note)
+	if err != nil {
+		return shortlistError(c, err)
+	}
+	return c.JSON(http.StatusOK, fav)
+}
+
+func (h *ShortlistHandler) DeleteFavorite(c echo.Context) error {
+	stationID, err := strconv.ParseInt(c.Param("station_id"), 10, 64)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid station ID"})
+	}
+	if err := h.u.DeleteFavorite(c.Request().Context(), currentUser(c).ID, stationID); err != nil {
+		return shortlistError(c, err)
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+func (h *ShortlistHandler) ListShortlists(c echo.Context) error {
+	lists, err := h.u.ListShortlists(c.Request().Context(), currentUser(c).ID)
+	if err != nil {
+		return shortlistError(c, err)
+	}
+	return c.JSON(http.StatusOK, lists)
+}
+
+func (h *ShortlistHandler) CreateShortlist(c echo.Context) error {
+	var req shortlistRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid request body"})
+	}
+	list, err := h.u.CreateShortlist(c.Request().Context(), currentUser(c).ID, req.Name)
+	if err != nil {
+		return shortlistError(c, err)
+	}
+	return c.JSON(http.StatusCreated, list)
+}
+
+// GetShortlist は候補リストを駅の現在のスコア・家賃相場付きで返す
+func (h *ShortlistHandler) GetShortlist(c echo.Context) error {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid shortlist ID"})
+	}
+	list, err := h.u.GetShortlist(c.Request().Context(), currentUser(c).ID, id, listFilter(c))
+	if err != nil {
+		return shortlistError(c, err)
+	}
+	return c.JSON(http.StatusOK, list)
+}
+
+func (h *ShortlistHandler) RenameShortlist(c echo.Context) error {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid shortlist ID"})
+	}
+	var req shortlistRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid request body"})
+	}
+	list, err := h.u.RenameShortlist(c.Request().Context(), currentUser(c).ID, id, req.Name)
+	if err != nil {
+		return shortlistError(c, err)
+	}
+	return c.JSON(http.StatusOK, list)
+}
+
+func (h *ShortlistHandler) DeleteShortlist(c echo.Context) error {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid shortlist ID"})
+	}
+	if err := h.u.DeleteShortlist(c.Request().Context(), currentUser(c).ID, id); err != nil {
+		return shortlistError(c, err)
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+func (h *ShortlistHandler) SaveShortlistItem(c echo.Context) error {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid shortlist ID"})
+	}
+	stationID, err := strconv.ParseInt(c.Param("station_id"), 10, 64)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid station ID"})
+	}
+	var req noteRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid request body"})
+	}
+
+	item, err := h.u.SaveShortlistItem(c.Request().Context(), currentUser(c).ID, id, stationID, req.Note)
+	if err != nil {
+		return shortlistError(c, err)
+	}
+	return c.JSON(http.StatusOK, item)
+}
+
+func (h *ShortlistHandler) DeleteShortlistItem(c echo.Context) error {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid shortlist ID"})
+	}
+	stationID, err := strconv.ParseInt(c.Param("station_id"), 10, 64)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid station ID"})
+	}
+	if err := h.u.DeleteShortlistItem(c.Request().Context(), currentUser(c).ID, id, stationID); err != nil {
+		return shortlistError(c, err)
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+// listFilter は一覧の駅に付けるスコアの重みと家賃相場の条件を読む
+func listFilter(c echo.Context) domain.StationFilter {
+	return domain.StationFilter{
+		BuildingType: c.QueryParam("building_type"),
+		Layout:       c.QueryParam("layout"),
+		Weights:      parseWeights(c),
+	}
+}
+
+func shortlistError(c echo.Context, err error) error {
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		return c.JSON(http.StatusNotFound, map[string]string{"error": "Not found"})
+	case errors.Is(err, usecase.ErrInvalidShortlistName), errors.Is(err, usecase.ErrNoteTooLong):
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	default:
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	}
+}
@@ -0,0 +1,173 @@
+package handler
+
+import (
+	"context"
+	"database/sql"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gigaptera/hikkoshi-lens/backend/internal/domain"
+	"github.com/gigaptera/hikkoshi-lens/backend/internal/usecase"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockAuthUsecase はAuthUsecaseのモック
+type MockAuthUsecase struct {
+	mock.Mock
+}
+
+func (m *MockAuthUsecase) RequestMagicLink(ctx context.Context, email, client string) error {
+	return m.Called(ctx, email, client).Error(0)
+}
+
+func (m *MockAuthUsecase) VerifyMagicLink(ctx context.Context, token string) (*usecase.LoginResult, error) {
+	args := m.Called(ctx, token)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*usecase.LoginResult), args.Error(1)
+}
+
+func (m *MockAuthUsecase) Authenticate(ctx context.Context, sessionToken string) (*domain.User, error) {
+	args := m.Called(ctx, sessionToken)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.User), args.Error(1)
+}
+
+func (m *MockAuthUsecase) Logout(ctx context.Context, sessionToken string) error {
+	return m.Called(ctx, sessionToken).Error(0)
+}
+
+// MockShortlistUsecase はShortlistUsecaseのモック (テストで使うメソッドのみ)
+type MockShortlistUsecase struct {
+	usecase.ShortlistUsecase
+	mock.Mock
+}
+
+func (m *MockShortlistUsecase) GetShortlist(ctx context.Context, userID, shortlistID int64, filter domain.StationFilter) (*domain.Shortlist, error) {
+	args := m.Called(ctx, userID, shortlistID, filter)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Shortlist), args.Error(1)
+}
+
+func (m *MockShortlistUsecase) CreateShortlist(ctx context.Context, userID int64, name string) (*domain.Shortlist, error) {
+	args := m.Called(ctx, userID, name)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Shortlist), args.Error(1)
+}
+
+// newMeServer は RequireUser を通した /me のルートだけを持つサーバーを返す
+func newMeServer(auth *MockAuthUsecase, u *MockShortlistUsecase) *echo.Echo {
+	e := echo.New()
+	h := NewShortlistHandler(u)
+	me := e.Group("/api/me", RequireUser(auth))
+	me.GET("", NewAuthHandler(auth).Me)
+	me.POST("/shortlists", h.CreateShortlist)
+	me.GET("/shortlists/:id", h.GetShortlist)
+	return e
+}
+
+func TestRequireUser_Unauthorized(t *testing.T) {
+	auth := new(MockAuthUsecase)
+	auth.On("Authenticate", mock.Anything, "").Return(nil, domain.ErrInvalidToken)
+	e := newMeServer(auth, new(MockShortlistUsecase))
+
+	req := httptest.NewRequest(http.MethodGet, "/api/me", nil)
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+	assert.Equal(t, "Bearer", rec.Header().Get(echo.HeaderWWWAuthenticate))
+}
+
+func TestRequireUser_Me(t *testing.T) {
+	auth := new(MockAuthUsecase)
+	auth.On("Authenticate", mock.Anything, "session-token").Return(&domain.User{ID: 7, Email: "taro@example.com"}, nil)
+	e := newMeServer(auth, new(MockShortlistUsecase))
+
+	req := httptest.NewRequest(http.MethodGet, "/api/me", nil)
+	req.Header.Set(echo.HeaderAuthorization, "Bearer session-token")
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"email":"taro@example.com"`)
+}
+
+func TestGetShortlist_WithScoresAndRent(t *testing.T) {
+	auth := new(MockAuthUsecase)
+	auth.On("Authenticate", mock.Anything, "tok").Return(&domain.User{ID: 7}, nil)
+	u := new(MockShortlistUsecase)
+	e := newMeServer(auth, u)
+
+	filter := domain.StationFilter{BuildingType: "mansion", Layout: "1r_1k_1dk", Weights: map[string]int{"rent": 80}}
+	list := &domain.Shortlist{ID: 3, Name: "通勤重視", ItemCount: 1, Items: []*domain.ShortlistItem{
+		{StationID: 1, Note: "駅前が静か", Station: &domain.Station{ID: 1, Name: "東京", TotalScore: 72.5, RentAvg: 12.5}},
+	}}
+	u.On("GetShortlist", mock.Anything, int64(7), int64(3), filter).Return(list, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/me/shortlists/3?building_type=mansion&layout=1r_1k_1dk&w_rent=80", nil)
+	req.Header.Set(echo.HeaderAuthorization, "Bearer tok")
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"note":"駅前が静か"`)
+	assert.Contains(t, rec.Body.String(), `"total_score":72.5`)
+	assert.Contains(t, rec.Body.String(), `"rent_avg":12.5`)
+	u.AssertExpectations(t)
+}
+
+func TestGetShortlist_NotFound(t *testing.T) {
+	auth := new(MockAuthUsecase)
+	auth.On("Authenticate", mock.Anything, "tok").Return(&domain.User{ID: 7}, nil)
+	u := new(MockShortlistUsecase)
+	e := newMeServer(auth, u)
+
+	// 他人のリストも存在しないリストと同じ扱い
+	u.On("GetShortlist", mock.Anything, int64(7), int64(99), mock.Anything).Return(nil, sql.ErrNoRows)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/me/shortlists/99", nil)
+	req.Header.Set(echo.HeaderAuthorization, "Bearer tok")
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
+
+func TestCreateShortlist(t *testing.T) {
+	auth := new(MockAuthUsecase)
+	auth.On("Authenticate", mock.Anything, "tok").Return(&domain.User{ID: 7}, nil)
+	u := new(MockShortlistUsecase)
+	e := newMeServer(auth, u)
+
+	u.On("CreateShortlist", mock.Anything, int64(7), "候補A").Return(&domain.Shortlist{ID: 4, Name: "候補A"}, nil)
+	u.On("CreateShortlist", mock.Anything, int64(7), "").Return(nil, usecase.ErrInvalidShortlistName)
+
+	tests := []struct {
+		body string
+		want int
+	}{
+		{`{"name":"候補A"}`, http.StatusCreated},
+		{`{"name":""}`, http.StatusBadRequest},
+		{`{"name":`, http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest(http.MethodPost, "/api/me/shortlists", strings.NewReader(tt.body))
+		req.Header.Set(echo.HeaderAuthorization, "Bearer tok")
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		assert.Equal(t, tt.want, rec.Code, tt.body)
+	}
+}
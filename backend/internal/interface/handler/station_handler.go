@@ -1,8 +1,11 @@
 package handler
 
 import (
+	"errors"
 	"net/http"
+	"sort"
 	"strconv"
+	"strings"
 
 	"github.com/gigaptera/hikkoshi-lens/backend/internal/domain"
 	"github.com/gigaptera/hikkoshi-lens/backend/internal/usecase"
@@ -10,11 +13,13 @@ import (
 )
 
 type StationHandler struct {
-	u usecase.StationUsecase
+	u       usecase.StationUsecase
+	commute usecase.CommuteUsecase // nil の場合は月額総額を返さない
+	moveIn  usecase.MoveInUsecase  // nil の場合は初期費用を返さない
 }
 
-func NewStationHandler(u usecase.StationUsecase) *StationHandler {
-	return &StationHandler{u: u}
+func NewStationHandler(u usecase.StationUsecase, commute usecase.CommuteUsecase, moveIn usecase.MoveInUsecase) *StationHandler {
+	return &StationHandler{u: u, commute: commute, moveIn: moveIn}
 }
 
 // Search is the new endpoint for station search with subsidy support
@@ -66,17 +71,7 @@ func (h *StationHandler) GetNearby(c echo.Context) error {
 	}
 
 	// Parse weights
-	weights := make(map[string]int)
-	weightKeys := []string{"access", "rent", "facility", "safety", "disaster"}
-	for _, key := range weightKeys {
-		valStr := c.QueryParam("w_" + key)
-		if valStr != "" {
-			val, err := strconv.Atoi(valStr)
-			if err == nil {
-				weights[key] = val
-			}
-		}
-	}
+	weights := parseWeights(c)
 
 	// Parse calculate_scores parameter (default: true for backward compatibility)
 	calculateScores := true
@@ -84,6 +79,19 @@ func (h *StationHandler) GetNearby(c echo.Context) error {
 		calculateScores = calcStr == "true" || calcStr == "1"
 	}
 
+	commute, withCost, err := parseCommuteQuery(c)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+	sortBy := c.QueryParam("sort")
+	switch sortBy {
+	case "", "score":
+	case "monthly_cost":
+		withCost = true
+	default:
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid sort"})
+	}
+
 	filter := domain.StationFilter{
 		RadiusMeter:     radius,
 		MinRent:         minRent,
@@ -94,6 +102,7 @@ func (h *StationHandler) GetNearby(c echo.Context) error {
 		CalculateScores: calculateScores,
 		SubsidyType:     subsidyType,
 		SubsidyRange:    subsidyRange,
+		Tags:            parseTags(c),
 	}
 
 	stations, err := h.u.GetNearbyStations(c.Request().Context(), lat, lon, filter)
@@ -101,6 +110,22 @@ func (h *StationHandler) GetNearby(c echo.Context) error {
 		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
 	}
 
+	if withCost && h.commute != nil {
+		costs, err := h.commute.MonthlyCosts(c.Request().Context(), lat, lon, stations, commute)
+		if errors.Is(err, domain.ErrInvalidCommute) {
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+		}
+		if err != nil {
+			return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+		}
+		stations = withMonthlyCosts(stations, costs, sortBy == "monthly_cost")
+	}
+
+	// 地図表示用にGeoJSONで返す (位置は geometry に入るためWKTの解析は不要)
+	if c.QueryParam("format") == "geojson" {
+		return geoJSON(c, domain.NewStationFeatureCollection(stations))
+	}
+
 	// 建物種別と間取りが両方指定されていない場合、warningを追加
 	if buildingType == "" || layout == "" {
 		return c.JSON(http.StatusOK, map[string]interface{}{
@@ -113,16 +138,97 @@ func (h *StationHandler) GetNearby(c echo.Context) error {
 	return c.JSON(http.StatusOK, stations)
 }
 
-func (h *StationHandler) GetStationsWithinThreeStops(c echo.Context) error {
-	idStr := c.Param("id")
-	id, err := strconv.ParseInt(idStr, 10, 64)
-	if err != nil {
-		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid station ID"})
+// parseCommuteQuery は月額総額の計算条件を読み取る
+// monthly_cost=true か勤務地の駅 (workplace_station_id) の指定があれば計算する
+func parseCommuteQuery(c echo.Context) (domain.CommuteQuery, bool, error) {
+	var q domain.CommuteQuery
+	enabled := c.QueryParam("monthly_cost") == "true" || c.QueryParam("monthly_cost") == "1"
+	if s := c.QueryParam("workplace_station_id"); s != "" {
+		id, err := strconv.ParseInt(s, 10, 64)
+		if err != nil || id <= 0 {
+			return q, false, errors.New("Invalid workplace_station_id")
+		}
+		q.WorkplaceStationID = id
+		enabled = true
+	}
+	if s := c.QueryParam("monthly_subsidy"); s != "" {
+		v, err := strconv.Atoi(s)
+		if err != nil || v < 0 {
+			return q, false, errors.New("Invalid monthly_subsidy")
+		}
+		q.MonthlySubsidy = v
 	}
+	q.PassCovered = c.QueryParam("pass_covered") == "true" || c.QueryParam("pass_covered") == "1"
+	q.BuildingType = c.QueryParam("building_type")
+	q.Layout = c.QueryParam("layout")
+	return q, enabled, nil
+}
 
-	// Parse weights (optional, mostly for display)
+// parseMoveInQuery は初期費用の見積もり条件を読み取る (move_in=true の場合のみ見積もる)
+// 引越し元の座標 (from_lat, from_lon) は両方指定した場合だけ引越し料金を加える
+func parseMoveInQuery(c echo.Context) (domain.MoveInQuery, bool, error) {
+	q := domain.MoveInQuery{BuildingType: c.QueryParam("building_type"), Layout: c.QueryParam("layout")}
+	enabled := c.QueryParam("move_in") == "true" || c.QueryParam("move_in") == "1"
+	latStr, lonStr := c.QueryParam("from_lat"), c.QueryParam("from_lon")
+	if latStr == "" && lonStr == "" {
+		return q, enabled, nil
+	}
+	lat, err := strconv.ParseFloat(latStr, 64)
+	if err != nil || lat < -90 || lat > 90 {
+		return q, false, errors.New("Invalid from_lat")
+	}
+	lon, err := strconv.ParseFloat(lonStr, 64)
+	if err != nil || lon < -180 || lon > 180 {
+		return q, false, errors.New("Invalid from_lon")
+	}
+	q.HasOrigin, q.FromLat, q.FromLon = true, lat, lon
+	return q, enabled, nil
+}
+
+// withMonthlyCosts は月額総額を付けた駅のコピーを返す (キャッシュ共有の検索結果は変更しない)
+// byCost の場合は総額の安い順に並べ、総額が不明な駅は (元の順のまま) 最後に置く
+func withMonthlyCosts(stations []*domain.Station, costs map[int64]*domain.MonthlyCost, byCost bool) []*domain.Station {
+	out := make([]*domain.Station, len(stations))
+	for i, s := range stations {
+		copied := *s
+		copied.MonthlyCost = costs[s.ID]
+		out[i] = &copied
+	}
+	if byCost {
+		sort.SliceStable(out, func(i, j int) bool {
+			a, b := out[i].MonthlyCost, out[j].MonthlyCost
+			if a == nil || a.Total == nil {
+				return false
+			}
+			if b == nil || b.Total == nil {
+				return true
+			}
+			return *a.Total < *b.Total
+		})
+	}
+	return out
+}
+
+// parseTags は tags クエリパラメータ (カンマ区切り) を読み取る。空要素と重複は除く
+func parseTags(c echo.Context) []string {
+	var tags []string
+	seen := make(map[string]bool)
+	for _, t := range strings.Split(c.QueryParam("tags"), ",") {
+		t = strings.TrimSpace(t)
+		if t == "" || seen[t] {
+			continue
+		}
+		seen[t] = true
+		tags = append(tags, t)
+	}
+	return tags
+}
+
+// parseWeights は w_<axis> クエリパラメータからスコアの重みを読み取る
+// w_bustle は負の値 (静かな駅を優先) も受け付ける。範囲はOpenAPIのバリデーションで検証する
+func parseWeights(c echo.Context) map[string]int {
 	weights := make(map[string]int)
-	weightKeys := []string{"access", "rent", "facility", "safety", "disaster"}
+	weightKeys := []string{"access", "rent", "facility", "safety", "disaster", "review", "bustle", "hilliness", "night_safety"}
 	for _, key := range weightKeys {
 		valStr := c.QueryParam("w_" + key)
 		if valStr != "" {
@@ -132,12 +238,28 @@ func (h *StationHandler) GetStationsWithinThreeStops(c echo.Context) error {
 			}
 		}
 	}
+	return weights
+}
+
+func (h *StationHandler) GetStationsWithinThreeStops(c echo.Context) error {
+	idStr := c.Param("id")
+	id, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid station ID"})
+	}
+
+	// Parse weights (optional, mostly for display)
+	weights := parseWeights(c)
 
 	stations, err := h.u.GetStationsWithinThreeStops(c.Request().Context(), id, weights)
 	if err != nil {
 		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
 	}
 
+	if c.QueryParam("format") == "geojson" {
+		return geoJSON(c, domain.NewStationFeatureCollection(stations))
+	}
+
 	return c.JSON(http.StatusOK, stations)
 }
 
@@ -164,10 +286,59 @@ func (h *StationHandler) GetStationDetail(c echo.Context) error {
 		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid station ID"})
 	}
 
+	filter, err := parseAffiliateFilter(c)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+	commute, withCost, err := parseCommuteQuery(c)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+	moveIn, withMoveIn, err := parseMoveInQuery(c)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+
 	detail, err := h.u.GetStationDetail(c.Request().Context(), id)
 	if err != nil {
 		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
 	}
 
+	// 月額総額は勤務地の駅 (workplace_station_id) の指定がある場合のみ
+	if withCost && commute.WorkplaceStationID != 0 && h.commute != nil {
+		cost, err := h.commute.StationMonthlyCost(c.Request().Context(), id, commute)
+		if errors.Is(err, domain.ErrInvalidCommute) {
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+		}
+		if err != nil {
+			return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+		}
+		copied := *detail
+		copied.MonthlyCost = cost
+		detail = &copied
+	}
+
+	if withMoveIn && h.moveIn != nil {
+		est, err := h.moveIn.Estimate(c.Request().Context(), id, moveIn)
+		if errors.Is(err, domain.ErrInvalidMoveIn) {
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+		}
+		if err != nil {
+			return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+		}
+		copied := *detail
+		copied.MoveInCost = est
+		detail = &copied
+	}
+
+	// 送客リンクに利用者の検索条件を付ける (キャッシュ共有の応答は変更せずコピーに付ける)
+	if !filter.IsZero() {
+		copied := *detail
+		q := "?" + affiliateQuery(filter)
+		copied.AffiliateLinks.Suumo += q
+		copied.AffiliateLinks.Homes += q
+		detail = &copied
+	}
+
 	return c.JSON(http.StatusOK, detail)
 }
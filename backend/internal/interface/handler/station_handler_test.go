@@ -2,8 +2,10 @@ package handler
 
 import (
 	"context"
+	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
 	"testing"
 
 	"github.com/gigaptera/hikkoshi-lens/backend/internal/domain"
@@ -54,7 +56,7 @@ func TestGetNearby_Success(t *testing.T) {
 	// Setup
 	e := echo.New()
 	mockUsecase := new(MockStationUsecase)
-	handler := NewStationHandler(mockUsecase)
+	handler := NewStationHandler(mockUsecase, nil, nil)
 
 	// モックの設定
 	mockStations := []*domain.Station{
@@ -88,7 +90,7 @@ func TestGetNearby_MissingLat(t *testing.T) {
 	// Setup
 	e := echo.New()
 	mockUsecase := new(MockStationUsecase)
-	handler := NewStationHandler(mockUsecase)
+	handler := NewStationHandler(mockUsecase, nil, nil)
 
 	// リクエストを作成（latなし）
 	req := httptest.NewRequest(http.MethodGet, "/api/stations/nearby?lon=139.7671", nil)
@@ -109,7 +111,7 @@ func TestGetNearby_MissingLon(t *testing.T) {
 	// Setup
 	e := echo.New()
 	mockUsecase := new(MockStationUsecase)
-	handler := NewStationHandler(mockUsecase)
+	handler := NewStationHandler(mockUsecase, nil, nil)
 
 	// リクエストを作成（lonなし）
 	req := httptest.NewRequest(http.MethodGet, "/api/stations/nearby?lat=35.6812", nil)
@@ -130,7 +132,7 @@ func TestGetNearby_WithFilters(t *testing.T) {
 	// Setup
 	e := echo.New()
 	mockUsecase := new(MockStationUsecase)
-	handler := NewStationHandler(mockUsecase)
+	handler := NewStationHandler(mockUsecase, nil, nil)
 
 	// モックの設定
 	mockStations := []*domain.Station{{ID: 1, Name: "東京"}}
@@ -157,7 +159,7 @@ func TestGetStationsWithinThreeStops_Success(t *testing.T) {
 	// Setup
 	e := echo.New()
 	mockUsecase := new(MockStationUsecase)
-	handler := NewStationHandler(mockUsecase)
+	handler := NewStationHandler(mockUsecase, nil, nil)
 
 	// モックの設定
 	mockStations := []*domain.Station{{ID: 1, Name: "東京"}}
@@ -184,7 +186,7 @@ func TestGetStationsWithinThreeStops_InvalidID(t *testing.T) {
 	// Setup
 	e := echo.New()
 	mockUsecase := new(MockStationUsecase)
-	handler := NewStationHandler(mockUsecase)
+	handler := NewStationHandler(mockUsecase, nil, nil)
 
 	// リクエストを作成（無効なID）
 	req := httptest.NewRequest(http.MethodGet, "/api/stations/invalid/three-stops", nil)
@@ -201,3 +203,255 @@ func TestGetStationsWithinThreeStops_InvalidID(t *testing.T) {
 	assert.Equal(t, http.StatusBadRequest, rec.Code)
 	assert.Contains(t, rec.Body.String(), "Invalid station ID")
 }
+
+// TestGetNearby_GeoJSON はformat=geojson指定時にFeatureCollectionを返すことを確認する
+func TestGetNearby_GeoJSON(t *testing.T) {
+	e := echo.New()
+	mockUsecase := new(MockStationUsecase)
+	handler := NewStationHandler(mockUsecase, nil, nil)
+
+	mockStations := []*domain.Station{
+		{ID: 1, Name: "東京", Lat: 35.6812, Lon: 139.7671, TotalScore: 80, RentAvg: 12.5},
+	}
+	mockUsecase.On("GetNearbyStations", mock.Anything, 35.6812, 139.7671, mock.Anything).Return(mockStations, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/stations/nearby?lat=35.6812&lon=139.7671&building_type=mansion&layout=1r_1k_1dk&format=geojson", nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+
+	err := handler.GetNearby(c)
+
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "application/geo+json", rec.Header().Get(echo.HeaderContentType))
+
+	var fc struct {
+		Type     string `json:"type"`
+		Features []struct {
+			ID       int64 `json:"id"`
+			Geometry struct {
+				Type        string    `json:"type"`
+				Coordinates []float64 `json:"coordinates"`
+			} `json:"geometry"`
+			Properties map[string]interface{} `json:"properties"`
+		} `json:"features"`
+	}
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &fc))
+	assert.Equal(t, "FeatureCollection", fc.Type)
+	if assert.Len(t, fc.Features, 1) {
+		f := fc.Features[0]
+		assert.Equal(t, int64(1), f.ID)
+		assert.Equal(t, "Point", f.Geometry.Type)
+		assert.Equal(t, []float64{139.7671, 35.6812}, f.Geometry.Coordinates) // [lon, lat]
+		assert.Equal(t, "東京", f.Properties["name"])
+		assert.Equal(t, 12.5, f.Properties["rent_avg"])
+	}
+}
+
+func TestGetStationDetail_AffiliateLinksWithFilter(t *testing.T) {
+	e := echo.New()
+	mockUsecase := new(MockStationUsecase)
+	handler := NewStationHandler(mockUsecase, nil, nil)
+
+	detail := &domain.StationDetail{
+		ID:   1,
+		Name: "東京",
+		AffiliateLinks: domain.AffiliateLinks{
+			Suumo: "/api/stations/1/affiliate/suumo",
+			Homes: "/api/stations/1/affiliate/homes",
+		},
+	}
+	mockUsecase.On("GetStationDetail", mock.Anything, int64(1)).Return(detail, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/stations/1/details?layout=1ldk_2k_2dk&max_rent=15", nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	c.SetParamNames("id")
+	c.SetParamValues("1")
+
+	err := handler.GetStationDetail(c)
+
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	var got domain.StationDetail
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &got))
+	assert.Equal(t, "/api/stations/1/affiliate/suumo?layout=1ldk_2k_2dk&max_rent=15", got.AffiliateLinks.Suumo)
+	assert.Equal(t, "/api/stations/1/affiliate/homes?layout=1ldk_2k_2dk&max_rent=15", got.AffiliateLinks.Homes)
+	// キャッシュで共有される応答は書き換えない
+	assert.Equal(t, "/api/stations/1/affiliate/suumo", detail.AffiliateLinks.Suumo)
+}
+
+// TestGetNearby_Tags はタグでの絞り込み条件の読み取りのテスト
+func TestGetNearby_Tags(t *testing.T) {
+	e := echo.New()
+	mockUsecase := new(MockStationUsecase)
+	handler := NewStationHandler(mockUsecase, nil, nil)
+
+	mockUsecase.On("GetNearbyStations", mock.Anything, 35.6812, 139.7671, mock.MatchedBy(func(f domain.StationFilter) bool {
+		return assert.ObjectsAreEqual([]string{"コスパ良好", "交通便利"}, f.Tags)
+	})).Return([]*domain.Station{{ID: 1, Name: "東京", Tags: []string{"コスパ良好", "交通便利"}}}, nil)
+
+	q := url.Values{"lat": {"35.6812"}, "lon": {"139.7671"}, "tags": {"コスパ良好, 交通便利,,コスパ良好"}}
+	req := httptest.NewRequest(http.MethodGet, "/api/stations/nearby?"+q.Encode(), nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+
+	assert.NoError(t, handler.GetNearby(c))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"tags":["コスパ良好","交通便利"]`)
+	mockUsecase.AssertExpectations(t)
+}
+
+// MockCommuteUsecase はCommuteUsecaseのモック
+type MockCommuteUsecase struct {
+	mock.Mock
+}
+
+func (m *MockCommuteUsecase) MonthlyCosts(ctx context.Context, lat, lon float64, stations []*domain.Station, q domain.CommuteQuery) (map[int64]*domain.MonthlyCost, error) {
+	args := m.Called(ctx, lat, lon, stations, q)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(map[int64]*domain.MonthlyCost), args.Error(1)
+}
+
+func (m *MockCommuteUsecase) StationMonthlyCost(ctx context.Context, stationID int64, q domain.CommuteQuery) (*domain.MonthlyCost, error) {
+	args := m.Called(ctx, stationID, q)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.MonthlyCost), args.Error(1)
+}
+
+func (m *MockCommuteUsecase) Invalidate(table string) {}
+
+func totalCost(total int) *domain.MonthlyCost {
+	return &domain.MonthlyCost{Total: &total}
+}
+
+// TestGetNearby_SortByMonthlyCost は月額総額の安い順の並べ替えのテスト
+func TestGetNearby_SortByMonthlyCost(t *testing.T) {
+	e := echo.New()
+	mockUsecase := new(MockStationUsecase)
+	mockCommute := new(MockCommuteUsecase)
+	handler := NewStationHandler(mockUsecase, mockCommute, nil)
+
+	stations := []*domain.Station{{ID: 1, Name: "高い"}, {ID: 2, Name: "不明"}, {ID: 3, Name: "安い"}}
+	mockUsecase.On("GetNearbyStations", mock.Anything, 35.6812, 139.7671, mock.Anything).Return(stations, nil)
+	mockCommute.On("MonthlyCosts", mock.Anything, 35.6812, 139.7671, stations, domain.CommuteQuery{
+		WorkplaceStationID: 10, MonthlySubsidy: 20000, PassCovered: true, BuildingType: "mansion", Layout: "1r_1k_1dk",
+	}).Return(map[int64]*domain.MonthlyCost{1: totalCost(90000), 2: {}, 3: totalCost(70000)}, nil)
+
+	q := url.Values{
+		"lat": {"35.6812"}, "lon": {"139.7671"}, "building_type": {"mansion"}, "layout": {"1r_1k_1dk"},
+		"workplace_station_id": {"10"}, "monthly_subsidy": {"20000"}, "pass_covered": {"true"}, "sort": {"monthly_cost"},
+	}
+	req := httptest.NewRequest(http.MethodGet, "/api/stations/search?"+q.Encode(), nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+
+	assert.NoError(t, handler.Search(c))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	var got []*domain.Station
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &got))
+	if assert.Len(t, got, 3) {
+		assert.Equal(t, []int64{3, 1, 2}, []int64{got[0].ID, got[1].ID, got[2].ID})
+		assert.Equal(t, 70000, *got[0].MonthlyCost.Total)
+	}
+	// キャッシュで共有される検索結果は書き換えない
+	assert.Nil(t, stations[0].MonthlyCost)
+	assert.Equal(t, int64(1), stations[0].ID)
+	mockCommute.AssertExpectations(t)
+}
+
+func TestGetNearby_InvalidCommuteParams(t *testing.T) {
+	e := echo.New()
+	handler := NewStationHandler(new(MockStationUsecase), new(MockCommuteUsecase), nil)
+
+	for _, q := range []string{"sort=price", "monthly_subsidy=-1", "workplace_station_id=abc"} {
+		req := httptest.NewRequest(http.MethodGet, "/api/stations/search?lat=35.6812&lon=139.7671&"+q, nil)
+		rec := httptest.NewRecorder()
+		assert.NoError(t, handler.Search(e.NewContext(req, rec)))
+		assert.Equal(t, http.StatusBadRequest, rec.Code, q)
+	}
+}
+
+func TestGetStationDetail_MonthlyCost(t *testing.T) {
+	e := echo.New()
+	mockUsecase := new(MockStationUsecase)
+	mockCommute := new(MockCommuteUsecase)
+	handler := NewStationHandler(mockUsecase, mockCommute, nil)
+
+	detail := &domain.StationDetail{ID: 1, Name: "東京"}
+	mockUsecase.On("GetStationDetail", mock.Anything, int64(1)).Return(detail, nil)
+	mockCommute.On("StationMonthlyCost", mock.Anything, int64(1), domain.CommuteQuery{WorkplaceStationID: 10, Layout: "1r_1k_1dk"}).
+		Return(totalCost(80000), nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/stations/1/details?workplace_station_id=10&layout=1r_1k_1dk", nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	c.SetParamNames("id")
+	c.SetParamValues("1")
+
+	assert.NoError(t, handler.GetStationDetail(c))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"total":80000`)
+	assert.Nil(t, detail.MonthlyCost)
+
+	// 存在しない勤務地の駅
+	mockCommute.On("StationMonthlyCost", mock.Anything, int64(1), domain.CommuteQuery{WorkplaceStationID: 99}).
+		Return(nil, domain.ErrInvalidCommute)
+	req = httptest.NewRequest(http.MethodGet, "/api/stations/1/details?workplace_station_id=99", nil)
+	rec = httptest.NewRecorder()
+	c = e.NewContext(req, rec)
+	c.SetParamNames("id")
+	c.SetParamValues("1")
+	assert.NoError(t, handler.GetStationDetail(c))
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
+
+type MockMoveInUsecase struct {
+	mock.Mock
+}
+
+func (m *MockMoveInUsecase) Estimate(ctx context.Context, stationID int64, q domain.MoveInQuery) (*domain.MoveInEstimate, error) {
+	args := m.Called(ctx, stationID, q)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.MoveInEstimate), args.Error(1)
+}
+
+func TestGetStationDetail_MoveInCost(t *testing.T) {
+	e := echo.New()
+	mockUsecase := new(MockStationUsecase)
+	mockMoveIn := new(MockMoveInUsecase)
+	handler := NewStationHandler(mockUsecase, nil, mockMoveIn)
+
+	detail := &domain.StationDetail{ID: 1, Name: "東京"}
+	mockUsecase.On("GetStationDetail", mock.Anything, int64(1)).Return(detail, nil)
+	mockMoveIn.On("Estimate", mock.Anything, int64(1), domain.MoveInQuery{
+		BuildingType: "apart", Layout: "1r_1k_1dk", HasOrigin: true, FromLat: 35.1, FromLon: 139.5,
+	}).Return(&domain.MoveInEstimate{Rent: 70000, Total: 350000}, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/stations/1/details?move_in=true&building_type=apart&layout=1r_1k_1dk&from_lat=35.1&from_lon=139.5", nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	c.SetParamNames("id")
+	c.SetParamValues("1")
+
+	assert.NoError(t, handler.GetStationDetail(c))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"move_in_cost":{"rent":70000`)
+	assert.Nil(t, detail.MoveInCost)
+
+	// 建物種別・間取りの指定がない
+	mockMoveIn.On("Estimate", mock.Anything, int64(1), domain.MoveInQuery{}).Return(nil, domain.ErrInvalidMoveIn)
+	req = httptest.NewRequest(http.MethodGet, "/api/stations/1/details?move_in=true", nil)
+	rec = httptest.NewRecorder()
+	c = e.NewContext(req, rec)
+	c.SetParamNames("id")
+	c.SetParamValues("1")
+	assert.NoError(t, handler.GetStationDetail(c))
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
@@ -0,0 +1,54 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gigaptera/hikkoshi-lens/backend/internal/domain"
+	"github.com/gigaptera/hikkoshi-lens/backend/internal/usecase"
+	"github.com/labstack/echo/v4"
+)
+
+const mimeMVT = "application/vnd.mapbox-vector-tile"
+
+type TileHandler struct {
+	u usecase.TileUsecase
+}
+
+func NewTileHandler(u usecase.TileUsecase) *TileHandler {
+	return &TileHandler{u: u}
+}
+
+// GetStationTile は /tiles/stations/:z/:x/:y.mvt のベクタータイルを返す
+// Echoは「:y.mvt」の形でパラメータを定義できないため、:y に含まれる拡張子をここで取り除く
+func (h *TileHandler) GetStationTile(c echo.Context) error {
+	z, errZ := strconv.Atoi(c.Param("z"))
+	x, errX := strconv.Atoi(c.Param("x"))
+	y, errY := strconv.Atoi(strings.TrimSuffix(c.Param("y"), ".mvt"))
+	if errZ != nil || errX != nil || errY != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid tile coordinates"})
+	}
+	tile := domain.TileCoord{Z: z, X: x, Y: y}
+	if err := tile.Validate(); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid tile coordinates: " + err.Error()})
+	}
+
+	filter := domain.StationFilter{
+		BuildingType:    c.QueryParam("building_type"),
+		Layout:          c.QueryParam("layout"),
+		Weights:         parseWeights(c),
+		CalculateScores: true,
+	}
+
+	data, err := h.u.GetStationTile(c.Request().Context(), tile, filter)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	}
+
+	c.Response().Header().Set(echo.HeaderCacheControl, "public, max-age=300")
+	if len(data) == 0 {
+		return c.NoContent(http.StatusNoContent)
+	}
+	return c.Blob(http.StatusOK, mimeMVT, data)
+}
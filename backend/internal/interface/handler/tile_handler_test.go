@@ -0,0 +1,85 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gigaptera/hikkoshi-lens/backend/internal/domain"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockTileUsecase はTileUsecaseのモック
+type MockTileUsecase struct {
+	mock.Mock
+}
+
+func (m *MockTileUsecase) GetStationTile(ctx context.Context, tile domain.TileCoord, filter domain.StationFilter) ([]byte, error) {
+	args := m.Called(ctx, tile, filter)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]byte), args.Error(1)
+}
+
+func newTileContext(e *echo.Echo, target, z, x, y string) (echo.Context, *httptest.ResponseRecorder) {
+	req := httptest.NewRequest(http.MethodGet, target, nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	c.SetParamNames("z", "x", "y")
+	c.SetParamValues(z, x, y)
+	return c, rec
+}
+
+// TestGetStationTile_Success は「.mvt」付きのY座標を解釈してタイルを返すことを確認する
+func TestGetStationTile_Success(t *testing.T) {
+	e := echo.New()
+	mockUsecase := new(MockTileUsecase)
+	handler := NewTileHandler(mockUsecase)
+
+	tile := domain.TileCoord{Z: 12, X: 3638, Y: 1612}
+	mockUsecase.On("GetStationTile", mock.Anything, tile, mock.MatchedBy(func(f domain.StationFilter) bool {
+		return f.Layout == "1r_1k_1dk" && f.Weights["rent"] == 80
+	})).Return([]byte{0x1a, 0x02}, nil)
+
+	c, rec := newTileContext(e, "/api/tiles/stations/12/3638/1612.mvt?layout=1r_1k_1dk&w_rent=80", "12", "3638", "1612.mvt")
+	err := handler.GetStationTile(c)
+
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "application/vnd.mapbox-vector-tile", rec.Header().Get(echo.HeaderContentType))
+	assert.Equal(t, []byte{0x1a, 0x02}, rec.Body.Bytes())
+	mockUsecase.AssertExpectations(t)
+}
+
+// TestGetStationTile_Empty は駅のないタイルで204を返すことを確認する
+func TestGetStationTile_Empty(t *testing.T) {
+	e := echo.New()
+	mockUsecase := new(MockTileUsecase)
+	handler := NewTileHandler(mockUsecase)
+	mockUsecase.On("GetStationTile", mock.Anything, domain.TileCoord{Z: 3, X: 0, Y: 0}, mock.Anything).Return([]byte{}, nil)
+
+	c, rec := newTileContext(e, "/api/tiles/stations/3/0/0.mvt", "3", "0", "0.mvt")
+	err := handler.GetStationTile(c)
+
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+}
+
+// TestGetStationTile_OutOfRange はズームレベルに対して範囲外のタイル座標を400で拒否することを確認する
+func TestGetStationTile_OutOfRange(t *testing.T) {
+	e := echo.New()
+	mockUsecase := new(MockTileUsecase)
+	handler := NewTileHandler(mockUsecase)
+
+	c, rec := newTileContext(e, "/api/tiles/stations/2/4/0.mvt", "2", "4", "0.mvt")
+	err := handler.GetStationTile(c)
+
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Contains(t, rec.Body.String(), "Invalid tile coordinates")
+	mockUsecase.AssertNotCalled(t, "GetStationTile", mock.Anything, mock.Anything, mock.Anything)
+}
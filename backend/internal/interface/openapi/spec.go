@@ -0,0 +1,161 @@
+package openapi
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+)
+
+//go:embed openapi.json
+var specJSON []byte
+
+// Spec はリクエスト検証に必要な範囲だけを読み込んだOpenAPI 3ドキュメント
+// 仕様書そのものは openapi.json を正とし、/api/openapi.json でそのまま配信する
+type Spec struct {
+	raw        []byte
+	basePath   string
+	operations map[string]map[string]*Operation // path -> HTTP method -> operation
+}
+
+type Operation struct {
+	OperationID string       `json:"operationId"`
+	Parameters  []*Parameter `json:"parameters"`
+}
+
+type Parameter struct {
+	Ref      string  `json:"$ref"`
+	Name     string  `json:"name"`
+	In       string  `json:"in"` // query, path
+	Required bool    `json:"required"`
+	Schema   *Schema `json:"schema"`
+}
+
+type Schema struct {
+	Type      string        `json:"type"` // integer, number, string, boolean, array
+	Format    string        `json:"format"`
+	Minimum   *float64      `json:"minimum"`
+	Maximum   *float64      `json:"maximum"`
+	MinLength *int          `json:"minLength"`
+	MaxLength *int          `json:"maxLength"`
+	Pattern   string        `json:"pattern"`
+	Enum      []interface{} `json:"enum"`
+	Items     *Schema       `json:"items"`
+
+	pattern *regexp.Regexp
+}
+
+type document struct {
+	Servers []struct {
+		URL string `json:"url"`
+	} `json:"servers"`
+	Paths      map[string]map[string]json.RawMessage `json:"paths"`
+	Components struct {
+		Parameters map[string]*Parameter `json:"parameters"`
+	} `json:"components"`
+}
+
+var httpMethods = map[string]string{
+	"get":    http.MethodGet,
+	"put":    http.MethodPut,
+	"post":   http.MethodPost,
+	"delete": http.MethodDelete,
+	"patch":  http.MethodPatch,
+}
+
+// Load は埋め込まれた openapi.json を読み込み、パラメータの $ref を解決する
+func Load() (*Spec, error) {
+	return Parse(specJSON)
+}
+
+func Parse(raw []byte) (*Spec, error) {
+	var doc document
+	if err := json.Unmarshal(raw, &doc); err != nil {
+		return nil, fmt.Errorf("failed to parse openapi spec: %w", err)
+	}
+
+	spec := &Spec{
+		raw:        raw,
+		operations: make(map[string]map[string]*Operation),
+	}
+	if len(doc.Servers) > 0 {
+		spec.basePath = strings.TrimSuffix(doc.Servers[0].URL, "/")
+	}
+
+	for path, item := range doc.Paths {
+		for key, body := range item {
+			method, ok := httpMethods[key]
+			if !ok {
+				continue // summary, parameters など操作以外のキー
+			}
+			op := new(Operation)
+			if err := json.Unmarshal(body, op); err != nil {
+				return nil, fmt.Errorf("failed to parse %s %s: %w", method, path, err)
+			}
+			for i, p := range op.Parameters {
+				resolved, err := resolveParameter(p, doc.Components.Parameters)
+				if err != nil {
+					return nil, fmt.Errorf("%s %s: %w", method, path, err)
+				}
+				if err := resolved.Schema.compile(); err != nil {
+					return nil, fmt.Errorf("%s %s: parameter %s: %w", method, path, resolved.Name, err)
+				}
+				op.Parameters[i] = resolved
+			}
+			if spec.operations[path] == nil {
+				spec.operations[path] = make(map[string]*Operation)
+			}
+			spec.operations[path][method] = op
+		}
+	}
+
+	return spec, nil
+}
+
+func resolveParameter(p *Parameter, components map[string]*Parameter) (*Parameter, error) {
+	if p.Ref == "" {
+		if p.Schema == nil {
+			p.Schema = &Schema{Type: "string"}
+		}
+		return p, nil
+	}
+	const prefix = "#/components/parameters/"
+	if !strings.HasPrefix(p.Ref, prefix) {
+		return nil, fmt.Errorf("unsupported $ref %q", p.Ref)
+	}
+	target, ok := components[strings.TrimPrefix(p.Ref, prefix)]
+	if !ok {
+		return nil, fmt.Errorf("unresolved $ref %q", p.Ref)
+	}
+	return resolveParameter(target, components)
+}
+
+// Raw は配信用の仕様書(JSON)を返す
+func (s *Spec) Raw() []byte {
+	return s.raw
+}
+
+// Operation はEchoのルートパス (例: /api/stations/:id/details) に対応する操作を返す
+// 仕様書に定義されていない場合は nil
+func (s *Spec) Operation(method, routePath string) *Operation {
+	ops, ok := s.operations[s.PathFromRoute(routePath)]
+	if !ok {
+		return nil
+	}
+	return ops[method]
+}
+
+// PathFromRoute はEchoのルートパスをOpenAPIのパス表記に変換する
+// servers[0].url (/api) を取り除き、:id を {id} に置き換える
+func (s *Spec) PathFromRoute(routePath string) string {
+	path := strings.TrimPrefix(routePath, s.basePath)
+	segments := strings.Split(path, "/")
+	for i, seg := range segments {
+		if strings.HasPrefix(seg, ":") {
+			segments[i] = "{" + strings.TrimPrefix(seg, ":") + "}"
+		}
+	}
+	return strings.Join(segments, "/")
+}
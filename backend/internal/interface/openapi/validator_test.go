@@ -0,0 +1,80 @@
+package openapi
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestServer(t *testing.T) *echo.Echo {
+	t.Helper()
+
+	spec, err := Load()
+	require.NoError(t, err)
+
+	e := echo.New()
+	api := e.Group("/api", RequestValidator(spec))
+	ok := func(c echo.Context) error { return c.String(http.StatusOK, "ok") }
+	api.GET("/stations/search", ok)
+	api.GET("/stations/:id/details", ok)
+	api.GET("/undocumented", ok)
+	return e
+}
+
+// TestRequestValidator はクエリ・パスパラメータの検証結果をテーブルで確認する
+func TestRequestValidator(t *testing.T) {
+	e := newTestServer(t)
+
+	testCases := []struct {
+		name     string
+		path     string
+		status   int
+		contains string
+	}{
+		{"valid search", "/api/stations/search?lat=35.6812&lon=139.7671&radius=3000&layout=1r_1k_1dk&w_rent=80", http.StatusOK, "ok"},
+		{"missing lat", "/api/stations/search?lon=139.7671", http.StatusBadRequest, "Invalid lat"},
+		{"lat out of range", "/api/stations/search?lat=999999&lon=139.7671", http.StatusBadRequest, "Invalid lat"},
+		{"radius not integer", "/api/stations/search?lat=35.6&lon=139.7&radius=abc", http.StatusBadRequest, "Invalid radius"},
+		{"radius too large", "/api/stations/search?lat=35.6&lon=139.7&radius=2147483647", http.StatusBadRequest, "Invalid radius"},
+		{"weight out of range", "/api/stations/search?lat=35.6&lon=139.7&w_rent=101", http.StatusBadRequest, "Invalid w_rent"},
+		{"negative bustle weight", "/api/stations/search?lat=35.6&lon=139.7&w_bustle=-60", http.StatusOK, "ok"},
+		{"negative weight", "/api/stations/search?lat=35.6&lon=139.7&w_rent=-60", http.StatusBadRequest, "Invalid w_rent"},
+		{"negative hilliness weight", "/api/stations/search?lat=35.6&lon=139.7&w_hilliness=-10", http.StatusBadRequest, "Invalid w_hilliness"},
+		{"night safety weight", "/api/stations/search?lat=35.6&lon=139.7&w_night_safety=40", http.StatusOK, "ok"},
+		{"night safety weight out of range", "/api/stations/search?lat=35.6&lon=139.7&w_night_safety=101", http.StatusBadRequest, "Invalid w_night_safety"},
+		{"weight not integer", "/api/stations/search?lat=35.6&lon=139.7&w_rent=high", http.StatusBadRequest, "Invalid w_rent"},
+		{"unknown building type", "/api/stations/search?lat=35.6&lon=139.7&building_type=castle", http.StatusBadRequest, "Invalid building_type"},
+		{"unknown subsidy type", "/api/stations/search?lat=35.6&lon=139.7&subsidy_type=all", http.StatusBadRequest, "Invalid subsidy_type"},
+		{"NaN number", "/api/stations/search?lat=NaN&lon=139.7", http.StatusBadRequest, "Invalid lat"},
+		{"invalid path id", "/api/stations/abc/details", http.StatusBadRequest, "Invalid id"},
+		{"negative path id", "/api/stations/-1/details", http.StatusBadRequest, "Invalid id"},
+		{"valid path id", "/api/stations/1/details", http.StatusOK, "ok"},
+		{"undocumented route passes through", "/api/undocumented?anything=1", http.StatusOK, "ok"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, tc.path, nil)
+			rec := httptest.NewRecorder()
+			e.ServeHTTP(rec, req)
+
+			assert.Equal(t, tc.status, rec.Code)
+			assert.Contains(t, rec.Body.String(), tc.contains)
+		})
+	}
+}
+
+// TestParse_UnresolvedRef は解決できない $ref をエラーにすることを確認する
+func TestParse_UnresolvedRef(t *testing.T) {
+	raw := []byte(`{
+		"servers": [{"url": "/api"}],
+		"paths": {"/x": {"get": {"parameters": [{"$ref": "#/components/parameters/Missing"}]}}}
+	}`)
+
+	_, err := Parse(raw)
+	assert.Error(t, err)
+}
@@ -0,0 +1,56 @@
+package usecase
+
+import (
+	"context"
+	"log"
+
+	"github.com/gigaptera/hikkoshi-lens/backend/internal/domain"
+	"github.com/gigaptera/hikkoshi-lens/backend/internal/domain/service"
+)
+
+type AffiliateUsecase interface {
+	// Redirect は送客先のURLを作ってクリックを記録し、リダイレクト先を返す
+	// referer はクリック元のページ (空でもよい)
+	Redirect(ctx context.Context, stationID int64, source string, filter domain.AffiliateFilter, referer string) (string, error)
+}
+
+type affiliateUsecase struct {
+	stationRepo domain.StationRepository
+	repo        domain.AffiliateRepository
+	links       *service.AffiliateLinkBuilder
+}
+
+func NewAffiliateUsecase(stationRepo domain.StationRepository, repo domain.AffiliateRepository, links *service.AffiliateLinkBuilder) AffiliateUsecase {
+	return &affiliateUsecase{stationRepo: stationRepo, repo: repo, links: links}
+}
+
+func (u *affiliateUsecase) Redirect(ctx context.Context, stationID int64, source string, filter domain.AffiliateFilter, referer string) (string, error) {
+	// 存在しない駅は sql.ErrNoRows を返す
+	if _, err := u.stationRepo.GetStation(ctx, stationID); err != nil {
+		return "", err
+	}
+
+	ids, err := u.repo.GetExternalIDs(ctx, stationID)
+	if err != nil {
+		return "", err
+	}
+	target, err := u.links.Build(source, ids[source], filter)
+	if err != nil {
+		return "", err
+	}
+
+	// 記録に失敗しても利用者の遷移は止めない
+	click := &domain.AffiliateClick{
+		StationID: stationID,
+		Source:    source,
+		Layout:    filter.Layout,
+		MinRent:   filter.MinRent,
+		MaxRent:   filter.MaxRent,
+		TargetURL: target,
+		Referer:   referer,
+	}
+	if err := u.repo.LogClick(ctx, click); err != nil {
+		log.Printf("Warning: failed to log affiliate click (station=%d, source=%s): %v", stationID, source, err)
+	}
+	return target, nil
+}
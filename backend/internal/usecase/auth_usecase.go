@@ -0,0 +1,175 @@
+package usecase
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"net/mail"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/gigaptera/hikkoshi-lens/backend/internal/domain"
+)
+
+var (
+	// ErrInvalidEmail はメールアドレスの形式が不正な場合のエラー
+	ErrInvalidEmail = errors.New("invalid email address")
+	// ErrMagicLinkRateLimited は同じ要求元からのログインリンクの要求が多すぎる場合のエラー
+	// 同じメールアドレスへの送信が多すぎる場合は、送信状況を推測されないようエラーにせず送信しない
+	ErrMagicLinkRateLimited = errors.New("too many login link requests; try again later")
+)
+
+// AuthOptions はマジックリンクとセッションの設定
+type AuthOptions struct {
+	BaseURL      string // リンク先のフロントエンド (BaseURL + "/auth/callback?token=...")
+	MagicLinkTTL time.Duration
+	SessionTTL   time.Duration
+	// ClientSecret は要求元 (IPアドレス) のHMACの鍵 (口コミの投稿元と同じく、IPアドレスを復元できないようにする)
+	ClientSecret string
+}
+
+// LoginResult はマジックリンクの確認後に返すセッション
+type LoginResult struct {
+	Token     string       `json:"token"` // Authorization: Bearer <token> で送る
+	ExpiresAt time.Time    `json:"expires_at"`
+	User      *domain.User `json:"user"`
+}
+
+type AuthUsecase interface {
+	// RequestMagicLink はログイン用のリンクをメールで送る (初回は確認後に利用者を作成する)
+	// 同じメールアドレスへの送信が上限に達している場合は送らずに nil を返す
+	// client は要求元の識別子 (IPアドレス) で、送信数の制限のためHMACにしてから保存する
+	RequestMagicLink(ctx context.Context, email, client string) error
+	// VerifyMagicLink はリンクのトークンを確認してセッションを発行する
+	VerifyMagicLink(ctx context.Context, token string) (*LoginResult, error)
+	// Authenticate はセッションのトークンから利用者を返す
+	Authenticate(ctx context.Context, sessionToken string) (*domain.User, error)
+	Logout(ctx context.Context, sessionToken string) error
+}
+
+type authUsecase struct {
+	repo   domain.UserRepository
+	mailer domain.MailSender
+	opts   AuthOptions
+	now    func() time.Time
+}
+
+func NewAuthUsecase(repo domain.UserRepository, mailer domain.MailSender, opts AuthOptions) AuthUsecase {
+	return &authUsecase{repo: repo, mailer: mailer, opts: opts, now: time.Now}
+}
+
+func (u *authUsecase) RequestMagicLink(ctx context.Context, email, client string) error {
+	addr, err := mail.ParseAddress(strings.TrimSpace(email))
+	if err != nil || addr.Name != "" {
+		return ErrInvalidEmail
+	}
+	email = strings.ToLower(addr.Address)
+
+	token, err := newToken()
+	if err != nil {
+		return err
+	}
+	// 送信数の制限 (同じメールアドレスへの送信と、同じ要求元からの要求)
+	now := u.now()
+	err = u.repo.CreateLoginTokenWithinLimit(ctx, &domain.LoginToken{
+		TokenHash:  hashToken(token),
+		Email:      email,
+		ClientHash: u.clientHash(client),
+		ExpiresAt:  now.Add(u.opts.MagicLinkTTL),
+	}, now.Add(-domain.MagicLinkRateWindow))
+	switch {
+	case errors.Is(err, domain.ErrLoginTokenLimitByEmail):
+		// 宛先ごとの送信状況を推測されないよう、送らずに受け付けた扱いにする
+		return nil
+	case errors.Is(err, domain.ErrLoginTokenLimitByClient):
+		return ErrMagicLinkRateLimited
+	case err != nil:
+		return err
+	}
+	u.deleteExpiredTokens(ctx, now)
+
+	link := strings.TrimSuffix(u.opts.BaseURL, "/") + "/auth/callback?token=" + url.QueryEscape(token)
+	return u.mailer.Send(ctx, domain.MailMessage{
+		To:      email,
+		Subject: "【ひっこしレンズ】ログインリンク",
+		Body: fmt.Sprintf("以下のリンクからログインしてください (%d分間有効、1回のみ使用できます)。\n\n%s\n\nこのメールに心当たりがない場合は破棄してください。\n",
+			int(u.opts.MagicLinkTTL.Minutes()), link),
+	})
+}
+
+func (u *authUsecase) VerifyMagicLink(ctx context.Context, token string) (*LoginResult, error) {
+	if token == "" {
+		return nil, domain.ErrInvalidToken
+	}
+	email, err := u.repo.ConsumeLoginToken(ctx, hashToken(token))
+	if err != nil {
+		return nil, err
+	}
+	u.deleteExpiredTokens(ctx, u.now())
+	user, err := u.repo.GetOrCreateByEmail(ctx, email)
+	if err != nil {
+		return nil, err
+	}
+
+	sessionToken, err := newToken()
+	if err != nil {
+		return nil, err
+	}
+	session := &domain.Session{
+		TokenHash: hashToken(sessionToken),
+		UserID:    user.ID,
+		ExpiresAt: u.now().Add(u.opts.SessionTTL),
+	}
+	if err := u.repo.CreateSession(ctx, session); err != nil {
+		return nil, err
+	}
+	return &LoginResult{Token: sessionToken, ExpiresAt: session.ExpiresAt, User: user}, nil
+}
+
+func (u *authUsecase) Authenticate(ctx context.Context, sessionToken string) (*domain.User, error) {
+	if sessionToken == "" {
+		return nil, domain.ErrInvalidToken
+	}
+	return u.repo.GetSessionUser(ctx, hashToken(sessionToken))
+}
+
+func (u *authUsecase) Logout(ctx context.Context, sessionToken string) error {
+	return u.repo.DeleteSession(ctx, hashToken(sessionToken))
+}
+
+// deleteExpiredTokens は期限切れのトークンを削除する (トークンの作成時・使用時に行う)
+// 送信数の制限は作成時刻で数えるため、数える期間より前に期限切れになったものだけを消す
+// 削除に失敗してもログインは続ける
+func (u *authUsecase) deleteExpiredTokens(ctx context.Context, now time.Time) {
+	if _, err := u.repo.DeleteLoginTokensExpiredBefore(ctx, now.Add(-domain.MagicLinkRateWindow)); err != nil {
+		log.Printf("Warning: failed to delete expired login tokens: %v", err)
+	}
+}
+
+func (u *authUsecase) clientHash(client string) string {
+	mac := hmac.New(sha256.New, []byte(u.opts.ClientSecret))
+	mac.Write([]byte(client))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// newToken は推測できない256bitのトークンを返す
+func newToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// hashToken はDBに保存するトークンのハッシュ (DBが漏れてもトークンとしては使えない)
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
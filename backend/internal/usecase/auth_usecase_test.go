@@ -0,0 +1,246 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/gigaptera/hikkoshi-lens/backend/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// memoryUserRepo はトークンの有効期限・使用済みを判定するインメモリのUserRepository
+type memoryUserRepo struct {
+	now      func() time.Time
+	users    map[string]*domain.User
+	tokens   map[string]*domain.LoginToken
+	sessions map[string]*domain.Session
+}
+
+func newMemoryUserRepo(now func() time.Time) *memoryUserRepo {
+	return &memoryUserRepo{
+		now:      now,
+		users:    make(map[string]*domain.User),
+		tokens:   make(map[string]*domain.LoginToken),
+		sessions: make(map[string]*domain.Session),
+	}
+}
+
+func (r *memoryUserRepo) GetOrCreateByEmail(ctx context.Context, email string) (*domain.User, error) {
+	if u, ok := r.users[email]; ok {
+		return u, nil
+	}
+	u := &domain.User{ID: int64(len(r.users) + 1), Email: email}
+	r.users[email] = u
+	return u, nil
+}
+
+func (r *memoryUserRepo) CreateLoginTokenWithinLimit(ctx context.Context, token *domain.LoginToken, since time.Time) error {
+	var byEmail, byClient int
+	for _, t := range r.tokens {
+		if t.CreatedAt.Before(since) {
+			continue
+		}
+		if t.Email == token.Email {
+			byEmail++
+		}
+		if t.ClientHash == token.ClientHash {
+			byClient++
+		}
+	}
+	if byClient >= domain.MaxMagicLinksPerClient {
+		return domain.ErrLoginTokenLimitByClient
+	}
+	if byEmail >= domain.MaxMagicLinksPerEmail {
+		return domain.ErrLoginTokenLimitByEmail
+	}
+	token.CreatedAt = r.now()
+	r.tokens[token.TokenHash] = token
+	return nil
+}
+
+func (r *memoryUserRepo) DeleteLoginTokensExpiredBefore(ctx context.Context, before time.Time) (int64, error) {
+	var n int64
+	for hash, t := range r.tokens {
+		if t.ExpiresAt.Before(before) {
+			delete(r.tokens, hash)
+			n++
+		}
+	}
+	return n, nil
+}
+
+func (r *memoryUserRepo) ConsumeLoginToken(ctx context.Context, tokenHash string) (string, error) {
+	t, ok := r.tokens[tokenHash]
+	if !ok || t.UsedAt != nil || !t.ExpiresAt.After(r.now()) {
+		return "", domain.ErrInvalidToken
+	}
+	now := r.now()
+	t.UsedAt = &now
+	return t.Email, nil
+}
+
+func (r *memoryUserRepo) CreateSession(ctx context.Context, session *domain.Session) error {
+	r.sessions[session.TokenHash] = session
+	return nil
+}
+
+func (r *memoryUserRepo) GetSessionUser(ctx context.Context, tokenHash string) (*domain.User, error) {
+	s, ok := r.sessions[tokenHash]
+	if !ok || !s.ExpiresAt.After(r.now()) {
+		return nil, domain.ErrInvalidToken
+	}
+	for _, u := range r.users {
+		if u.ID == s.UserID {
+			return u, nil
+		}
+	}
+	return nil, domain.ErrInvalidToken
+}
+
+func (r *memoryUserRepo) DeleteSession(ctx context.Context, tokenHash string) error {
+	delete(r.sessions, tokenHash)
+	return nil
+}
+
+// recordingMailer は送信したメールを記録する
+type recordingMailer struct {
+	sent []domain.MailMessage
+}
+
+func (m *recordingMailer) Send(ctx context.Context, msg domain.MailMessage) error {
+	m.sent = append(m.sent, msg)
+	return nil
+}
+
+var linkPattern = regexp.MustCompile(`https?://\S+`)
+
+func tokenFromMail(t *testing.T, msg domain.MailMessage) string {
+	link := linkPattern.FindString(msg.Body)
+	require.NotEmpty(t, link)
+	u, err := url.Parse(link)
+	require.NoError(t, err)
+	return u.Query().Get("token")
+}
+
+func newTestAuth() (*authUsecase, *memoryUserRepo, *recordingMailer, *time.Time) {
+	now := time.Date(2026, 10, 19, 9, 0, 0, 0, time.UTC)
+	clock := func() time.Time { return now }
+	repo := newMemoryUserRepo(clock)
+	mailer := &recordingMailer{}
+	u := NewAuthUsecase(repo, mailer, AuthOptions{
+		BaseURL:      "https://hikkoshi-lens.example/",
+		MagicLinkTTL: 15 * time.Minute,
+		SessionTTL:   24 * time.Hour,
+		ClientSecret: "secret",
+	}).(*authUsecase)
+	u.now = clock
+	return u, repo, mailer, &now
+}
+
+func TestAuthUsecase_MagicLinkLogin(t *testing.T) {
+	u, repo, mailer, _ := newTestAuth()
+	ctx := context.Background()
+
+	require.NoError(t, u.RequestMagicLink(ctx, " Taro@Example.com ", "192.0.2.1"))
+	require.Len(t, mailer.sent, 1)
+	assert.Equal(t, "taro@example.com", mailer.sent[0].To)
+	assert.Contains(t, mailer.sent[0].Body, "https://hikkoshi-lens.example/auth/callback?token=")
+
+	// DBには平文のトークン・IPアドレスを保存しない
+	token := tokenFromMail(t, mailer.sent[0])
+	_, stored := repo.tokens[token]
+	assert.False(t, stored)
+	for _, lt := range repo.tokens {
+		assert.Len(t, lt.ClientHash, 64)
+		assert.NotContains(t, lt.ClientHash, "192.0.2.1")
+	}
+
+	result, err := u.VerifyMagicLink(ctx, token)
+	require.NoError(t, err)
+	assert.Equal(t, "taro@example.com", result.User.Email)
+	assert.NotEqual(t, token, result.Token)
+
+	user, err := u.Authenticate(ctx, result.Token)
+	require.NoError(t, err)
+	assert.Equal(t, result.User.ID, user.ID)
+
+	// リンクは1回だけ
+	_, err = u.VerifyMagicLink(ctx, token)
+	assert.ErrorIs(t, err, domain.ErrInvalidToken)
+
+	require.NoError(t, u.Logout(ctx, result.Token))
+	_, err = u.Authenticate(ctx, result.Token)
+	assert.ErrorIs(t, err, domain.ErrInvalidToken)
+}
+
+func TestAuthUsecase_Expiry(t *testing.T) {
+	u, _, mailer, now := newTestAuth()
+	ctx := context.Background()
+
+	require.NoError(t, u.RequestMagicLink(ctx, "hanako@example.com", "192.0.2.1"))
+	token := tokenFromMail(t, mailer.sent[0])
+
+	*now = now.Add(16 * time.Minute)
+	_, err := u.VerifyMagicLink(ctx, token)
+	assert.ErrorIs(t, err, domain.ErrInvalidToken)
+}
+
+func TestAuthUsecase_InvalidInput(t *testing.T) {
+	u, _, mailer, _ := newTestAuth()
+	ctx := context.Background()
+
+	for _, email := range []string{"", "not-an-email", "Taro <taro@example.com>"} {
+		assert.ErrorIs(t, u.RequestMagicLink(ctx, email, "192.0.2.1"), ErrInvalidEmail, email)
+	}
+	assert.Empty(t, mailer.sent)
+
+	_, err := u.VerifyMagicLink(ctx, "")
+	assert.ErrorIs(t, err, domain.ErrInvalidToken)
+	_, err = u.Authenticate(ctx, "")
+	assert.ErrorIs(t, err, domain.ErrInvalidToken)
+}
+
+func TestAuthUsecase_RateLimit(t *testing.T) {
+	u, _, mailer, now := newTestAuth()
+	ctx := context.Background()
+
+	// 同じメールアドレスへの送信
+	for i := 0; i < domain.MaxMagicLinksPerEmail; i++ {
+		require.NoError(t, u.RequestMagicLink(ctx, "taro@example.com", fmt.Sprintf("192.0.2.%d", i)))
+	}
+	// 宛先ごとの上限は送信状況を推測されないようエラーにせず、送信だけしない
+	assert.NoError(t, u.RequestMagicLink(ctx, "Taro@example.com", "192.0.2.99"))
+	assert.Len(t, mailer.sent, domain.MaxMagicLinksPerEmail)
+
+	// 同じ要求元からの要求 (宛先を変えても数える)
+	for i := 0; i < domain.MaxMagicLinksPerClient; i++ {
+		require.NoError(t, u.RequestMagicLink(ctx, fmt.Sprintf("user%d@example.com", i), "198.51.100.1"))
+	}
+	assert.ErrorIs(t, u.RequestMagicLink(ctx, "other@example.com", "198.51.100.1"), ErrMagicLinkRateLimited)
+
+	// 期間が過ぎれば再び送れる
+	*now = now.Add(domain.MagicLinkRateWindow + time.Minute)
+	assert.NoError(t, u.RequestMagicLink(ctx, "taro@example.com", "198.51.100.1"))
+}
+
+func TestAuthUsecase_DeletesExpiredTokens(t *testing.T) {
+	u, repo, mailer, now := newTestAuth()
+	ctx := context.Background()
+
+	require.NoError(t, u.RequestMagicLink(ctx, "taro@example.com", "192.0.2.1"))
+	used := tokenFromMail(t, mailer.sent[0])
+	_, err := u.VerifyMagicLink(ctx, used)
+	require.NoError(t, err)
+	require.NoError(t, u.RequestMagicLink(ctx, "hanako@example.com", "192.0.2.2"))
+	assert.Len(t, repo.tokens, 2)
+
+	// 送信数を数える期間を過ぎて期限切れになったトークンは、次の作成時に削除する
+	*now = now.Add(domain.MagicLinkRateWindow + 20*time.Minute)
+	require.NoError(t, u.RequestMagicLink(ctx, "jiro@example.com", "192.0.2.3"))
+	assert.Len(t, repo.tokens, 1)
+}
@@ -0,0 +1,25 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/gigaptera/hikkoshi-lens/backend/internal/domain"
+	"github.com/gigaptera/hikkoshi-lens/backend/internal/domain/service"
+)
+
+type BudgetUsecase interface {
+	// Advise は額面の給与から手取りを見積もり、検索条件 (min_rent / max_rent) に使える家賃の目安を返す
+	Advise(ctx context.Context, in domain.BudgetInput) (*domain.BudgetAdvice, error)
+}
+
+type budgetUsecase struct {
+	advisor *service.BudgetAdvisor
+}
+
+func NewBudgetUsecase(advisor *service.BudgetAdvisor) BudgetUsecase {
+	return &budgetUsecase{advisor: advisor}
+}
+
+func (u *budgetUsecase) Advise(ctx context.Context, in domain.BudgetInput) (*domain.BudgetAdvice, error) {
+	return u.advisor.Advise(in)
+}
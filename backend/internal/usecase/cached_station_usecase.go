@@ -0,0 +1,106 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gigaptera/hikkoshi-lens/backend/internal/domain"
+)
+
+// coordPrecision は検索キーに使う座標の小数点以下の桁数 (3桁 ≒ 100m)
+// 重みスライダー操作で同じ地点の検索が連続するため、近接した座標をまとめてキャッシュする
+const coordPrecision = 3
+
+// CachedStationUsecase はStationUsecaseの前段に置く応答キャッシュ
+// - 検索条件を正規化したキーでTTL・件数上限付きのLRUに保存する
+// - 同一キーの同時リクエストはsingleflightで1回の問い合わせにまとめる
+// - market_prices / station_scores 更新時は Invalidate で全件破棄する
+// 返却する駅スライスは複数リクエストで共有されるため、呼び出し側で変更しないこと
+type CachedStationUsecase struct {
+	*responseCache
+	inner StationUsecase
+}
+
+func NewCachedStationUsecase(inner StationUsecase, ttl time.Duration, maxEntries int) *CachedStationUsecase {
+	return &CachedStationUsecase{
+		responseCache: newResponseCache(ttl, maxEntries),
+		inner:         inner,
+	}
+}
+
+func (u *CachedStationUsecase) GetNearbyStations(ctx context.Context, lat, lon float64, filter domain.StationFilter) ([]*domain.Station, error) {
+	lat, lon = roundCoord(lat), roundCoord(lon)
+	key := "search:" + searchKey(lat, lon, filter)
+	v, err := u.load(ctx, key, func(ctx context.Context) (any, error) {
+		return u.inner.GetNearbyStations(ctx, lat, lon, filter)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return v.([]*domain.Station), nil
+}
+
+func (u *CachedStationUsecase) GetStationsWithinThreeStops(ctx context.Context, stationID int64, weights map[string]int) ([]*domain.Station, error) {
+	key := fmt.Sprintf("three-stops:%d:%s", stationID, weightsKey(weights))
+	v, err := u.load(ctx, key, func(ctx context.Context) (any, error) {
+		return u.inner.GetStationsWithinThreeStops(ctx, stationID, weights)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return v.([]*domain.Station), nil
+}
+
+func (u *CachedStationUsecase) GetStationsByLine(ctx context.Context, organizationCode, lineName string) ([]*domain.Station, error) {
+	key := fmt.Sprintf("line:%q:%q", organizationCode, lineName)
+	v, err := u.load(ctx, key, func(ctx context.Context) (any, error) {
+		return u.inner.GetStationsByLine(ctx, organizationCode, lineName)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return v.([]*domain.Station), nil
+}
+
+func (u *CachedStationUsecase) GetStationDetail(ctx context.Context, stationID int64) (*domain.StationDetail, error) {
+	key := fmt.Sprintf("detail:%d", stationID)
+	v, err := u.load(ctx, key, func(ctx context.Context) (any, error) {
+		return u.inner.GetStationDetail(ctx, stationID)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return v.(*domain.StationDetail), nil
+}
+
+func roundCoord(v float64) float64 {
+	p := math.Pow10(coordPrecision)
+	return math.Round(v*p) / p
+}
+
+// searchKey は検索条件を正規化したキャッシュキーを返す
+// 座標は丸め済み、重みは0を除いてキー順に、タグは名前順に並べる
+func searchKey(lat, lon float64, f domain.StationFilter) string {
+	tags := append([]string(nil), f.Tags...)
+	sort.Strings(tags)
+	return fmt.Sprintf("%.*f,%.*f|r=%d|rent=%g-%g|%s|%s|calc=%t|subsidy=%s:%d|w=%s|tags=%q",
+		coordPrecision, lat, coordPrecision, lon,
+		f.RadiusMeter, f.MinRent, f.MaxRent, f.BuildingType, f.Layout,
+		f.CalculateScores, f.SubsidyType, f.SubsidyRange, weightsKey(f.Weights), tags)
+}
+
+func weightsKey(weights map[string]int) string {
+	parts := make([]string, 0, len(weights))
+	for k, v := range weights {
+		if v == 0 {
+			continue
+		}
+		parts = append(parts, fmt.Sprintf("%s=%d", k, v))
+	}
+	sort.Strings(parts)
+	return strings.Join(parts, ",")
+}
@@ -0,0 +1,136 @@
+package usecase
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gigaptera/hikkoshi-lens/backend/internal/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+// countingUsecase は呼び出し回数を数えるStationUsecase
+type countingUsecase struct {
+	calls   atomic.Int32
+	release chan struct{}
+	lastLat float64
+	ctxErr  error // 読み込みを終えた時点の ctx.Err()
+}
+
+func (u *countingUsecase) GetNearbyStations(ctx context.Context, lat, lon float64, filter domain.StationFilter) ([]*domain.Station, error) {
+	u.calls.Add(1)
+	u.lastLat = lat
+	if u.release != nil {
+		<-u.release
+	}
+	u.ctxErr = ctx.Err()
+	return []*domain.Station{{ID: 1, Name: "東京"}}, nil
+}
+
+func (u *countingUsecase) GetStationsWithinThreeStops(ctx context.Context, stationID int64, weights map[string]int) ([]*domain.Station, error) {
+	u.calls.Add(1)
+	return []*domain.Station{{ID: stationID}}, nil
+}
+
+func (u *countingUsecase) GetStationsByLine(ctx context.Context, organizationCode, lineName string) ([]*domain.Station, error) {
+	u.calls.Add(1)
+	return nil, nil
+}
+
+func (u *countingUsecase) GetStationDetail(ctx context.Context, stationID int64) (*domain.StationDetail, error) {
+	u.calls.Add(1)
+	return &domain.StationDetail{ID: stationID}, nil
+}
+
+// TestCachedStationUsecase_NormalizesKey は近接座標・重み順序の違いが同じキーになることを確認する
+func TestCachedStationUsecase_NormalizesKey(t *testing.T) {
+	inner := &countingUsecase{}
+	u := NewCachedStationUsecase(inner, time.Minute, 100)
+	ctx := context.Background()
+
+	filter := domain.StationFilter{RadiusMeter: 3000, Layout: "1r_1k_1dk", Weights: map[string]int{"access": 50, "rent": 50, "safety": 0}}
+	_, err := u.GetNearbyStations(ctx, 35.68121, 139.76712, filter)
+	assert.NoError(t, err)
+
+	filter2 := domain.StationFilter{RadiusMeter: 3000, Layout: "1r_1k_1dk", Weights: map[string]int{"rent": 50, "access": 50}}
+	_, err = u.GetNearbyStations(ctx, 35.68118, 139.76709, filter2)
+	assert.NoError(t, err)
+
+	assert.Equal(t, int32(1), inner.calls.Load())
+	assert.Equal(t, 35.681, inner.lastLat) // 丸めた座標で問い合わせる
+	assert.Equal(t, uint64(1), u.Stats().Hits)
+
+	// 重みが変われば別キー
+	filter2.Weights["rent"] = 80
+	_, _ = u.GetNearbyStations(ctx, 35.68118, 139.76709, filter2)
+	assert.Equal(t, int32(2), inner.calls.Load())
+}
+
+// TestCachedStationUsecase_Singleflight は同時の同一リクエストが1回にまとめられることを確認する
+func TestCachedStationUsecase_Singleflight(t *testing.T) {
+	inner := &countingUsecase{release: make(chan struct{})}
+	u := NewCachedStationUsecase(inner, time.Minute, 100)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, _ = u.GetNearbyStations(context.Background(), 35.6812, 139.7671, domain.StationFilter{RadiusMeter: 500})
+		}()
+	}
+	time.Sleep(50 * time.Millisecond)
+	close(inner.release)
+	wg.Wait()
+
+	assert.Equal(t, int32(1), inner.calls.Load())
+}
+
+// TestCachedStationUsecase_FirstCallerCanceled は最初のリクエストが切断しても、
+// 同じキーを待つリクエストが共有の読み込み結果を受け取れることを確認する
+func TestCachedStationUsecase_FirstCallerCanceled(t *testing.T) {
+	inner := &countingUsecase{release: make(chan struct{})}
+	u := NewCachedStationUsecase(inner, time.Minute, 100)
+	filter := domain.StationFilter{RadiusMeter: 500}
+
+	firstCtx, cancel := context.WithCancel(context.Background())
+	firstErr := make(chan error, 1)
+	go func() {
+		_, err := u.GetNearbyStations(firstCtx, 35.6812, 139.7671, filter)
+		firstErr <- err
+	}()
+	time.Sleep(20 * time.Millisecond)
+
+	second := make(chan []*domain.Station, 1)
+	go func() {
+		stations, err := u.GetNearbyStations(context.Background(), 35.6812, 139.7671, filter)
+		assert.NoError(t, err)
+		second <- stations
+	}()
+	time.Sleep(20 * time.Millisecond)
+
+	cancel()
+	assert.ErrorIs(t, <-firstErr, context.Canceled)
+	close(inner.release)
+
+	assert.Len(t, <-second, 1)
+	assert.NoError(t, inner.ctxErr)
+	assert.Equal(t, int32(1), inner.calls.Load())
+}
+
+// TestCachedStationUsecase_Invalidate はデータ更新通知でキャッシュが破棄されることを確認する
+func TestCachedStationUsecase_Invalidate(t *testing.T) {
+	inner := &countingUsecase{}
+	u := NewCachedStationUsecase(inner, time.Minute, 100)
+	ctx := context.Background()
+
+	_, _ = u.GetStationDetail(ctx, 1)
+	_, _ = u.GetStationDetail(ctx, 1)
+	assert.Equal(t, int32(1), inner.calls.Load())
+
+	u.Invalidate("market_prices")
+	_, _ = u.GetStationDetail(ctx, 1)
+	assert.Equal(t, int32(2), inner.calls.Load())
+}
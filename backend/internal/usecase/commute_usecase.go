@@ -0,0 +1,183 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"sync"
+
+	"github.com/gigaptera/hikkoshi-lens/backend/internal/domain"
+	"github.com/gigaptera/hikkoshi-lens/backend/internal/domain/service"
+	"golang.org/x/sync/singleflight"
+)
+
+// maxCachedWorkplaces は定期代の計算結果を保持する勤務地の駅の数 (超えたら全て捨てる)
+const maxCachedWorkplaces = 256
+
+type CommuteUsecase interface {
+	// MonthlyCosts は検索結果の駅ごとの月額総額 (家賃 + 定期代 - 家賃補助) を返す
+	// 家賃は駅の RentAvg を使う。駅は変更しない (キャッシュ共有の検索結果のため)
+	// 勤務地の駅の指定がない場合は検索地点 (lat, lon) の最寄り駅を勤務地とする
+	MonthlyCosts(ctx context.Context, lat, lon float64, stations []*domain.Station, q domain.CommuteQuery) (map[int64]*domain.MonthlyCost, error)
+	// StationMonthlyCost は駅詳細の月額総額を返す。家賃は q の建物種別・間取りの相場を使う
+	StationMonthlyCost(ctx context.Context, stationID int64, q domain.CommuteQuery) (*domain.MonthlyCost, error)
+	// Invalidate は駅データ・運賃表の更新時に路線グラフと計算結果を捨てる
+	Invalidate(table string)
+}
+
+type commuteUsecase struct {
+	graphs      *TransitGraphs
+	stationRepo domain.StationRepository
+	fareRepo    domain.FareRepository
+	group       singleflight.Group // 運賃表の読み込みと勤務地ごとの定期代の計算を1回にまとめる
+
+	// mu はマップの読み書きだけに使う (読み込み・計算はロックの外で行い、他の勤務地の要求を待たせない)
+	mu         sync.Mutex
+	fares      *service.FareTable
+	passes     map[int64]map[int64]*domain.CommutePass // 勤務地の駅ID -> 駅ID -> 定期券
+	generation uint64                                  // Invalidateのたびに進め、更新前のデータの計算結果を保存しない
+}
+
+// graphs は等時間圏と共有する路線グラフ
+func NewCommuteUsecase(graphs *TransitGraphs, stationRepo domain.StationRepository, fareRepo domain.FareRepository) CommuteUsecase {
+	return &commuteUsecase{graphs: graphs, stationRepo: stationRepo, fareRepo: fareRepo}
+}
+
+func (u *commuteUsecase) Invalidate(table string) {
+	if table != "stations" && table != "fare_bands" {
+		return
+	}
+	u.graphs.Invalidate(table)
+	u.mu.Lock()
+	u.fares, u.passes = nil, nil
+	u.generation++
+	u.mu.Unlock()
+}
+
+// fareTable は運賃表を読み込み、次の更新まで使い回す
+func (u *commuteUsecase) fareTable(ctx context.Context, gen uint64) (*service.FareTable, error) {
+	u.mu.Lock()
+	fares := u.fares
+	u.mu.Unlock()
+	if fares != nil {
+		return fares, nil
+	}
+
+	v, err, _ := u.group.Do("fares", func() (any, error) {
+		bands, err := u.fareRepo.ListBands(context.WithoutCancel(ctx))
+		if err != nil {
+			return nil, fmt.Errorf("load fare bands: %w", err)
+		}
+		fares := service.NewFareTable(bands)
+		u.mu.Lock()
+		if gen == u.generation {
+			u.fares = fares
+		}
+		u.mu.Unlock()
+		return fares, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return v.(*service.FareTable), nil
+}
+
+// commutePasses は勤務地の駅から各駅への定期券を返す
+// 勤務地の駅IDが 0 の場合は (lat, lon) の最寄り駅を使う
+func (u *commuteUsecase) commutePasses(ctx context.Context, workplaceStationID int64, lat, lon float64) (map[int64]*domain.CommutePass, error) {
+	u.mu.Lock()
+	gen := u.generation
+	u.mu.Unlock()
+
+	graph, err := u.graphs.Graph(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if workplaceStationID == 0 {
+		id, ok := graph.NearestStation(lat, lon)
+		if !ok {
+			return map[int64]*domain.CommutePass{}, nil
+		}
+		workplaceStationID = id
+	}
+
+	u.mu.Lock()
+	passes, ok := u.passes[workplaceStationID]
+	u.mu.Unlock()
+	if ok {
+		return passes, nil
+	}
+
+	v, err, _ := u.group.Do(strconv.FormatInt(workplaceStationID, 10), func() (any, error) {
+		fares, err := u.fareTable(ctx, gen)
+		if err != nil {
+			return nil, err
+		}
+		passes := graph.CommutePasses(workplaceStationID, fares)
+		if len(passes) == 0 {
+			// 勤務地の駅自身は必ず含まれるため、空なら存在しない駅
+			return nil, fmt.Errorf("%w: unknown workplace station %d", domain.ErrInvalidCommute, workplaceStationID)
+		}
+
+		u.mu.Lock()
+		defer u.mu.Unlock()
+		if gen == u.generation {
+			if u.passes == nil || len(u.passes) >= maxCachedWorkplaces {
+				u.passes = make(map[int64]map[int64]*domain.CommutePass)
+			}
+			u.passes[workplaceStationID] = passes
+		}
+		return passes, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return v.(map[int64]*domain.CommutePass), nil
+}
+
+func (u *commuteUsecase) MonthlyCosts(ctx context.Context, lat, lon float64, stations []*domain.Station, q domain.CommuteQuery) (map[int64]*domain.MonthlyCost, error) {
+	if q.MonthlySubsidy < 0 {
+		return nil, domain.ErrInvalidCommute
+	}
+	passes, err := u.commutePasses(ctx, q.WorkplaceStationID, lat, lon)
+	if err != nil {
+		return nil, err
+	}
+
+	costs := make(map[int64]*domain.MonthlyCost, len(stations))
+	for _, s := range stations {
+		costs[s.ID] = domain.NewMonthlyCost(s.RentAvg, passes[s.ID], q)
+	}
+	return costs, nil
+}
+
+func (u *commuteUsecase) StationMonthlyCost(ctx context.Context, stationID int64, q domain.CommuteQuery) (*domain.MonthlyCost, error) {
+	if q.WorkplaceStationID == 0 || q.MonthlySubsidy < 0 {
+		return nil, domain.ErrInvalidCommute
+	}
+	station, err := u.stationRepo.GetStation(ctx, stationID)
+	if err != nil {
+		return nil, err
+	}
+	passes, err := u.commutePasses(ctx, q.WorkplaceStationID, 0, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	rent := marketRent(station, q.BuildingType, q.Layout)
+	return domain.NewMonthlyCost(rent, passes[station.ID], q), nil
+}
+
+// marketRent は建物種別と間取りが両方一致する家賃相場 (万円) を返す (検索結果の RentAvg と同じ)
+// 指定がない・相場がない場合は 0
+func marketRent(station *domain.Station, buildingType, layout string) float64 {
+	if buildingType == "" || layout == "" {
+		return 0
+	}
+	for _, mp := range station.MarketPrices {
+		if mp.BuildingType == buildingType && mp.Layout == layout {
+			return mp.Rent
+		}
+	}
+	return 0
+}
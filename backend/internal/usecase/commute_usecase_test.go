@@ -0,0 +1,172 @@
+package usecase
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/gigaptera/hikkoshi-lens/backend/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type stubFareRepo struct {
+	domain.FareRepository
+	bands     []*domain.FareBand
+	listCalls int
+}
+
+func (r *stubFareRepo) ListBands(ctx context.Context) ([]*domain.FareBand, error) {
+	r.listCalls++
+	return r.bands, nil
+}
+
+func commuteFares() *stubFareRepo {
+	return &stubFareRepo{bands: []*domain.FareBand{
+		{OrganizationCode: "X", MinKm: 1, MaxKm: 3, Fare: 150, Pass1M: 5000, Pass3M: 14000},
+		{OrganizationCode: "X", MinKm: 4, MaxKm: 10, Fare: 200, Pass1M: 6000},
+	}}
+}
+
+func newTestCommuteUsecase(stations *stubStationRepo, fares *stubFareRepo) CommuteUsecase {
+	return NewCommuteUsecase(NewTransitGraphs(stations), stations, fares)
+}
+
+func TestCommuteUsecase_MonthlyCosts(t *testing.T) {
+	u := newTestCommuteUsecase(isochroneStations(), commuteFares())
+	results := []*domain.Station{
+		{ID: 2, RentAvg: 8.5},
+		{ID: 3}, // 家賃相場なし
+	}
+
+	// 勤務地の駅を指定しない場合は検索地点の最寄り駅 (1番駅)
+	costs, err := u.MonthlyCosts(context.Background(), 35.0, 139.0, results, domain.CommuteQuery{MonthlySubsidy: 20000})
+	require.NoError(t, err)
+
+	c := costs[2]
+	require.NotNil(t, c.Total)
+	assert.Equal(t, 85000, *c.Rent)
+	assert.Equal(t, 4667, *c.Pass)
+	assert.Equal(t, 20000, c.Subsidy)
+	assert.Equal(t, 85000+4667-20000, *c.Total)
+	assert.Equal(t, int64(1), c.Commute.WorkplaceStationID)
+
+	assert.Nil(t, costs[3].Rent)
+	assert.Nil(t, costs[3].Total)
+	assert.Equal(t, 6000, *costs[3].Pass)
+	assert.Equal(t, 0, costs[3].Subsidy) // 家賃が不明なら補助も差し引かない
+}
+
+func TestCommuteUsecase_PassCoveredAndSubsidyCap(t *testing.T) {
+	u := newTestCommuteUsecase(isochroneStations(), commuteFares())
+	results := []*domain.Station{{ID: 3, RentAvg: 5}}
+
+	costs, err := u.MonthlyCosts(context.Background(), 0, 0, results, domain.CommuteQuery{WorkplaceStationID: 1, MonthlySubsidy: 80000, PassCovered: true})
+	require.NoError(t, err)
+
+	c := costs[3]
+	assert.True(t, c.PassCoveredByEmployer)
+	assert.Equal(t, 0, *c.Pass)
+	assert.Equal(t, 6000, c.Commute.Monthly) // 参考として定期券は返す
+	assert.Equal(t, 50000, c.Subsidy)
+	assert.Equal(t, 0, *c.Total)
+}
+
+func TestCommuteUsecase_InvalidQuery(t *testing.T) {
+	u := newTestCommuteUsecase(isochroneStations(), commuteFares())
+	ctx := context.Background()
+
+	_, err := u.MonthlyCosts(ctx, 0, 0, nil, domain.CommuteQuery{WorkplaceStationID: 99})
+	assert.ErrorIs(t, err, domain.ErrInvalidCommute)
+	_, err = u.MonthlyCosts(ctx, 0, 0, nil, domain.CommuteQuery{WorkplaceStationID: 1, MonthlySubsidy: -1})
+	assert.ErrorIs(t, err, domain.ErrInvalidCommute)
+	_, err = u.StationMonthlyCost(ctx, 2, domain.CommuteQuery{})
+	assert.ErrorIs(t, err, domain.ErrInvalidCommute)
+}
+
+func TestCommuteUsecase_StationMonthlyCost(t *testing.T) {
+	stations := isochroneStations()
+	stations.stations[2].MarketPrices = []*domain.MarketPrice{
+		{BuildingType: "mansion", Layout: "1r_1k_1dk", Rent: 9},
+		{BuildingType: "apart", Layout: "1r_1k_1dk", Rent: 6.5},
+	}
+	u := newTestCommuteUsecase(stations, commuteFares())
+
+	c, err := u.StationMonthlyCost(context.Background(), 2, domain.CommuteQuery{WorkplaceStationID: 1, BuildingType: "apart", Layout: "1r_1k_1dk"})
+	require.NoError(t, err)
+	assert.Equal(t, 65000, *c.Rent)
+	assert.Equal(t, 65000+4667, *c.Total)
+
+	// 間取りの指定がなければ家賃は不明
+	c, err = u.StationMonthlyCost(context.Background(), 2, domain.CommuteQuery{WorkplaceStationID: 1})
+	require.NoError(t, err)
+	assert.Nil(t, c.Rent)
+	assert.NotNil(t, c.Pass)
+}
+
+func TestCommuteUsecase_Invalidate(t *testing.T) {
+	stations := isochroneStations()
+	fares := commuteFares()
+	u := newTestCommuteUsecase(stations, fares)
+	ctx := context.Background()
+	q := domain.CommuteQuery{WorkplaceStationID: 1}
+
+	_, err := u.MonthlyCosts(ctx, 0, 0, nil, q)
+	require.NoError(t, err)
+	_, err = u.MonthlyCosts(ctx, 0, 0, nil, q)
+	require.NoError(t, err)
+	assert.Equal(t, 1, fares.listCalls)
+
+	u.Invalidate("market_prices")
+	_, err = u.MonthlyCosts(ctx, 0, 0, nil, q)
+	require.NoError(t, err)
+	assert.Equal(t, 1, fares.listCalls)
+
+	// 運賃表の更新では路線グラフを作り直さない
+	u.Invalidate("fare_bands")
+	_, err = u.MonthlyCosts(ctx, 0, 0, nil, q)
+	require.NoError(t, err)
+	assert.Equal(t, 2, fares.listCalls)
+	assert.Equal(t, 1, stations.listCalls)
+
+	u.Invalidate("stations")
+	_, err = u.MonthlyCosts(ctx, 0, 0, nil, q)
+	require.NoError(t, err)
+	assert.Equal(t, 3, fares.listCalls)
+	assert.Equal(t, 2, stations.listCalls)
+}
+
+// TestCommuteUsecase_SharesTransitGraph は等時間圏と定期代の計算が路線グラフを共有することを確認する
+func TestCommuteUsecase_SharesTransitGraph(t *testing.T) {
+	stations := isochroneStations()
+	graphs := NewTransitGraphs(stations)
+	commute := NewCommuteUsecase(graphs, stations, commuteFares())
+	isochrone := NewIsochroneUsecase(graphs, &stubIsochroneRepo{})
+	ctx := context.Background()
+
+	_, err := isochrone.GetIsochrones(ctx, 35.0, 139.0, []int{30})
+	require.NoError(t, err)
+	_, err = commute.MonthlyCosts(ctx, 0, 0, nil, domain.CommuteQuery{WorkplaceStationID: 1})
+	require.NoError(t, err)
+	assert.Equal(t, 1, stations.listCalls)
+}
+
+// TestCommuteUsecase_ConcurrentWorkplaces は複数の勤務地を同時に計算しても結果が揃うことを確認する
+func TestCommuteUsecase_ConcurrentWorkplaces(t *testing.T) {
+	u := newTestCommuteUsecase(isochroneStations(), commuteFares())
+	ctx := context.Background()
+	_, err := u.MonthlyCosts(ctx, 0, 0, nil, domain.CommuteQuery{WorkplaceStationID: 1}) // グラフと運賃表を読み込んでおく
+	require.NoError(t, err)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 30; i++ {
+		wg.Add(1)
+		go func(workplace int64) {
+			defer wg.Done()
+			costs, err := u.MonthlyCosts(ctx, 0, 0, []*domain.Station{{ID: workplace}}, domain.CommuteQuery{WorkplaceStationID: workplace})
+			assert.NoError(t, err)
+			assert.Equal(t, 0, *costs[workplace].Pass) // 勤務地の駅自身は定期代なし
+		}(int64(i%3 + 1))
+	}
+	wg.Wait()
+}
@@ -0,0 +1,85 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/gigaptera/hikkoshi-lens/backend/internal/domain"
+	"github.com/gigaptera/hikkoshi-lens/backend/internal/domain/service"
+)
+
+type CompareUsecase interface {
+	// Compare は指定した駅を指定した順に、スコア・家賃相場付きで返す
+	// 勤務地の駅の指定があれば月額総額を、MoveIn の指定があれば初期費用を付ける
+	// 存在しない駅を含む場合は sql.ErrNoRows
+	Compare(ctx context.Context, ids []int64, q domain.CompareQuery) ([]*domain.Station, error)
+}
+
+type compareUsecase struct {
+	stationRepo domain.StationRepository
+	scoreRepo   domain.StationScoreRepository
+	scoring     *service.ScoringService
+	commute     CommuteUsecase
+	estimator   *service.MoveInEstimator
+}
+
+func NewCompareUsecase(stationRepo domain.StationRepository, scoreRepo domain.StationScoreRepository, scoring *service.ScoringService, commute CommuteUsecase, estimator *service.MoveInEstimator) CompareUsecase {
+	return &compareUsecase{stationRepo: stationRepo, scoreRepo: scoreRepo, scoring: scoring, commute: commute, estimator: estimator}
+}
+
+func (u *compareUsecase) Compare(ctx context.Context, ids []int64, q domain.CompareQuery) ([]*domain.Station, error) {
+	if len(ids) < domain.MinCompareStations || len(ids) > domain.MaxCompareStations {
+		return nil, domain.ErrInvalidCompare
+	}
+	seen := make(map[int64]bool, len(ids))
+	for _, id := range ids {
+		if seen[id] {
+			return nil, domain.ErrInvalidCompare
+		}
+		seen[id] = true
+	}
+	if q.MoveIn != nil && (q.BuildingType == "" || q.Layout == "") {
+		return nil, domain.ErrInvalidMoveIn
+	}
+
+	stations, err := u.stationRepo.GetByIDs(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	if len(stations) != len(ids) {
+		return nil, sql.ErrNoRows
+	}
+	attachAxisScores(ctx, u.scoreRepo, stations)
+	u.scoring.CalculateScores(stations, q.Weights)
+	for _, s := range stations {
+		s.RentAvg = marketRent(s, q.BuildingType, q.Layout)
+	}
+
+	if q.Commute != nil && u.commute != nil {
+		costs, err := u.commute.MonthlyCosts(ctx, 0, 0, stations, *q.Commute)
+		if err != nil {
+			return nil, err
+		}
+		for _, s := range stations {
+			s.MonthlyCost = costs[s.ID]
+		}
+	}
+	if q.MoveIn != nil {
+		for _, s := range stations {
+			if s.RentAvg > 0 {
+				s.MoveInCost = u.estimator.Estimate(s, s.RentAvg, *q.MoveIn)
+			}
+		}
+	}
+
+	// スコア順に並んでいるため、指定した順に戻す
+	byID := make(map[int64]*domain.Station, len(stations))
+	for _, s := range stations {
+		byID[s.ID] = s
+	}
+	result := make([]*domain.Station, len(ids))
+	for i, id := range ids {
+		result[i] = byID[id]
+	}
+	return result, nil
+}
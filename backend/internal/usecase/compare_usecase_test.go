@@ -0,0 +1,69 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/gigaptera/hikkoshi-lens/backend/internal/domain"
+	"github.com/gigaptera/hikkoshi-lens/backend/internal/domain/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestCompareUsecase(stations *stubStationRepo) CompareUsecase {
+	return NewCompareUsecase(
+		stations,
+		&memoryScoreRepo{},
+		service.NewScoringService(),
+		NewCommuteUsecase(NewTransitGraphs(stations), stations, commuteFares()),
+		service.NewMoveInEstimator(service.DefaultMoveInConfig()),
+	)
+}
+
+func TestCompareUsecase_Compare(t *testing.T) {
+	u := newTestCompareUsecase(moveInStations())
+
+	q := domain.CompareQuery{
+		BuildingType: "mansion",
+		Layout:       "1r_1k_1dk",
+		Commute:      &domain.CommuteQuery{WorkplaceStationID: 2},
+		MoveIn:       &domain.MoveInQuery{BuildingType: "mansion", Layout: "1r_1k_1dk", HasOrigin: true, FromLat: 35.1, FromLon: 139.0},
+	}
+	stations, err := u.Compare(context.Background(), []int64{2, 1}, q)
+	require.NoError(t, err)
+
+	// 指定した順
+	require.Len(t, stations, 2)
+	assert.Equal(t, int64(2), stations[0].ID)
+	assert.Equal(t, int64(1), stations[1].ID)
+
+	// 1番駅: 家賃相場・定期代・初期費用 (引越し料金込み) がそろう
+	s := stations[1]
+	assert.Equal(t, 8.0, s.RentAvg)
+	require.NotNil(t, s.MonthlyCost)
+	require.NotNil(t, s.MonthlyCost.Total)
+	assert.Equal(t, 80000+4667, *s.MonthlyCost.Total)
+	require.NotNil(t, s.MoveInCost)
+	assert.Equal(t, domain.MoveInMoving, s.MoveInCost.Items[len(s.MoveInCost.Items)-1].Key)
+
+	// 2番駅は相場がないため初期費用なし
+	assert.Nil(t, stations[0].MoveInCost)
+	assert.Nil(t, stations[0].MonthlyCost.Total)
+}
+
+func TestCompareUsecase_Invalid(t *testing.T) {
+	u := newTestCompareUsecase(moveInStations())
+	ctx := context.Background()
+
+	_, err := u.Compare(ctx, []int64{1}, domain.CompareQuery{})
+	assert.ErrorIs(t, err, domain.ErrInvalidCompare)
+	_, err = u.Compare(ctx, []int64{1, 1}, domain.CompareQuery{})
+	assert.ErrorIs(t, err, domain.ErrInvalidCompare)
+	_, err = u.Compare(ctx, []int64{1, 2, 3, 4, 5, 6}, domain.CompareQuery{})
+	assert.ErrorIs(t, err, domain.ErrInvalidCompare)
+	_, err = u.Compare(ctx, []int64{1, 2}, domain.CompareQuery{MoveIn: &domain.MoveInQuery{}})
+	assert.ErrorIs(t, err, domain.ErrInvalidMoveIn)
+	_, err = u.Compare(ctx, []int64{1, 99}, domain.CompareQuery{})
+	assert.ErrorIs(t, err, sql.ErrNoRows)
+}
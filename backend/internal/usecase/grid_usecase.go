@@ -0,0 +1,43 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+
+	"github.com/gigaptera/hikkoshi-lens/backend/internal/domain"
+)
+
+// MaxGridCells は1回の応答で返すセル数の上限
+// 1kmの六角形で東京23区全体がおよそ500セルなので、都道府県単位の表示までを想定する
+const MaxGridCells = 10000
+
+// ErrTooManyCells は範囲内のセル数が上限を超えた場合のエラー
+var ErrTooManyCells = errors.New("too many grid cells in bbox, zoom in or use a coarser grid")
+
+type GridUsecase interface {
+	// GetHeatmap は範囲内のセルを指標の値付きのGeoJSONで返す
+	GetHeatmap(ctx context.Context, grid string, bbox domain.BBox, metric string) (*domain.FeatureCollection, error)
+}
+
+type gridUsecase struct {
+	repo domain.GridRepository
+}
+
+func NewGridUsecase(repo domain.GridRepository) GridUsecase {
+	return &gridUsecase{repo: repo}
+}
+
+func (u *gridUsecase) GetHeatmap(ctx context.Context, grid string, bbox domain.BBox, metric string) (*domain.FeatureCollection, error) {
+	// 上限を1件超えて取得し、超えていれば途中までの結果を返さずエラーにする
+	features, err := u.repo.GetFeatures(ctx, grid, bbox, metric, MaxGridCells+1)
+	if err != nil {
+		return nil, err
+	}
+	if len(features) > MaxGridCells {
+		return nil, ErrTooManyCells
+	}
+
+	fc := domain.NewFeatureCollection()
+	fc.Features = append(fc.Features, features...)
+	return fc, nil
+}
@@ -0,0 +1,111 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/gigaptera/hikkoshi-lens/backend/internal/domain"
+	"github.com/gigaptera/hikkoshi-lens/backend/internal/domain/service"
+)
+
+// InsightRunStats は解説文の生成ジョブ1回分の集計
+type InsightRunStats struct {
+	Stations     int // 対象の駅数
+	Generated    int // 生成して保存したもの
+	Unchanged    int // 入力・生成手段が前回と同じため飛ばしたもの
+	NoData       int // 材料となるデータがないため飛ばしたもの
+	Failed       int // 生成・検証・保存に失敗したもの (次回に再試行される)
+	LimitReached bool
+}
+
+// InsightUsecase は入力が変わった駅の解説文を再生成する
+// 家賃相場・スコア・タグの更新後に cmd/insights から呼ぶ
+type InsightUsecase interface {
+	// Regenerate は入力のハッシュか生成手段が前回と異なる駅だけを生成する
+	// force の場合は全駅を生成し直す。limit > 0 の場合は生成する駅数の上限 (LLMの呼び出し回数の制限)
+	Regenerate(ctx context.Context, force bool, limit int) (InsightRunStats, error)
+}
+
+type insightUsecase struct {
+	repo      domain.InsightRepository
+	generator domain.InsightGenerator
+	now       func() time.Time
+}
+
+func NewInsightUsecase(repo domain.InsightRepository, generator domain.InsightGenerator) InsightUsecase {
+	return &insightUsecase{repo: repo, generator: generator, now: time.Now}
+}
+
+func (u *insightUsecase) Regenerate(ctx context.Context, force bool, limit int) (InsightRunStats, error) {
+	var stats InsightRunStats
+	inputs, err := u.repo.ListInputs(ctx)
+	if err != nil {
+		return stats, err
+	}
+	service.SetInsightLineMedians(inputs)
+
+	states, err := u.repo.ListStates(ctx)
+	if err != nil {
+		return stats, err
+	}
+
+	for _, in := range inputs {
+		if err := ctx.Err(); err != nil {
+			return stats, err
+		}
+		stats.Stations++
+
+		hash := in.Hash()
+		if prev, ok := states[in.StationID]; ok && !force &&
+			prev.InputHash == hash && prev.Generator == u.generator.Name() {
+			stats.Unchanged++
+			continue
+		}
+		if limit > 0 && stats.Generated+stats.Failed >= limit {
+			stats.LimitReached = true
+			continue
+		}
+
+		err := u.generateOne(ctx, in, hash)
+		switch {
+		case err == nil:
+			stats.Generated++
+		case errors.Is(err, domain.ErrInsufficientInsightData):
+			stats.NoData++
+		default:
+			// 1駅の失敗で他の駅の生成を止めない
+			log.Printf("Warning: insight for station %d: %v", in.StationID, err)
+			stats.Failed++
+		}
+	}
+	return stats, nil
+}
+
+// generateOne は1駅の解説文を生成・検証して保存する
+func (u *insightUsecase) generateOne(ctx context.Context, in *domain.InsightInput, hash string) error {
+	insight, err := u.generator.Generate(ctx, in)
+	if err != nil {
+		return err
+	}
+	if err := domain.ValidateInsight(insight); err != nil {
+		return err
+	}
+	// 住民の声・更新日は生成結果に含めない (住民の声は実際の口コミから、更新日は generated_at から作る)
+	insight.ResidentVoices = domain.ResidentVoices{}
+	insight.LastUpdated = ""
+
+	err = u.repo.Upsert(ctx, &domain.StationInsight{
+		StationID:   in.StationID,
+		Insight:     *insight,
+		InputHash:   hash,
+		Generator:   u.generator.Name(),
+		GeneratedAt: u.now(),
+	})
+	if err != nil {
+		return fmt.Errorf("save: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,137 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/gigaptera/hikkoshi-lens/backend/internal/domain"
+	"github.com/gigaptera/hikkoshi-lens/backend/internal/domain/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type memoryInsightRepo struct {
+	inputs []*domain.InsightInput
+	stored map[int64]domain.StationInsight
+}
+
+func (r *memoryInsightRepo) ListInputs(ctx context.Context) ([]*domain.InsightInput, error) {
+	return r.inputs, nil
+}
+
+func (r *memoryInsightRepo) ListStates(ctx context.Context) (map[int64]domain.StationInsight, error) {
+	states := make(map[int64]domain.StationInsight, len(r.stored))
+	for id, s := range r.stored {
+		states[id] = s
+	}
+	return states, nil
+}
+
+func (r *memoryInsightRepo) Upsert(ctx context.Context, insight *domain.StationInsight) error {
+	if r.stored == nil {
+		r.stored = make(map[int64]domain.StationInsight)
+	}
+	r.stored[insight.StationID] = *insight
+	return nil
+}
+
+func (r *memoryInsightRepo) Get(ctx context.Context, stationID int64) (*domain.StationInsight, error) {
+	s, ok := r.stored[stationID]
+	if !ok {
+		return nil, sql.ErrNoRows
+	}
+	return &s, nil
+}
+
+// failingGenerator は指定した駅だけ生成に失敗する
+type failingGenerator struct {
+	domain.InsightGenerator
+	failID int64
+	calls  int
+}
+
+func (g *failingGenerator) Generate(ctx context.Context, in *domain.InsightInput) (*domain.AIInsight, error) {
+	g.calls++
+	if in.StationID == g.failID {
+		return nil, errors.New("upstream error")
+	}
+	return g.InsightGenerator.Generate(ctx, in)
+}
+
+func TestInsightUsecase_Regenerate(t *testing.T) {
+	repo := &memoryInsightRepo{inputs: []*domain.InsightInput{
+		{StationID: 1, Name: "池尻大橋", Lines: []string{"田園都市線"}, Scores: map[string]float64{"safety": 90}},
+		{StationID: 2, Name: "三軒茶屋", Lines: []string{"田園都市線"}, Facilities: map[string]int{domain.POICategorySupermarket: 5}},
+		{StationID: 3, Name: "駒場東大前"},
+	}}
+	gen := &failingGenerator{InsightGenerator: service.NewTemplateInsightGenerator()}
+	u := NewInsightUsecase(repo, gen).(*insightUsecase)
+	u.now = func() time.Time { return time.Date(2026, 10, 19, 0, 0, 0, 0, time.UTC) }
+	ctx := context.Background()
+
+	stats, err := u.Regenerate(ctx, false, 0)
+	require.NoError(t, err)
+	assert.Equal(t, InsightRunStats{Stations: 3, Generated: 2, NoData: 1}, stats)
+	assert.Equal(t, "template-v1", repo.stored[1].Generator)
+	assert.Equal(t, repo.inputs[0].Hash(), repo.stored[1].InputHash)
+
+	// 入力が変わっていなければ生成しない
+	gen.calls = 0
+	stats, err = u.Regenerate(ctx, false, 0)
+	require.NoError(t, err)
+	assert.Equal(t, InsightRunStats{Stations: 3, Unchanged: 2, NoData: 1}, stats)
+	assert.Equal(t, 1, gen.calls)
+
+	// 入力が変わった駅だけ生成し直す。失敗した駅は前回の内容を残す
+	repo.inputs[0].Scores["safety"] = 20
+	repo.inputs[1].Facilities[domain.POICategorySupermarket] = 0
+	gen.failID = 2
+	stats, err = u.Regenerate(ctx, false, 0)
+	require.NoError(t, err)
+	assert.Equal(t, InsightRunStats{Stations: 3, Generated: 1, NoData: 1, Failed: 1}, stats)
+	assert.Equal(t, []string{"治安スコアが20と低め"}, repo.stored[1].Insight.Summary.Cons)
+	assert.Equal(t, []string{"徒歩圏にスーパーが5軒ある"}, repo.stored[2].Insight.Summary.Pros)
+
+	// force は変わっていない駅も生成し、limit で件数を抑える
+	gen.failID = 0
+	stats, err = u.Regenerate(ctx, true, 1)
+	require.NoError(t, err)
+	assert.Equal(t, 1, stats.Generated)
+	assert.True(t, stats.LimitReached)
+}
+
+func TestStationUsecase_DetailInsight(t *testing.T) {
+	stations := &stubStationRepo{stations: map[int64]*domain.Station{1: {ID: 1, Name: "池尻大橋"}}}
+	insights := &memoryInsightRepo{stored: map[int64]domain.StationInsight{
+		1: {
+			StationID:   1,
+			Insight:     domain.AIInsight{Summary: domain.AISummary{Pros: []string{"徒歩圏にスーパーが5軒ある"}}},
+			Generator:   "template-v1",
+			GeneratedAt: time.Date(2026, 10, 19, 3, 0, 0, 0, time.UTC),
+		},
+	}}
+	u := NewStationUsecase(StationUsecaseDeps{
+		Repo:        stations,
+		ScoreRepo:   &stubScoreRepo{},
+		InsightRepo: insights,
+		Scoring:     service.NewScoringService(),
+	})
+
+	detail, err := u.GetStationDetail(context.Background(), 1)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"徒歩圏にスーパーが5軒ある"}, detail.AIInsight.Summary.Pros)
+	assert.Equal(t, []string{}, detail.AIInsight.Summary.Cons)
+	assert.Equal(t, []string{}, detail.AIInsight.ResidentVoices.Positive)
+	assert.Equal(t, "2026-10-19", detail.AIInsight.LastUpdated)
+
+	// 未生成の駅は空の解説文 (作り話を返さない)
+	delete(insights.stored, 1)
+	detail, err = u.GetStationDetail(context.Background(), 1)
+	require.NoError(t, err)
+	assert.Empty(t, detail.AIInsight.Summary.Pros)
+	assert.Empty(t, detail.AIInsight.ResidentVoices.Negative)
+	assert.Empty(t, detail.AIInsight.LastUpdated)
+}
@@ -0,0 +1,128 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gigaptera/hikkoshi-lens/backend/internal/domain"
+)
+
+type IsochroneUsecase interface {
+	// GetIsochrones は出発地 (勤務地) から各時間帯 (分) で住める範囲を Polygon のGeoJSONで返す
+	// 時間帯の長い順に並べる (地図で短い時間帯を上に重ねるため)
+	GetIsochrones(ctx context.Context, lat, lon float64, bands []int) (*domain.FeatureCollection, error)
+	// Invalidate は駅データの更新時に路線グラフを作り直させる
+	Invalidate(table string)
+}
+
+type isochroneUsecase struct {
+	graphs *TransitGraphs
+	repo   domain.IsochroneRepository
+}
+
+func NewIsochroneUsecase(graphs *TransitGraphs, repo domain.IsochroneRepository) IsochroneUsecase {
+	return &isochroneUsecase{graphs: graphs, repo: repo}
+}
+
+func (u *isochroneUsecase) Invalidate(table string) {
+	u.graphs.Invalidate(table)
+}
+
+func (u *isochroneUsecase) GetIsochrones(ctx context.Context, lat, lon float64, bands []int) (*domain.FeatureCollection, error) {
+	graph, err := u.graphs.Graph(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	maxBand := 0
+	for _, b := range bands {
+		if b > maxBand {
+			maxBand = b
+		}
+	}
+	reachable := graph.Reachable(lat, lon, float64(maxBand))
+
+	fc := domain.NewFeatureCollection()
+	for _, band := range sortedBandsDesc(bands) {
+		// 出発地から直接歩ける範囲と、到達した駅から残り時間で歩ける範囲の和
+		circles := []domain.WalkCircle{{Lat: lat, Lon: lon, RadiusMeter: domain.WalkRadiusMeter(float64(band))}}
+		stationCount := 0
+		for _, r := range reachable {
+			rest := float64(band) - r.Minutes
+			if rest <= 0 {
+				break // 所要時間順なので以降も届かない
+			}
+			stationCount++
+			circles = append(circles, domain.WalkCircle{Lat: r.Lat, Lon: r.Lon, RadiusMeter: domain.WalkRadiusMeter(rest)})
+		}
+
+		geom, err := u.repo.UnionCircles(ctx, circles)
+		if err != nil {
+			return nil, fmt.Errorf("union isochrone %d min: %w", band, err)
+		}
+		fc.Features = append(fc.Features, &domain.Feature{
+			Type:     "Feature",
+			ID:       band,
+			Geometry: geom,
+			Properties: map[string]interface{}{
+				"minutes":       band,
+				"station_count": stationCount,
+			},
+		})
+	}
+	return fc, nil
+}
+
+func sortedBandsDesc(bands []int) []int {
+	seen := make(map[int]bool, len(bands))
+	var out []int
+	for _, b := range bands {
+		if !seen[b] {
+			seen[b] = true
+			out = append(out, b)
+		}
+	}
+	sort.Sort(sort.Reverse(sort.IntSlice(out)))
+	return out
+}
+
+// CachedIsochroneUsecase は等時間圏の応答キャッシュ
+// 出発地は約100m単位に丸めてから計算し、同じ勤務地周辺の要求で結果を共有する
+type CachedIsochroneUsecase struct {
+	*responseCache
+	inner IsochroneUsecase
+}
+
+func NewCachedIsochroneUsecase(inner IsochroneUsecase, ttl time.Duration, maxEntries int) *CachedIsochroneUsecase {
+	return &CachedIsochroneUsecase{
+		responseCache: newResponseCache(ttl, maxEntries),
+		inner:         inner,
+	}
+}
+
+func (u *CachedIsochroneUsecase) Invalidate(table string) {
+	u.inner.Invalidate(table)
+	u.responseCache.Invalidate(table)
+}
+
+func (u *CachedIsochroneUsecase) GetIsochrones(ctx context.Context, lat, lon float64, bands []int) (*domain.FeatureCollection, error) {
+	lat, lon = roundCoord(lat), roundCoord(lon)
+	sorted := sortedBandsDesc(bands)
+	parts := make([]string, len(sorted))
+	for i, b := range sorted {
+		parts[i] = strconv.Itoa(b)
+	}
+
+	key := fmt.Sprintf("isochrone:%.*f,%.*f|%s", coordPrecision, lat, coordPrecision, lon, strings.Join(parts, ","))
+	v, err := u.load(ctx, key, func(ctx context.Context) (any, error) {
+		return u.inner.GetIsochrones(ctx, lat, lon, sorted)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return v.(*domain.FeatureCollection), nil
+}
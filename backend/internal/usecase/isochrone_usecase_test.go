@@ -0,0 +1,78 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+
+	"github.com/gigaptera/hikkoshi-lens/backend/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// stubIsochroneRepo は結合を行わず、渡された円を記録する
+type stubIsochroneRepo struct {
+	circles [][]domain.WalkCircle
+}
+
+func (r *stubIsochroneRepo) UnionCircles(ctx context.Context, circles []domain.WalkCircle) (*domain.Geometry, error) {
+	r.circles = append(r.circles, circles)
+	return &domain.Geometry{Type: "MultiPolygon"}, nil
+}
+
+// 約2km間隔で東西に並ぶ1路線の3駅
+func isochroneStations() *stubStationRepo {
+	return &stubStationRepo{stations: map[int64]*domain.Station{
+		1: {ID: 1, StationCode: "A01", OrganizationCode: "X", LineName: "A線", Name: "一丁目", Lat: 35.0, Lon: 139.00},
+		2: {ID: 2, StationCode: "A02", OrganizationCode: "X", LineName: "A線", Name: "中央", Lat: 35.0, Lon: 139.022},
+		3: {ID: 3, StationCode: "A03", OrganizationCode: "X", LineName: "A線", Name: "三丁目", Lat: 35.0, Lon: 139.044},
+	}}
+}
+
+func TestIsochroneUsecase_GetIsochrones(t *testing.T) {
+	stationRepo := isochroneStations()
+	repo := &stubIsochroneRepo{}
+	u := NewIsochroneUsecase(NewTransitGraphs(stationRepo), repo)
+
+	fc, err := u.GetIsochrones(context.Background(), 35.0, 139.0, []int{5, 30, 5})
+	require.NoError(t, err)
+
+	// 重複を除き、長い時間帯から順に並ぶ
+	require.Len(t, fc.Features, 2)
+	assert.Equal(t, 30, fc.Features[0].Properties["minutes"])
+	assert.Equal(t, 3, fc.Features[0].Properties["station_count"])
+	assert.Equal(t, 5, fc.Features[1].Properties["minutes"])
+	assert.Equal(t, 2, fc.Features[1].Properties["station_count"])
+
+	// 30分: 出発地 + 3駅。どの円も徒歩範囲の上限を超えない
+	require.Len(t, repo.circles, 2)
+	assert.Len(t, repo.circles[0], 4)
+	for _, c := range repo.circles[0] {
+		assert.LessOrEqual(t, c.RadiusMeter, float64(domain.MaxWalkBufferMeter))
+	}
+	// 5分: 3.5分ほどで着く隣の駅からは残り1.5分ほどしか歩けない
+	assert.Len(t, repo.circles[1], 3)
+	assert.Less(t, repo.circles[1][2].RadiusMeter, 150.0)
+}
+
+func TestIsochroneUsecase_GraphIsReused(t *testing.T) {
+	stationRepo := isochroneStations()
+	u := NewIsochroneUsecase(NewTransitGraphs(stationRepo), &stubIsochroneRepo{})
+	ctx := context.Background()
+
+	_, err := u.GetIsochrones(ctx, 35.0, 139.0, []int{30})
+	require.NoError(t, err)
+	_, err = u.GetIsochrones(ctx, 35.0, 139.02, []int{30})
+	require.NoError(t, err)
+	assert.Equal(t, 1, stationRepo.listCalls)
+
+	// 駅以外の更新ではグラフを作り直さない
+	u.Invalidate("market_prices")
+	_, err = u.GetIsochrones(ctx, 35.0, 139.0, []int{30})
+	require.NoError(t, err)
+	assert.Equal(t, 1, stationRepo.listCalls)
+
+	u.Invalidate("stations")
+	_, err = u.GetIsochrones(ctx, 35.0, 139.0, []int{30})
+	require.NoError(t, err)
+	assert.Equal(t, 2, stationRepo.listCalls)
+}
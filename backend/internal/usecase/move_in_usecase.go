@@ -0,0 +1,38 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/gigaptera/hikkoshi-lens/backend/internal/domain"
+	"github.com/gigaptera/hikkoshi-lens/backend/internal/domain/service"
+)
+
+type MoveInUsecase interface {
+	// Estimate は駅の家賃相場 (q の建物種別・間取り) から初期費用を見積もる
+	// 相場がない場合は nil を返す
+	Estimate(ctx context.Context, stationID int64, q domain.MoveInQuery) (*domain.MoveInEstimate, error)
+}
+
+type moveInUsecase struct {
+	stationRepo domain.StationRepository
+	estimator   *service.MoveInEstimator
+}
+
+func NewMoveInUsecase(stationRepo domain.StationRepository, estimator *service.MoveInEstimator) MoveInUsecase {
+	return &moveInUsecase{stationRepo: stationRepo, estimator: estimator}
+}
+
+func (u *moveInUsecase) Estimate(ctx context.Context, stationID int64, q domain.MoveInQuery) (*domain.MoveInEstimate, error) {
+	if q.BuildingType == "" || q.Layout == "" {
+		return nil, domain.ErrInvalidMoveIn
+	}
+	station, err := u.stationRepo.GetStation(ctx, stationID)
+	if err != nil {
+		return nil, err
+	}
+	rent := marketRent(station, q.BuildingType, q.Layout)
+	if rent <= 0 {
+		return nil, nil
+	}
+	return u.estimator.Estimate(station, rent, q), nil
+}
@@ -0,0 +1,44 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/gigaptera/hikkoshi-lens/backend/internal/domain"
+	"github.com/gigaptera/hikkoshi-lens/backend/internal/domain/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// 約2km離れた同じ路線の2駅。相場は1番駅の mansion 1r_1k_1dk のみ
+func moveInStations() *stubStationRepo {
+	return &stubStationRepo{stations: map[int64]*domain.Station{
+		1: {ID: 1, StationCode: "A01", OrganizationCode: "X", LineName: "A線", Name: "一丁目", PrefectureCode: 13, Lat: 35.0, Lon: 139.0, MarketPrices: []*domain.MarketPrice{
+			{BuildingType: "mansion", Layout: "1r_1k_1dk", Rent: 8},
+		}},
+		2: {ID: 2, StationCode: "A02", OrganizationCode: "X", LineName: "A線", Name: "中央", PrefectureCode: 27, Lat: 35.0, Lon: 139.022},
+	}}
+}
+
+func TestMoveInUsecase_Estimate(t *testing.T) {
+	u := NewMoveInUsecase(moveInStations(), service.NewMoveInEstimator(service.DefaultMoveInConfig()))
+	ctx := context.Background()
+
+	est, err := u.Estimate(ctx, 1, domain.MoveInQuery{BuildingType: "mansion", Layout: "1r_1k_1dk"})
+	require.NoError(t, err)
+	require.NotNil(t, est)
+	assert.Equal(t, 80000, est.Rent)
+	assert.Equal(t, 13, est.PrefectureCode)
+	assert.Greater(t, est.Total, 80000*4)
+
+	// 相場がない
+	est, err = u.Estimate(ctx, 1, domain.MoveInQuery{BuildingType: "apart", Layout: "1r_1k_1dk"})
+	assert.NoError(t, err)
+	assert.Nil(t, est)
+
+	_, err = u.Estimate(ctx, 1, domain.MoveInQuery{Layout: "1r_1k_1dk"})
+	assert.ErrorIs(t, err, domain.ErrInvalidMoveIn)
+	_, err = u.Estimate(ctx, 99, domain.MoveInQuery{BuildingType: "mansion", Layout: "1r_1k_1dk"})
+	assert.ErrorIs(t, err, sql.ErrNoRows)
+}
@@ -0,0 +1,107 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/gigaptera/hikkoshi-lens/backend/internal/domain"
+	"github.com/gigaptera/hikkoshi-lens/backend/internal/domain/service"
+)
+
+// NightSafetyOptions は夜間の安全性の集計条件
+type NightSafetyOptions struct {
+	RadiusMeter int    // 街灯の密度を数える駅からの半径
+	Version     string // station_scores のデータバージョン
+	BatchSize   int    // station_scores の書き込みバッチサイズ
+}
+
+// NightSafetyRunStats は夜間の安全性の集計1回分の結果
+type NightSafetyRunStats struct {
+	Stations     int64 // 集計した駅数
+	NightScores  int   // night_safety 軸のスコアを保存した駅数
+	NoData       int   // 街灯・交番のどちらのデータもなく、night_safety 軸のスコアを削除した駅数
+	SafetyScores int   // safety 軸のスコアを計算し直した駅数
+}
+
+// NightSafetyUsecase は街灯・交番の取り込み後に夜間の安全性とそのスコアを更新する
+// cmd/import/osm_pois・cmd/import/koban から呼ぶ
+type NightSafetyUsecase interface {
+	// Refresh は pois から夜間の安全性を集計し直し、night_safety 軸と safety 軸のスコアを更新する
+	// safety 軸は夜間の成分を含むため、cmd/scores recompute を待たずに同じ入力から計算し直して食い違わないようにする
+	Refresh(ctx context.Context, opts NightSafetyOptions) (NightSafetyRunStats, error)
+}
+
+type nightSafetyUsecase struct {
+	stationRepo domain.StationRepository
+	nightRepo   domain.NightSafetyRepository
+	scoreRepo   domain.StationScoreRepository
+	scoring     *service.ScoringService
+}
+
+func NewNightSafetyUsecase(stationRepo domain.StationRepository, nightRepo domain.NightSafetyRepository, scoreRepo domain.StationScoreRepository, scoring *service.ScoringService) NightSafetyUsecase {
+	return &nightSafetyUsecase{stationRepo: stationRepo, nightRepo: nightRepo, scoreRepo: scoreRepo, scoring: scoring}
+}
+
+func (u *nightSafetyUsecase) Refresh(ctx context.Context, opts NightSafetyOptions) (NightSafetyRunStats, error) {
+	var stats NightSafetyRunStats
+	updated, err := u.nightRepo.Refresh(ctx, opts.RadiusMeter)
+	if err != nil {
+		return stats, fmt.Errorf("refresh night safety: %w", err)
+	}
+	stats.Stations = updated
+
+	rows, err := u.nightRepo.ListAll(ctx)
+	if err != nil {
+		return stats, fmt.Errorf("load night safety: %w", err)
+	}
+	stations, err := u.stationRepo.ListAll(ctx)
+	if err != nil {
+		return stats, fmt.Errorf("load stations: %w", err)
+	}
+	for _, s := range stations {
+		s.NightSafety = rows[s.ID]
+	}
+
+	scores := service.NightSafetyScores(rows, opts.Version)
+	stats.NightScores = len(scores)
+	safety, err := u.scoring.RecomputeAxis("safety", stations, opts.Version)
+	if err != nil {
+		return stats, err
+	}
+	stats.SafetyScores = len(safety)
+	if err := u.upsert(ctx, append(scores, safety...), opts.BatchSize); err != nil {
+		return stats, err
+	}
+
+	// 指標がなくなった駅に以前のスコアを残さない
+	for stationID, n := range rows {
+		if _, ok := n.Score(); ok {
+			continue
+		}
+		if err := u.scoreRepo.Delete(ctx, stationID, domain.NightSafetyScoreAxis); err != nil {
+			return stats, fmt.Errorf("delete night safety score: %w", err)
+		}
+		stats.NoData++
+	}
+
+	if err := u.scoreRepo.NotifyUpdated(ctx); err != nil {
+		return stats, fmt.Errorf("notify station_scores update: %w", err)
+	}
+	return stats, nil
+}
+
+func (u *nightSafetyUsecase) upsert(ctx context.Context, scores []*domain.StationScore, batchSize int) error {
+	if batchSize < 1 {
+		batchSize = len(scores)
+	}
+	for i := 0; i < len(scores); i += batchSize {
+		end := i + batchSize
+		if end > len(scores) {
+			end = len(scores)
+		}
+		if err := u.scoreRepo.Upsert(ctx, scores[i:end]); err != nil {
+			return fmt.Errorf("upsert scores batch %d-%d: %w", i, end, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,55 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+
+	"github.com/gigaptera/hikkoshi-lens/backend/internal/domain"
+	"github.com/gigaptera/hikkoshi-lens/backend/internal/domain/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// memoryNightSafetyRepo は Refresh で rows を集計結果として返す NightSafetyRepository
+type memoryNightSafetyRepo struct {
+	domain.NightSafetyRepository
+	rows         map[int64]*domain.StationNightSafety
+	refreshedFor int
+}
+
+func (r *memoryNightSafetyRepo) Refresh(ctx context.Context, radiusMeter int) (int64, error) {
+	r.refreshedFor = radiusMeter
+	return int64(len(r.rows)), nil
+}
+
+func (r *memoryNightSafetyRepo) ListAll(ctx context.Context) (map[int64]*domain.StationNightSafety, error) {
+	return r.rows, nil
+}
+
+func TestNightSafetyUsecase_Refresh(t *testing.T) {
+	value := func(v float64) *float64 { return &v }
+	stations := &stubStationRepo{stations: map[int64]*domain.Station{5: {ID: 5}, 10: {ID: 10}, 15: {ID: 15}}}
+	night := &memoryNightSafetyRepo{rows: map[int64]*domain.StationNightSafety{
+		5:  {StationID: 5, StreetLampDensity: value(400), NearestPoliceMeter: value(200)},
+		10: {StationID: 10, StreetLampDensity: value(0), NearestPoliceMeter: value(2000)},
+		// 街灯・交番のどちらのデータもない駅
+		15: {StationID: 15},
+	}}
+	// 前回の集計で保存した値が残っている
+	scores := &memoryScoreRepo{scores: map[int64]map[string]float64{15: {domain.NightSafetyScoreAxis: 40}}}
+	u := NewNightSafetyUsecase(stations, night, scores, service.NewScoringService())
+
+	stats, err := u.Refresh(context.Background(), NightSafetyOptions{RadiusMeter: 500, Version: "v1", BatchSize: 2})
+	require.NoError(t, err)
+	assert.Equal(t, NightSafetyRunStats{Stations: 3, NightScores: 2, NoData: 1, SafetyScores: 3}, stats)
+	assert.Equal(t, 500, night.refreshedFor)
+
+	assert.Equal(t, 100.0, scores.scores[5][domain.NightSafetyScoreAxis])
+	assert.Equal(t, 0.0, scores.scores[10][domain.NightSafetyScoreAxis])
+	assert.NotContains(t, scores.scores[15], domain.NightSafetyScoreAxis)
+	// safety 軸も同じ入力から計算し直す (ID%5 が同じ駅は夜間の成分だけで差がつく)
+	assert.Equal(t, 100.0, scores.scores[5]["safety"])
+	assert.Equal(t, 0.0, scores.scores[10]["safety"])
+	assert.Contains(t, scores.scores[15], "safety")
+	assert.Equal(t, 1, scores.notified)
+}
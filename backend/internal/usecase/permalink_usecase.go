@@ -0,0 +1,125 @@
+package usecase
+
+import (
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/gigaptera/hikkoshi-lens/backend/internal/domain"
+)
+
+// ErrInvalidPermalink は共有する検索条件・有効期間が不正な場合のエラー
+var ErrInvalidPermalink = errors.New("invalid permalink")
+
+const base62 = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz"
+
+// PermalinkResult は共有リンクを開いたときの応答 (保存した条件と再検索の結果)
+type PermalinkResult struct {
+	Permalink *domain.SearchPermalink `json:"permalink"`
+	Stations  []*domain.Station       `json:"stations"`
+}
+
+// PermalinkUsecase は検索条件の共有リンク
+// 開くたびに保存した条件で検索し直すため、結果は常に最新のスコア・家賃相場になる
+type PermalinkUsecase interface {
+	// Create は検索条件を保存してコードを発行する。ttl が0なら domain.DefaultPermalinkTTL
+	Create(ctx context.Context, lat, lon float64, filter domain.StationFilter, ttl time.Duration) (*domain.SearchPermalink, error)
+	// Resolve は閲覧数を数えて検索を再実行する。存在しないコードは sql.ErrNoRows、期限切れは domain.ErrPermalinkExpired
+	Resolve(ctx context.Context, code string) (*PermalinkResult, error)
+}
+
+type permalinkUsecase struct {
+	repo     domain.PermalinkRepository
+	stations StationUsecase
+	baseURL  string
+	now      func() time.Time
+	newCode  func() (string, error)
+}
+
+// NewPermalinkUsecase は共有リンクを作る。baseURL はフロントエンド (共有URLは baseURL + "/s/" + code)
+func NewPermalinkUsecase(repo domain.PermalinkRepository, stations StationUsecase, baseURL string) PermalinkUsecase {
+	return &permalinkUsecase{repo: repo, stations: stations, baseURL: strings.TrimSuffix(baseURL, "/"), now: time.Now, newCode: newPermalinkCode}
+}
+
+func (u *permalinkUsecase) Create(ctx context.Context, lat, lon float64, filter domain.StationFilter, ttl time.Duration) (*domain.SearchPermalink, error) {
+	if err := normalizeSearch(lat, lon, &filter); err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidPermalink, err)
+	}
+	if ttl == 0 {
+		ttl = domain.DefaultPermalinkTTL
+	}
+	if ttl < 0 || ttl > domain.MaxPermalinkTTL {
+		return nil, fmt.Errorf("%w: expiry must be at most %d days", ErrInvalidPermalink, int(domain.MaxPermalinkTTL.Hours()/24))
+	}
+
+	link := &domain.SearchPermalink{Lat: lat, Lon: lon, Filter: filter, ExpiresAt: u.now().Add(ttl)}
+	// コードの衝突は稀だが、起きた場合は作り直す
+	for attempt := 0; ; attempt++ {
+		code, err := u.newCode()
+		if err != nil {
+			return nil, err
+		}
+		link.Code = code
+		err = u.repo.Create(ctx, link)
+		if err == nil {
+			break
+		}
+		if !errors.Is(err, sql.ErrNoRows) || attempt >= 4 {
+			return nil, fmt.Errorf("create permalink: %w", err)
+		}
+	}
+	link.URL = u.shareURL(link.Code)
+	return link, nil
+}
+
+func (u *permalinkUsecase) Resolve(ctx context.Context, code string) (*PermalinkResult, error) {
+	if !isPermalinkCode(code) {
+		return nil, sql.ErrNoRows
+	}
+	link, err := u.repo.View(ctx, code, u.now())
+	if err != nil {
+		return nil, err
+	}
+	link.URL = u.shareURL(link.Code)
+
+	stations, err := u.stations.GetNearbyStations(ctx, link.Lat, link.Lon, link.Filter)
+	if err != nil {
+		return nil, err
+	}
+	return &PermalinkResult{Permalink: link, Stations: stations}, nil
+}
+
+func (u *permalinkUsecase) shareURL(code string) string {
+	return u.baseURL + "/s/" + code
+}
+
+// newPermalinkCode は推測されにくい base62 のコードを生成する
+func newPermalinkCode() (string, error) {
+	max := big.NewInt(int64(len(base62)))
+	b := make([]byte, domain.PermalinkCodeLength)
+	for i := range b {
+		n, err := rand.Int(rand.Reader, max)
+		if err != nil {
+			return "", err
+		}
+		b[i] = base62[n.Int64()]
+	}
+	return string(b), nil
+}
+
+func isPermalinkCode(code string) bool {
+	if len(code) != domain.PermalinkCodeLength {
+		return false
+	}
+	for i := 0; i < len(code); i++ {
+		if !strings.ContainsRune(base62, rune(code[i])) {
+			return false
+		}
+	}
+	return true
+}
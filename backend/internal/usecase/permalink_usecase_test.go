@@ -0,0 +1,107 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/gigaptera/hikkoshi-lens/backend/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type memoryPermalinkRepo struct {
+	links map[string]*domain.SearchPermalink
+}
+
+func (r *memoryPermalinkRepo) Create(ctx context.Context, link *domain.SearchPermalink) error {
+	if _, ok := r.links[link.Code]; ok {
+		return sql.ErrNoRows
+	}
+	stored := *link
+	r.links[link.Code] = &stored
+	return nil
+}
+
+func (r *memoryPermalinkRepo) View(ctx context.Context, code string, now time.Time) (*domain.SearchPermalink, error) {
+	link, ok := r.links[code]
+	if !ok {
+		return nil, sql.ErrNoRows
+	}
+	if !link.ExpiresAt.After(now) {
+		return nil, domain.ErrPermalinkExpired
+	}
+	link.ViewCount++
+	link.LastViewedAt = &now
+	copied := *link
+	return &copied, nil
+}
+
+func (r *memoryPermalinkRepo) DeleteExpired(ctx context.Context, before time.Time) (int, error) {
+	return 0, nil
+}
+
+func TestPermalinkUsecase_CreateAndResolve(t *testing.T) {
+	repo := &memoryPermalinkRepo{links: map[string]*domain.SearchPermalink{}}
+	search := &stubSearch{stations: []*domain.Station{{ID: 10, Name: "池尻大橋", TotalScore: 71}}}
+	u := NewPermalinkUsecase(repo, search, "https://hikkoshi-lens.example/").(*permalinkUsecase)
+	now := time.Date(2026, 10, 19, 9, 0, 0, 0, time.UTC)
+	u.now = func() time.Time { return now }
+
+	filter := domain.StationFilter{Layout: "1ldk", Weights: map[string]int{"rent": 80}, SubsidyType: "from_workplace", SubsidyRange: 2}
+	link, err := u.Create(context.Background(), 35.658, 139.7016, filter, 0)
+	require.NoError(t, err)
+	assert.Len(t, link.Code, domain.PermalinkCodeLength)
+	assert.True(t, isPermalinkCode(link.Code))
+	assert.Equal(t, "https://hikkoshi-lens.example/s/"+link.Code, link.URL)
+	assert.Equal(t, now.Add(domain.DefaultPermalinkTTL), link.ExpiresAt)
+
+	res, err := u.Resolve(context.Background(), link.Code)
+	require.NoError(t, err)
+	assert.Equal(t, 1, res.Permalink.ViewCount)
+	assert.Equal(t, "https://hikkoshi-lens.example/s/"+link.Code, res.Permalink.URL)
+	assert.Len(t, res.Stations, 1)
+	// 保存した条件 (既定値を補ったもの) で検索し直す
+	assert.Equal(t, 500, search.filter.RadiusMeter)
+	assert.Equal(t, "1ldk", search.filter.Layout)
+	assert.Equal(t, 2, search.filter.SubsidyRange)
+
+	res, err = u.Resolve(context.Background(), link.Code)
+	require.NoError(t, err)
+	assert.Equal(t, 2, res.Permalink.ViewCount)
+
+	// 期限切れ
+	now = now.Add(domain.DefaultPermalinkTTL)
+	_, err = u.Resolve(context.Background(), link.Code)
+	assert.ErrorIs(t, err, domain.ErrPermalinkExpired)
+}
+
+func TestPermalinkUsecase_CodeCollision(t *testing.T) {
+	repo := &memoryPermalinkRepo{links: map[string]*domain.SearchPermalink{"AAAAAAAA": {Code: "AAAAAAAA"}}}
+	u := NewPermalinkUsecase(repo, &stubSearch{}, "").(*permalinkUsecase)
+	codes := []string{"AAAAAAAA", "BBBBBBBB"}
+	u.newCode = func() (string, error) {
+		code := codes[0]
+		codes = codes[1:]
+		return code, nil
+	}
+
+	link, err := u.Create(context.Background(), 35.658, 139.7016, domain.StationFilter{}, time.Hour)
+	require.NoError(t, err)
+	assert.Equal(t, "BBBBBBBB", link.Code)
+}
+
+func TestPermalinkUsecase_Invalid(t *testing.T) {
+	u := NewPermalinkUsecase(&memoryPermalinkRepo{links: map[string]*domain.SearchPermalink{}}, &stubSearch{}, "")
+
+	_, err := u.Create(context.Background(), 135, 139, domain.StationFilter{}, 0)
+	assert.ErrorIs(t, err, ErrInvalidPermalink)
+	_, err = u.Create(context.Background(), 35, 139, domain.StationFilter{}, domain.MaxPermalinkTTL+time.Hour)
+	assert.ErrorIs(t, err, ErrInvalidPermalink)
+
+	_, err = u.Resolve(context.Background(), "not-a-code")
+	assert.ErrorIs(t, err, sql.ErrNoRows)
+	_, err = u.Resolve(context.Background(), "ZZZZZZZZ")
+	assert.ErrorIs(t, err, sql.ErrNoRows)
+}
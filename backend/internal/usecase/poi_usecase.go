@@ -0,0 +1,83 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/gigaptera/hikkoshi-lens/backend/internal/domain"
+)
+
+type POIUsecase interface {
+	GetStationPOIs(ctx context.Context, stationID int64, filter domain.POIFilter) (*domain.StationPOIs, error)
+}
+
+type poiUsecase struct {
+	stationRepo domain.StationRepository
+	poiRepo     domain.POIRepository
+	walk        *WalkDistances
+}
+
+// NewPOIUsecase はPOI検索のユースケースを作る
+// walk が nil または徒歩ネットワークなしの場合、徒歩分数は直線距離から推定する
+func NewPOIUsecase(stationRepo domain.StationRepository, poiRepo domain.POIRepository, walk *WalkDistances) POIUsecase {
+	return &poiUsecase{stationRepo: stationRepo, poiRepo: poiRepo, walk: walk}
+}
+
+// GetStationPOIs は駅周辺のPOIをカテゴリごとにまとめて返す
+// 指定カテゴリ (未指定なら全カテゴリ) は0件でもグループを返すため、地図レイヤーの表示切り替えにそのまま使える
+func (u *poiUsecase) GetStationPOIs(ctx context.Context, stationID int64, filter domain.POIFilter) (*domain.StationPOIs, error) {
+	// 存在しない駅は sql.ErrNoRows を返す
+	station, err := u.stationRepo.GetStation(ctx, stationID)
+	if err != nil {
+		return nil, err
+	}
+
+	pois, err := u.poiRepo.GetNearStation(ctx, stationID, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	categories := filter.Categories
+	if len(categories) == 0 {
+		categories = domain.POICategories
+	}
+
+	groups := make(map[string]*domain.POIGroup, len(categories))
+	result := &domain.StationPOIs{StationID: stationID, RadiusMeter: filter.RadiusMeter}
+	for _, c := range categories {
+		if _, ok := groups[c]; ok {
+			continue
+		}
+		g := &domain.POIGroup{Category: c, Layer: domain.POICategoryLayer(c), POIs: []*domain.POI{}}
+		groups[c] = g
+		result.Groups = append(result.Groups, g)
+	}
+
+	// 検索半径は直線距離のため、道路距離は遠回りの分だけ長く探す
+	setPOIWalkMinutes(u.walk, station, pois, domain.EstimateWalkMeters(float64(filter.RadiusMeter))*2)
+
+	// リポジトリはカテゴリ・距離順で返す
+	for _, p := range pois {
+		g, ok := groups[p.Category]
+		if !ok {
+			continue
+		}
+		g.POIs = append(g.POIs, p)
+		g.Count++
+	}
+	return result, nil
+}
+
+// setPOIWalkMinutes は駅から施設までの徒歩分数を道路距離 (maxMeter まで探す) で求める
+// 徒歩ネットワークがない・経路が見つからない場合は直線距離から推定する
+func setPOIWalkMinutes(walk *WalkDistances, station *domain.Station, pois []*domain.POI, maxMeter float64) {
+	targets := make([]domain.Location, len(pois))
+	straight := make([]float64, len(pois))
+	for i, p := range pois {
+		targets[i] = domain.Location{Lat: p.Lat, Lon: p.Lon}
+		straight[i] = p.Distance
+	}
+	meters := walk.Meters(domain.Location{Lat: station.Lat, Lon: station.Lon}, targets, straight, maxMeter)
+	for i, p := range pois {
+		p.WalkMinutes = domain.WalkMinutes(meters[i])
+	}
+}
@@ -0,0 +1,149 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"sort"
+	"testing"
+
+	"github.com/gigaptera/hikkoshi-lens/backend/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// stubStationRepo はGetStation・GetByIDs・ListAll・GetByLineだけを実装したStationRepository
+type stubStationRepo struct {
+	domain.StationRepository
+	stations  map[int64]*domain.Station
+	listCalls int
+}
+
+func (r *stubStationRepo) GetStation(ctx context.Context, id int64) (*domain.Station, error) {
+	s, ok := r.stations[id]
+	if !ok {
+		return nil, sql.ErrNoRows
+	}
+	return s, nil
+}
+
+// GetByIDs はDBから読み込んだ場合と同じく駅のコピーを返す
+func (r *stubStationRepo) GetByIDs(ctx context.Context, ids []int64) ([]*domain.Station, error) {
+	var stations []*domain.Station
+	for _, id := range ids {
+		if s, ok := r.stations[id]; ok {
+			copied := *s
+			stations = append(stations, &copied)
+		}
+	}
+	return stations, nil
+}
+
+func (r *stubStationRepo) ListAll(ctx context.Context) ([]*domain.Station, error) {
+	r.listCalls++
+	stations := make([]*domain.Station, 0, len(r.stations))
+	for _, s := range r.stations {
+		stations = append(stations, s)
+	}
+	sort.Slice(stations, func(i, j int) bool { return stations[i].ID < stations[j].ID })
+	return stations, nil
+}
+
+// GetByLine は路線の駅をID順 (路線上の並び順の代わり) に返す
+func (r *stubStationRepo) GetByLine(ctx context.Context, organizationCode, lineName string) ([]*domain.Station, error) {
+	var stations []*domain.Station
+	for _, s := range r.stations {
+		if s.OrganizationCode == organizationCode && s.LineName == lineName {
+			stations = append(stations, s)
+		}
+	}
+	sort.Slice(stations, func(i, j int) bool { return stations[i].ID < stations[j].ID })
+	return stations, nil
+}
+
+// stubPOIRepo はGetNearStationだけを実装したPOIRepository
+type stubPOIRepo struct {
+	domain.POIRepository
+	pois       []*domain.POI
+	lastFilter domain.POIFilter
+}
+
+func (r *stubPOIRepo) GetNearStation(ctx context.Context, stationID int64, filter domain.POIFilter) ([]*domain.POI, error) {
+	r.lastFilter = filter
+	return r.pois, nil
+}
+
+// GetNearest は pois のうちカテゴリの一致するものを先頭から limit 件返す
+func (r *stubPOIRepo) GetNearest(ctx context.Context, stationID int64, category string, limit int) ([]*domain.POI, error) {
+	var pois []*domain.POI
+	for _, p := range r.pois {
+		if p.Category == category && len(pois) < limit {
+			pois = append(pois, p)
+		}
+	}
+	return pois, nil
+}
+
+func TestPOIUsecase_GetStationPOIs_GroupsByCategory(t *testing.T) {
+	poiRepo := &stubPOIRepo{pois: []*domain.POI{
+		{ID: 1, Category: domain.POICategoryConvenience, Name: "A", Distance: 80},
+		{ID: 2, Category: domain.POICategoryConvenience, Name: "B", Distance: 400},
+		{ID: 3, Category: domain.POICategoryPolice, Name: "東京駅前交番", Distance: 120, Lat: 35.68, Lon: 139.76},
+	}}
+	u := NewPOIUsecase(&stubStationRepo{stations: map[int64]*domain.Station{1: {ID: 1}}}, poiRepo, nil)
+
+	filter := domain.POIFilter{RadiusMeter: 800, Limit: 50, Categories: []string{"police", "convenience", "shelter"}}
+	res, err := u.GetStationPOIs(context.Background(), 1, filter)
+	require.NoError(t, err)
+	assert.Equal(t, filter, poiRepo.lastFilter)
+
+	// 指定順にグループを返し、0件のカテゴリも含める
+	require.Len(t, res.Groups, 3)
+	assert.Equal(t, "police", res.Groups[0].Category)
+	assert.Equal(t, domain.POILayerSafety, res.Groups[0].Layer)
+	assert.Equal(t, "convenience", res.Groups[1].Category)
+	assert.Equal(t, 2, res.Groups[1].Count)
+	assert.Equal(t, "shelter", res.Groups[2].Category)
+	assert.Equal(t, domain.POILayerDisaster, res.Groups[2].Layer)
+	assert.Empty(t, res.Groups[2].POIs)
+
+	// 80m * 1.3 / 80m/分 = 1.3 → 2分 (切り上げ)
+	assert.Equal(t, 2, res.Groups[1].POIs[0].WalkMinutes)
+	assert.Equal(t, 7, res.Groups[1].POIs[1].WalkMinutes)
+
+	fc := res.FeatureCollection()
+	require.Len(t, fc.Features, 3)
+	assert.Equal(t, []float64{139.76, 35.68}, fc.Features[0].Geometry.Coordinates)
+	assert.Equal(t, "safety", fc.Features[0].Properties["layer"])
+}
+
+func TestPOIUsecase_GetStationPOIs_WalkNetwork(t *testing.T) {
+	poiRepo := &stubPOIRepo{pois: []*domain.POI{
+		{ID: 1, Category: domain.POICategorySupermarket, Distance: 100, Lat: 35.001, Lon: 139.0},
+		{ID: 2, Category: domain.POICategorySupermarket, Distance: 200, Lat: 35.002, Lon: 139.0},
+	}}
+	// 1件目は線路の向こう側で踏切まで遠回りする。2件目は経路が見つからない
+	router := &stubWalkRouter{meters: map[domain.Location]float64{{Lat: 35.001, Lon: 139.0}: 700}}
+	station := &domain.Station{ID: 1, Lat: 35.0, Lon: 139.0}
+	u := NewPOIUsecase(&stubStationRepo{stations: map[int64]*domain.Station{1: station}}, poiRepo, NewWalkDistances(router, 100))
+
+	res, err := u.GetStationPOIs(context.Background(), 1, domain.POIFilter{RadiusMeter: 800, Limit: 50, Categories: []string{"supermarket"}})
+	require.NoError(t, err)
+	pois := res.Groups[0].POIs
+	assert.Equal(t, 9, pois[0].WalkMinutes) // 700m / 80m/分
+	assert.Equal(t, 4, pois[1].WalkMinutes) // 200m * 1.3 / 80m/分
+}
+
+func TestPOIUsecase_GetStationPOIs_AllCategoriesByDefault(t *testing.T) {
+	u := NewPOIUsecase(&stubStationRepo{stations: map[int64]*domain.Station{1: {ID: 1}}}, &stubPOIRepo{}, nil)
+
+	res, err := u.GetStationPOIs(context.Background(), 1, domain.POIFilter{RadiusMeter: 800, Limit: 50})
+	require.NoError(t, err)
+	assert.Len(t, res.Groups, len(domain.POICategories))
+}
+
+func TestPOIUsecase_GetStationPOIs_StationNotFound(t *testing.T) {
+	u := NewPOIUsecase(&stubStationRepo{}, &stubPOIRepo{}, nil)
+
+	_, err := u.GetStationPOIs(context.Background(), 999, domain.POIFilter{RadiusMeter: 800, Limit: 50})
+	assert.ErrorIs(t, err, sql.ErrNoRows)
+}
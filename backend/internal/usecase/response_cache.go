@@ -0,0 +1,77 @@
+package usecase
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/gigaptera/hikkoshi-lens/backend/internal/infrastructure/cache"
+	"golang.org/x/sync/singleflight"
+)
+
+// sharedLoadTimeout は複数のリクエストが待つ共有の読み込みの上限時間
+// 読み込みは最初のリクエストの切断では中断しないため、この時間で打ち切る
+const sharedLoadTimeout = 30 * time.Second
+
+// responseCache はキャッシュ付きユースケースに共通の、LRU + singleflight + 世代管理による読み込み
+type responseCache struct {
+	cache *cache.LRU[string, any]
+	group singleflight.Group
+
+	mu         sync.Mutex
+	generation uint64 // Invalidateのたびに進め、無効化前に開始した問い合わせ結果を保存しない
+}
+
+func newResponseCache(ttl time.Duration, maxEntries int) *responseCache {
+	return &responseCache{cache: cache.NewLRU[string, any](ttl, maxEntries)}
+}
+
+// Invalidate はキャッシュを全件破棄する
+// table は更新されたテーブル名 (ログ用途)。キャッシュする応答はどれも複数テーブルに依存するため区別しない
+func (u *responseCache) Invalidate(table string) {
+	u.mu.Lock()
+	u.generation++
+	u.mu.Unlock()
+	u.cache.Purge()
+}
+
+// Stats はキャッシュのヒット数などを返す
+func (u *responseCache) Stats() cache.Stats {
+	return u.cache.Stats()
+}
+
+// load はキャッシュから返すか、同じ key の読み込みを1回にまとめて fn を呼ぶ
+// fn には呼び出し元のキャンセルを引き継がない ctx を渡す (最初のクライアントが切断しても、
+// 同じ key を待つ他のリクエストを失敗させない)。呼び出し元は自分の ctx の終了で待つのをやめる
+func (u *responseCache) load(ctx context.Context, key string, fn func(ctx context.Context) (any, error)) (any, error) {
+	if v, ok := u.cache.Get(key); ok {
+		return v, nil
+	}
+
+	ch := u.group.DoChan(key, func() (any, error) {
+		loadCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), sharedLoadTimeout)
+		defer cancel()
+
+		gen := u.currentGeneration()
+		v, err := fn(loadCtx)
+		if err != nil {
+			return nil, err // エラーはキャッシュしない
+		}
+		if gen == u.currentGeneration() {
+			u.cache.Set(key, v)
+		}
+		return v, nil
+	})
+	select {
+	case res := <-ch:
+		return res.Val, res.Err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+func (u *responseCache) currentGeneration() uint64 {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	return u.generation
+}
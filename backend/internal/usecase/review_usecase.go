@@ -0,0 +1,253 @@
+package usecase
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/gigaptera/hikkoshi-lens/backend/internal/domain"
+	"github.com/gigaptera/hikkoshi-lens/backend/internal/domain/score"
+	"github.com/gigaptera/hikkoshi-lens/backend/internal/domain/service"
+)
+
+const (
+	defaultReviewListLimit = 20
+	maxReviewListLimit     = 100
+	defaultReviewQueueSize = 50
+	maxReviewQueueSize     = 200
+	maxModerationNote      = 200
+)
+
+var (
+	// ErrReviewRateLimited は同じ投稿元からの投稿が多すぎる場合のエラー
+	ErrReviewRateLimited = errors.New("too many reviews from this client; try again later")
+	// ErrInvalidModeration は審査結果が不正な場合のエラー
+	ErrInvalidModeration = errors.New("invalid moderation")
+)
+
+// StationReviews は駅の承認済み口コミ
+type StationReviews struct {
+	Summary *domain.ReviewSummary `json:"summary"`
+	Reviews []*domain.Review      `json:"reviews"`
+}
+
+// ReviewUsecase は駅の口コミの投稿・審査
+// 投稿は審査待ちとして保存し、管理者が承認したものだけを公開・集計する
+type ReviewUsecase interface {
+	// Submit は口コミを審査待ちとして保存する。client は投稿元の識別子 (IPアドレス) で、HMACにしてから保存する
+	Submit(ctx context.Context, stationID int64, in domain.ReviewInput, client string) (*domain.Review, error)
+	List(ctx context.Context, stationID int64, limit int) (*StationReviews, error)
+	// Queue は審査状態ごとの口コミを古い順に返す (status 省略時は審査待ち)
+	Queue(ctx context.Context, status string, limit, offset int) ([]*domain.Review, error)
+	// Moderate は承認・却下を保存し、駅の review 軸のスコアを更新する
+	Moderate(ctx context.Context, id int64, status, note string) (*domain.Review, error)
+}
+
+type reviewUsecase struct {
+	repo         domain.ReviewRepository
+	stationRepo  domain.StationRepository
+	scoreRepo    domain.StationScoreRepository
+	filter       *service.ReviewFilter
+	clientSecret []byte
+	now          func() time.Time
+}
+
+// NewReviewUsecase は口コミの投稿・審査を作る
+// clientSecret は投稿元のHMACの鍵 (IPアドレスを復元できないようにするため、環境ごとに秘密の値を使う)
+func NewReviewUsecase(repo domain.ReviewRepository, stationRepo domain.StationRepository, scoreRepo domain.StationScoreRepository, filter *service.ReviewFilter, clientSecret string) ReviewUsecase {
+	return &reviewUsecase{
+		repo:         repo,
+		stationRepo:  stationRepo,
+		scoreRepo:    scoreRepo,
+		filter:       filter,
+		clientSecret: []byte(clientSecret),
+		now:          time.Now,
+	}
+}
+
+func (u *reviewUsecase) Submit(ctx context.Context, stationID int64, in domain.ReviewInput, client string) (*domain.Review, error) {
+	review, err := u.newReview(in)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := u.stationRepo.GetStation(ctx, stationID); err != nil {
+		return nil, err
+	}
+
+	// 投稿数の制限 (1日あたりの件数と、同じ駅への連続投稿)
+	review.ClientHash = u.clientHash(client)
+	now := u.now()
+	n, err := u.repo.CountByClientSince(ctx, review.ClientHash, 0, now.Add(-24*time.Hour))
+	if err != nil {
+		return nil, err
+	}
+	if n >= domain.MaxReviewsPerClientPerDay {
+		return nil, ErrReviewRateLimited
+	}
+	n, err = u.repo.CountByClientSince(ctx, review.ClientHash, stationID, now.Add(-domain.ReviewStationCooldown))
+	if err != nil {
+		return nil, err
+	}
+	if n > 0 {
+		return nil, ErrReviewRateLimited
+	}
+
+	review.StationID = stationID
+	review.Status = domain.ReviewStatusPending
+	if err := u.repo.Create(ctx, review); err != nil {
+		return nil, err
+	}
+	return review, nil
+}
+
+// newReview は評価と本文を検証して保存する口コミを作る
+func (u *reviewUsecase) newReview(in domain.ReviewInput) (*domain.Review, error) {
+	if len(in.Ratings) == 0 {
+		return nil, fmt.Errorf("%w: at least one rating is required", domain.ErrInvalidReview)
+	}
+	ratings := make(map[string]int, len(in.Ratings))
+	for axis, v := range in.Ratings {
+		if !isReviewAxis(axis) {
+			return nil, fmt.Errorf("%w: unknown rating axis %q", domain.ErrInvalidReview, axis)
+		}
+		if v < 1 || v > 5 {
+			return nil, fmt.Errorf("%w: rating must be 1-5", domain.ErrInvalidReview)
+		}
+		ratings[axis] = v
+	}
+
+	review := &domain.Review{
+		Ratings:  ratings,
+		Positive: strings.TrimSpace(in.Positive),
+		Negative: strings.TrimSpace(in.Negative),
+	}
+	if review.Positive == "" && review.Negative == "" {
+		return nil, fmt.Errorf("%w: positive or negative text is required", domain.ErrInvalidReview)
+	}
+	seen := make(map[string]bool)
+	for _, text := range []string{review.Positive, review.Negative} {
+		if text == "" {
+			continue
+		}
+		flags, err := u.filter.Check(text)
+		if err != nil {
+			return nil, err
+		}
+		for _, f := range flags {
+			if !seen[f] {
+				seen[f] = true
+				review.Flags = append(review.Flags, f)
+			}
+		}
+	}
+	return review, nil
+}
+
+func isReviewAxis(axis string) bool {
+	for _, a := range domain.ReviewAxes {
+		if a == axis {
+			return true
+		}
+	}
+	return false
+}
+
+func (u *reviewUsecase) clientHash(client string) string {
+	mac := hmac.New(sha256.New, u.clientSecret)
+	mac.Write([]byte(client))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func (u *reviewUsecase) List(ctx context.Context, stationID int64, limit int) (*StationReviews, error) {
+	if limit <= 0 {
+		limit = defaultReviewListLimit
+	}
+	if limit > maxReviewListLimit {
+		limit = maxReviewListLimit
+	}
+	if _, err := u.stationRepo.GetStation(ctx, stationID); err != nil {
+		return nil, err
+	}
+	summary, err := u.repo.Summary(ctx, stationID)
+	if err != nil {
+		return nil, err
+	}
+	reviews, err := u.repo.ListApproved(ctx, stationID, limit)
+	if err != nil {
+		return nil, err
+	}
+	return &StationReviews{Summary: summary, Reviews: reviews}, nil
+}
+
+func (u *reviewUsecase) Queue(ctx context.Context, status string, limit, offset int) ([]*domain.Review, error) {
+	if status == "" {
+		status = domain.ReviewStatusPending
+	}
+	switch status {
+	case domain.ReviewStatusPending, domain.ReviewStatusApproved, domain.ReviewStatusRejected:
+	default:
+		return nil, fmt.Errorf("%w: unknown status %q", ErrInvalidModeration, status)
+	}
+	if limit <= 0 {
+		limit = defaultReviewQueueSize
+	}
+	if limit > maxReviewQueueSize {
+		limit = maxReviewQueueSize
+	}
+	if offset < 0 {
+		offset = 0
+	}
+	return u.repo.ListByStatus(ctx, status, limit, offset)
+}
+
+func (u *reviewUsecase) Moderate(ctx context.Context, id int64, status, note string) (*domain.Review, error) {
+	if status != domain.ReviewStatusApproved && status != domain.ReviewStatusRejected {
+		return nil, fmt.Errorf("%w: status must be %s or %s", ErrInvalidModeration, domain.ReviewStatusApproved, domain.ReviewStatusRejected)
+	}
+	note = strings.TrimSpace(note)
+	if utf8.RuneCountInString(note) > maxModerationNote {
+		return nil, fmt.Errorf("%w: note must be at most %d characters", ErrInvalidModeration, maxModerationNote)
+	}
+
+	review, err := u.repo.Moderate(ctx, id, status, note, u.now())
+	if err != nil {
+		return nil, err
+	}
+	// 審査結果は保存済みなので、スコアの更新に失敗してもエラーにしない (次の審査で更新される)
+	if err := u.refreshReviewScore(ctx, review.StationID); err != nil {
+		log.Printf("Warning: failed to update review score for station %d: %v", review.StationID, err)
+	}
+	return review, nil
+}
+
+// refreshReviewScore は承認済み口コミの平均評価を station_scores の review 軸に保存する
+// 件数が domain.MinReviewsForScore に満たない駅はスコアを削除する (中間値として扱われる)
+// 保存後に更新を通知し、検索・駅詳細のキャッシュ (review 軸と住民の声) を破棄させる
+func (u *reviewUsecase) refreshReviewScore(ctx context.Context, stationID int64) error {
+	summary, err := u.repo.Summary(ctx, stationID)
+	if err != nil {
+		return err
+	}
+	if summary.Count < domain.MinReviewsForScore {
+		err = u.scoreRepo.Delete(ctx, stationID, domain.ReviewScoreAxis)
+	} else {
+		err = u.scoreRepo.Upsert(ctx, []*domain.StationScore{{
+			StationID:       stationID,
+			Axis:            domain.ReviewScoreAxis,
+			RawScore:        summary.Overall,
+			NormalizedScore: score.ReviewRatingScore(summary.Overall),
+			DataVersion:     "reviews",
+		}})
+	}
+	if err != nil {
+		return err
+	}
+	return u.scoreRepo.NotifyUpdated(ctx)
+}
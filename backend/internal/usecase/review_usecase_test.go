@@ -0,0 +1,308 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/gigaptera/hikkoshi-lens/backend/internal/domain"
+	"github.com/gigaptera/hikkoshi-lens/backend/internal/domain/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type memoryReviewRepo struct {
+	reviews []*domain.Review
+}
+
+func (r *memoryReviewRepo) Create(ctx context.Context, review *domain.Review) error {
+	review.ID = int64(len(r.reviews) + 1)
+	if review.CreatedAt.IsZero() {
+		review.CreatedAt = time.Now()
+	}
+	r.reviews = append(r.reviews, review)
+	return nil
+}
+
+func (r *memoryReviewRepo) CountByClientSince(ctx context.Context, clientHash string, stationID int64, since time.Time) (int, error) {
+	n := 0
+	for _, rv := range r.reviews {
+		if rv.ClientHash == clientHash && !rv.CreatedAt.Before(since) && (stationID == 0 || rv.StationID == stationID) {
+			n++
+		}
+	}
+	return n, nil
+}
+
+func (r *memoryReviewRepo) ListApproved(ctx context.Context, stationID int64, limit int) ([]*domain.Review, error) {
+	reviews := make([]*domain.Review, 0)
+	for i := len(r.reviews) - 1; i >= 0 && len(reviews) < limit; i-- {
+		if rv := r.reviews[i]; rv.StationID == stationID && rv.Status == domain.ReviewStatusApproved {
+			reviews = append(reviews, rv)
+		}
+	}
+	return reviews, nil
+}
+
+func (r *memoryReviewRepo) Summary(ctx context.Context, stationID int64) (*domain.ReviewSummary, error) {
+	summary := &domain.ReviewSummary{Ratings: make(map[string]float64)}
+	sums, counts := make(map[string]int), make(map[string]int)
+	total, n := 0, 0
+	for _, rv := range r.reviews {
+		if rv.StationID != stationID || rv.Status != domain.ReviewStatusApproved {
+			continue
+		}
+		summary.Count++
+		for axis, v := range rv.Ratings {
+			sums[axis] += v
+			counts[axis]++
+			total += v
+			n++
+		}
+	}
+	for axis, sum := range sums {
+		summary.Ratings[axis] = float64(sum) / float64(counts[axis])
+	}
+	if n > 0 {
+		summary.Overall = float64(total) / float64(n)
+	}
+	return summary, nil
+}
+
+func (r *memoryReviewRepo) ListByStatus(ctx context.Context, status string, limit, offset int) ([]*domain.Review, error) {
+	reviews := make([]*domain.Review, 0)
+	for _, rv := range r.reviews {
+		if rv.Status == status {
+			reviews = append(reviews, rv)
+		}
+	}
+	if offset >= len(reviews) {
+		return []*domain.Review{}, nil
+	}
+	reviews = reviews[offset:]
+	if len(reviews) > limit {
+		reviews = reviews[:limit]
+	}
+	return reviews, nil
+}
+
+func (r *memoryReviewRepo) Moderate(ctx context.Context, id int64, status, note string, at time.Time) (*domain.Review, error) {
+	for _, rv := range r.reviews {
+		if rv.ID == id {
+			rv.Status = status
+			rv.ModerationNote = note
+			rv.ModeratedAt = &at
+			return rv, nil
+		}
+	}
+	return nil, sql.ErrNoRows
+}
+
+// memoryScoreRepo は Upsert / Delete の結果を保持する StationScoreRepository
+type memoryScoreRepo struct {
+	domain.StationScoreRepository
+	scores   map[int64]map[string]float64
+	notified int // NotifyUpdated の呼び出し回数
+}
+
+func (r *memoryScoreRepo) Upsert(ctx context.Context, scores []*domain.StationScore) error {
+	if r.scores == nil {
+		r.scores = make(map[int64]map[string]float64)
+	}
+	for _, s := range scores {
+		if r.scores[s.StationID] == nil {
+			r.scores[s.StationID] = make(map[string]float64)
+		}
+		r.scores[s.StationID][s.Axis] = s.NormalizedScore
+	}
+	return nil
+}
+
+func (r *memoryScoreRepo) GetByStationIDs(ctx context.Context, stationIDs []int64) (map[int64]map[string]float64, error) {
+	result := make(map[int64]map[string]float64)
+	for _, id := range stationIDs {
+		if scores, ok := r.scores[id]; ok {
+			result[id] = scores
+		}
+	}
+	return result, nil
+}
+
+func (r *memoryScoreRepo) Delete(ctx context.Context, stationID int64, axis string) error {
+	delete(r.scores[stationID], axis)
+	return nil
+}
+
+func (r *memoryScoreRepo) NotifyUpdated(ctx context.Context) error {
+	r.notified++
+	return nil
+}
+
+func newTestReviewUsecase(repo *memoryReviewRepo, scores *memoryScoreRepo) *reviewUsecase {
+	stations := &stubStationRepo{stations: map[int64]*domain.Station{1: {ID: 1}, 2: {ID: 2}, 3: {ID: 3}, 4: {ID: 4}}}
+	filter := service.NewReviewFilter(service.DefaultBannedWords())
+	return NewReviewUsecase(repo, stations, scores, filter, "secret").(*reviewUsecase)
+}
+
+func TestReviewUsecase_Submit(t *testing.T) {
+	repo := &memoryReviewRepo{}
+	u := newTestReviewUsecase(repo, &memoryScoreRepo{})
+	ctx := context.Background()
+
+	review, err := u.Submit(ctx, 1, domain.ReviewInput{
+		Ratings:  map[string]int{"safety": 4, "rent": 3},
+		Positive: "  夜も人通りが多く安心  ",
+	}, "203.0.113.1")
+	require.NoError(t, err)
+	assert.Equal(t, domain.ReviewStatusPending, review.Status)
+	assert.Equal(t, "夜も人通りが多く安心", review.Positive)
+	assert.Len(t, review.ClientHash, 64)
+	assert.NotContains(t, review.ClientHash, "203.0.113.1")
+	assert.Empty(t, review.Flags)
+
+	// URLは受け付けて審査時の注意点にする
+	review, err = u.Submit(ctx, 2, domain.ReviewInput{
+		Ratings:  map[string]int{"facility": 5},
+		Negative: "詳しくは https://example.com を見て",
+	}, "203.0.113.1")
+	require.NoError(t, err)
+	assert.Equal(t, []string{service.ReviewFlagURL}, review.Flags)
+
+	invalid := []domain.ReviewInput{
+		{Positive: "評価がない口コミです"},
+		{Ratings: map[string]int{"safety": 6}, Positive: "評価が範囲外の口コミ"},
+		{Ratings: map[string]int{"noise": 3}, Positive: "存在しない軸の口コミ"},
+		{Ratings: map[string]int{"safety": 3}},
+		{Ratings: map[string]int{"safety": 3}, Positive: "短い"},
+	}
+	for _, in := range invalid {
+		_, err := u.Submit(ctx, 3, in, "203.0.113.2")
+		assert.ErrorIs(t, err, domain.ErrInvalidReview, in)
+	}
+
+	_, err = u.Submit(ctx, 3, domain.ReviewInput{Ratings: map[string]int{"safety": 1}, Negative: "管理人は死ねばいい"}, "203.0.113.2")
+	assert.ErrorIs(t, err, domain.ErrReviewRejected)
+
+	_, err = u.Submit(ctx, 99, domain.ReviewInput{Ratings: map[string]int{"safety": 3}, Positive: "存在しない駅の口コミ"}, "203.0.113.2")
+	assert.ErrorIs(t, err, sql.ErrNoRows)
+}
+
+func TestReviewUsecase_RateLimit(t *testing.T) {
+	repo := &memoryReviewRepo{}
+	u := newTestReviewUsecase(repo, &memoryScoreRepo{})
+	ctx := context.Background()
+	in := domain.ReviewInput{Ratings: map[string]int{"access": 4}, Positive: "駅まで平坦で歩きやすい"}
+
+	_, err := u.Submit(ctx, 1, in, "198.51.100.1")
+	require.NoError(t, err)
+
+	// 同じ駅への再投稿
+	_, err = u.Submit(ctx, 1, in, "198.51.100.1")
+	assert.ErrorIs(t, err, ErrReviewRateLimited)
+	// 別の投稿元は投稿できる
+	_, err = u.Submit(ctx, 1, in, "198.51.100.2")
+	require.NoError(t, err)
+
+	// 1日の上限
+	for id := int64(2); id <= domain.MaxReviewsPerClientPerDay; id++ {
+		_, err = u.Submit(ctx, id, in, "198.51.100.1")
+		require.NoError(t, err)
+	}
+	_, err = u.Submit(ctx, 4, in, "198.51.100.1")
+	assert.ErrorIs(t, err, ErrReviewRateLimited)
+
+	// 24時間たてば投稿できる
+	u.now = func() time.Time { return time.Now().Add(25 * time.Hour) }
+	_, err = u.Submit(ctx, 4, in, "198.51.100.1")
+	require.NoError(t, err)
+}
+
+func TestReviewUsecase_Moderate(t *testing.T) {
+	repo := &memoryReviewRepo{}
+	scores := &memoryScoreRepo{}
+	u := newTestReviewUsecase(repo, scores)
+	ctx := context.Background()
+
+	for i, client := range []string{"a", "b", "c"} {
+		_, err := u.Submit(ctx, 1, domain.ReviewInput{
+			Ratings:  map[string]int{"safety": 5, "rent": 3 + i%2},
+			Positive: "スーパーが遅くまで開いている",
+		}, client)
+		require.NoError(t, err)
+	}
+
+	queue, err := u.Queue(ctx, "", 0, 0)
+	require.NoError(t, err)
+	require.Len(t, queue, 3)
+
+	_, err = u.Moderate(ctx, queue[0].ID, "deleted", "")
+	assert.ErrorIs(t, err, ErrInvalidModeration)
+	_, err = u.Moderate(ctx, 99, domain.ReviewStatusApproved, "")
+	assert.ErrorIs(t, err, sql.ErrNoRows)
+
+	// 件数が少ないうちはスコアを付けない
+	for _, rv := range queue[:2] {
+		_, err := u.Moderate(ctx, rv.ID, domain.ReviewStatusApproved, "")
+		require.NoError(t, err)
+	}
+	assert.NotContains(t, scores.scores[1], domain.ReviewScoreAxis)
+
+	_, err = u.Moderate(ctx, queue[2].ID, domain.ReviewStatusApproved, "問題なし")
+	require.NoError(t, err)
+	// 評価 5,3,5,4,5,3 の平均 25/6 を 0-100 にする
+	assert.InDelta(t, (25.0/6-1)/4*100, scores.scores[1][domain.ReviewScoreAxis], 1e-9)
+	// 審査のたびに更新を通知してキャッシュを破棄させる
+	assert.Equal(t, 3, scores.notified)
+
+	list, err := u.List(ctx, 1, 0)
+	require.NoError(t, err)
+	assert.Equal(t, 3, list.Summary.Count)
+	assert.Len(t, list.Reviews, 3)
+
+	// 却下すると件数が減りスコアを消す
+	_, err = u.Moderate(ctx, queue[0].ID, domain.ReviewStatusRejected, "重複")
+	require.NoError(t, err)
+	assert.NotContains(t, scores.scores[1], domain.ReviewScoreAxis)
+	assert.Equal(t, 4, scores.notified)
+
+	rejected, err := u.Queue(ctx, domain.ReviewStatusRejected, 10, 0)
+	require.NoError(t, err)
+	require.Len(t, rejected, 1)
+	assert.Equal(t, "重複", rejected[0].ModerationNote)
+}
+
+func TestStationUsecase_DetailReviews(t *testing.T) {
+	stations := &stubStationRepo{stations: map[int64]*domain.Station{1: {ID: 1, Name: "池尻大橋"}}}
+	reviews := &memoryReviewRepo{}
+	for i, rv := range []*domain.Review{
+		{Positive: "公園が近い", Status: domain.ReviewStatusApproved, Ratings: map[string]int{"facility": 4}},
+		{Negative: "坂が多い", Status: domain.ReviewStatusApproved, Ratings: map[string]int{"access": 2}},
+		{Positive: "審査待ちの口コミ", Status: domain.ReviewStatusPending, Ratings: map[string]int{"facility": 5}},
+	} {
+		rv.StationID = 1
+		rv.CreatedAt = time.Date(2026, 10, 1+i, 0, 0, 0, 0, time.UTC)
+		require.NoError(t, reviews.Create(context.Background(), rv))
+	}
+	u := NewStationUsecase(StationUsecaseDeps{
+		Repo:       stations,
+		ScoreRepo:  &stubScoreRepo{},
+		ReviewRepo: reviews,
+		Scoring:    service.NewScoringService(),
+	})
+
+	detail, err := u.GetStationDetail(context.Background(), 1)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"公園が近い"}, detail.AIInsight.ResidentVoices.Positive)
+	assert.Equal(t, []string{"坂が多い"}, detail.AIInsight.ResidentVoices.Negative)
+	assert.Equal(t, 2, detail.Reviews.Count)
+	assert.Equal(t, 3.0, detail.Reviews.Overall)
+
+	ratings := make([]string, 0, len(detail.Reviews.Ratings))
+	for axis := range detail.Reviews.Ratings {
+		ratings = append(ratings, axis)
+	}
+	sort.Strings(ratings)
+	assert.Equal(t, []string{"access", "facility"}, ratings)
+}
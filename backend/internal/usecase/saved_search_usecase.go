@@ -0,0 +1,133 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/gigaptera/hikkoshi-lens/backend/internal/domain"
+)
+
+var (
+	// ErrInvalidSavedSearch は保存する検索条件が不正な場合のエラー
+	ErrInvalidSavedSearch = errors.New("invalid saved search")
+	// ErrTooManySavedSearches は保存できる検索条件の上限に達した場合のエラー
+	ErrTooManySavedSearches = fmt.Errorf("at most %d saved searches per user", domain.MaxSavedSearchesPerUser)
+)
+
+// SavedSearchUsecase はログイン中の利用者の保存した検索条件
+// 結果の変化の検出と通知は SearchAlertUsecase (cmd/alerts) が行う
+type SavedSearchUsecase interface {
+	List(ctx context.Context, userID int64) ([]*domain.SavedSearch, error)
+	Create(ctx context.Context, userID int64, search *domain.SavedSearch) (*domain.SavedSearch, error)
+	// Get は前回実行時の結果付きで返す
+	Get(ctx context.Context, userID, id int64) (*domain.SavedSearch, error)
+	Delete(ctx context.Context, userID, id int64) error
+}
+
+type savedSearchUsecase struct {
+	repo domain.SavedSearchRepository
+}
+
+func NewSavedSearchUsecase(repo domain.SavedSearchRepository) SavedSearchUsecase {
+	return &savedSearchUsecase{repo: repo}
+}
+
+func (u *savedSearchUsecase) List(ctx context.Context, userID int64) ([]*domain.SavedSearch, error) {
+	return u.repo.List(ctx, userID)
+}
+
+func (u *savedSearchUsecase) Create(ctx context.Context, userID int64, search *domain.SavedSearch) (*domain.SavedSearch, error) {
+	if err := normalizeSavedSearch(search); err != nil {
+		return nil, err
+	}
+	n, err := u.repo.Count(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if n >= domain.MaxSavedSearchesPerUser {
+		return nil, ErrTooManySavedSearches
+	}
+
+	search.ID = 0
+	search.UserID = userID
+	search.LastRunAt = nil
+	if err := u.repo.Create(ctx, search); err != nil {
+		return nil, err
+	}
+	return search, nil
+}
+
+func (u *savedSearchUsecase) Get(ctx context.Context, userID, id int64) (*domain.SavedSearch, error) {
+	search, err := u.repo.Get(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+	search.Results, err = u.repo.GetResults(ctx, search.ID)
+	if err != nil {
+		return nil, err
+	}
+	return search, nil
+}
+
+func (u *savedSearchUsecase) Delete(ctx context.Context, userID, id int64) error {
+	return u.repo.Delete(ctx, userID, id)
+}
+
+// normalizeSavedSearch は入力を検証し、省略された値に既定値を入れる
+func normalizeSavedSearch(s *domain.SavedSearch) error {
+	invalid := func(msg string) error {
+		return fmt.Errorf("%w: %s", ErrInvalidSavedSearch, msg)
+	}
+
+	s.Name = strings.TrimSpace(s.Name)
+	if s.Name == "" || utf8.RuneCountInString(s.Name) > domain.MaxSavedSearchNameLength {
+		return invalid(fmt.Sprintf("name must be 1-%d characters", domain.MaxSavedSearchNameLength))
+	}
+	if err := normalizeSearch(s.Lat, s.Lon, &s.Filter); err != nil {
+		return invalid(err.Error())
+	}
+
+	if s.RentThreshold < 0 {
+		return invalid("rent_threshold must not be negative")
+	}
+	if s.RentThreshold == 0 {
+		s.RentThreshold = domain.DefaultRentChangeThreshold
+	}
+
+	switch s.Channel {
+	case "", domain.NotifyChannelEmail:
+		s.WebhookURL = ""
+	case domain.NotifyChannelWebhook:
+		if !domain.IsValidWebhookURL(s.WebhookURL) {
+			return invalid("webhook_url must be an absolute https URL")
+		}
+	default:
+		return invalid("channel must be email or webhook")
+	}
+	return nil
+}
+
+// normalizeSearch は検索の起点と条件を検証し、省略された値に既定値を入れる (保存した検索・共有リンク共通)
+func normalizeSearch(lat, lon float64, f *domain.StationFilter) error {
+	if lat < -90 || lat > 90 || lon < -180 || lon > 180 {
+		return errors.New("lat/lon out of range")
+	}
+	if f.RadiusMeter <= 0 {
+		f.RadiusMeter = 500
+	}
+	if f.RadiusMeter > 5000 {
+		return errors.New("radius_meter must be at most 5000")
+	}
+	if f.MinRent < 0 || f.MaxRent < 0 || (f.MaxRent > 0 && f.MinRent > f.MaxRent) {
+		return errors.New("invalid rent range")
+	}
+	if f.SubsidyType == "" {
+		f.SubsidyType = "none"
+	}
+	// 結果の比較・共有先での表示に使うためスコアは常に計算する
+	f.CalculateScores = true
+	return nil
+}
@@ -0,0 +1,124 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/gigaptera/hikkoshi-lens/backend/internal/domain"
+)
+
+// AlertRunStats は通知ジョブ1回分の集計
+type AlertRunStats struct {
+	Searches int // 実行した検索条件の数
+	Baseline int // 初回のため結果を保存しただけのもの
+	Notified int // 変化があり通知したもの
+	Failed   int // 検索・通知・保存に失敗したもの (次回に再試行される)
+}
+
+// SearchAlertUsecase は保存した検索を再実行し、前回からの変化を通知する
+// 家賃相場・スコアの更新後に cmd/alerts から呼ぶ
+type SearchAlertUsecase interface {
+	Run(ctx context.Context) (AlertRunStats, error)
+}
+
+type searchAlertUsecase struct {
+	repo           domain.SavedSearchRepository
+	stations       StationUsecase // 最新の値で比べるためキャッシュなしのものを渡す
+	notifiers      map[string]domain.Notifier
+	defaultChannel string
+}
+
+// NewSearchAlertUsecase は通知ジョブを作る
+// notifiers は送り先 (domain.NotifyChannel*) ごとの通知手段。channel が空の検索条件には defaultChannel を使う
+func NewSearchAlertUsecase(repo domain.SavedSearchRepository, stations StationUsecase, notifiers map[string]domain.Notifier, defaultChannel string) SearchAlertUsecase {
+	return &searchAlertUsecase{repo: repo, stations: stations, notifiers: notifiers, defaultChannel: defaultChannel}
+}
+
+func (u *searchAlertUsecase) Run(ctx context.Context) (AlertRunStats, error) {
+	var stats AlertRunStats
+	searches, err := u.repo.ListAll(ctx)
+	if err != nil {
+		return stats, err
+	}
+
+	for _, s := range searches {
+		if err := ctx.Err(); err != nil {
+			return stats, err
+		}
+		stats.Searches++
+		baseline := s.LastRunAt == nil
+		notified, err := u.runOne(ctx, s)
+		if err != nil {
+			// 1件の失敗で他の利用者への通知を止めない
+			log.Printf("Warning: saved search %d: %v", s.ID, err)
+			stats.Failed++
+			continue
+		}
+		switch {
+		case baseline:
+			stats.Baseline++
+		case notified:
+			stats.Notified++
+		}
+	}
+	return stats, nil
+}
+
+// runOne は1件の検索条件を再実行し、変化があれば通知してから結果を保存する
+// 通知に失敗した場合は結果を保存せず、次回の実行で同じ変化を再度通知する
+func (u *searchAlertUsecase) runOne(ctx context.Context, s *domain.SavedSearch) (bool, error) {
+	filter := s.Filter
+	filter.CalculateScores = true
+	if filter.RadiusMeter <= 0 {
+		filter.RadiusMeter = 500
+	}
+
+	stations, err := u.stations.GetNearbyStations(ctx, s.Lat, s.Lon, filter)
+	if err != nil {
+		return false, fmt.Errorf("search: %w", err)
+	}
+	curr := make([]*domain.SavedSearchResult, 0, len(stations))
+	for _, st := range stations {
+		curr = append(curr, &domain.SavedSearchResult{
+			StationID:   st.ID,
+			StationName: st.Name,
+			RentAvg:     st.RentAvg,
+			TotalScore:  st.TotalScore,
+		})
+	}
+
+	notified := false
+	if s.LastRunAt != nil {
+		prev, err := u.repo.GetResults(ctx, s.ID)
+		if err != nil {
+			return false, fmt.Errorf("load previous results: %w", err)
+		}
+		if changes := domain.DiffSearchResults(prev, curr, s.RentThreshold); len(changes) > 0 {
+			notifier, err := u.notifier(s.Channel)
+			if err != nil {
+				return false, err
+			}
+			if err := notifier.Notify(ctx, &domain.SearchAlert{Search: s, Changes: changes}); err != nil {
+				return false, fmt.Errorf("notify: %w", err)
+			}
+			notified = true
+		}
+	}
+
+	if err := u.repo.ReplaceResults(ctx, s.ID, curr); err != nil {
+		return notified, fmt.Errorf("save results: %w", err)
+	}
+	return notified, nil
+}
+
+func (u *searchAlertUsecase) notifier(channel string) (domain.Notifier, error) {
+	if channel == "" {
+		channel = u.defaultChannel
+	}
+	n, ok := u.notifiers[channel]
+	if !ok {
+		return nil, fmt.Errorf("no notifier for channel %q", channel)
+	}
+	return n, nil
+}
@@ -0,0 +1,153 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/gigaptera/hikkoshi-lens/backend/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// memorySavedSearchRepo は通知ジョブが使うメソッドだけを持つインメモリの SavedSearchRepository
+type memorySavedSearchRepo struct {
+	domain.SavedSearchRepository
+	searches []*domain.SavedSearch
+	results  map[int64][]*domain.SavedSearchResult
+}
+
+func (r *memorySavedSearchRepo) ListAll(ctx context.Context) ([]*domain.SavedSearch, error) {
+	return r.searches, nil
+}
+
+func (r *memorySavedSearchRepo) GetResults(ctx context.Context, searchID int64) ([]*domain.SavedSearchResult, error) {
+	return r.results[searchID], nil
+}
+
+func (r *memorySavedSearchRepo) ReplaceResults(ctx context.Context, searchID int64, results []*domain.SavedSearchResult) error {
+	r.results[searchID] = results
+	now := time.Now()
+	for _, s := range r.searches {
+		if s.ID == searchID {
+			s.LastRunAt = &now
+		}
+	}
+	return nil
+}
+
+// stubSearch は GetNearbyStations で固定の駅を返す StationUsecase
+type stubSearch struct {
+	StationUsecase
+	stations []*domain.Station
+	filter   domain.StationFilter
+}
+
+func (s *stubSearch) GetNearbyStations(ctx context.Context, lat, lon float64, filter domain.StationFilter) ([]*domain.Station, error) {
+	s.filter = filter
+	return s.stations, nil
+}
+
+type recordingNotifier struct {
+	alerts []*domain.SearchAlert
+	err    error
+}
+
+func (n *recordingNotifier) Notify(ctx context.Context, alert *domain.SearchAlert) error {
+	if n.err != nil {
+		return n.err
+	}
+	n.alerts = append(n.alerts, alert)
+	return nil
+}
+
+func TestSearchAlertUsecase_Run(t *testing.T) {
+	repo := &memorySavedSearchRepo{
+		searches: []*domain.SavedSearch{{ID: 1, Name: "渋谷通勤", RentThreshold: 0.3, Filter: domain.StationFilter{MaxRent: 10}}},
+		results:  map[int64][]*domain.SavedSearchResult{},
+	}
+	search := &stubSearch{stations: []*domain.Station{
+		{ID: 10, Name: "池尻大橋", RentAvg: 9.8},
+		{ID: 11, Name: "三軒茶屋", RentAvg: 9.5},
+	}}
+	notifier := &recordingNotifier{}
+	u := NewSearchAlertUsecase(repo, search, map[string]domain.Notifier{domain.NotifyChannelLog: notifier}, domain.NotifyChannelLog)
+
+	// 初回は結果を保存するだけで通知しない
+	stats, err := u.Run(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, AlertRunStats{Searches: 1, Baseline: 1}, stats)
+	assert.Empty(t, notifier.alerts)
+	assert.Len(t, repo.results[1], 2)
+	assert.True(t, search.filter.CalculateScores)
+	assert.Equal(t, 500, search.filter.RadiusMeter)
+
+	// 新しい駅と閾値以上の値下がり。閾値未満の変化は通知しない
+	search.stations = []*domain.Station{
+		{ID: 10, Name: "池尻大橋", RentAvg: 9.7},
+		{ID: 11, Name: "三軒茶屋", RentAvg: 9.1},
+		{ID: 12, Name: "駒場東大前", RentAvg: 9.9},
+	}
+	stats, err = u.Run(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, AlertRunStats{Searches: 1, Notified: 1}, stats)
+	require.Len(t, notifier.alerts, 1)
+	assert.Equal(t, []domain.SearchChange{
+		{Kind: domain.SearchChangeNew, StationID: 12, StationName: "駒場東大前", NewRent: 9.9},
+		{Kind: domain.SearchChangeRentDown, StationID: 11, StationName: "三軒茶屋", OldRent: 9.5, NewRent: 9.1},
+	}, notifier.alerts[0].Changes)
+
+	// 変化がなければ通知しない
+	stats, err = u.Run(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, AlertRunStats{Searches: 1}, stats)
+	assert.Len(t, notifier.alerts, 1)
+}
+
+func TestSearchAlertUsecase_NotifyFailureKeepsSnapshot(t *testing.T) {
+	last := time.Now()
+	repo := &memorySavedSearchRepo{
+		searches: []*domain.SavedSearch{{ID: 1, RentThreshold: 0.3, Channel: domain.NotifyChannelWebhook, LastRunAt: &last}},
+		results: map[int64][]*domain.SavedSearchResult{
+			1: {{StationID: 10, StationName: "池尻大橋", RentAvg: 10.5}},
+		},
+	}
+	search := &stubSearch{stations: []*domain.Station{{ID: 10, Name: "池尻大橋", RentAvg: 9.9}}}
+	webhook := &recordingNotifier{err: errors.New("connection refused")}
+	u := NewSearchAlertUsecase(repo, search, map[string]domain.Notifier{domain.NotifyChannelWebhook: webhook}, domain.NotifyChannelLog)
+
+	stats, err := u.Run(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, AlertRunStats{Searches: 1, Failed: 1}, stats)
+	// 次回も同じ変化を通知できるよう前回の結果を残す
+	assert.Equal(t, 10.5, repo.results[1][0].RentAvg)
+
+	webhook.err = nil
+	stats, err = u.Run(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 1, stats.Notified)
+	require.Len(t, webhook.alerts, 1)
+	assert.Equal(t, domain.SearchChangeRentDown, webhook.alerts[0].Changes[0].Kind)
+}
+
+func TestNormalizeSavedSearch(t *testing.T) {
+	s := &domain.SavedSearch{Name: " 渋谷通勤 ", Lat: 35.66, Lon: 139.70}
+	require.NoError(t, normalizeSavedSearch(s))
+	assert.Equal(t, "渋谷通勤", s.Name)
+	assert.Equal(t, 500, s.Filter.RadiusMeter)
+	assert.Equal(t, "none", s.Filter.SubsidyType)
+	assert.Equal(t, domain.DefaultRentChangeThreshold, s.RentThreshold)
+
+	invalid := []*domain.SavedSearch{
+		{Name: "", Lat: 35, Lon: 139},
+		{Name: "x", Lat: 91, Lon: 139},
+		{Name: "x", Lat: 35, Lon: 139, Filter: domain.StationFilter{MinRent: 12, MaxRent: 10}},
+		{Name: "x", Lat: 35, Lon: 139, RentThreshold: -1},
+		{Name: "x", Lat: 35, Lon: 139, Channel: "sms"},
+		{Name: "x", Lat: 35, Lon: 139, Channel: domain.NotifyChannelWebhook, WebhookURL: "http://example.com/hook"},
+	}
+	for _, s := range invalid {
+		assert.ErrorIs(t, normalizeSavedSearch(s), ErrInvalidSavedSearch, "%+v", s)
+	}
+}
@@ -0,0 +1,196 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/gigaptera/hikkoshi-lens/backend/internal/domain"
+	"github.com/gigaptera/hikkoshi-lens/backend/internal/domain/service"
+)
+
+var (
+	// ErrInvalidShortlistName は候補リスト名が空または長すぎる場合のエラー
+	ErrInvalidShortlistName = errors.New("shortlist name must be 1-100 characters")
+	// ErrNoteTooLong はメモが長すぎる場合のエラー
+	ErrNoteTooLong = errors.New("note must be at most 1000 characters")
+)
+
+// ShortlistUsecase はお気に入りと名前付きの候補リスト
+// 駅には一覧を返す時点のスコアと家賃相場を付ける (保存時の値ではなく常に最新)
+// filter は Weights (スコアの重み)、BuildingType と Layout (家賃相場の条件) だけを使う
+type ShortlistUsecase interface {
+	ListFavorites(ctx context.Context, userID int64, filter domain.StationFilter) ([]*domain.Favorite, error)
+	SaveFavorite(ctx context.Context, userID, stationID int64, note string) (*domain.Favorite, error)
+	DeleteFavorite(ctx context.Context, userID, stationID int64) error
+
+	ListShortlists(ctx context.Context, userID int64) ([]*domain.Shortlist, error)
+	CreateShortlist(ctx context.Context, userID int64, name string) (*domain.Shortlist, error)
+	GetShortlist(ctx context.Context, userID, shortlistID int64, filter domain.StationFilter) (*domain.Shortlist, error)
+	RenameShortlist(ctx context.Context, userID, shortlistID int64, name string) (*domain.Shortlist, error)
+	DeleteShortlist(ctx context.Context, userID, shortlistID int64) error
+	SaveShortlistItem(ctx context.Context, userID, shortlistID, stationID int64, note string) (*domain.ShortlistItem, error)
+	DeleteShortlistItem(ctx context.Context, userID, shortlistID, stationID int64) error
+}
+
+type shortlistUsecase struct {
+	repo        domain.ShortlistRepository
+	stationRepo domain.StationRepository
+	scoreRepo   domain.StationScoreRepository
+	scoring     *service.ScoringService
+}
+
+func NewShortlistUsecase(repo domain.ShortlistRepository, stationRepo domain.StationRepository, scoreRepo domain.StationScoreRepository, scoring *service.ScoringService) ShortlistUsecase {
+	return &shortlistUsecase{repo: repo, stationRepo: stationRepo, scoreRepo: scoreRepo, scoring: scoring}
+}
+
+func (u *shortlistUsecase) ListFavorites(ctx context.Context, userID int64, filter domain.StationFilter) ([]*domain.Favorite, error) {
+	favorites, err := u.repo.ListFavorites(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	ids := make([]int64, len(favorites))
+	for i, f := range favorites {
+		ids[i] = f.StationID
+	}
+	stations, err := u.scoredStations(ctx, ids, filter)
+	if err != nil {
+		return nil, err
+	}
+	for _, f := range favorites {
+		f.Station = stations[f.StationID]
+	}
+	return favorites, nil
+}
+
+func (u *shortlistUsecase) SaveFavorite(ctx context.Context, userID, stationID int64, note string) (*domain.Favorite, error) {
+	if utf8.RuneCountInString(note) > domain.MaxNoteLength {
+		return nil, ErrNoteTooLong
+	}
+	// 存在しない駅は sql.ErrNoRows を返す
+	if _, err := u.stationRepo.GetStation(ctx, stationID); err != nil {
+		return nil, err
+	}
+	fav := &domain.Favorite{UserID: userID, StationID: stationID, Note: note}
+	if err := u.repo.UpsertFavorite(ctx, fav); err != nil {
+		return nil, err
+	}
+	return fav, nil
+}
+
+func (u *shortlistUsecase) DeleteFavorite(ctx context.Context, userID, stationID int64) error {
+	return u.repo.DeleteFavorite(ctx, userID, stationID)
+}
+
+func (u *shortlistUsecase) ListShortlists(ctx context.Context, userID int64) ([]*domain.Shortlist, error) {
+	return u.repo.ListShortlists(ctx, userID)
+}
+
+func (u *shortlistUsecase) CreateShortlist(ctx context.Context, userID int64, name string) (*domain.Shortlist, error) {
+	name, err := validateShortlistName(name)
+	if err != nil {
+		return nil, err
+	}
+	list := &domain.Shortlist{UserID: userID, Name: name}
+	if err := u.repo.CreateShortlist(ctx, list); err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+func (u *shortlistUsecase) GetShortlist(ctx context.Context, userID, shortlistID int64, filter domain.StationFilter) (*domain.Shortlist, error) {
+	list, err := u.repo.GetShortlist(ctx, userID, shortlistID)
+	if err != nil {
+		return nil, err
+	}
+	items, err := u.repo.ListShortlistItems(ctx, shortlistID)
+	if err != nil {
+		return nil, err
+	}
+	ids := make([]int64, len(items))
+	for i, item := range items {
+		ids[i] = item.StationID
+	}
+	stations, err := u.scoredStations(ctx, ids, filter)
+	if err != nil {
+		return nil, err
+	}
+	for _, item := range items {
+		item.Station = stations[item.StationID]
+	}
+	list.Items = items
+	return list, nil
+}
+
+func (u *shortlistUsecase) RenameShortlist(ctx context.Context, userID, shortlistID int64, name string) (*domain.Shortlist, error) {
+	name, err := validateShortlistName(name)
+	if err != nil {
+		return nil, err
+	}
+	if err := u.repo.RenameShortlist(ctx, userID, shortlistID, name); err != nil {
+		return nil, err
+	}
+	return u.repo.GetShortlist(ctx, userID, shortlistID)
+}
+
+func (u *shortlistUsecase) DeleteShortlist(ctx context.Context, userID, shortlistID int64) error {
+	return u.repo.DeleteShortlist(ctx, userID, shortlistID)
+}
+
+func (u *shortlistUsecase) SaveShortlistItem(ctx context.Context, userID, shortlistID, stationID int64, note string) (*domain.ShortlistItem, error) {
+	if utf8.RuneCountInString(note) > domain.MaxNoteLength {
+		return nil, ErrNoteTooLong
+	}
+	// 他人のリスト・存在しない駅は sql.ErrNoRows
+	if _, err := u.repo.GetShortlist(ctx, userID, shortlistID); err != nil {
+		return nil, err
+	}
+	if _, err := u.stationRepo.GetStation(ctx, stationID); err != nil {
+		return nil, err
+	}
+	item := &domain.ShortlistItem{ShortlistID: shortlistID, StationID: stationID, Note: note}
+	if err := u.repo.UpsertShortlistItem(ctx, item); err != nil {
+		return nil, err
+	}
+	return item, nil
+}
+
+func (u *shortlistUsecase) DeleteShortlistItem(ctx context.Context, userID, shortlistID, stationID int64) error {
+	if _, err := u.repo.GetShortlist(ctx, userID, shortlistID); err != nil {
+		return err
+	}
+	return u.repo.DeleteShortlistItem(ctx, shortlistID, stationID)
+}
+
+// scoredStations は駅を現在のスコア・家賃相場付きで取得する
+func (u *shortlistUsecase) scoredStations(ctx context.Context, ids []int64, filter domain.StationFilter) (map[int64]*domain.Station, error) {
+	stations, err := u.stationRepo.GetByIDs(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	attachAxisScores(ctx, u.scoreRepo, stations)
+	u.scoring.CalculateScores(stations, filter.Weights)
+
+	result := make(map[int64]*domain.Station, len(stations))
+	for _, s := range stations {
+		if filter.BuildingType != "" && filter.Layout != "" {
+			for _, mp := range s.MarketPrices {
+				if mp.BuildingType == filter.BuildingType && mp.Layout == filter.Layout {
+					s.RentAvg = mp.Rent
+					break
+				}
+			}
+		}
+		result[s.ID] = s
+	}
+	return result, nil
+}
+
+func validateShortlistName(name string) (string, error) {
+	name = strings.TrimSpace(name)
+	if name == "" || utf8.RuneCountInString(name) > domain.MaxShortlistNameLength {
+		return "", ErrInvalidShortlistName
+	}
+	return name, nil
+}
@@ -2,6 +2,10 @@ package usecase
 
 import (
 	"context"
+	"database/sql"
+	"errors"
+	"log"
+	"math"
 
 	"github.com/gigaptera/hikkoshi-lens/backend/internal/domain"
 	"github.com/gigaptera/hikkoshi-lens/backend/internal/domain/service"
@@ -15,12 +19,360 @@ type StationUsecase interface {
 }
 
 type stationUsecase struct {
-	repo    domain.StationRepository
-	scoring *service.ScoringService
+	repo          domain.StationRepository
+	scoreRepo     domain.StationScoreRepository
+	tagRepo       domain.TagRepository
+	insightRepo   domain.InsightRepository
+	reviewRepo    domain.ReviewRepository
+	passengerRepo domain.PassengerRepository
+	terrainRepo   domain.TerrainRepository
+	poiRepo       domain.POIRepository
+	nightRepo     domain.NightSafetyRepository
+	walk          *WalkDistances
+	scoring       *service.ScoringService
 }
 
-func NewStationUsecase(repo domain.StationRepository, scoring *service.ScoringService) StationUsecase {
-	return &stationUsecase{repo: repo, scoring: scoring}
+// StationUsecaseDeps は駅検索のユースケースの依存
+// Repo・ScoreRepo・Scoring は必須。他のリポジトリは省略でき、その場合はタグ・駅詳細の該当項目が空になる
+type StationUsecaseDeps struct {
+	Repo          domain.StationRepository
+	ScoreRepo     domain.StationScoreRepository
+	TagRepo       domain.TagRepository
+	InsightRepo   domain.InsightRepository
+	ReviewRepo    domain.ReviewRepository
+	PassengerRepo domain.PassengerRepository
+	TerrainRepo   domain.TerrainRepository
+	POIRepo       domain.POIRepository
+	NightRepo     domain.NightSafetyRepository
+	// Walk が nil または徒歩ネットワークなしの場合、access は検索地点からの直線距離で計算する
+	Walk    *WalkDistances
+	Scoring *service.ScoringService
+}
+
+// NewStationUsecase は駅検索のユースケースを作る
+func NewStationUsecase(deps StationUsecaseDeps) StationUsecase {
+	return &stationUsecase{
+		repo:          deps.Repo,
+		scoreRepo:     deps.ScoreRepo,
+		tagRepo:       deps.TagRepo,
+		insightRepo:   deps.InsightRepo,
+		reviewRepo:    deps.ReviewRepo,
+		passengerRepo: deps.PassengerRepo,
+		terrainRepo:   deps.TerrainRepo,
+		poiRepo:       deps.POIRepo,
+		nightRepo:     deps.NightRepo,
+		walk:          deps.Walk,
+		scoring:       deps.Scoring,
+	}
+}
+
+// attachAxisScores は station_scores の事前計算済みスコアを駅に設定する
+// 取得に失敗した場合はスコア計算時にその場で計算されるため、ログのみ出して続行する
+func attachAxisScores(ctx context.Context, scoreRepo domain.StationScoreRepository, stations []*domain.Station) {
+	if len(stations) == 0 {
+		return
+	}
+	ids := make([]int64, len(stations))
+	for i, s := range stations {
+		ids[i] = s.ID
+	}
+
+	axisScores, err := scoreRepo.GetByStationIDs(ctx, ids)
+	if err != nil {
+		log.Printf("Warning: failed to load precomputed station scores: %v", err)
+		return
+	}
+	for _, s := range stations {
+		s.AxisScores = axisScores[s.ID]
+	}
+}
+
+// attachWalkDistances は検索地点 (勤務地) から駅までの道路距離と徒歩分数を設定する
+// 道路距離は domain.MaxWalkAccessMeter まで経路を探し、それより遠い駅や経路のない駅は直線距離から推定する
+// (同じ検索結果の中で直線距離と道路距離が混ざらないよう、全駅に WalkDistance を設定する)
+func attachWalkDistances(walk *WalkDistances, lat, lon float64, stations []*domain.Station) {
+	if !walk.Enabled() || len(stations) == 0 {
+		return
+	}
+	targets := make([]domain.Location, len(stations))
+	straight := make([]float64, len(stations))
+	for i, s := range stations {
+		targets[i] = domain.Location{Lat: s.Lat, Lon: s.Lon}
+		straight[i] = s.Distance
+		if straight[i] == 0 {
+			// 家賃補助で追加した駅は検索時の距離を持たない
+			straight[i] = service.HaversineMeter(lat, lon, s.Lat, s.Lon)
+		}
+	}
+	meters := walk.Meters(domain.Location{Lat: lat, Lon: lon}, targets, straight, domain.MaxWalkAccessMeter)
+	for i, s := range stations {
+		s.WalkDistance = math.Round(meters[i])
+		s.WalkMinutes = 0
+		if meters[i] <= domain.MaxWalkAccessMeter {
+			s.WalkMinutes = domain.WalkMinutes(meters[i])
+		}
+	}
+}
+
+// attachTags は事前計算済みのタグを駅に設定する
+// 取得に失敗した場合はタグなしで続行する (タグでの絞り込みがある場合はエラーを返す)
+func attachTags(ctx context.Context, tagRepo domain.TagRepository, stations []*domain.Station, required bool) error {
+	if tagRepo == nil || len(stations) == 0 {
+		return nil
+	}
+	ids := make([]int64, len(stations))
+	for i, s := range stations {
+		ids[i] = s.ID
+	}
+
+	tags, err := tagRepo.GetByStationIDs(ctx, ids)
+	if err != nil {
+		if required {
+			return err
+		}
+		log.Printf("Warning: failed to load station tags: %v", err)
+		return nil
+	}
+	for _, s := range stations {
+		s.Tags = tags[s.ID]
+	}
+	return nil
+}
+
+// loadInsight は生成済みの解説文を返す
+// 未生成・取得失敗の場合は空の解説文を返す (作り話のテキストは返さない)
+func loadInsight(ctx context.Context, insightRepo domain.InsightRepository, stationID int64) domain.AIInsight {
+	insight := domain.AIInsight{}
+	if insightRepo == nil {
+		return insight
+	}
+	stored, err := insightRepo.Get(ctx, stationID)
+	switch {
+	case err == nil:
+		insight = stored.Insight
+		insight.LastUpdated = stored.GeneratedAt.Format("2006-01-02")
+	case !errors.Is(err, sql.ErrNoRows):
+		log.Printf("Warning: failed to load station insight: %v", err)
+	}
+	if insight.Summary.Pros == nil {
+		insight.Summary.Pros = []string{}
+	}
+	if insight.Summary.Cons == nil {
+		insight.Summary.Cons = []string{}
+	}
+	return insight
+}
+
+// detailScoreWeights は駅の詳細の総合スコアの重み (駅そのものの良さを同じ重みで合計する)
+// access は勤務地を指定しない詳細では計算できず、bustle は好みで良し悪しが分かれるため含めない
+// night_safety は safety 軸に含まれるため重ねて数えない
+var detailScoreWeights = map[string]int{
+	"rent":                    1,
+	"facility":                1,
+	"safety":                  1,
+	"disaster":                1,
+	domain.ReviewScoreAxis:    1,
+	domain.HillinessScoreAxis: 1,
+}
+
+// detailScore は検索と同じく station_scores の値から駅の詳細のスコアを計算する
+func (u *stationUsecase) detailScore(ctx context.Context, station *domain.Station) domain.DetailScore {
+	attachAxisScores(ctx, u.scoreRepo, []*domain.Station{station})
+	u.scoring.CalculateScores([]*domain.Station{station}, detailScoreWeights)
+	d := station.ScoreDetails
+	return domain.DetailScore{
+		Total: station.TotalScore,
+		Radar: domain.RadarScore{
+			Rent:     d["rent"],
+			Safety:   d["safety"],
+			Facility: d["facility"],
+			// 勤務地からの距離で決まるため、詳細では中間値
+			Access:   service.NeutralAxisScore,
+			Disaster: d["disaster"],
+		},
+		Details: d,
+	}
+}
+
+// marketData は間取りごとの家賃相場と、同じ路線の隣の駅との平均家賃の差を返す
+// 隣の駅を取得できない場合は差を 0 として続行する
+func (u *stationUsecase) marketData(ctx context.Context, station *domain.Station) domain.MarketData {
+	data := domain.MarketData{Prices: layoutRents(station.MarketPrices)}
+	neighbors, err := u.repo.GetByLine(ctx, station.OrganizationCode, station.LineName)
+	if err != nil {
+		log.Printf("Warning: failed to load stations on the line: %v", err)
+		return data
+	}
+	for i, s := range neighbors {
+		if s.ID != station.ID {
+			continue
+		}
+		if i+1 < len(neighbors) {
+			data.NeighborComparison.NextStationDiff = rentDiffYen(station.MarketPrices, neighbors[i+1].MarketPrices)
+		}
+		if i > 0 {
+			data.NeighborComparison.PrevStationDiff = rentDiffYen(station.MarketPrices, neighbors[i-1].MarketPrices)
+		}
+		break
+	}
+	return data
+}
+
+// layoutRents は間取りごとの家賃相場 (建物種別の平均、万円) を返す
+func layoutRents(prices []*domain.MarketPrice) map[string]float64 {
+	sums := make(map[string]float64)
+	counts := make(map[string]int)
+	for _, mp := range prices {
+		if mp.Rent > 0 {
+			sums[mp.Layout] += mp.Rent
+			counts[mp.Layout]++
+		}
+	}
+	rents := make(map[string]float64, len(sums))
+	for layout, sum := range sums {
+		rents[layout] = math.Round(sum/float64(counts[layout])*100) / 100
+	}
+	return rents
+}
+
+// rentDiffYen は全ての間取りの平均家賃の差 (円) を返す。どちらかの相場がない場合は 0
+func rentDiffYen(own, other []*domain.MarketPrice) float64 {
+	a, okA := averageRent(own)
+	b, okB := averageRent(other)
+	if !okA || !okB {
+		return 0
+	}
+	return math.Round((a - b) * 10000)
+}
+
+func averageRent(prices []*domain.MarketPrice) (float64, bool) {
+	sum, count := 0.0, 0
+	for _, mp := range prices {
+		if mp.Rent > 0 {
+			sum += mp.Rent
+			count++
+		}
+	}
+	if count == 0 {
+		return 0, false
+	}
+	return sum / float64(count), true
+}
+
+// loadPassengers は駅の1日あたりの乗降客数を詳細に設定する
+// 未登録・取得失敗の場合は null のまま続行する
+func loadPassengers(ctx context.Context, passengerRepo domain.PassengerRepository, detail *domain.StationDetail) {
+	if passengerRepo == nil {
+		return
+	}
+	p, err := passengerRepo.Get(ctx, detail.ID)
+	switch {
+	case err == nil:
+		detail.DailyPassengers = p.DailyPassengers
+		detail.PassengersYear = p.PassengersYear
+	case !errors.Is(err, sql.ErrNoRows):
+		log.Printf("Warning: failed to load daily passengers: %v", err)
+	}
+}
+
+// loadTerrain は駅周辺の地形を詳細に設定する
+// 未計算・取得失敗の場合は省略して続行する
+func loadTerrain(ctx context.Context, terrainRepo domain.TerrainRepository, detail *domain.StationDetail) {
+	if terrainRepo == nil {
+		return
+	}
+	t, err := terrainRepo.Get(ctx, detail.ID)
+	switch {
+	case err == nil:
+		detail.Terrain = t
+	case !errors.Is(err, sql.ErrNoRows):
+		log.Printf("Warning: failed to load station terrain: %v", err)
+	}
+}
+
+// loadNightSafety は街灯の密度と最寄りの交番までの距離を詳細に設定する
+// 未集計・取得失敗の場合は省略して続行する
+func loadNightSafety(ctx context.Context, nightRepo domain.NightSafetyRepository, detail *domain.StationDetail) {
+	if nightRepo == nil {
+		return
+	}
+	n, err := nightRepo.Get(ctx, detail.ID)
+	switch {
+	case err == nil:
+		detail.NightSafety = n
+	case !errors.Is(err, sql.ErrNoRows):
+		log.Printf("Warning: failed to load night safety: %v", err)
+	}
+}
+
+// loadNearestShelters は駅に最も近い避難場所 (距離によらず domain.MaxNearestShelters 件) を詳細に設定する
+// 取得に失敗した場合は空のまま続行する
+func loadNearestShelters(ctx context.Context, poiRepo domain.POIRepository, walk *WalkDistances, station *domain.Station, detail *domain.StationDetail) {
+	detail.NearestShelters = []*domain.POI{}
+	if poiRepo == nil {
+		return
+	}
+	shelters, err := poiRepo.GetNearest(ctx, station.ID, domain.POICategoryShelter, domain.MaxNearestShelters)
+	if err != nil {
+		log.Printf("Warning: failed to load nearest shelters: %v", err)
+		return
+	}
+	if len(shelters) == 0 {
+		return
+	}
+	// 近い順のため、最も遠い避難場所の直線距離を基準に道路距離を探す
+	setPOIWalkMinutes(walk, station, shelters, domain.EstimateWalkMeters(shelters[len(shelters)-1].Distance)*2)
+	detail.NearestShelters = shelters
+}
+
+// loadReviews は承認済み口コミの集計と、新しい口コミから住民の声を作る
+// 取得に失敗した場合は口コミなしとして続行する
+func loadReviews(ctx context.Context, reviewRepo domain.ReviewRepository, stationID int64) (domain.ResidentVoices, domain.ReviewSummary) {
+	voices := domain.ResidentVoices{Positive: []string{}, Negative: []string{}}
+	if reviewRepo == nil {
+		return voices, domain.ReviewSummary{Ratings: map[string]float64{}}
+	}
+	summary, err := reviewRepo.Summary(ctx, stationID)
+	if err != nil {
+		log.Printf("Warning: failed to load review summary: %v", err)
+		return voices, domain.ReviewSummary{Ratings: map[string]float64{}}
+	}
+	if summary.Count == 0 {
+		return voices, *summary
+	}
+
+	// 良い点・気になる点の片方だけの口コミもあるため、多めに取得して詰める
+	reviews, err := reviewRepo.ListApproved(ctx, stationID, domain.MaxResidentVoices*4)
+	if err != nil {
+		log.Printf("Warning: failed to load reviews: %v", err)
+		return voices, *summary
+	}
+	for _, r := range reviews {
+		if r.Positive != "" && len(voices.Positive) < domain.MaxResidentVoices {
+			voices.Positive = append(voices.Positive, r.Positive)
+		}
+		if r.Negative != "" && len(voices.Negative) < domain.MaxResidentVoices {
+			voices.Negative = append(voices.Negative, r.Negative)
+		}
+	}
+	return voices, *summary
+}
+
+// hasAllTags は駅が指定したタグをすべて持つかどうかを返す
+func hasAllTags(station *domain.Station, tags []string) bool {
+	for _, want := range tags {
+		found := false
+		for _, t := range station.Tags {
+			if t == want {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
 }
 
 func (u *stationUsecase) GetNearbyStations(ctx context.Context, lat, lon float64, filter domain.StationFilter) ([]*domain.Station, error) {
@@ -48,13 +400,25 @@ func (u *stationUsecase) GetNearbyStations(ctx context.Context, lat, lon float64
 			subsidyRange = 3 // デフォルト
 		}
 
-		// 各最寄り駅について、その路線の駅を取得
+		// 最寄り駅の路線をまとめて1回のクエリで取得
+		lineKeys := make([]domain.LineKey, 0, len(nearbyStations))
+		seenLines := make(map[domain.LineKey]bool)
 		for _, nearbyStation := range nearbyStations {
-			// 同じ路線の全駅を取得
-			lineStations, err := u.repo.GetByLine(ctx, nearbyStation.OrganizationCode, nearbyStation.LineName)
-			if err != nil {
-				continue // エラーは無視して次の駅へ
+			key := domain.LineKey{OrganizationCode: nearbyStation.OrganizationCode, LineName: nearbyStation.LineName}
+			if !seenLines[key] {
+				seenLines[key] = true
+				lineKeys = append(lineKeys, key)
 			}
+		}
+		stationsByLine, err := u.repo.GetByLines(ctx, lineKeys)
+		if err != nil {
+			log.Printf("Warning: failed to load line stations for subsidy expansion: %v", err)
+			stationsByLine = nil // 最寄り駅のみで続行
+		}
+
+		// 各最寄り駅について、その路線の駅を展開
+		for _, nearbyStation := range nearbyStations {
+			lineStations := stationsByLine[domain.LineKey{OrganizationCode: nearbyStation.OrganizationCode, LineName: nearbyStation.LineName}]
 
 			// 最寄り駅の位置を探す
 			nearbyIndex := -1
@@ -125,8 +489,26 @@ func (u *stationUsecase) GetNearbyStations(ctx context.Context, lat, lon float64
 		allStations = append(allStations, station)
 	}
 
-	// 4. スコア計算
+	// タグを付け、tags= の指定があればすべてのタグを持つ駅に絞る
+	// (家賃補助の展開は勤務地からの距離で決まるため、絞り込みは展開後に行う)
+	if err := attachTags(ctx, u.tagRepo, allStations, len(filter.Tags) > 0); err != nil {
+		return nil, err
+	}
+	if len(filter.Tags) > 0 {
+		filtered := allStations[:0]
+		for _, station := range allStations {
+			if hasAllTags(station, filter.Tags) {
+				filtered = append(filtered, station)
+			}
+		}
+		allStations = filtered
+	}
+
+	// 4. スコア計算 (access は徒歩ネットワークがあれば道路距離を使う)
+	// 道路距離は access のためだけに経路を探すため、スコアを計算しない検索では省く
 	if filter.CalculateScores {
+		attachWalkDistances(u.walk, lat, lon, allStations)
+		attachAxisScores(ctx, u.scoreRepo, allStations)
 		u.scoring.CalculateScores(allStations, filter.Weights)
 	}
 
@@ -207,6 +589,7 @@ func (u *stationUsecase) GetStationsWithinThreeStops(ctx context.Context, statio
 		originalOrder[s.ID] = i
 	}
 
+	attachAxisScores(ctx, u.scoreRepo, result)
 	u.scoring.CalculateScores(result, weights)
 
 	// 元の順序に戻す
@@ -256,60 +639,43 @@ func (u *stationUsecase) GetStationsByLine(ctx context.Context, organizationCode
 }
 
 func (u *stationUsecase) GetStationDetail(ctx context.Context, stationID int64) (*domain.StationDetail, error) {
-	// TODO: Replace with actual data fetching logic
 	station, err := u.repo.GetStation(ctx, stationID)
 	if err != nil {
 		return nil, err
 	}
+	// タグは駅の詳細に必須ではないため、取得に失敗した場合は空のまま続行する
+	if err := attachTags(ctx, u.tagRepo, []*domain.Station{station}, true); err != nil {
+		log.Printf("Warning: failed to load station tags: %v", err)
+	}
+	tags := station.Tags
+	if tags == nil {
+		tags = []string{}
+	}
+
+	voices, reviews := loadReviews(ctx, u.reviewRepo, station.ID)
 
 	detail := &domain.StationDetail{
-		ID:   station.ID,
-		Name: station.Name,
-		Location: domain.Location{
-			Lat: 26.1979, // Akamine mock coords
-			Lon: 127.6627,
-		},
-		Lines: []string{station.LineName}, // In reality, fetch all connecting lines
-		Tags:  []string{"コスパ良好", "学生街", "隠れた名店"},
-		AIInsight: domain.AIInsight{
-			Summary: domain.AISummary{
-				Pros: []string{"駅前に24時間スーパーがある", "都心まで急行で15分"},
-				Cons: []string{"駅周辺は坂が多い", "夜間は街灯が少なめ"},
-			},
-			ResidentVoices: domain.ResidentVoices{
-				Positive: []string{"パン屋の「こむぎ」がおいしい", "公園が多くて子供が遊びやすい"},
-				Negative: []string{"朝のラッシュ時は改札が入場制限かかることがある", "深夜のバイク音が気になる"},
-			},
-			Trend:       "駅北側の再開発が進んでおり、新しいカフェが増えている",
-			LastUpdated: "2025-12-28",
-		},
-		Score: domain.DetailScore{
-			Total: 84,
-			Radar: domain.RadarScore{
-				Rent:     85,
-				Safety:   70,
-				Facility: 90,
-				Access:   80,
-				Disaster: 60,
-			},
-		},
-		MarketPrice: domain.MarketData{
-			Prices: map[string]float64{
-				"1R":   7.5,
-				"1K":   8.2,
-				"1LDK": 12.5,
-				"2LDK": 15.0,
-			},
-			NeighborComparison: domain.NeighborComparison{
-				NextStationDiff: -5000,
-				PrevStationDiff: 2000,
-			},
-		},
+		ID:          station.ID,
+		Name:        station.Name,
+		Location:    domain.Location{Lat: station.Lat, Lon: station.Lon},
+		Lines:       []string{station.LineName}, // In reality, fetch all connecting lines
+		Tags:        tags,
+		AIInsight:   loadInsight(ctx, u.insightRepo, station.ID),
+		Reviews:     reviews,
+		Score:       u.detailScore(ctx, station),
+		MarketPrice: u.marketData(ctx, station),
+		// 外部サイトへは計測用のリダイレクトを経由させる (検索条件はハンドラーがクエリに付ける)
 		AffiliateLinks: domain.AffiliateLinks{
-			Suumo: "https://suumo.jp/chintai/", // TODO: Generate dynamic link
-			Homes: "https://www.homes.co.jp/",  // TODO: Generate dynamic link
+			Suumo: domain.AffiliateRedirectPath(station.ID, domain.AffiliateSourceSuumo),
+			Homes: domain.AffiliateRedirectPath(station.ID, domain.AffiliateSourceHomes),
 		},
 	}
+	// 住民の声は承認済みの口コミだけから作る (生成した解説文には含めない)
+	detail.AIInsight.ResidentVoices = voices
+	loadPassengers(ctx, u.passengerRepo, detail)
+	loadTerrain(ctx, u.terrainRepo, detail)
+	loadNearestShelters(ctx, u.poiRepo, u.walk, station, detail)
+	loadNightSafety(ctx, u.nightRepo, detail)
 
 	return detail, nil
 }
@@ -0,0 +1,349 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"sort"
+	"testing"
+
+	"github.com/gigaptera/hikkoshi-lens/backend/internal/domain"
+	"github.com/gigaptera/hikkoshi-lens/backend/internal/domain/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// nearbyStationRepo は GetNearby で固定の駅を返す StationRepository
+type nearbyStationRepo struct {
+	stubStationRepo
+	nearby []*domain.Station
+}
+
+func (r *nearbyStationRepo) GetNearby(ctx context.Context, lat, lon float64, filter domain.StationFilter) ([]*domain.Station, error) {
+	return r.nearby, nil
+}
+
+type stubScoreRepo struct {
+	domain.StationScoreRepository
+	scores map[int64]map[string]float64
+}
+
+func (r *stubScoreRepo) GetByStationIDs(ctx context.Context, ids []int64) (map[int64]map[string]float64, error) {
+	result := make(map[int64]map[string]float64)
+	for _, id := range ids {
+		if axes, ok := r.scores[id]; ok {
+			result[id] = axes
+		}
+	}
+	return result, nil
+}
+
+// stubPassengerRepo は rows にある駅の乗降客数を返す PassengerRepository
+type stubPassengerRepo struct {
+	domain.PassengerRepository
+	rows map[int64]*domain.StationPassengers
+}
+
+func (r *stubPassengerRepo) Get(ctx context.Context, stationID int64) (*domain.StationPassengers, error) {
+	row, ok := r.rows[stationID]
+	if !ok {
+		return nil, sql.ErrNoRows
+	}
+	return row, nil
+}
+
+// stubTerrainRepo は rows にある駅の地形を返す TerrainRepository
+type stubTerrainRepo struct {
+	domain.TerrainRepository
+	rows map[int64]*domain.StationTerrain
+}
+
+func (r *stubTerrainRepo) Get(ctx context.Context, stationID int64) (*domain.StationTerrain, error) {
+	row, ok := r.rows[stationID]
+	if !ok {
+		return nil, sql.ErrNoRows
+	}
+	return row, nil
+}
+
+type stubNightSafetyRepo struct {
+	domain.NightSafetyRepository
+	rows map[int64]*domain.StationNightSafety
+}
+
+func (r *stubNightSafetyRepo) Get(ctx context.Context, stationID int64) (*domain.StationNightSafety, error) {
+	row, ok := r.rows[stationID]
+	if !ok {
+		return nil, sql.ErrNoRows
+	}
+	return row, nil
+}
+
+type stubTagRepo struct {
+	domain.TagRepository
+	tags map[int64][]string
+	err  error
+}
+
+func (r *stubTagRepo) GetByStationIDs(ctx context.Context, ids []int64) (map[int64][]string, error) {
+	return r.tags, r.err
+}
+
+func TestStationUsecase_TagFilter(t *testing.T) {
+	repo := &nearbyStationRepo{nearby: []*domain.Station{
+		{ID: 1, Name: "池尻大橋"},
+		{ID: 2, Name: "三軒茶屋"},
+		{ID: 3, Name: "駒場東大前"},
+	}}
+	tags := &stubTagRepo{tags: map[int64][]string{
+		1: {"コスパ良好"},
+		2: {"コスパ良好", "交通便利"},
+		3: {"学生街"},
+	}}
+	u := NewStationUsecase(StationUsecaseDeps{
+		Repo:      repo,
+		ScoreRepo: &stubScoreRepo{},
+		TagRepo:   tags,
+		Scoring:   service.NewScoringService(),
+	})
+
+	// 絞り込みなしでもタグを付ける
+	stations, err := u.GetNearbyStations(context.Background(), 35.65, 139.68, domain.StationFilter{RadiusMeter: 1000})
+	require.NoError(t, err)
+	require.Len(t, stations, 3)
+	for _, s := range stations {
+		assert.Equal(t, tags.tags[s.ID], s.Tags)
+	}
+
+	// 指定したタグをすべて持つ駅だけ
+	stations, err = u.GetNearbyStations(context.Background(), 35.65, 139.68, domain.StationFilter{RadiusMeter: 1000, Tags: []string{"コスパ良好"}})
+	require.NoError(t, err)
+	var ids []int64
+	for _, s := range stations {
+		ids = append(ids, s.ID)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	assert.Equal(t, []int64{1, 2}, ids)
+
+	stations, err = u.GetNearbyStations(context.Background(), 35.65, 139.68, domain.StationFilter{RadiusMeter: 1000, Tags: []string{"コスパ良好", "交通便利"}})
+	require.NoError(t, err)
+	require.Len(t, stations, 1)
+	assert.Equal(t, int64(2), stations[0].ID)
+}
+
+func TestStationUsecase_TagLoadFailure(t *testing.T) {
+	repo := &nearbyStationRepo{nearby: []*domain.Station{{ID: 1, Name: "池尻大橋"}}}
+	tags := &stubTagRepo{err: errors.New("connection refused")}
+	u := NewStationUsecase(StationUsecaseDeps{
+		Repo:      repo,
+		ScoreRepo: &stubScoreRepo{},
+		TagRepo:   tags,
+		Scoring:   service.NewScoringService(),
+	})
+
+	// 絞り込みがなければタグなしで続行
+	stations, err := u.GetNearbyStations(context.Background(), 35.65, 139.68, domain.StationFilter{RadiusMeter: 1000})
+	require.NoError(t, err)
+	assert.Len(t, stations, 1)
+
+	// 絞り込みがあれば結果が不正確になるためエラー
+	_, err = u.GetNearbyStations(context.Background(), 35.65, 139.68, domain.StationFilter{RadiusMeter: 1000, Tags: []string{"コスパ良好"}})
+	assert.Error(t, err)
+}
+
+func TestStationUsecase_DetailPassengers(t *testing.T) {
+	stations := &stubStationRepo{stations: map[int64]*domain.Station{1: {ID: 1, Name: "新宿"}, 2: {ID: 2, Name: "赤嶺"}}}
+	passengers := 1520000
+	repo := &stubPassengerRepo{rows: map[int64]*domain.StationPassengers{
+		1: {StationID: 1, DailyPassengers: &passengers, PassengersYear: 2023},
+	}}
+	u := NewStationUsecase(StationUsecaseDeps{
+		Repo:          stations,
+		ScoreRepo:     &stubScoreRepo{},
+		PassengerRepo: repo,
+		Scoring:       service.NewScoringService(),
+	})
+
+	detail, err := u.GetStationDetail(context.Background(), 1)
+	require.NoError(t, err)
+	require.NotNil(t, detail.DailyPassengers)
+	assert.Equal(t, 1520000, *detail.DailyPassengers)
+	assert.Equal(t, 2023, detail.PassengersYear)
+
+	// 未登録の駅は null
+	detail, err = u.GetStationDetail(context.Background(), 2)
+	require.NoError(t, err)
+	assert.Nil(t, detail.DailyPassengers)
+}
+
+func TestStationUsecase_DetailScoreAndMarket(t *testing.T) {
+	rents := func(values ...float64) []*domain.MarketPrice {
+		prices := make([]*domain.MarketPrice, len(values))
+		for i, v := range values {
+			prices[i] = &domain.MarketPrice{BuildingType: "mansion", Layout: fmt.Sprintf("layout%d", i), Rent: v}
+		}
+		return prices
+	}
+	onLine := func(id int64, name string, prices []*domain.MarketPrice) *domain.Station {
+		return &domain.Station{ID: id, Name: name, OrganizationCode: "tokyu", LineName: "東横線", Lat: 35.64, Lon: 139.7, MarketPrices: prices}
+	}
+	stations := &stubStationRepo{stations: map[int64]*domain.Station{
+		1: onLine(1, "渋谷", rents(12, 14)),
+		2: onLine(2, "代官山", rents(10, 10)),
+		3: onLine(3, "中目黒", nil),
+	}}
+	scores := &stubScoreRepo{scores: map[int64]map[string]float64{
+		2: {"facility": 80, "safety": 60, "disaster": 40, domain.ReviewScoreAxis: 70, domain.HillinessScoreAxis: 90, domain.NightSafetyScoreAxis: 30},
+	}}
+	u := NewStationUsecase(StationUsecaseDeps{Repo: stations, ScoreRepo: scores, Scoring: service.NewScoringService()})
+
+	detail, err := u.GetStationDetail(context.Background(), 2)
+	require.NoError(t, err)
+	assert.Equal(t, domain.Location{Lat: 35.64, Lon: 139.7}, detail.Location)
+
+	// rent は相場 10万円 → 60点、access は勤務地がないため中間値
+	assert.Equal(t, domain.RadarScore{Rent: 60, Safety: 60, Facility: 80, Access: 50, Disaster: 40}, detail.Score.Radar)
+	assert.InDelta(t, (60+80+60+40+70+90)/6.0, detail.Score.Total, 0.001)
+	assert.Equal(t, 30.0, detail.Score.Details[domain.NightSafetyScoreAxis])
+
+	assert.Equal(t, map[string]float64{"layout0": 10, "layout1": 10}, detail.MarketPrice.Prices)
+	// 渋谷 (平均13万円) より3万円安く、中目黒は相場がないため 0
+	assert.Equal(t, -30000.0, detail.MarketPrice.NeighborComparison.PrevStationDiff)
+	assert.Equal(t, 0.0, detail.MarketPrice.NeighborComparison.NextStationDiff)
+}
+
+func TestStationUsecase_DetailTerrain(t *testing.T) {
+	stations := &stubStationRepo{stations: map[int64]*domain.Station{1: {ID: 1, Name: "代官山"}, 2: {ID: 2, Name: "赤嶺"}}}
+	repo := &stubTerrainRepo{rows: map[int64]*domain.StationTerrain{
+		1: {StationID: 1, Elevation: 32.5, AvgSlope: 6.2, ElevationGain: 18, RelativeHeight: 12, RadiusMeter: 800, Method: domain.TerrainMethodNetwork},
+	}}
+	u := NewStationUsecase(StationUsecaseDeps{
+		Repo:        stations,
+		ScoreRepo:   &stubScoreRepo{},
+		TerrainRepo: repo,
+		Scoring:     service.NewScoringService(),
+	})
+
+	detail, err := u.GetStationDetail(context.Background(), 1)
+	require.NoError(t, err)
+	require.NotNil(t, detail.Terrain)
+	assert.Equal(t, 6.2, detail.Terrain.AvgSlope)
+	assert.Equal(t, domain.TerrainMethodNetwork, detail.Terrain.Method)
+
+	// 未計算の駅は省略
+	detail, err = u.GetStationDetail(context.Background(), 2)
+	require.NoError(t, err)
+	assert.Nil(t, detail.Terrain)
+}
+
+func TestStationUsecase_DetailNightSafety(t *testing.T) {
+	lamps, police := 120.0, 450.0
+	stations := &stubStationRepo{stations: map[int64]*domain.Station{1: {ID: 1, Name: "三軒茶屋"}, 2: {ID: 2, Name: "赤嶺"}}}
+	repo := &stubNightSafetyRepo{rows: map[int64]*domain.StationNightSafety{
+		1: {StationID: 1, StreetLampDensity: &lamps, NearestPoliceMeter: &police, RadiusMeter: 500},
+	}}
+	u := NewStationUsecase(StationUsecaseDeps{
+		Repo:      stations,
+		ScoreRepo: &stubScoreRepo{},
+		NightRepo: repo,
+		Scoring:   service.NewScoringService(),
+	})
+
+	detail, err := u.GetStationDetail(context.Background(), 1)
+	require.NoError(t, err)
+	require.NotNil(t, detail.NightSafety)
+	assert.Equal(t, 120.0, *detail.NightSafety.StreetLampDensity)
+	assert.Equal(t, 450.0, *detail.NightSafety.NearestPoliceMeter)
+
+	// 未集計の駅は省略
+	detail, err = u.GetStationDetail(context.Background(), 2)
+	require.NoError(t, err)
+	assert.Nil(t, detail.NightSafety)
+}
+
+func TestStationUsecase_DetailNearestShelters(t *testing.T) {
+	stations := &stubStationRepo{stations: map[int64]*domain.Station{1: {ID: 1, Name: "代々木公園", Lat: 35.669, Lon: 139.690}}}
+	pois := &stubPOIRepo{pois: []*domain.POI{
+		{ID: 1, Category: domain.POICategoryShelter, Name: "代々木公園", Distance: 240},
+		{ID: 2, Category: domain.POICategoryConvenience, Name: "コンビニ", Distance: 50},
+		{ID: 3, Category: domain.POICategoryShelter, Name: "区立中学校", Distance: 600},
+		{ID: 4, Category: domain.POICategoryShelter, Name: "区立小学校", Distance: 900},
+		{ID: 5, Category: domain.POICategoryShelter, Name: "都立高校", Distance: 1200},
+	}}
+	u := NewStationUsecase(StationUsecaseDeps{
+		Repo:      stations,
+		ScoreRepo: &stubScoreRepo{},
+		POIRepo:   pois,
+		Scoring:   service.NewScoringService(),
+	})
+
+	detail, err := u.GetStationDetail(context.Background(), 1)
+	require.NoError(t, err)
+	require.Len(t, detail.NearestShelters, domain.MaxNearestShelters)
+	assert.Equal(t, "代々木公園", detail.NearestShelters[0].Name)
+	assert.Equal(t, "区立小学校", detail.NearestShelters[2].Name)
+	// 徒歩ネットワークがなければ直線距離×1.3から推定 (240m -> 312m -> 4分)
+	assert.Equal(t, 4, detail.NearestShelters[0].WalkMinutes)
+
+	// 避難場所のデータがない場合は空配列
+	u = NewStationUsecase(StationUsecaseDeps{
+		Repo:      stations,
+		ScoreRepo: &stubScoreRepo{},
+		POIRepo:   &stubPOIRepo{},
+		Scoring:   service.NewScoringService(),
+	})
+	detail, err = u.GetStationDetail(context.Background(), 1)
+	require.NoError(t, err)
+	assert.NotNil(t, detail.NearestShelters)
+	assert.Empty(t, detail.NearestShelters)
+}
+
+func TestStationUsecase_WalkAccess(t *testing.T) {
+	// 直線距離は近いが川を渡るため遠回りになる駅と、直線距離どおりに歩ける駅
+	riverside := &domain.Station{ID: 1, Name: "川向こう", Lat: 35.01, Lon: 139.0, Distance: 500}
+	straight := &domain.Station{ID: 2, Name: "まっすぐ", Lat: 35.02, Lon: 139.0, Distance: 800}
+	far := &domain.Station{ID: 3, Name: "遠方", Lat: 35.2, Lon: 139.0, Distance: 20000}
+	router := &stubWalkRouter{meters: map[domain.Location]float64{
+		{Lat: 35.01, Lon: 139.0}: 2600,
+		{Lat: 35.02, Lon: 139.0}: 900,
+	}}
+	repo := &nearbyStationRepo{nearby: []*domain.Station{riverside, straight, far}}
+	u := NewStationUsecase(StationUsecaseDeps{
+		Repo:      repo,
+		ScoreRepo: &stubScoreRepo{},
+		Walk:      NewWalkDistances(router, 100),
+		Scoring:   service.NewScoringService(),
+	})
+
+	stations, err := u.GetNearbyStations(context.Background(), 35.0, 139.0, domain.StationFilter{
+		RadiusMeter: 30000, CalculateScores: true, Weights: map[string]int{"access": 100},
+	})
+	require.NoError(t, err)
+	require.Len(t, stations, 3)
+	assert.Equal(t, []int64{2, 1, 3}, []int64{stations[0].ID, stations[1].ID, stations[2].ID})
+
+	assert.Equal(t, 900.0, straight.WalkDistance)
+	assert.Equal(t, 12, straight.WalkMinutes)
+	assert.Equal(t, 33, riverside.WalkMinutes)
+	// 徒歩圏外の駅は直線距離から推定し、徒歩分数は付けない
+	assert.Equal(t, 26000.0, far.WalkDistance)
+	assert.Zero(t, far.WalkMinutes)
+}
+
+// TestStationUsecase_WalkAccessOnlyWithScores はスコアを計算しない検索では道路距離の経路を探さないことを確認する
+func TestStationUsecase_WalkAccessOnlyWithScores(t *testing.T) {
+	station := &domain.Station{ID: 1, Name: "川向こう", Lat: 35.01, Lon: 139.0, Distance: 500}
+	router := &stubWalkRouter{meters: map[domain.Location]float64{{Lat: 35.01, Lon: 139.0}: 2600}}
+	u := NewStationUsecase(StationUsecaseDeps{
+		Repo:      &nearbyStationRepo{nearby: []*domain.Station{station}},
+		ScoreRepo: &stubScoreRepo{},
+		Walk:      NewWalkDistances(router, 100),
+		Scoring:   service.NewScoringService(),
+	})
+
+	_, err := u.GetNearbyStations(context.Background(), 35.0, 139.0, domain.StationFilter{RadiusMeter: 3000})
+	require.NoError(t, err)
+	assert.Empty(t, router.requested)
+	assert.Zero(t, station.WalkDistance)
+}
@@ -0,0 +1,98 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/gigaptera/hikkoshi-lens/backend/internal/domain"
+	"github.com/gigaptera/hikkoshi-lens/backend/internal/domain/service"
+)
+
+// tileDefaultWeights はタイルで重みが指定されなかった場合の重み
+// タイルには勤務地がないため access 軸は使わず、駅単体で決まる軸を均等に扱う
+var tileDefaultWeights = map[string]int{"rent": 25, "facility": 25, "safety": 25, "disaster": 25}
+
+type TileUsecase interface {
+	// GetStationTile は駅のベクタータイル (MVT) を返す。filter のうち建物種別・間取り・重みを使う
+	GetStationTile(ctx context.Context, tile domain.TileCoord, filter domain.StationFilter) ([]byte, error)
+}
+
+type tileUsecase struct {
+	repo      domain.StationTileRepository
+	scoreRepo domain.StationScoreRepository
+	scoring   *service.ScoringService
+}
+
+func NewTileUsecase(repo domain.StationTileRepository, scoreRepo domain.StationScoreRepository, scoring *service.ScoringService) TileUsecase {
+	return &tileUsecase{repo: repo, scoreRepo: scoreRepo, scoring: scoring}
+}
+
+func (u *tileUsecase) GetStationTile(ctx context.Context, tile domain.TileCoord, filter domain.StationFilter) ([]byte, error) {
+	if err := tile.Validate(); err != nil {
+		return nil, err
+	}
+
+	stations, err := u.repo.GetInTile(ctx, tile, filter)
+	if err != nil {
+		return nil, err
+	}
+	if len(stations) == 0 {
+		return []byte{}, nil
+	}
+
+	// スコアはAPIの検索結果と同じくGoで計算し、タイルの属性として埋め込む
+	weights := tileWeights(filter.Weights)
+	attachAxisScores(ctx, u.scoreRepo, stations)
+	u.scoring.CalculateScores(stations, weights)
+	for _, s := range stations {
+		for _, mp := range s.MarketPrices {
+			if mp.BuildingType == filter.BuildingType && mp.Layout == filter.Layout {
+				s.RentAvg = mp.Rent
+				break
+			}
+		}
+	}
+
+	return u.repo.RenderStations(ctx, tile, stations)
+}
+
+// tileWeights は access を除いた重みを返す (すべて0なら既定の重み)
+func tileWeights(weights map[string]int) map[string]int {
+	result := make(map[string]int, len(weights))
+	for k, v := range weights {
+		if k == "access" || v == 0 {
+			continue
+		}
+		result[k] = v
+	}
+	if len(result) == 0 {
+		return tileDefaultWeights
+	}
+	return result
+}
+
+// CachedTileUsecase はタイルの応答キャッシュ
+// 地図のパン・ズームで同じタイルが繰り返し要求されるため、条件ごとのタイルをLRUに保存する
+type CachedTileUsecase struct {
+	*responseCache
+	inner TileUsecase
+}
+
+func NewCachedTileUsecase(inner TileUsecase, ttl time.Duration, maxEntries int) *CachedTileUsecase {
+	return &CachedTileUsecase{
+		responseCache: newResponseCache(ttl, maxEntries),
+		inner:         inner,
+	}
+}
+
+func (u *CachedTileUsecase) GetStationTile(ctx context.Context, tile domain.TileCoord, filter domain.StationFilter) ([]byte, error) {
+	key := fmt.Sprintf("tile:%s|%s|%s|w=%s", tile, filter.BuildingType, filter.Layout, weightsKey(tileWeights(filter.Weights)))
+	v, err := u.load(ctx, key, func(ctx context.Context) (any, error) {
+		return u.inner.GetStationTile(ctx, tile, filter)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return v.([]byte), nil
+}
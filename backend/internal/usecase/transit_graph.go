@@ -0,0 +1,73 @@
+package usecase
+
+import (
+	"context"
+	"sync"
+
+	"github.com/gigaptera/hikkoshi-lens/backend/internal/domain"
+	"github.com/gigaptera/hikkoshi-lens/backend/internal/domain/service"
+	"golang.org/x/sync/singleflight"
+)
+
+// TransitGraphs は全駅の路線グラフを作り、次の駅データ更新まで使い回す
+// 全国のグラフは大きいため、等時間圏と定期代の計算で1つを共有する
+type TransitGraphs struct {
+	stationRepo domain.StationRepository
+	opts        service.TransitOptions
+	group       singleflight.Group
+
+	mu         sync.Mutex
+	graph      *service.TransitGraph
+	generation uint64 // Invalidateのたびに進め、更新前の駅データで作ったグラフを保存しない
+}
+
+func NewTransitGraphs(stationRepo domain.StationRepository) *TransitGraphs {
+	return &TransitGraphs{stationRepo: stationRepo, opts: service.DefaultTransitOptions()}
+}
+
+// Invalidate は駅データの更新時にグラフを作り直させる
+func (g *TransitGraphs) Invalidate(table string) {
+	if table != "stations" {
+		return
+	}
+	g.mu.Lock()
+	g.graph = nil
+	g.generation++
+	g.mu.Unlock()
+}
+
+// Graph は路線グラフを返す。未作成なら作る (同時の要求は1回にまとめ、ロックの外で作る)
+func (g *TransitGraphs) Graph(ctx context.Context) (*service.TransitGraph, error) {
+	g.mu.Lock()
+	graph, gen := g.graph, g.generation
+	g.mu.Unlock()
+	if graph != nil {
+		return graph, nil
+	}
+
+	v, err, _ := g.group.Do("graph", func() (any, error) {
+		// 最初の要求が切断しても、同じグラフを待つ他の要求を失敗させない
+		stations, err := g.stationRepo.ListAll(context.WithoutCancel(ctx))
+		if err != nil {
+			return nil, err
+		}
+		located := stations[:0]
+		for _, s := range stations {
+			if s.Lat != 0 || s.Lon != 0 {
+				located = append(located, s)
+			}
+		}
+		graph := service.NewTransitGraph(located, g.opts)
+
+		g.mu.Lock()
+		if gen == g.generation {
+			g.graph = graph
+		}
+		g.mu.Unlock()
+		return graph, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return v.(*service.TransitGraph), nil
+}
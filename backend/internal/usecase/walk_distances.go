@@ -0,0 +1,82 @@
+package usecase
+
+import (
+	"math"
+
+	"github.com/gigaptera/hikkoshi-lens/backend/internal/domain"
+	"github.com/gigaptera/hikkoshi-lens/backend/internal/infrastructure/cache"
+)
+
+// walkLeg は道路距離のキャッシュのキー (座標は約1m単位に丸める)
+type walkLeg struct {
+	fromLat, fromLon, toLat, toLon int32
+	maxMeter                       int32
+}
+
+func walkCoord(v float64) int32 {
+	return int32(math.Round(v * 1e5))
+}
+
+// WalkDistances は徒歩ネットワークの道路距離を区間ごとにキャッシュする
+// 駅 -> 周辺施設、勤務地 -> 駅 の区間は同じ組み合わせが繰り返し要求されるため、
+// 未計算の区間だけを出発地ごとに1回の経路探索でまとめて求める
+// 徒歩ネットワークは起動時に読み込んだまま変わらないため、データ更新による破棄はしない
+type WalkDistances struct {
+	router domain.WalkRouter
+	legs   *cache.LRU[walkLeg, float64]
+}
+
+// NewWalkDistances は道路距離のキャッシュを作る。router が nil の場合は常に直線距離から推定する
+func NewWalkDistances(router domain.WalkRouter, maxEntries int) *WalkDistances {
+	return &WalkDistances{router: router, legs: cache.NewLRU[walkLeg, float64](0, maxEntries)}
+}
+
+// Enabled は徒歩ネットワークで道路距離を求められるかを返す
+func (w *WalkDistances) Enabled() bool {
+	return w != nil && w.router != nil
+}
+
+// Meters は from から各地点までの道路距離 (m) を返す
+// 経路が求まらない地点は straight (直線距離) から推定する。徒歩ネットワークがない場合もすべて推定値
+func (w *WalkDistances) Meters(from domain.Location, targets []domain.Location, straight []float64, maxMeter float64) []float64 {
+	result := make([]float64, len(targets))
+	var missing []int
+	for i, t := range targets {
+		result[i] = -1
+		if !w.Enabled() {
+			continue
+		}
+		if m, ok := w.legs.Get(w.key(from, t, maxMeter)); ok {
+			result[i] = m
+			continue
+		}
+		missing = append(missing, i)
+	}
+
+	if len(missing) > 0 {
+		points := make([]domain.Location, len(missing))
+		for j, i := range missing {
+			points[j] = targets[i]
+		}
+		for j, m := range w.router.WalkMeters(from, points, maxMeter) {
+			i := missing[j]
+			result[i] = m
+			w.legs.Set(w.key(from, targets[i], maxMeter), m)
+		}
+	}
+
+	for i, m := range result {
+		if m < 0 {
+			result[i] = domain.EstimateWalkMeters(straight[i])
+		}
+	}
+	return result
+}
+
+func (w *WalkDistances) key(from, to domain.Location, maxMeter float64) walkLeg {
+	return walkLeg{
+		fromLat: walkCoord(from.Lat), fromLon: walkCoord(from.Lon),
+		toLat: walkCoord(to.Lat), toLon: walkCoord(to.Lon),
+		maxMeter: int32(maxMeter),
+	}
+}
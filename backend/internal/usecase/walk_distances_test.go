@@ -0,0 +1,55 @@
+package usecase
+
+import (
+	"testing"
+
+	"github.com/gigaptera/hikkoshi-lens/backend/internal/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+// stubWalkRouter は地点ごとに固定の道路距離を返す WalkRouter (登録のない地点は経路なし)
+type stubWalkRouter struct {
+	meters    map[domain.Location]float64
+	requested [][]domain.Location
+}
+
+func (r *stubWalkRouter) WalkMeters(from domain.Location, targets []domain.Location, maxMeter float64) []float64 {
+	r.requested = append(r.requested, targets)
+	result := make([]float64, len(targets))
+	for i, t := range targets {
+		m, ok := r.meters[t]
+		if !ok || m > maxMeter {
+			m = -1
+		}
+		result[i] = m
+	}
+	return result
+}
+
+func TestWalkDistances_WithoutNetwork(t *testing.T) {
+	var nilWalk *WalkDistances
+	for _, w := range []*WalkDistances{nilWalk, NewWalkDistances(nil, 10)} {
+		assert.False(t, w.Enabled())
+		got := w.Meters(domain.Location{}, []domain.Location{{Lat: 1}}, []float64{100}, 1000)
+		assert.Equal(t, []float64{130}, got) // 直線距離 × 1.3
+	}
+}
+
+func TestWalkDistances_CachesLegs(t *testing.T) {
+	a, b, c := domain.Location{Lat: 35.1, Lon: 139.1}, domain.Location{Lat: 35.2, Lon: 139.2}, domain.Location{Lat: 35.3, Lon: 139.3}
+	router := &stubWalkRouter{meters: map[domain.Location]float64{a: 450, b: 1200}}
+	w := NewWalkDistances(router, 100)
+	from := domain.Location{Lat: 35.0, Lon: 139.0}
+
+	got := w.Meters(from, []domain.Location{a, c}, []float64{300, 500}, 2000)
+	assert.Equal(t, []float64{450, 650}, got) // c は経路がないため直線距離から推定
+
+	// 計算済みの区間 (経路なしを含む) は経路探索に渡さない
+	got = w.Meters(from, []domain.Location{a, b, c}, []float64{300, 900, 500}, 2000)
+	assert.Equal(t, []float64{450, 1200, 650}, got)
+	assert.Equal(t, [][]domain.Location{{a, c}, {b}}, router.requested)
+
+	// 探索の上限が違う場合は別の区間として扱う
+	w.Meters(from, []domain.Location{a}, []float64{300}, 400)
+	assert.Len(t, router.requested, 3)
+}
@@ -1,3 +1,6 @@
+-- +goose Up
+-- +goose StatementBegin
+
 -- Recreate market_prices table to support 15 combinations and proper FK
 DROP TABLE IF EXISTS market_prices;
 
@@ -16,3 +19,20 @@ CREATE TABLE market_prices (
 );
 
 CREATE INDEX idx_market_prices_station_id ON market_prices(station_id);
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS market_prices;
+
+CREATE TABLE market_prices (
+    id SERIAL PRIMARY KEY,
+    station_id BIGINT UNIQUE NOT NULL,
+    avg_rent_1r NUMERIC(10, 2),
+    avg_rent_1ldk NUMERIC(10, 2),
+    source TEXT,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT fk_station_market FOREIGN KEY (station_id) REFERENCES stations(id) ON DELETE CASCADE
+);
+-- +goose StatementEnd
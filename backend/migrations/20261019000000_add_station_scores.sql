@@ -0,0 +1,23 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- station_scores: 駅単体のデータで決まる軸スコアの事前計算結果 (cmd/scores recompute)
+CREATE TABLE IF NOT EXISTS station_scores (
+    station_id BIGINT NOT NULL,
+    axis VARCHAR(50) NOT NULL,                -- 'facility', 'safety', 'disaster' ...
+    raw_score DOUBLE PRECISION NOT NULL,      -- Strategyの生の値
+    normalized_score DOUBLE PRECISION NOT NULL, -- 全駅でmin-max正規化した値 (0-100)
+    data_version VARCHAR(50) NOT NULL,        -- 計算に使ったデータのバージョン
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (station_id, axis),
+    CONSTRAINT fk_station_scores FOREIGN KEY (station_id) REFERENCES stations(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_station_scores_data_version ON station_scores(data_version);
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS station_scores;
+-- +goose StatementEnd
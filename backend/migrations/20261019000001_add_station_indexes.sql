@@ -0,0 +1,16 @@
+-- +goose NO TRANSACTION
+-- +goose Up
+
+-- GetNearby の ST_DWithin / ST_Distance 用 (geography の GIST インデックス)
+CREATE INDEX CONCURRENTLY IF NOT EXISTS idx_stations_location ON stations USING GIST (location);
+
+-- GetByLine / GetByLines の路線単位の検索用
+CREATE INDEX CONCURRENTLY IF NOT EXISTS idx_stations_organization_line ON stations (organization_code, line_name);
+
+-- 駅の接続路線 (lines) のリレーション読み込み用
+CREATE INDEX CONCURRENTLY IF NOT EXISTS idx_lines_station_id ON lines (station_id);
+
+-- +goose Down
+DROP INDEX CONCURRENTLY IF EXISTS idx_lines_station_id;
+DROP INDEX CONCURRENTLY IF EXISTS idx_stations_organization_line;
+DROP INDEX CONCURRENTLY IF EXISTS idx_stations_location;
@@ -0,0 +1,26 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- pois: 駅周辺の個別施設 (OSMのPBFから取り込み、cmd/import/osm_pois)
+-- facilities の件数はこのテーブルから空間結合で集計する
+CREATE TABLE IF NOT EXISTS pois (
+    id BIGSERIAL PRIMARY KEY,
+    source VARCHAR(50) NOT NULL,       -- 'osm' など
+    source_id VARCHAR(100) NOT NULL,   -- ソース内のID (OSMなら 'node/123', 'way/456')
+    category VARCHAR(50) NOT NULL,     -- 'supermarket', 'convenience', 'hospital' ...
+    name TEXT,
+    location GEOGRAPHY(POINT, 4326) NOT NULL, -- wayは構成ノードの重心
+    tags JSONB,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (source, source_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_pois_location ON pois USING GIST (location);
+CREATE INDEX IF NOT EXISTS idx_pois_category ON pois (category);
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS pois;
+-- +goose StatementEnd
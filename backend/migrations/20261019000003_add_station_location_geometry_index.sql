@@ -0,0 +1,9 @@
+-- +goose NO TRANSACTION
+-- +goose Up
+
+-- ベクタータイル (/api/tiles/stations) のタイル範囲検索用
+-- タイル範囲はWebメルカトルの矩形なので geography ではなく geometry で比較する
+CREATE INDEX CONCURRENTLY IF NOT EXISTS idx_stations_location_geometry ON stations USING GIST ((location::geometry));
+
+-- +goose Down
+DROP INDEX CONCURRENTLY IF EXISTS idx_stations_location_geometry;
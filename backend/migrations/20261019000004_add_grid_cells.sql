@@ -0,0 +1,41 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- grid_cells: ヒートマップ用に地域を分割したセル (cmd/grid cells)
+-- grid はセルの形と大きさを表す名前 ('hex_1000' など)。i, j は ST_HexagonGrid / ST_SquareGrid のセル番号
+CREATE TABLE IF NOT EXISTS grid_cells (
+    id BIGSERIAL PRIMARY KEY,
+    grid VARCHAR(50) NOT NULL,
+    shape VARCHAR(10) NOT NULL,          -- 'hex' or 'square'
+    size_m INTEGER NOT NULL,             -- セルの大きさ (m)
+    i INTEGER NOT NULL,
+    j INTEGER NOT NULL,
+    geom GEOMETRY(POLYGON, 4326) NOT NULL,
+    centroid GEOGRAPHY(POINT, 4326) NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (grid, i, j)
+);
+
+CREATE INDEX IF NOT EXISTS idx_grid_cells_geom ON grid_cells USING GIST (geom);
+
+-- grid_cell_metrics: セルごとの指標 (cmd/grid metrics)
+-- 指標ごとに行を持つ縦持ちにして、指標の追加でスキーマを変えずに済むようにする
+CREATE TABLE IF NOT EXISTS grid_cell_metrics (
+    cell_id BIGINT NOT NULL,
+    metric VARCHAR(50) NOT NULL,         -- 'facility_density', 'hazard_level', 'rent_1r_1k_1dk' ...
+    value DOUBLE PRECISION NOT NULL,
+    data_version VARCHAR(50) NOT NULL,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (cell_id, metric),
+    CONSTRAINT fk_grid_cell_metrics FOREIGN KEY (cell_id) REFERENCES grid_cells(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_grid_cell_metrics_metric ON grid_cell_metrics (metric);
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS grid_cell_metrics;
+DROP TABLE IF EXISTS grid_cells;
+-- +goose StatementEnd
@@ -0,0 +1,36 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- station_external_ids: 外部サイトの駅ID (SUUMOの ek_XXXXX など)
+-- SUUMOはクローラー (cmd/crawler) が駅ページを巡回する際に保存し、HOME'S はCSVから取り込む
+CREATE TABLE IF NOT EXISTS station_external_ids (
+    station_id BIGINT NOT NULL REFERENCES stations(id) ON DELETE CASCADE,
+    source VARCHAR(50) NOT NULL,       -- 'suumo', 'homes'
+    area VARCHAR(50) NOT NULL,         -- URLの地域部分 ('tokyo' など)
+    external_id VARCHAR(100) NOT NULL, -- SUUMOなら '20110'
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (station_id, source)
+);
+
+-- affiliate_clicks: 外部サイトへの送客ログ (リダイレクト時に記録)
+CREATE TABLE IF NOT EXISTS affiliate_clicks (
+    id BIGSERIAL PRIMARY KEY,
+    station_id BIGINT NOT NULL REFERENCES stations(id) ON DELETE CASCADE,
+    source VARCHAR(50) NOT NULL,
+    layout VARCHAR(50),
+    min_rent DOUBLE PRECISION,
+    max_rent DOUBLE PRECISION,
+    target_url TEXT NOT NULL,
+    referer TEXT,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_affiliate_clicks_station_created ON affiliate_clicks (station_id, created_at);
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS affiliate_clicks;
+DROP TABLE IF EXISTS station_external_ids;
+-- +goose StatementEnd
@@ -0,0 +1,73 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- users: メールアドレスだけで識別する利用者 (パスワードは持たず、マジックリンクでログインする)
+CREATE TABLE IF NOT EXISTS users (
+    id BIGSERIAL PRIMARY KEY,
+    email VARCHAR(254) NOT NULL UNIQUE, -- 小文字に正規化して保存
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    last_login_at TIMESTAMP WITH TIME ZONE
+);
+
+-- login_tokens: マジックリンクのワンタイムトークン (平文は保存せずSHA-256のみ)
+CREATE TABLE IF NOT EXISTS login_tokens (
+    token_hash CHAR(64) PRIMARY KEY,
+    email VARCHAR(254) NOT NULL,
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    used_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_login_tokens_expires_at ON login_tokens (expires_at);
+
+-- sessions: ログイン後のセッション (Authorization: Bearer <token> のSHA-256)
+CREATE TABLE IF NOT EXISTS sessions (
+    token_hash CHAR(64) PRIMARY KEY,
+    user_id BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_sessions_user_id ON sessions (user_id);
+
+-- favorites: お気に入りの駅
+CREATE TABLE IF NOT EXISTS favorites (
+    user_id BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    station_id BIGINT NOT NULL REFERENCES stations(id) ON DELETE CASCADE,
+    note TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (user_id, station_id)
+);
+
+-- shortlists: 名前付きの候補リスト ("通勤重視" など)
+CREATE TABLE IF NOT EXISTS shortlists (
+    id BIGSERIAL PRIMARY KEY,
+    user_id BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    name VARCHAR(100) NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_shortlists_user_id ON shortlists (user_id);
+
+CREATE TABLE IF NOT EXISTS shortlist_items (
+    shortlist_id BIGINT NOT NULL REFERENCES shortlists(id) ON DELETE CASCADE,
+    station_id BIGINT NOT NULL REFERENCES stations(id) ON DELETE CASCADE,
+    note TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (shortlist_id, station_id)
+);
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS shortlist_items;
+DROP TABLE IF EXISTS shortlists;
+DROP TABLE IF EXISTS favorites;
+DROP TABLE IF EXISTS sessions;
+DROP TABLE IF EXISTS login_tokens;
+DROP TABLE IF EXISTS users;
+-- +goose StatementEnd
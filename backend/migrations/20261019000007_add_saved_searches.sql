@@ -0,0 +1,39 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- saved_searches: 利用者が保存した検索条件 (勤務地 + StationFilter)
+-- 家賃相場の更新後に cmd/alerts が再検索し、前回の結果との差分を通知する
+CREATE TABLE IF NOT EXISTS saved_searches (
+    id BIGSERIAL PRIMARY KEY,
+    user_id BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    name VARCHAR(100) NOT NULL,
+    lat DOUBLE PRECISION NOT NULL,
+    lon DOUBLE PRECISION NOT NULL,
+    filter JSONB NOT NULL,                    -- domain.StationFilter
+    rent_threshold DOUBLE PRECISION NOT NULL, -- これ以上 (万円) 家賃相場が変わった駅を通知する
+    channel VARCHAR(20) NOT NULL DEFAULT '',  -- 'email', 'webhook'。空ならサーバーの既定 (ALERT_NOTIFIER)
+    webhook_url TEXT,
+    last_run_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_saved_searches_user_id ON saved_searches (user_id);
+
+-- saved_search_results: 前回実行時の検索結果 (差分の比較元)
+CREATE TABLE IF NOT EXISTS saved_search_results (
+    saved_search_id BIGINT NOT NULL REFERENCES saved_searches(id) ON DELETE CASCADE,
+    station_id BIGINT NOT NULL REFERENCES stations(id) ON DELETE CASCADE,
+    station_name VARCHAR(255) NOT NULL,
+    rent_avg DOUBLE PRECISION NOT NULL DEFAULT 0,
+    total_score DOUBLE PRECISION NOT NULL DEFAULT 0,
+    PRIMARY KEY (saved_search_id, station_id)
+);
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS saved_search_results;
+DROP TABLE IF EXISTS saved_searches;
+-- +goose StatementEnd
@@ -0,0 +1,24 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- search_permalinks: 検索条件の共有リンク (家族などに候補の駅を送る)
+-- 利用者を問わず作成でき、期限切れのリンクは解決できない
+CREATE TABLE IF NOT EXISTS search_permalinks (
+    code VARCHAR(16) PRIMARY KEY, -- base62 のランダムな文字列
+    lat DOUBLE PRECISION NOT NULL,
+    lon DOUBLE PRECISION NOT NULL,
+    filter JSONB NOT NULL,        -- domain.StationFilter
+    view_count INTEGER NOT NULL DEFAULT 0,
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    last_viewed_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_search_permalinks_expires_at ON search_permalinks (expires_at);
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS search_permalinks;
+-- +goose StatementEnd
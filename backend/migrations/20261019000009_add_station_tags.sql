@@ -0,0 +1,22 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- station_tags: ルールで事前計算した駅のタグ (cmd/tags で全件置き換える)
+CREATE TABLE IF NOT EXISTS station_tags (
+    station_id BIGINT NOT NULL REFERENCES stations(id) ON DELETE CASCADE,
+    tag VARCHAR(50) NOT NULL,
+    position INTEGER NOT NULL DEFAULT 0, -- ルールの定義順 (表示順)
+    data_version VARCHAR(50) NOT NULL,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (station_id, tag)
+);
+
+-- tags= での絞り込み用
+CREATE INDEX IF NOT EXISTS idx_station_tags_tag ON station_tags (tag);
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS station_tags;
+-- +goose StatementEnd
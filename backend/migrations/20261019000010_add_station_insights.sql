@@ -0,0 +1,18 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- station_insights: 駅データから生成した解説文 (cmd/insights で入力が変わった駅だけ再生成する)
+CREATE TABLE IF NOT EXISTS station_insights (
+    station_id BIGINT PRIMARY KEY REFERENCES stations(id) ON DELETE CASCADE,
+    insight JSONB NOT NULL,            -- domain.AIInsight
+    input_hash VARCHAR(64) NOT NULL,   -- 生成に使った入力 (domain.InsightInput) のSHA-256
+    generator VARCHAR(100) NOT NULL,   -- 'template-v1', 'llm:<model>'
+    generated_at TIMESTAMP WITH TIME ZONE NOT NULL
+);
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS station_insights;
+-- +goose StatementEnd
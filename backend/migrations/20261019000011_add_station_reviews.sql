@@ -0,0 +1,32 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- station_reviews: 駅の口コミ (承認されたものだけを公開・集計する)
+CREATE TABLE IF NOT EXISTS station_reviews (
+    id BIGSERIAL PRIMARY KEY,
+    station_id BIGINT NOT NULL REFERENCES stations(id) ON DELETE CASCADE,
+    ratings JSONB NOT NULL,                  -- 軸 -> 1〜5
+    positive TEXT NOT NULL DEFAULT '',       -- 良い点
+    negative TEXT NOT NULL DEFAULT '',       -- 気になる点
+    status VARCHAR(20) NOT NULL DEFAULT 'pending', -- 'pending', 'approved', 'rejected'
+    flags TEXT[],                            -- 自動チェックで見つかった審査時の注意点 (url, html, contact, repeated)
+    client_hash VARCHAR(64) NOT NULL,        -- 投稿元 (IPアドレス) のHMAC
+    moderation_note TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    moderated_at TIMESTAMP WITH TIME ZONE,
+    CONSTRAINT chk_station_reviews_status CHECK (status IN ('pending', 'approved', 'rejected'))
+);
+
+-- 駅詳細・口コミ一覧 (承認済みを新しい順)
+CREATE INDEX IF NOT EXISTS idx_station_reviews_station ON station_reviews (station_id, status, created_at DESC);
+-- 審査キュー
+CREATE INDEX IF NOT EXISTS idx_station_reviews_status ON station_reviews (status, created_at);
+-- 投稿数の制限
+CREATE INDEX IF NOT EXISTS idx_station_reviews_client ON station_reviews (client_hash, created_at);
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS station_reviews;
+-- +goose StatementEnd
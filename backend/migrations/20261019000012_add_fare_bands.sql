@@ -0,0 +1,26 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- fare_bands: 事業者ごとの距離帯別の運賃と定期代 (円、cmd/import/fares で取り込む)
+-- 定期代が 0 の期間は発売していないものとして扱う
+CREATE TABLE IF NOT EXISTS fare_bands (
+    organization_code VARCHAR(20) NOT NULL,
+    min_km INT NOT NULL,                 -- 営業キロ (1km未満切り上げ) の下限
+    max_km INT NOT NULL,                 -- 上限 (この距離を含む)
+    fare INT NOT NULL,                   -- 片道の普通運賃
+    pass_1m INT NOT NULL DEFAULT 0,
+    pass_3m INT NOT NULL DEFAULT 0,
+    pass_6m INT NOT NULL DEFAULT 0,
+    data_version VARCHAR(50) NOT NULL,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (organization_code, min_km),
+    CONSTRAINT chk_fare_bands_km CHECK (min_km >= 1 AND max_km >= min_km),
+    CONSTRAINT chk_fare_bands_fare CHECK (fare > 0 AND pass_1m >= 0 AND pass_3m >= 0 AND pass_6m >= 0)
+);
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS fare_bands;
+-- +goose StatementEnd
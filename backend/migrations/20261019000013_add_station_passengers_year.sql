@@ -0,0 +1,21 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- station_details.daily_passengers は cmd/import/passengers が国土数値情報 (S12) から取り込む
+-- 取り込んだ集計年度とデータバージョンを残す
+ALTER TABLE station_details
+    ADD COLUMN IF NOT EXISTS passengers_year INT,
+    ADD COLUMN IF NOT EXISTS passengers_version VARCHAR(50);
+
+ALTER TABLE station_details
+    ADD CONSTRAINT chk_station_details_passengers CHECK (daily_passengers IS NULL OR daily_passengers >= 0);
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE station_details DROP CONSTRAINT IF EXISTS chk_station_details_passengers;
+ALTER TABLE station_details
+    DROP COLUMN IF EXISTS passengers_version,
+    DROP COLUMN IF EXISTS passengers_year;
+-- +goose StatementEnd
@@ -0,0 +1,23 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- station_terrain: 駅周辺の徒歩経路の起伏 (cmd/import/terrain が国土地理院の標高タイルから計算する)
+CREATE TABLE IF NOT EXISTS station_terrain (
+    station_id BIGINT PRIMARY KEY REFERENCES stations(id) ON DELETE CASCADE,
+    elevation DOUBLE PRECISION NOT NULL,       -- 駅の標高 (m)
+    avg_slope DOUBLE PRECISION NOT NULL,       -- 経路の平均勾配 (%)
+    elevation_gain DOUBLE PRECISION NOT NULL,  -- 駅と徒歩圏の端の間の経路で登る高さ (m) の平均
+    relative_height DOUBLE PRECISION NOT NULL, -- 周辺で最も低い地点からの高さ (m)
+    radius_meter INT NOT NULL,
+    method VARCHAR(20) NOT NULL,               -- network / radial
+    data_version VARCHAR(50) NOT NULL,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT chk_station_terrain CHECK (avg_slope >= 0 AND elevation_gain >= 0 AND relative_height >= 0)
+);
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS station_terrain;
+-- +goose StatementEnd
@@ -0,0 +1,18 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- station_disaster_access: 駅から最寄りの避難場所・救急病院までの直線距離 (cmd/import/disaster_facilities が pois から集計する)
+-- disaster_risks とは別に持つ (ハザードのデータがない駅に disaster_risks の行を作ると「リスクなし」と扱われるため)
+CREATE TABLE IF NOT EXISTS station_disaster_access (
+    station_id BIGINT PRIMARY KEY REFERENCES stations(id) ON DELETE CASCADE,
+    nearest_shelter_meter DOUBLE PRECISION,            -- 最寄りの避難場所 (m)。避難場所がない場合は NULL
+    nearest_emergency_hospital_meter DOUBLE PRECISION, -- 最寄りの救急告示病院・災害拠点病院 (m)
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS station_disaster_access;
+-- +goose StatementEnd
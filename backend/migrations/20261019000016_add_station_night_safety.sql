@@ -0,0 +1,18 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- station_night_safety: 夜間の安全性の指標 (cmd/import/osm_pois と cmd/import/koban が pois から集計する)
+CREATE TABLE IF NOT EXISTS station_night_safety (
+    station_id BIGINT PRIMARY KEY REFERENCES stations(id) ON DELETE CASCADE,
+    street_lamp_density DOUBLE PRECISION, -- 半径 radius_meter 以内の街灯 (本/km²)。街灯のデータがない場合は NULL
+    nearest_police_meter DOUBLE PRECISION, -- 最寄りの交番・警察署 (m)
+    radius_meter INT NOT NULL,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS station_night_safety;
+-- +goose StatementEnd
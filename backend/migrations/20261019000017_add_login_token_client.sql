@@ -0,0 +1,17 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- login_tokens.client_hash: ログインリンクの要求元 (IPアドレスのHMAC)。要求元ごとの送信数の制限に使う
+ALTER TABLE login_tokens ADD COLUMN IF NOT EXISTS client_hash CHAR(64) NOT NULL DEFAULT '';
+
+CREATE INDEX IF NOT EXISTS idx_login_tokens_email_created_at ON login_tokens (email, created_at);
+CREATE INDEX IF NOT EXISTS idx_login_tokens_client_hash_created_at ON login_tokens (client_hash, created_at);
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_login_tokens_client_hash_created_at;
+DROP INDEX IF EXISTS idx_login_tokens_email_created_at;
+ALTER TABLE login_tokens DROP COLUMN IF EXISTS client_hash;
+-- +goose StatementEnd
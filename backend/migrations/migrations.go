@@ -0,0 +1,8 @@
+// Package migrations はスキーマ定義のSQLマイグレーションをバイナリに埋め込む
+// ファイルは goose 形式 (-- +goose Up / -- +goose Down) で記述し、cmd/migrate で適用する
+package migrations
+
+import "embed"
+
+//go:embed *.sql
+var FS embed.FS
@@ -0,0 +1,213 @@
+package benchmark
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/gigaptera/hikkoshi-lens/backend/internal/domain"
+	"github.com/gigaptera/hikkoshi-lens/backend/internal/domain/service"
+	"github.com/gigaptera/hikkoshi-lens/backend/internal/infrastructure/repository"
+	"github.com/gigaptera/hikkoshi-lens/backend/internal/usecase"
+	"github.com/joho/godotenv"
+	"github.com/uptrace/bun"
+	"github.com/uptrace/bun/dialect/pgdialect"
+	"github.com/uptrace/bun/driver/pgdriver"
+)
+
+// 検索のホットパス (GetNearby, GetByLines, 家賃補助付き検索) のクエリ性能を計測するベンチマーク
+// data/processed/stationcode.json の全国の駅を投入したDBに対して実行する
+//
+//	go test -run '^$' -bench . -benchtime 200x ./test/benchmark/
+//
+// DATABASE_URL が未設定の場合はスキップする
+// マイグレーション (cmd/migrate up) 適用済みのDBであること
+
+// 東京駅
+const (
+	benchLat = 35.681236
+	benchLon = 139.767125
+)
+
+var (
+	seedOnce sync.Once
+	seedErr  error
+)
+
+type stationJSON struct {
+	Company     string    `json:"company"`
+	Line        string    `json:"line"`
+	Station     string    `json:"station"`
+	StationCode string    `json:"stationcode"`
+	Coordinates []float64 `json:"coordinates"` // [lon, lat]
+}
+
+func openDB(b *testing.B) *bun.DB {
+	b.Helper()
+
+	_ = godotenv.Load(filepath.Join("..", "..", ".env"))
+	dsn := os.Getenv("DATABASE_URL")
+	if dsn == "" {
+		b.Skip("DATABASE_URL is not set")
+	}
+
+	db := bun.NewDB(sql.OpenDB(pgdriver.NewConnector(pgdriver.WithDSN(dsn))), pgdialect.New())
+	if err := db.PingContext(context.Background()); err != nil {
+		b.Skipf("database is not reachable: %v", err)
+	}
+
+	seedOnce.Do(func() { seedErr = seedNationalDataset(context.Background(), db) })
+	if seedErr != nil {
+		b.Fatalf("Failed to seed national dataset: %v", seedErr)
+	}
+	return db
+}
+
+// seedNationalDataset は全国の駅と家賃相場 (mansion / 1r_1k_1dk) を投入する
+// 既存の行は変更しない (ON CONFLICT DO NOTHING)
+func seedNationalDataset(ctx context.Context, db *bun.DB) error {
+	data, err := os.ReadFile(filepath.Join("..", "..", "..", "data", "processed", "stationcode.json"))
+	if err != nil {
+		return err
+	}
+	var rows []stationJSON
+	if err := json.Unmarshal(data, &rows); err != nil {
+		return err
+	}
+
+	stations := make([]*domain.Station, 0, len(rows))
+	for _, r := range rows {
+		if len(r.Coordinates) < 2 {
+			continue
+		}
+		stations = append(stations, &domain.Station{
+			StationCode:      r.StationCode,
+			OrganizationCode: r.Company,
+			LineName:         r.Line,
+			Name:             r.Station,
+			Location:         fmt.Sprintf("POINT(%f %f)", r.Coordinates[0], r.Coordinates[1]),
+		})
+	}
+
+	const batchSize = 1000
+	for i := 0; i < len(stations); i += batchSize {
+		end := min(i+batchSize, len(stations))
+		batch := stations[i:end]
+		if _, err := db.NewInsert().Model(&batch).On("CONFLICT (station_code) DO NOTHING").Exec(ctx); err != nil {
+			return err
+		}
+	}
+
+	_, err = db.ExecContext(ctx, `
+		INSERT INTO market_prices (station_id, building_type, layout, avg_rent, source)
+		SELECT id, 'mansion', '1r_1k_1dk', 6 + (id % 60) / 10.0, 'BENCHMARK'
+		FROM stations
+		ON CONFLICT (station_id, building_type, layout) DO NOTHING`)
+	if err != nil {
+		return err
+	}
+
+	_, err = db.ExecContext(ctx, "ANALYZE stations; ANALYZE market_prices")
+	return err
+}
+
+func BenchmarkGetNearby(b *testing.B) {
+	db := openDB(b)
+	defer db.Close()
+	repo := repository.NewStationRepository(db)
+	ctx := context.Background()
+
+	for _, radius := range []int{500, 3000, 20000} {
+		b.Run(fmt.Sprintf("radius=%d", radius), func(b *testing.B) {
+			filter := domain.StationFilter{RadiusMeter: radius, BuildingType: "mansion", Layout: "1r_1k_1dk"}
+			var found int
+			for i := 0; i < b.N; i++ {
+				stations, err := repo.GetNearby(ctx, benchLat, benchLon, filter)
+				if err != nil {
+					b.Fatal(err)
+				}
+				found = len(stations)
+			}
+			b.ReportMetric(float64(found), "stations/op")
+		})
+	}
+}
+
+func BenchmarkGetByLines(b *testing.B) {
+	db := openDB(b)
+	defer db.Close()
+	repo := repository.NewStationRepository(db)
+	ctx := context.Background()
+
+	nearby, err := repo.GetNearby(ctx, benchLat, benchLon, domain.StationFilter{RadiusMeter: 3000})
+	if err != nil {
+		b.Fatal(err)
+	}
+	seen := make(map[domain.LineKey]bool)
+	var keys []domain.LineKey
+	for _, s := range nearby {
+		k := domain.LineKey{OrganizationCode: s.OrganizationCode, LineName: s.LineName}
+		if !seen[k] {
+			seen[k] = true
+			keys = append(keys, k)
+		}
+	}
+
+	b.Run("batched", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			if _, err := repo.GetByLines(ctx, keys); err != nil {
+				b.Fatal(err)
+			}
+		}
+		b.ReportMetric(float64(len(keys)), "lines/op")
+	})
+
+	// 旧実装 (最寄り駅ごとにGetByLine) との比較用
+	b.Run("per-station", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			for _, s := range nearby {
+				if _, err := repo.GetByLine(ctx, s.OrganizationCode, s.LineName); err != nil {
+					b.Fatal(err)
+				}
+			}
+		}
+		b.ReportMetric(float64(len(nearby)), "queries/op")
+	})
+}
+
+func BenchmarkSearchWithSubsidy(b *testing.B) {
+	db := openDB(b)
+	defer db.Close()
+	uc := usecase.NewStationUsecase(usecase.StationUsecaseDeps{
+		Repo:      repository.NewStationRepository(db),
+		ScoreRepo: repository.NewStationScoreRepository(db),
+		TagRepo:   repository.NewTagRepository(db),
+		Scoring:   service.NewScoringService(),
+	})
+	ctx := context.Background()
+
+	filter := domain.StationFilter{
+		RadiusMeter:     3000,
+		BuildingType:    "mansion",
+		Layout:          "1r_1k_1dk",
+		Weights:         map[string]int{"access": 50, "rent": 50},
+		CalculateScores: true,
+		SubsidyType:     "from_workplace",
+		SubsidyRange:    3,
+	}
+
+	var found int
+	for i := 0; i < b.N; i++ {
+		stations, err := uc.GetNearbyStations(ctx, benchLat, benchLon, filter)
+		if err != nil {
+			b.Fatal(err)
+		}
+		found = len(stations)
+	}
+	b.ReportMetric(float64(found), "stations/op")
+}
@@ -242,3 +242,42 @@ func GetConcurrentTestStations() []*domain.Station {
 		},
 	}
 }
+
+// GetMaliciousReviewTexts は口コミの本文として受け付けてはいけない入力を返す
+// GetMaliciousInputStations の制御文字・巨大文字列に加え、禁止語を全角・ゼロ幅文字で崩したものを含む
+func GetMaliciousReviewTexts() []string {
+	stations := GetMaliciousInputStations()
+	return []string{
+		// 制御文字 (ID:1003)
+		stations[3].StationCode,
+		stations[3].Name,
+		// 巨大文字列 (ID:1002)
+		stations[2].Name,
+		stations[2].Address,
+		// ゼロ幅文字・ソフトハイフンだけ (ID:1004)
+		stations[4].StationCode,
+		stations[4].Name,
+		// 禁止語 (表記ゆれ)
+		"管理会社の人は死ね",
+		"ＦＵＣＫな街、二度と住まない",
+		"死\u200bね死\u200bね",
+		"副業で稼げる方法はこちら",
+		// 空白だけ
+		" 　 \n\t",
+	}
+}
+
+// GetSuspiciousReviewTexts は受け付けるが審査時の注意点が付く入力を返す
+// SQLインジェクション・XSSの文字列は本文としてそのまま保存し、表示側でエスケープする
+func GetSuspiciousReviewTexts() []string {
+	stations := GetMaliciousInputStations()
+	return []string{
+		// XSS試行 (ID:1001)
+		stations[1].StationCode,
+		stations[1].Address,
+		"<a href='https://example.com'>格安物件</a>",
+		// 連絡先
+		"お部屋探しは 090-1234-5678 まで",
+		"問い合わせ: agent@example.com",
+	}
+}
@@ -0,0 +1,39 @@
+package helper_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/gigaptera/hikkoshi-lens/backend/internal/domain"
+	"github.com/gigaptera/hikkoshi-lens/backend/internal/domain/service"
+	"github.com/gigaptera/hikkoshi-lens/backend/test/helper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestReviewFilter_MaliciousTexts は悪意のある口コミの本文が受け付けられないことを確認する
+func TestReviewFilter_MaliciousTexts(t *testing.T) {
+	f := service.NewReviewFilter(service.DefaultBannedWords())
+
+	for _, text := range helper.GetMaliciousReviewTexts() {
+		_, err := f.Check(text)
+		require.Error(t, err, "%q", text)
+		assert.True(t, errors.Is(err, domain.ErrInvalidReview) || errors.Is(err, domain.ErrReviewRejected), "%q: %v", text, err)
+	}
+}
+
+// TestReviewFilter_SuspiciousTexts は判断が必要な本文に審査時の注意点が付くことを確認する
+func TestReviewFilter_SuspiciousTexts(t *testing.T) {
+	f := service.NewReviewFilter(service.DefaultBannedWords())
+
+	for _, text := range helper.GetSuspiciousReviewTexts() {
+		flags, err := f.Check(text)
+		require.NoError(t, err, "%q", text)
+		assert.NotEmpty(t, flags, "%q", text)
+	}
+
+	// SQLインジェクションの文字列は本文として受け付ける (パラメータで保存する)
+	flags, err := f.Check(helper.GetMaliciousInputStations()[0].Address)
+	require.NoError(t, err)
+	assert.Empty(t, flags)
+}
@@ -12,6 +12,7 @@ import (
 	"github.com/gigaptera/hikkoshi-lens/backend/internal/domain/service"
 	"github.com/gigaptera/hikkoshi-lens/backend/internal/infrastructure/repository"
 	"github.com/gigaptera/hikkoshi-lens/backend/internal/interface/handler"
+	"github.com/gigaptera/hikkoshi-lens/backend/internal/interface/openapi"
 	"github.com/gigaptera/hikkoshi-lens/backend/internal/usecase"
 	"github.com/joho/godotenv"
 	"github.com/labstack/echo/v4"
@@ -86,8 +87,14 @@ func NewTestServer(t *testing.T) *TestServer {
 	e := echo.New()
 	e.HideBanner = true // テスト時はバナーを非表示
 
+	// OpenAPI仕様書（リクエスト検証用）
+	spec, err := openapi.Load()
+	if err != nil {
+		t.Fatalf("Failed to load OpenAPI spec: %v", err)
+	}
+
 	// ハンドラーのセットアップ
-	setupRoutes(e, db)
+	setupRoutes(e, db, spec)
 
 	return &TestServer{
 		Echo: e,
@@ -96,8 +103,8 @@ func NewTestServer(t *testing.T) *TestServer {
 }
 
 // setupRoutes はルートを設定します
-func setupRoutes(e *echo.Echo, db *bun.DB) {
-	api := e.Group("/api")
+func setupRoutes(e *echo.Echo, db *bun.DB, spec *openapi.Spec) {
+	api := e.Group("/api", openapi.RequestValidator(spec))
 	{
 		// Health
 		hHealth := handler.NewHealthHandler()
@@ -105,9 +112,21 @@ func setupRoutes(e *echo.Echo, db *bun.DB) {
 
 		// Station
 		repoStation := repository.NewStationRepository(db)
+		repoStationScore := repository.NewStationScoreRepository(db)
 		svcScoring := service.NewScoringService()
-		ucStation := usecase.NewStationUsecase(repoStation, svcScoring)
-		hStation := handler.NewStationHandler(ucStation)
+		ucStation := usecase.NewStationUsecase(usecase.StationUsecaseDeps{
+			Repo:          repoStation,
+			ScoreRepo:     repoStationScore,
+			TagRepo:       repository.NewTagRepository(db),
+			InsightRepo:   repository.NewInsightRepository(db),
+			ReviewRepo:    repository.NewReviewRepository(db),
+			PassengerRepo: repository.NewPassengerRepository(db),
+			TerrainRepo:   repository.NewTerrainRepository(db),
+			POIRepo:       repository.NewPOIRepository(db),
+			NightRepo:     repository.NewNightSafetyRepository(db),
+			Scoring:       svcScoring,
+		})
+		hStation := handler.NewStationHandler(ucStation, nil, nil)
 		api.GET("/stations/nearby", hStation.GetNearby)
 		api.GET("/stations/:id/three-stops", hStation.GetStationsWithinThreeStops)
 	}